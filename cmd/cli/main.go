@@ -7,18 +7,30 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
 	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+	"github.com/twitchtv/twirp"
 )
 
 func main() {
 	flag.Usage = func() {
 		fmt.Printf("Usage: acai-cli [command] [options]\n")
 		fmt.Println("Commands:")
-		fmt.Println("  ask        Create a new conversation with assistant or continue an existing one")
+		fmt.Println("  ask        Create a new conversation with assistant or continue an existing one, type '/attach <file>' to send a file or '/schema <file>' to get the next reply as JSON")
 		fmt.Println("  list       List existing conversations")
 		fmt.Println("  show       Show conversation by ID")
+		fmt.Println("  notes      List your notes, optionally matching a search text")
+		fmt.Println("  todos      List your open to-do items, optionally matching a search text")
+		fmt.Println("  reminders  List your pending reminders, or cancel one with 'reminders cancel <id>'")
+		fmt.Println("  calendar   Export your calendar as .ics with 'calendar export', or add events with 'calendar import <file> [time zone]'")
+		fmt.Println("  memories   List what the assistant remembers about you, or forget one fact with 'memories delete <id>'")
+		fmt.Println("  personas   List the personas a conversation can be started with")
+		fmt.Println("  tools      Show your tool policy, or set one list with 'tools allow|deny|confirm <tool,tool,...>'")
 	}
 
 	if len(os.Args) < 2 {
@@ -33,15 +45,50 @@ func main() {
 		url = v
 	}
 
+	// UNITS sets the unit preference of new conversations: metric, imperial or both
+	unitPref := pb.Units_UNITS_UNSPECIFIED
+	if v := os.Getenv("UNITS"); v != "" {
+		system, err := units.Parse(v)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		unitPref = pb.Units(pb.Units_value[strings.ToUpper(string(system))])
+	}
+
+	// PERSONA sets the persona of new conversations, see the personas command
+	persona := os.Getenv("PERSONA")
+
 	cli := pb.NewChatServiceJSONClient(url, http.DefaultClient)
 	ctx := context.Background()
 
+	header := http.Header{}
+
+	// USER_ID selects whose notes and to-do items are used, the server's default user otherwise
+	if v := os.Getenv("USER_ID"); v != "" {
+		header.Set(auth.Header, v)
+	}
+
+	// NO_CACHE asks for fresh answers rather than ones from the server's response cache
+	if os.Getenv("NO_CACHE") != "" {
+		header.Set("Cache-Control", "no-cache")
+	}
+
+	if len(header) > 0 {
+		var err error
+		if ctx, err = twirp.WithHTTPRequestHeaders(ctx, header); err != nil {
+			fmt.Printf("Error setting request headers: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	switch os.Args[1] {
 	case "ask":
 		fmt.Println("Press CMD+C to exit.")
 		fmt.Println()
 
 		cid := ""
+		var pendingCalls []*pb.ToolCall
 		if len(os.Args) >= 3 {
 			cid = os.Args[2]
 			resp, err := cli.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: cid})
@@ -58,6 +105,7 @@ func main() {
 			for _, msg := range resp.GetConversation().GetMessages() {
 				fmt.Printf("%s, %s:\n%s\n\n", msg.GetRole(), msg.GetTimestamp().AsTime().Format(time.TimeOnly), msg.GetContent())
 			}
+			pendingCalls = resp.GetConversation().GetPendingToolCalls()
 		} else {
 			fmt.Println("Starting a new conversation, type your message below.")
 			fmt.Println()
@@ -65,6 +113,51 @@ func main() {
 
 		reader := bufio.NewReader(os.Stdin)
 
+		// Files attached with /attach are sent with the next message
+		var attachmentIDs []string
+
+		// The JSON Schema set with /schema applies to the next message
+		var responseSchema string
+
+		// show prints the reply, then the JSON document asked for with /schema
+		show := func(reply, structured string) {
+			fmt.Printf("ASSISTANT:\n%s\n\n", reply)
+			if structured != "" {
+				fmt.Printf("JSON:\n%s\n\n", structured)
+			}
+		}
+
+		// confirm asks the user about each tool call the reply waits on until it is done
+		confirm := func(reply, structured string, pending []*pb.ToolCall) (string, string) {
+			for len(pending) > 0 {
+				call := pending[0]
+				fmt.Printf("The assistant wants to run %s %s. Allow it? [y/N] ", call.GetName(), call.GetArguments())
+				answer, _, err := reader.ReadLine()
+				if err != nil {
+					fmt.Printf("Error reading input: %v\n", err)
+					os.Exit(1)
+				}
+
+				out, err := cli.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{
+					ConversationId: cid,
+					ToolCallId:     call.GetId(),
+					Approve:        strings.EqualFold(strings.TrimSpace(string(answer)), "y"),
+				})
+				if err != nil {
+					fmt.Printf("Error confirming tool call: %v\n", err)
+					os.Exit(1)
+				}
+
+				reply, structured, pending = out.GetReply(), out.GetStructuredReply(), out.GetPendingToolCalls()
+			}
+			fmt.Println()
+			return reply, structured
+		}
+
+		if len(pendingCalls) > 0 {
+			show(confirm("", "", pendingCalls))
+		}
+
 		for {
 			fmt.Printf("USER:\n")
 			line, _, err := reader.ReadLine()
@@ -75,9 +168,48 @@ func main() {
 
 			fmt.Println()
 
+			if path, ok := strings.CutPrefix(string(line), "/attach "); ok {
+				path = strings.TrimSpace(path)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					fmt.Printf("Error reading file: %v\n\n", err)
+					continue
+				}
+
+				out, err := cli.UploadAttachment(ctx, &pb.UploadAttachmentRequest{
+					Filename:       filepath.Base(path),
+					Data:           data,
+					ConversationId: cid,
+				})
+				if err != nil {
+					fmt.Printf("Error uploading file: %v\n\n", err)
+					continue
+				}
+
+				attachmentIDs = append(attachmentIDs, out.GetAttachment().GetId())
+				fmt.Printf("Attached %s, it will be sent with your next message.\n\n", out.GetAttachment().GetFilename())
+				continue
+			}
+
+			if path, ok := strings.CutPrefix(string(line), "/schema "); ok {
+				data, err := os.ReadFile(strings.TrimSpace(path))
+				if err != nil {
+					fmt.Printf("Error reading file: %v\n\n", err)
+					continue
+				}
+
+				responseSchema = string(data)
+				fmt.Printf("The reply to your next message will include JSON matching the schema.\n\n")
+				continue
+			}
+
 			if cid == "" {
 				out, err := cli.StartConversation(ctx, &pb.StartConversationRequest{
-					Message: string(line),
+					Message:        string(line),
+					Units:          unitPref,
+					AttachmentIds:  attachmentIDs,
+					Persona:        persona,
+					ResponseSchema: responseSchema,
 				})
 
 				if err != nil {
@@ -91,13 +223,16 @@ func main() {
 				fmt.Println()
 
 				cid = out.GetConversationId()
-				fmt.Printf("ASSISTANT:\n%s\n\n", out.GetReply())
+				attachmentIDs, responseSchema = nil, ""
+				show(confirm(out.GetReply(), out.GetStructuredReply(), out.GetPendingToolCalls()))
 				continue
 			}
 
 			out, err := cli.ContinueConversation(ctx, &pb.ContinueConversationRequest{
 				ConversationId: cid,
 				Message:        string(line),
+				AttachmentIds:  attachmentIDs,
+				ResponseSchema: responseSchema,
 			})
 
 			if err != nil {
@@ -105,7 +240,9 @@ func main() {
 				os.Exit(1)
 			}
 
-			fmt.Printf("ASSISTANT:\n%s\n\n", out.GetReply())
+			attachmentIDs, responseSchema = nil, ""
+
+			show(confirm(out.GetReply(), out.GetStructuredReply(), out.GetPendingToolCalls()))
 		}
 
 	case "list":
@@ -146,5 +283,216 @@ func main() {
 		for _, msg := range resp.GetConversation().GetMessages() {
 			fmt.Printf("%s, %s:\n%s\n\n", msg.GetRole(), msg.GetTimestamp().AsTime().Format(time.TimeOnly), msg.GetContent())
 		}
+	case "notes":
+		resp, err := cli.ListNotes(ctx, &pb.ListNotesRequest{Query: strings.Join(os.Args[2:], " ")})
+		if err != nil {
+			fmt.Printf("Error listing notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(resp.Notes) == 0 {
+			fmt.Println("No notes found.")
+			return
+		}
+
+		for _, note := range resp.Notes {
+			fmt.Printf("%s   %s   %s\n%s\n\n", note.GetId(), note.GetUpdatedAt().AsTime().Format(time.DateOnly), note.GetTitle(), note.GetContent())
+		}
+	case "todos":
+		resp, err := cli.ListTodos(ctx, &pb.ListTodosRequest{Query: strings.Join(os.Args[2:], " ")})
+		if err != nil {
+			fmt.Printf("Error listing to-do items: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(resp.Todos) == 0 {
+			fmt.Println("No open to-do items.")
+			return
+		}
+
+		fmt.Println("ID                         DUE          TEXT")
+		for _, todo := range resp.Todos {
+			due := "-"
+			if todo.GetDue() != nil {
+				due = todo.GetDue().AsTime().Format(time.DateOnly)
+			}
+			fmt.Printf("%s   %-10s   %s\n", todo.GetId(), due, todo.GetText())
+		}
+	case "reminders":
+		if len(os.Args) > 2 && os.Args[2] == "cancel" {
+			if len(os.Args) < 4 {
+				fmt.Println("Error: reminder ID required")
+				os.Exit(1)
+			}
+
+			resp, err := cli.CancelReminder(ctx, &pb.CancelReminderRequest{ReminderId: os.Args[3]})
+			if err != nil {
+				fmt.Printf("Error cancelling reminder: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Cancelled:", resp.GetReminder().GetText())
+			return
+		}
+
+		resp, err := cli.ListReminders(ctx, &pb.ListRemindersRequest{})
+		if err != nil {
+			fmt.Printf("Error listing reminders: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(resp.Reminders) == 0 {
+			fmt.Println("No pending reminders.")
+			return
+		}
+
+		fmt.Println("ID                         DUE                          TEXT")
+		for _, r := range resp.Reminders {
+			due := r.GetDueAt().AsTime()
+			if loc, err := time.LoadLocation(r.GetTimeZone()); err == nil {
+				due = due.In(loc)
+			}
+			fmt.Printf("%s   %-26s   %s\n", r.GetId(), due.Format("Mon 2 Jan 2006 15:04 MST"), r.GetText())
+		}
+	case "calendar":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: 'export' or 'import <file> [time zone]' required")
+			os.Exit(1)
+		}
+
+		switch os.Args[2] {
+		case "export":
+			resp, err := cli.ExportCalendar(ctx, &pb.ExportCalendarRequest{})
+			if err != nil {
+				fmt.Printf("Error exporting calendar: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(resp.GetIcs())
+		case "import":
+			if len(os.Args) < 4 {
+				fmt.Println("Error: .ics file required")
+				os.Exit(1)
+			}
+
+			data, err := os.ReadFile(os.Args[3])
+			if err != nil {
+				fmt.Printf("Error reading calendar: %v\n", err)
+				os.Exit(1)
+			}
+
+			req := &pb.ImportCalendarRequest{Ics: string(data)}
+			if len(os.Args) > 4 {
+				req.TimeZone = os.Args[4]
+			}
+
+			resp, err := cli.ImportCalendar(ctx, req)
+			if err != nil {
+				fmt.Printf("Error importing calendar: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Imported %d new and %d updated event(s):\n", resp.GetCreated(), resp.GetUpdated())
+			for _, e := range resp.GetEvents() {
+				start := e.GetStart().AsTime()
+				if loc, err := time.LoadLocation(e.GetTimeZone()); err == nil && !e.GetAllDay() {
+					start = start.In(loc)
+				}
+				layout := "Mon 2 Jan 2006 15:04 MST"
+				if e.GetAllDay() {
+					layout = "Mon 2 Jan 2006"
+				}
+				fmt.Printf("%s   %-26s   %s\n", e.GetId(), start.Format(layout), e.GetTitle())
+			}
+		default:
+			fmt.Printf("Error: unknown calendar command '%s'\n", os.Args[2])
+			os.Exit(1)
+		}
+	case "memories":
+		if len(os.Args) > 2 && os.Args[2] == "delete" {
+			if len(os.Args) < 4 {
+				fmt.Println("Error: memory ID required")
+				os.Exit(1)
+			}
+
+			if _, err := cli.DeleteMemory(ctx, &pb.DeleteMemoryRequest{MemoryId: os.Args[3]}); err != nil {
+				fmt.Printf("Error deleting memory: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Forgotten.")
+			return
+		}
+
+		resp, err := cli.ListMemories(ctx, &pb.ListMemoriesRequest{})
+		if err != nil {
+			fmt.Printf("Error listing memories: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(resp.Memories) == 0 {
+			fmt.Println("Nothing remembered yet.")
+			return
+		}
+
+		fmt.Println("ID                         LEARNED      FACT")
+		for _, m := range resp.Memories {
+			fmt.Printf("%s   %-10s   %s\n", m.GetId(), m.GetCreatedAt().AsTime().Format(time.DateOnly), m.GetText())
+		}
+
+	case "personas":
+		resp, err := cli.ListPersonas(ctx, &pb.ListPersonasRequest{})
+		if err != nil {
+			fmt.Printf("Error listing personas: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("NAME             DESCRIPTION")
+		for _, p := range resp.Personas {
+			fmt.Printf("%-15s  %s\n", p.GetName(), p.GetDescription())
+		}
+
+	case "tools":
+		resp, err := cli.GetToolPolicy(ctx, &pb.GetToolPolicyRequest{})
+		if err != nil {
+			fmt.Printf("Error getting tool policy: %v\n", err)
+			os.Exit(1)
+		}
+		policy := resp.GetPolicy()
+
+		if len(os.Args) > 2 {
+			var names []string
+			if len(os.Args) > 3 {
+				names = strings.Split(os.Args[3], ",")
+			}
+
+			switch os.Args[2] {
+			case "allow":
+				policy.Allow = names
+			case "deny":
+				policy.Deny = names
+			case "confirm":
+				policy.Confirm = names
+			default:
+				fmt.Printf("Error: unknown list %q, use allow, deny or confirm\n", os.Args[2])
+				os.Exit(1)
+			}
+
+			out, err := cli.SetToolPolicy(ctx, &pb.SetToolPolicyRequest{Policy: policy})
+			if err != nil {
+				fmt.Printf("Error setting tool policy: %v\n", err)
+				os.Exit(1)
+			}
+			policy = out.GetPolicy()
+		}
+
+		show := func(names []string, none string) string {
+			if len(names) == 0 {
+				return none
+			}
+			return strings.Join(names, ", ")
+		}
+		fmt.Println("Allow:  ", show(policy.GetAllow(), "all tools"))
+		fmt.Println("Deny:   ", show(policy.GetDeny(), "none"))
+		fmt.Println("Confirm:", show(policy.GetConfirm(), "none"))
 	}
 }
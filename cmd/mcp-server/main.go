@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
+	"github.com/isabermoussa/personal-assistant-API/internal/httpx"
+	"github.com/isabermoussa/personal-assistant-API/internal/mcp"
+)
+
+func main() {
+	transport := flag.String("transport", "stdio", "Transport to serve MCP over: stdio or http")
+	addr := flag.String("addr", "localhost:8081", "Listen address for the http transport")
+	flag.Parse()
+
+	// Stdout carries the protocol on stdio, so logs must go to stderr
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The deployment's TOOLS_ALLOW and TOOLS_DENY apply here too; MCP clients confirm calls themselves
+	server := mcp.NewServer("personal-assistant-tools", "1.0.0", tools.Allowed(assistant.New().Tools(), assistant.ToolPolicyFromEnv()))
+
+	switch *transport {
+	case "stdio":
+		slog.Info("Serving MCP over stdio")
+		if err := server.ServeStdio(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("MCP stdio server failed", "error", err)
+			os.Exit(1)
+		}
+
+	case "http":
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", httpx.Logger()(httpx.Recovery()(server)))
+
+		srv := &http.Server{Addr: *addr, Handler: mux}
+
+		go func() {
+			slog.Info("Serving MCP over HTTP", "addr", *addr, "path", "/mcp")
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("MCP HTTP server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("MCP server forced to shutdown", "error", err)
+		}
+
+	default:
+		slog.Error("Unknown transport", "transport", *transport)
+		os.Exit(2)
+	}
+}
@@ -11,12 +11,19 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/guard"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/recall"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
 	"github.com/isabermoussa/personal-assistant-API/internal/httpx"
 	"github.com/isabermoussa/personal-assistant-API/internal/mongox"
 	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"github.com/isabermoussa/personal-assistant-API/internal/reminders"
 	"github.com/isabermoussa/personal-assistant-API/internal/telemetry"
 	"github.com/twitchtv/twirp"
 )
@@ -45,10 +52,48 @@ func main() {
 		panic(err)
 	}
 
+	personas, err := assistant.LoadPersonasFromEnv()
+	if err != nil {
+		slog.Error("Failed to load personas", "error", err)
+		panic(err)
+	}
+
+	sealer, err := pii.SealerFromEnv()
+	if err != nil {
+		slog.Error("Failed to configure the storage of personal data", "error", err)
+		panic(err)
+	}
+
+	redactor := pii.RedactorFromEnv()
+
 	mongo := mongox.MustConnect()
 
-	repo := model.New(mongo)
-	assist := assistant.New()
+	repo := model.New(mongo, model.WithSealer(sealer))
+	assist := assistant.New(
+		assistant.WithNoteStore(repo),
+		assistant.WithTodoStore(repo),
+		assistant.WithReminderStore(repo),
+		assistant.WithCalendarStore(repo),
+		assistant.WithMemoryStore(repo),
+		assistant.WithConversationIndex(recall.NewIndexFromEnv(mongo, recall.WithRedactor(redactor), recall.WithSealer(sealer))),
+		assistant.WithAttachmentLibrary(attachments.NewLibrary(recall.NewOpenAIEmbedder(), repo, attachments.WithRedactor(redactor))),
+		assistant.WithPersonas(personas...),
+		assistant.WithPersonaStore(repo),
+		assistant.WithToolPolicy(assistant.ToolPolicyFromEnv()),
+		assistant.WithToolPolicyStore(repo),
+		assistant.WithFallbacks(assistant.FallbacksFromEnv()...),
+		assistant.WithCache(cache.NewFromEnv(mongo)),
+		assistant.WithGuard(guard.NewFromEnv(guard.WithRedactor(redactor))),
+		assistant.WithRedactor(redactor),
+	)
+
+	// Deliver due reminders in the background until shutdown
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		reminders.NewScheduler(repo, reminders.NewDeliverersFromEnv(repo)).Run(schedulerCtx)
+	}()
 
 	server := chat.NewServer(repo, assist)
 
@@ -59,6 +104,8 @@ func main() {
 		telemetry.MetricsMiddleware(metrics), // Add metrics
 		httpx.Logger(),                       // Existing logger
 		httpx.Recovery(),                     // Existing recovery
+		auth.Middleware(),                    // Resolve the user from X-User-Id
+		cache.Middleware(),                   // Skip the response cache on Cache-Control: no-cache
 	)
 
 	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -97,5 +144,9 @@ func main() {
 		slog.Error("Server forced to shutdown", "error", err)
 	}
 
+	// Reminders interrupted mid-delivery are picked up again once their lease expires
+	stopScheduler()
+	<-schedulerDone
+
 	slog.Info("Server exited")
 }
@@ -0,0 +1,69 @@
+// Package auth identifies the user a request acts for. The server trusts the
+// X-User-Id header, so it must sit behind a proxy or gateway that authenticates
+// callers and sets the header; requests without it act for DefaultUser.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"unicode"
+)
+
+// Header carries the ID of the user a request acts for
+const Header = "X-User-Id"
+
+// DefaultUser owns the data of requests that don't name a user,
+// e.g. single-user deployments and the MCP server
+const DefaultUser = "default"
+
+// maxUserIDLength bounds user IDs, which end up in every stored document
+const maxUserIDLength = 128
+
+type contextKey struct{}
+
+// WithUser returns a context acting for the given user
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// FromContext returns the user ctx acts for, or DefaultUser
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultUser
+}
+
+// ParseUserID validates a user ID taken from a request
+func ParseUserID(id string) (string, error) {
+	if id == "" {
+		return DefaultUser, nil
+	}
+	if len(id) > maxUserIDLength {
+		return "", fmt.Errorf("user ID is longer than %d bytes", maxUserIDLength)
+	}
+	for _, r := range id {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return "", fmt.Errorf("user ID contains invalid character %q", r)
+		}
+	}
+	return id, nil
+}
+
+// Middleware puts the user named by the X-User-Id header into the request context
+func Middleware() func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := ParseUserID(r.Header.Get(Header))
+			if err != nil {
+				slog.WarnContext(r.Context(), "Rejected request with invalid user ID", "error", err)
+				http.Error(w, fmt.Sprintf("invalid %s header: %v", Header, err), http.StatusBadRequest)
+				return
+			}
+
+			handler.ServeHTTP(w, r.WithContext(WithUser(r.Context(), userID)))
+		})
+	}
+}
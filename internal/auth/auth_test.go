@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultUser {
+		t.Errorf("FromContext(empty) = %q, want %q", got, DefaultUser)
+	}
+	if got := FromContext(WithUser(context.Background(), "alice")); got != "alice" {
+		t.Errorf("FromContext = %q, want %q", got, "alice")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "named user", header: "user-42", wantStatus: http.StatusOK, wantUser: "user-42"},
+		{name: "no header", header: "", wantStatus: http.StatusOK, wantUser: DefaultUser},
+		{name: "email as ID", header: "ana@example.com", wantStatus: http.StatusOK, wantUser: "ana@example.com"},
+		{name: "whitespace", header: "user 42", wantStatus: http.StatusBadRequest},
+		{name: "too long", header: strings.Repeat("x", maxUserIDLength+1), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/twirp/acai.chat.ChatService/ListNotes", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got != tt.wantUser {
+				t.Errorf("user = %q, want %q", got, tt.wantUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"slices"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+// Slot is a span of free time
+type Slot struct {
+	Start, End time.Time
+}
+
+// Hours are the hours of each day free time is looked for in, in minutes since
+// midnight of the zone the search runs in
+type Hours struct {
+	Start, End int
+
+	// Weekends also looks for free time on Saturdays and Sundays
+	Weekends bool
+}
+
+// FreeTime returns the spans of at least length between from and to, within hours
+// on each day in loc, that no event overlaps. All-day events don't block time,
+// as calendars treat them as free by default.
+func FreeTime(events []*model.Event, from, to time.Time, loc *time.Location, hours Hours, length time.Duration) []Slot {
+	var busy []Slot
+	for _, e := range events {
+		if !e.AllDay && e.End.After(e.Start) {
+			busy = append(busy, Slot{Start: e.Start, End: e.End})
+		}
+	}
+	slices.SortFunc(busy, func(a, b Slot) int { return a.Start.Compare(b.Start) })
+
+	var free []Slot
+	first := from.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !hours.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+
+		// time.Date normalises the minutes, and the wall clock survives DST changes
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.Start, 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.End, 0, 0, loc)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		for _, b := range busy {
+			if !b.End.After(start) {
+				continue
+			}
+			if !b.Start.Before(end) {
+				break
+			}
+			if b.Start.Sub(start) >= length {
+				free = append(free, Slot{Start: start, End: b.Start})
+			}
+			start = b.End
+		}
+		if end.Sub(start) >= length {
+			free = append(free, Slot{Start: start, End: end})
+		}
+	}
+
+	return free
+}
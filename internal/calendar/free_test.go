@@ -0,0 +1,112 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+func TestFreeTime(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 10, day, hour, minute, 0, 0, madrid)
+	}
+	event := func(start, end time.Time) *model.Event {
+		return &model.Event{Start: start, End: end, TimeZone: "Europe/Madrid"}
+	}
+	workday := Hours{Start: 9 * 60, End: 18 * 60}
+
+	tests := []struct {
+		name   string
+		events []*model.Event
+		from   time.Time
+		to     time.Time
+		hours  Hours
+		length time.Duration
+		want   []Slot
+	}{
+		{
+			name:   "gaps between events",
+			events: []*model.Event{event(at(22, 10, 0), at(22, 11, 0)), event(at(22, 13, 0), at(22, 14, 30))},
+			from:   at(22, 0, 0),
+			to:     at(23, 0, 0),
+			hours:  workday,
+			length: time.Hour,
+			want: []Slot{
+				{at(22, 9, 0), at(22, 10, 0)},
+				{at(22, 11, 0), at(22, 13, 0)},
+				{at(22, 14, 30), at(22, 18, 0)},
+			},
+		},
+		{
+			name: "overlapping and unsorted events",
+			events: []*model.Event{
+				event(at(22, 12, 0), at(22, 13, 0)),
+				event(at(22, 8, 0), at(22, 12, 30)),
+				event(at(22, 9, 30), at(22, 10, 0)),
+			},
+			from:   at(22, 0, 0),
+			to:     at(23, 0, 0),
+			hours:  workday,
+			length: 30 * time.Minute,
+			want:   []Slot{{at(22, 13, 0), at(22, 18, 0)}},
+		},
+		{
+			name:   "short gaps are skipped",
+			events: []*model.Event{event(at(22, 9, 20), at(22, 17, 45))},
+			from:   at(22, 0, 0),
+			to:     at(23, 0, 0),
+			hours:  workday,
+			length: 30 * time.Minute,
+		},
+		{
+			name:   "starts from the given time",
+			from:   at(22, 16, 10),
+			to:     at(23, 10, 0),
+			hours:  workday,
+			length: time.Hour,
+			want:   []Slot{{at(22, 16, 10), at(22, 18, 0)}, {at(23, 9, 0), at(23, 10, 0)}},
+		},
+		{
+			name:   "weekends and all-day events",
+			events: []*model.Event{{Start: time.Date(2025, 10, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 10, 25, 0, 0, 0, 0, time.UTC), AllDay: true}},
+			from:   at(24, 0, 0),
+			to:     at(27, 0, 0),
+			hours:  workday,
+			length: time.Hour,
+			want:   []Slot{{at(24, 9, 0), at(24, 18, 0)}},
+		},
+		{
+			name:   "weekends included",
+			from:   at(25, 0, 0),
+			to:     at(26, 0, 0),
+			hours:  Hours{Start: 10 * 60, End: 14 * 60, Weekends: true},
+			length: time.Hour,
+			want:   []Slot{{at(25, 10, 0), at(25, 14, 0)}},
+		},
+		{
+			// Clocks go back on 26 October in Madrid: working hours stay 9 to 18 local
+			name:   "across a DST change",
+			from:   at(24, 17, 0),
+			to:     at(28, 0, 0),
+			hours:  workday,
+			length: time.Hour,
+			want:   []Slot{{at(24, 17, 0), at(24, 18, 0)}, {at(27, 9, 0), at(27, 18, 0)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FreeTime(tt.events, tt.from, tt.to, madrid, tt.hours, tt.length)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d slots %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("slot %d = %s–%s, want %s–%s", i, got[i].Start, got[i].End, tt.want[i].Start, tt.want[i].End)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package calendar converts personal calendar events to and from iCalendar
+// (RFC 5545) and finds free time between them.
+package calendar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+const (
+	// MaxImportEvents bounds how many events one import may contain
+	MaxImportEvents = 1000
+
+	// ProductID identifies the exporter in .ics files
+	ProductID = "-//personal-assistant-API//Calendar//EN"
+)
+
+// Parse reads the events of an iCalendar file. Times without a zone are taken
+// to be in loc. Cancelled events are skipped, and recurring events are read as
+// their first occurrence. The events have no ID or user.
+func Parse(r io.Reader, loc *time.Location) ([]*model.Event, error) {
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	vevents := cal.Events()
+	if len(vevents) > MaxImportEvents {
+		return nil, fmt.Errorf("calendar has %d events, the limit is %d", len(vevents), MaxImportEvents)
+	}
+
+	var events []*model.Event
+	for i, v := range vevents {
+		if status := text(v, ics.ComponentPropertyStatus); strings.EqualFold(status, "CANCELLED") {
+			continue
+		}
+
+		e, err := parseEvent(v, loc)
+		if err != nil {
+			return nil, fmt.Errorf("event %d (%q): %w", i+1, text(v, ics.ComponentPropertySummary), err)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+func parseEvent(v *ics.VEvent, loc *time.Location) (*model.Event, error) {
+	start, allDay, zone, err := parseTime(v.GetProperty(ics.ComponentPropertyDtStart), loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+
+	var end time.Time
+	switch {
+	case v.GetProperty(ics.ComponentPropertyDtEnd) != nil:
+		if end, _, _, err = parseTime(v.GetProperty(ics.ComponentPropertyDtEnd), loc); err != nil {
+			return nil, fmt.Errorf("invalid DTEND: %w", err)
+		}
+	case v.GetProperty(ics.ComponentPropertyDuration) != nil:
+		d, err := parseDuration(v.GetProperty(ics.ComponentPropertyDuration).Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DURATION: %w", err)
+		}
+		end = start.Add(d)
+	case allDay:
+		// RFC 5545: an all-day event without an end takes up its day
+		end = start.AddDate(0, 0, 1)
+	default:
+		end = start
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("ends before it starts")
+	}
+
+	title := text(v, ics.ComponentPropertySummary)
+	if title == "" {
+		title = "(no title)"
+	}
+
+	uid := v.Id()
+	if uid == "" {
+		// Without a UID, re-importing the same file should still not duplicate events
+		sum := sha256.Sum256([]byte(title + "\x00" + start.UTC().Format(time.RFC3339)))
+		uid = hex.EncodeToString(sum[:16]) + "@import"
+	}
+
+	return &model.Event{
+		UID:         uid,
+		Title:       title,
+		Description: text(v, ics.ComponentPropertyDescription),
+		Location:    text(v, ics.ComponentPropertyLocation),
+		Start:       start,
+		End:         end,
+		AllDay:      allDay,
+		TimeZone:    zone,
+	}, nil
+}
+
+// parseTime reads a DATE or DATE-TIME property. All-day dates become midnight UTC;
+// times with an unknown TZID, such as Windows zone names, are taken to be in loc.
+func parseTime(p *ics.IANAProperty, loc *time.Location) (time.Time, bool, string, error) {
+	if p == nil {
+		return time.Time{}, false, "", fmt.Errorf("missing")
+	}
+	value := strings.TrimSpace(p.Value)
+
+	vt := p.ICalParameters[string(ics.ParameterValue)]
+	if len(value) == 8 || len(vt) == 1 && vt[0] == "DATE" {
+		t, err := time.ParseInLocation("20060102", value, time.UTC)
+		return t, true, loc.String(), err
+	}
+
+	if utc, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.ParseInLocation("20060102T150405", utc, time.UTC)
+		return t, false, loc.String(), err
+	}
+
+	zone := loc
+	if tzid, ok := p.ICalParameters["TZID"]; ok && len(tzid) == 1 {
+		if l, err := time.LoadLocation(strings.Trim(tzid[0], `"`)); err == nil {
+			zone = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, zone)
+	return t, false, zone.String(), err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration parses an RFC 5545 duration such as "PT1H30M" or "P1D"
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("unsupported duration '%s'", value)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("unsupported duration '%s'", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func text(v *ics.VEvent, p ics.ComponentProperty) string {
+	if prop := v.GetProperty(p); prop != nil {
+		return strings.TrimSpace(prop.Value)
+	}
+	return ""
+}
+
+// Export renders events as an iCalendar feed named name
+func Export(events []*model.Event, name string) string {
+	cal := ics.NewCalendar()
+	cal.SetMethod(ics.MethodPublish)
+	cal.SetProductId(ProductID)
+	cal.SetXWRCalName(name)
+
+	for _, e := range events {
+		v := cal.AddEvent(e.UID)
+		v.SetDtStampTime(e.UpdatedAt)
+		v.SetCreatedTime(e.CreatedAt)
+		v.SetSummary(e.Title)
+		if e.Description != "" {
+			v.SetDescription(e.Description)
+		}
+		if e.Location != "" {
+			v.SetLocation(e.Location)
+		}
+
+		if e.AllDay {
+			v.SetAllDayStartAt(e.Start.UTC())
+			v.SetAllDayEndAt(e.End.UTC())
+		} else {
+			v.SetStartAt(e.Start)
+			v.SetEndAt(e.End)
+		}
+	}
+
+	return cal.Serialize()
+}
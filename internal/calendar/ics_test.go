@@ -0,0 +1,251 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// booking is shaped like an airline confirmation: UTC flight times, a hotel
+// stay as all-day dates, a Windows zone name and a cancelled segment
+const booking = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Airline//Booking//EN
+METHOD:PUBLISH
+BEGIN:VEVENT
+UID:IB3166-20250902@iberia.example
+DTSTAMP:20250820T101500Z
+DTSTART:20250902T071500Z
+DTEND:20250902T193000Z
+SUMMARY:Flight IB3166 Madrid → Tokyo
+LOCATION:Madrid-Barajas (MAD)\, Terminal 4S
+DESCRIPTION:Booking reference XK7P2Q\nSeat 34A
+END:VEVENT
+BEGIN:VEVENT
+UID:hotel-8831@hotels.example
+DTSTAMP:20250820T101500Z
+DTSTART;VALUE=DATE:20250903
+DTEND;VALUE=DATE:20250907
+SUMMARY:Hotel Gracery Shinjuku
+END:VEVENT
+BEGIN:VEVENT
+DTSTAMP:20250820T101500Z
+DTSTART;TZID=Asia/Tokyo:20250904T100000
+DURATION:PT2H30M
+SUMMARY:TeamLab Planets
+END:VEVENT
+BEGIN:VEVENT
+UID:meeting-1@outlook.example
+DTSTAMP:20250820T101500Z
+DTSTART;TZID=Romance Standard Time:20250901T090000
+DTEND;TZID=Romance Standard Time:20250901T093000
+SUMMARY:Standup
+END:VEVENT
+BEGIN:VEVENT
+UID:IB3167-20250907@iberia.example
+DTSTAMP:20250820T101500Z
+DTSTART:20250907T013000Z
+DTEND:20250907T163000Z
+SUMMARY:Flight IB3167 Tokyo → Madrid
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParse(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	events, err := Parse(strings.NewReader(booking), madrid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events without the cancelled one, got %d", len(events))
+	}
+
+	tests := []struct {
+		name   string
+		got    *model.Event
+		title  string
+		start  time.Time
+		end    time.Time
+		allDay bool
+		zone   string
+	}{
+		{
+			name:  "UTC times are shown in the import zone",
+			got:   events[0],
+			title: "Flight IB3166 Madrid → Tokyo",
+			start: time.Date(2025, 9, 2, 7, 15, 0, 0, time.UTC),
+			end:   time.Date(2025, 9, 2, 19, 30, 0, 0, time.UTC),
+			zone:  "Europe/Madrid",
+		},
+		{
+			name:   "all-day dates",
+			got:    events[1],
+			title:  "Hotel Gracery Shinjuku",
+			start:  time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC),
+			allDay: true,
+			zone:   "Europe/Madrid",
+		},
+		{
+			name:  "TZID and duration",
+			got:   events[2],
+			title: "TeamLab Planets",
+			start: time.Date(2025, 9, 4, 10, 0, 0, 0, tokyo),
+			end:   time.Date(2025, 9, 4, 12, 30, 0, 0, tokyo),
+			zone:  "Asia/Tokyo",
+		},
+		{
+			name:  "unknown TZID falls back to the import zone",
+			got:   events[3],
+			title: "Standup",
+			start: time.Date(2025, 9, 1, 9, 0, 0, 0, madrid),
+			end:   time.Date(2025, 9, 1, 9, 30, 0, 0, madrid),
+			zone:  "Europe/Madrid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.got
+			if e.Title != tt.title || !e.Start.Equal(tt.start) || !e.End.Equal(tt.end) || e.AllDay != tt.allDay || e.TimeZone != tt.zone {
+				t.Errorf("got %q %s–%s all-day %v in %s, want %q %s–%s all-day %v in %s",
+					e.Title, e.Start, e.End, e.AllDay, e.TimeZone, tt.title, tt.start, tt.end, tt.allDay, tt.zone)
+			}
+		})
+	}
+
+	if e := events[0]; e.UID != "IB3166-20250902@iberia.example" || e.Location != "Madrid-Barajas (MAD), Terminal 4S" || e.Description != "Booking reference XK7P2Q\nSeat 34A" {
+		t.Errorf("unexpected flight details: uid %q, location %q, description %q", e.UID, e.Location, e.Description)
+	}
+
+	// Events without a UID get a stable one, so importing twice updates them
+	again, _ := Parse(strings.NewReader(booking), madrid)
+	if uid := events[2].UID; !strings.HasSuffix(uid, "@import") || again[2].UID != uid {
+		t.Errorf("expected a stable generated UID, got %q and %q", uid, again[2].UID)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	event := func(lines string) string {
+		return "BEGIN:VCALENDAR\nVERSION:2.0\nBEGIN:VEVENT\nUID:x\n" + lines + "\nEND:VEVENT\nEND:VCALENDAR\n"
+	}
+
+	tests := []struct {
+		name    string
+		ics     string
+		wantErr string
+	}{
+		{name: "not a calendar", ics: "hello", wantErr: "failed to parse calendar"},
+		{name: "no start", ics: event("SUMMARY:Lunch"), wantErr: `event 1 ("Lunch"): invalid DTSTART`},
+		{name: "bad start", ics: event("DTSTART:tomorrow"), wantErr: "invalid DTSTART"},
+		{name: "bad duration", ics: event("DTSTART:20250902T071500Z\nDURATION:1 hour"), wantErr: "invalid DURATION"},
+		{name: "ends before start", ics: event("DTSTART:20250902T071500Z\nDTEND:20250902T061500Z"), wantErr: "ends before it starts"},
+		{name: "too many events", ics: "BEGIN:VCALENDAR\n" + strings.Repeat("BEGIN:VEVENT\nDTSTART:20250902\nEND:VEVENT\n", MaxImportEvents+1) + "END:VCALENDAR\n", wantErr: "the limit is 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.ics), time.UTC)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "PT1H30M", want: 90 * time.Minute},
+		{in: "P1D", want: 24 * time.Hour},
+		{in: "P1W", want: 7 * 24 * time.Hour},
+		{in: "P1DT12H", want: 36 * time.Hour},
+		{in: "PT45S", want: 45 * time.Second},
+		{in: "-PT15M", want: -15 * time.Minute},
+		{in: "P", wantErr: true},
+		{in: "PT", wantErr: true},
+		{in: "1H", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDuration(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	created := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+
+	events := []*model.Event{
+		{
+			ID:          primitive.NewObjectID(),
+			UID:         "dentist@personal-assistant",
+			Title:       "Dentist; bring X-rays, forms",
+			Description: "Dr. Puig\nFloor 2",
+			Location:    "Carrer de Mallorca 120, Barcelona",
+			Start:       time.Date(2025, 9, 10, 9, 30, 0, 0, madrid),
+			End:         time.Date(2025, 9, 10, 10, 15, 0, 0, madrid),
+			TimeZone:    "Europe/Madrid",
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		},
+		{
+			ID:        primitive.NewObjectID(),
+			UID:       "holiday@personal-assistant",
+			Title:     "Holiday",
+			Start:     time.Date(2025, 9, 11, 0, 0, 0, 0, time.UTC),
+			End:       time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC),
+			AllDay:    true,
+			TimeZone:  "Europe/Madrid",
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+
+	out := Export(events, "Ana's calendar")
+
+	for _, want := range []string{
+		"PRODID:" + ProductID,
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Ana's calendar",
+		"UID:dentist@personal-assistant",
+		"DTSTART:20250910T073000Z",
+		"DTEND:20250910T081500Z",
+		`SUMMARY:Dentist\; bring X-rays\, forms`,
+		"DTSTART;VALUE=DATE:20250911",
+		"DTEND;VALUE=DATE:20250913",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected export to contain '%s', got:\n%s", want, out)
+		}
+	}
+
+	// What is exported imports back unchanged
+	back, err := Parse(strings.NewReader(out), madrid)
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if len(back) != len(events) {
+		t.Fatalf("expected %d events back, got %d", len(events), len(back))
+	}
+	for i, e := range events {
+		b := back[i]
+		if b.UID != e.UID || b.Title != e.Title || b.Description != e.Description || b.Location != e.Location ||
+			!b.Start.Equal(e.Start) || !b.End.Equal(e.End) || b.AllDay != e.AllDay || b.TimeZone != e.TimeZone {
+			t.Errorf("event %d changed in the round trip:\n got %+v\nwant %+v", i, b, e)
+		}
+	}
+}
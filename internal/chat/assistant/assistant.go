@@ -1,33 +1,165 @@
 package assistant
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/currency"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/guard"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/recall"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/weather"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
 	"github.com/openai/openai-go/v2"
 )
 
 type Assistant struct {
 	cli           openai.Client
-	weatherClient *weather.Client
+	weatherClient weather.Provider
+	calendars     *holidays.Calendars
+	places        *geo.Gazetteer
+	rates         currency.RateProvider
+	notes         tools.NoteStore
+	todos         tools.TodoStore
+	reminders     tools.ReminderStore
+	calendar      tools.CalendarStore
+	memories      MemoryStore
+	index         *recall.Index
+	library       *attachments.Library
+	personas      map[string]*model.Persona
+	personaStore  PersonaStore
+	toolPolicy    model.ToolPolicy
+	policyStore   ToolPolicyStore
+	retryPolicy   RetryPolicy
+	fallbacks     []Fallback
+	cache         *cache.Cache
+	guard         *guard.Guard
+	redactor      *pii.Redactor
 	tools         []tools.Tool
 }
 
 // Option configures an Assistant
 type Option func(*Assistant)
 
-// WithWeatherClient sets a custom weather client
-func WithWeatherClient(client *weather.Client) Option {
+// WithWeatherClient sets a custom weather provider
+func WithWeatherClient(client weather.Provider) Option {
 	return func(a *Assistant) {
 		a.weatherClient = client
 	}
 }
 
+// WithHolidayCalendars sets custom holiday calendars
+func WithHolidayCalendars(calendars *holidays.Calendars) Option {
+	return func(a *Assistant) {
+		a.calendars = calendars
+	}
+}
+
+// WithGazetteer sets a custom gazetteer for place name lookups
+func WithGazetteer(places *geo.Gazetteer) Option {
+	return func(a *Assistant) {
+		a.places = places
+	}
+}
+
+// WithRateProvider sets a custom exchange rate provider
+func WithRateProvider(rates currency.RateProvider) Option {
+	return func(a *Assistant) {
+		a.rates = rates
+	}
+}
+
+// WithNoteStore enables the notes tool, storing notes in the given store
+func WithNoteStore(notes tools.NoteStore) Option {
+	return func(a *Assistant) {
+		a.notes = notes
+	}
+}
+
+// WithTodoStore enables the todos tool, storing to-do items in the given store
+func WithTodoStore(todos tools.TodoStore) Option {
+	return func(a *Assistant) {
+		a.todos = todos
+	}
+}
+
+// WithReminderStore enables the create_reminder tool, storing reminders in the given store
+func WithReminderStore(reminders tools.ReminderStore) Option {
+	return func(a *Assistant) {
+		a.reminders = reminders
+	}
+}
+
+// WithCalendarStore enables the calendar tools, storing events in the given store
+func WithCalendarStore(calendar tools.CalendarStore) Option {
+	return func(a *Assistant) {
+		a.calendar = calendar
+	}
+}
+
+// WithMemoryStore enables long-term memory: facts about the user are extracted
+// by Remember and recalled in the system prompt of every reply
+func WithMemoryStore(memories MemoryStore) Option {
+	return func(a *Assistant) {
+		a.memories = memories
+	}
+}
+
+// WithConversationIndex enables search over past conversations: conversations
+// are added to the index by IndexConversation and searched by the
+// search_past_conversations tool
+func WithConversationIndex(index *recall.Index) Option {
+	return func(a *Assistant) {
+		a.index = index
+	}
+}
+
+// WithAttachmentLibrary enables attachments: files are indexed by AddAttachment
+// and the search_attachments tool searches those sent in the conversation
+func WithAttachmentLibrary(library *attachments.Library) Option {
+	return func(a *Assistant) {
+		a.library = library
+	}
+}
+
+// WithCache reuses language model completions and results of pure tools from
+// the cache, see cache.NewFromEnv. Nothing is cached without it.
+func WithCache(c *cache.Cache) Option {
+	return func(a *Assistant) {
+		a.cache = c
+	}
+}
+
+// WithGuard screens user messages and replies with the checks of g, and
+// quarantines instruction-like text in tool results, see guard.NewFromEnv
+func WithGuard(g *guard.Guard) Option {
+	return func(a *Assistant) {
+		a.guard = g
+	}
+}
+
+// WithRedactor replaces the personal data r detects with placeholders in
+// everything sent to the language model, and restores it in the answers, see
+// pii.RedactorFromEnv
+func WithRedactor(r *pii.Redactor) Option {
+	return func(a *Assistant) {
+		a.redactor = r
+	}
+}
+
 // WithOpenAIClient sets a custom OpenAI client
 func WithOpenAIClient(client openai.Client) Option {
 	return func(a *Assistant) {
@@ -39,7 +171,16 @@ func WithOpenAIClient(client openai.Client) Option {
 func New(opts ...Option) *Assistant {
 	a := &Assistant{
 		cli:           openai.NewClient(),
-		weatherClient: weather.NewClient(),
+		weatherClient: weather.NewProviderFromEnv(),
+		calendars:     holidays.NewCalendarsFromEnv(),
+		places:        geo.Default(),
+		rates:         currency.NewProviderFromEnv(),
+		personas:      map[string]*model.Persona{},
+		retryPolicy:   defaultRetryPolicy,
+	}
+
+	for _, p := range builtinPersonas {
+		a.personas[p.Name] = p
 	}
 
 	// Apply options
@@ -49,30 +190,89 @@ func New(opts ...Option) *Assistant {
 
 	// Initialize tools with dependencies
 	a.tools = []tools.Tool{
-		tools.NewWeatherTool(a.weatherClient),
+		tools.NewWeatherTool(a.weatherClient, a.places),
 		tools.NewDateTool(),
-		tools.NewHolidaysTool(),
-		tools.NewTimeZoneTool(),
+		tools.NewHolidaysTool(a.calendars),
+		tools.NewBusinessDaysTool(a.calendars),
+		tools.NewTimeZoneTool(a.places),
+		tools.NewLocationTool(a.places),
+		tools.NewMeetingSlotsTool(a.calendars),
+		tools.NewConvertUnitsTool(),
+		tools.NewCurrencyTool(a.rates),
+		tools.NewCalculateTool(),
+	}
+
+	// Personal data tools need storage, which callers without a database don't provide
+	if a.notes != nil {
+		a.tools = append(a.tools, tools.NewNotesTool(a.notes))
 	}
+	if a.todos != nil {
+		a.tools = append(a.tools, tools.NewTodosTool(a.todos))
+	}
+	if a.reminders != nil {
+		a.tools = append(a.tools, tools.NewRemindersTool(a.reminders, a.places))
+	}
+	if a.calendar != nil {
+		a.tools = append(a.tools,
+			tools.NewCreateEventTool(a.calendar, a.places),
+			tools.NewListEventsTool(a.calendar, a.places),
+			tools.NewFindFreeTimeTool(a.calendar, a.places),
+		)
+	}
+	if a.index != nil {
+		a.tools = append(a.tools, tools.NewSearchConversationsTool(a.index))
+	}
+	if a.library != nil {
+		a.tools = append(a.tools, tools.NewSearchAttachmentsTool(a.library))
+	}
+
+	// Results of tools that only depend on their arguments are reused
+	a.tools = tools.Cached(a.tools, a.cache)
+
+	a.checkPersonaTools()
 
 	return a
 }
 
+// Tools returns the tools registered with the assistant
+func (a *Assistant) Tools() []tools.Tool {
+	return a.tools
+}
+
+// IndexConversation makes the messages of conv searchable from the user's other
+// conversations. It does nothing without a conversation index.
+func (a *Assistant) IndexConversation(ctx context.Context, conv *model.Conversation) error {
+	if a.index == nil {
+		return nil
+	}
+	return a.index.Add(ctx, auth.FromContext(ctx), conv)
+}
+
+// AddAttachment extracts and indexes the text of an uploaded file and stores it
+func (a *Assistant) AddAttachment(ctx context.Context, att *model.Attachment) error {
+	if a.library == nil {
+		return attachments.ErrDisabled
+	}
+	return a.library.Add(ctx, att)
+}
+
 func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string, error) {
 	if len(conv.Messages) == 0 {
 		return "An empty conversation", nil
 	}
 
 	slog.InfoContext(ctx, "Generating title for conversation", "conversation_id", conv.ID)
+	vault := a.redactor.NewVault()
+
 	// Build messages array: system instruction first, then user messages
 	msgs := []openai.ChatCompletionMessageParamUnion{
-		openai.SystemMessage("You are a title generator. Extract the main topic from the user's message and create a short, descriptive title. Do NOT answer the question. Examples: 'What is the weather like in Barcelona?' → 'Weather in Barcelona'. Maximum 80 characters, no quotes."),
+		openai.SystemMessage(titleInstructions(a.personaFor(ctx, conv))),
 	}
 	for _, m := range conv.Messages {
-		msgs = append(msgs, openai.UserMessage(m.Content))
+		msgs = append(msgs, openai.UserMessage(vault.Redact(m.Content)))
 	}
 
-	resp, err := a.cli.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+	resp, err := a.complete(ctx, openai.ChatCompletionNewParams{
 		Model:    openai.ChatModelGPT4o,
 		Messages: msgs,
 	})
@@ -85,7 +285,7 @@ func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string
 		return "", errors.New("empty response from OpenAI for title generation")
 	}
 
-	title := resp.Choices[0].Message.Content
+	title := vault.Restore(resp.Choices[0].Message.Content)
 	title = strings.ReplaceAll(title, "\n", " ")
 	title = strings.Trim(title, " \t\r\n-\"'")
 
@@ -96,54 +296,165 @@ func (a *Assistant) Title(ctx context.Context, conv *model.Conversation) (string
 	return title, nil
 }
 
+// CheckMessage screens a user message before it is stored and replied to,
+// returning a *model.PolicyViolation when a guardrail rejects it
+func (a *Assistant) CheckMessage(ctx context.Context, text string) error {
+	// Moderation runs at the provider, so it gets the message redacted too
+	return a.guard.CheckInput(ctx, a.redactor.NewVault().Redact(text))
+}
+
 func (a *Assistant) Reply(ctx context.Context, conv *model.Conversation) (string, error) {
+	reply, _, err := a.StructuredReply(ctx, conv)
+	return reply, err
+}
+
+// StructuredReply replies to the conversation like Reply. When the last message
+// asks for a response schema, it also returns the answer as a JSON document
+// matching it, asking the model once more if the first answer doesn't.
+func (a *Assistant) StructuredReply(ctx context.Context, conv *model.Conversation) (string, string, error) {
 	if len(conv.Messages) == 0 {
-		return "", errors.New("conversation has no messages")
+		return "", "", errors.New("conversation has no messages")
 	}
 
 	slog.InfoContext(ctx, "Generating reply for conversation", "conversation_id", conv.ID)
 
+	// Tools render measurements in the conversation's units
+	ctx = units.WithContext(ctx, conv.Units)
+
+	// Reminders set while replying are delivered back into this conversation
+	ctx = model.WithConversationID(ctx, conv.ID)
+
+	persona := a.personaFor(ctx, conv)
+	policies, err := a.toolPolicies(ctx, conv, persona)
+	if err != nil {
+		return "", "", err
+	}
+	allowed := tools.Allowed(a.tools, policies...)
+
+	format, err := responseSchema(conv)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Personal data goes to the model as placeholders, restored in the reply
+	// and in the arguments of tool calls
+	vault := a.redactor.NewVault()
+
 	msgs := []openai.ChatCompletionMessageParamUnion{
-		openai.SystemMessage("You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses."),
+		openai.SystemMessage(systemPrompt(persona) + " " + conv.Units.Describe() + vault.Redact(a.recall(ctx, conv))),
 	}
 
 	for _, m := range conv.Messages {
 		switch m.Role {
 		case model.RoleUser:
-			msgs = append(msgs, openai.UserMessage(m.Content))
+			msgs = append(msgs, openai.UserMessage(vault.Redact(m.Content+describeAttachments(m.Attachments))))
 		case model.RoleAssistant:
-			msgs = append(msgs, openai.AssistantMessage(m.Content))
+			msgs = append(msgs, openai.AssistantMessage(vault.Redact(m.Content)))
 		}
 	}
 
-	for i := 0; i < 15; i++ {
-		resp, err := a.cli.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-			Model:    openai.ChatModelGPT4_1,
+	// A reply that waited for the user's confirmation goes on from its tool calls
+	var steps [][]*model.ToolCall
+	if conv.Pending != nil {
+		resumed, err := a.resume(ctx, allowed, conv.Pending, vault)
+		if err != nil {
+			return "", "", err
+		}
+		msgs = append(msgs, resumed...)
+		steps = conv.Pending.Steps
+	}
+
+	retried := false
+	for i := len(steps); i < 15; i++ {
+		params := openai.ChatCompletionNewParams{
+			Model:    cmp.Or(persona.Model, replyModel),
 			Messages: msgs,
-			Tools:    tools.Definitions(a.tools),
-		})
+			Tools:    tools.Definitions(a.tools, policies...),
+		}
+		if persona.Temperature != nil {
+			params.Temperature = openai.Float(*persona.Temperature)
+		}
+		if format != nil {
+			params.ResponseFormat = responseFormat(format)
+		}
+
+		resp, err := a.complete(ctx, params)
 
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 
 		if len(resp.Choices) == 0 {
-			return "", errors.New("no choices returned by OpenAI")
+			return "", "", errors.New("no choices returned by OpenAI")
 		}
 
 		if message := resp.Choices[0].Message; len(message.ToolCalls) > 0 {
 			msgs = append(msgs, message.ToParam())
 
+			step := make([]*model.ToolCall, 0, len(message.ToolCalls))
 			for _, call := range message.ToolCalls {
-				slog.InfoContext(ctx, "Tool call received", "name", call.Function.Name, "args", call.Function.Arguments)
-				msgs = append(msgs, tools.Dispatch(ctx, a.tools, call))
+				step = append(step, a.toolCall(ctx, allowed, policies, call, vault))
+			}
+			steps = append(steps, step)
+
+			// Stop until the user approves or declines the calls that need it
+			if slices.ContainsFunc(step, func(c *model.ToolCall) bool { return c.Status == model.ToolCallPending }) {
+				conv.Pending = &model.PendingReply{Steps: steps, CreatedAt: time.Now()}
+				return "", "", model.ErrConfirmationRequired
+			}
+
+			for _, c := range step {
+				msgs = append(msgs, openai.ToolMessage(vault.Redact(c.Result), c.ID))
+			}
+
+			continue
+		}
+
+		content := resp.Choices[0].Message.Content
+		if format == nil {
+			if err := a.guard.CheckOutput(ctx, content); err != nil {
+				return "", "", err
 			}
+			conv.Pending = nil
+			return vault.Restore(content), "", nil
+		}
 
+		reply, structured, err := parseStructured(format, vault.Restore(content))
+		if err != nil {
+			if retried {
+				return "", "", fmt.Errorf("the reply doesn't match the response schema: %w", err)
+			}
+			slog.WarnContext(ctx, "Structured reply doesn't match the response schema, retrying", "error", err)
+			retried = true
+			msgs = append(msgs, openai.AssistantMessage(content), openai.UserMessage(vault.Redact(fmt.Sprintf(structuredRetryPrompt, err))))
 			continue
 		}
 
-		return resp.Choices[0].Message.Content, nil
+		if err := a.guard.CheckOutput(ctx, vault.Redact(reply+"\n"+structured)); err != nil {
+			return "", "", err
+		}
+		conv.Pending = nil
+		return reply, structured, nil
 	}
 
-	return "", errors.New("too many tool calls, unable to generate reply")
+	return "", "", errors.New("too many tool calls, unable to generate reply")
+}
+
+// describeAttachments tells the model which files came with a message, so it
+// knows to search them
+func describeAttachments(refs []*model.AttachmentRef) string {
+	if len(refs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n[Attached files, searchable with search_attachments:")
+	for _, r := range refs {
+		fmt.Fprintf(&b, " %s", r.Filename)
+		if r.Pages > 0 {
+			fmt.Fprintf(&b, " (%d pages)", r.Pages)
+		}
+		b.WriteString(";")
+	}
+	return strings.TrimSuffix(b.String(), ";") + "]"
 }
@@ -246,3 +246,18 @@ func TestTitleGenerationPrompt(t *testing.T) {
 		t.Logf("Prompt structure validated: %q", prompt)
 	})
 }
+
+func TestDescribeAttachments(t *testing.T) {
+	if got := describeAttachments(nil); got != "" {
+		t.Errorf("expected nothing for a message without files, got %q", got)
+	}
+
+	got := describeAttachments([]*model.AttachmentRef{
+		{Filename: "itinerary.pdf", Pages: 3},
+		{Filename: "notes.md"},
+	})
+	want := "\n\n[Attached files, searchable with search_attachments: itinerary.pdf (3 pages); notes.md]"
+	if got != want {
+		t.Errorf("describeAttachments() = %q, want %q", got, want)
+	}
+}
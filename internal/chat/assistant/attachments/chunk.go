@@ -0,0 +1,128 @@
+package attachments
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// chunkSize is the target length of a chunk, in characters
+	chunkSize = 1200
+
+	// chunkOverlap is how much text consecutive chunks share, in characters,
+	// so a passage cut at a chunk boundary is still found whole
+	chunkOverlap = 200
+)
+
+// Chunk is a passage of a file's text
+type Chunk struct {
+	// Page is the page the chunk starts on, 0 for files without pages
+	Page int
+	Text string
+}
+
+// Split cuts sections into chunks of about chunkSize characters on line
+// boundaries, each starting with the last lines of the one before. Chunks
+// don't span pages, so each can be cited by its page.
+func Split(sections []Section) []Chunk {
+	var chunks []Chunk
+	for _, s := range sections {
+		for _, text := range splitText(s.Text, chunkSize, chunkOverlap) {
+			chunks = append(chunks, Chunk{Page: s.Page, Text: text})
+		}
+	}
+	return chunks
+}
+
+// splitText cuts text into pieces of at most size characters, repeating up to
+// overlap characters of whole lines at the start of each piece
+func splitText(text string, size, overlap int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			// Keep one blank line between paragraphs
+			if n := len(lines); n > 0 && lines[n-1] != "" {
+				lines = append(lines, "")
+			}
+			continue
+		}
+		lines = append(lines, wrap(line, size)...)
+	}
+
+	var (
+		pieces  []string
+		current []string
+		length  int
+		fresh   bool // current has lines not in the previous piece
+	)
+	emit := func() {
+		if fresh {
+			pieces = append(pieces, strings.TrimSpace(strings.Join(current, "\n")))
+		}
+
+		// Start the next piece with the tail of this one
+		var tail []string
+		kept := 0
+		for i := len(current) - 1; i >= 0; i-- {
+			n := utf8.RuneCountInString(current[i]) + 1
+			if kept+n > overlap {
+				break
+			}
+			tail = append([]string{current[i]}, tail...)
+			kept += n
+		}
+		current, length, fresh = tail, kept, false
+	}
+
+	for _, line := range lines {
+		n := utf8.RuneCountInString(line) + 1
+		if length+n > size && fresh {
+			emit()
+		}
+		if length+n > size {
+			// The overlap and the line don't fit together
+			current, length = nil, 0
+		}
+		current = append(current, line)
+		length += n
+		fresh = fresh || line != ""
+	}
+	emit()
+
+	return pieces
+}
+
+// wrap cuts a line longer than size characters at spaces, or anywhere
+// when a word is longer than size
+func wrap(line string, size int) []string {
+	if utf8.RuneCountInString(line) <= size {
+		return []string{line}
+	}
+
+	var out []string
+	var b strings.Builder
+	for _, word := range strings.Fields(line) {
+		for utf8.RuneCountInString(word) > size {
+			if b.Len() > 0 {
+				out = append(out, b.String())
+				b.Reset()
+			}
+			r := []rune(word)
+			out = append(out, string(r[:size]))
+			word = string(r[size:])
+		}
+		if b.Len() > 0 && utf8.RuneCountInString(b.String())+1+utf8.RuneCountInString(word) > size {
+			out = append(out, b.String())
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(word)
+	}
+	if b.Len() > 0 {
+		out = append(out, b.String())
+	}
+	return out
+}
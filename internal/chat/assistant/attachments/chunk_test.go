@@ -0,0 +1,71 @@
+package attachments
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplit(t *testing.T) {
+	t.Run("short sections are one chunk each", func(t *testing.T) {
+		chunks := Split([]Section{
+			{Page: 1, Text: "Booking confirmation IB3166\n\n\n\nMadrid to Tokyo"},
+			{Page: 2, Text: "Departure 2 September 2025 09:15"},
+		})
+
+		want := []Chunk{
+			{Page: 1, Text: "Booking confirmation IB3166\n\nMadrid to Tokyo"},
+			{Page: 2, Text: "Departure 2 September 2025 09:15"},
+		}
+		if fmt.Sprint(chunks) != fmt.Sprint(want) {
+			t.Errorf("Split() = %q, want %q", chunks, want)
+		}
+	})
+
+	t.Run("long text overlaps on line boundaries", func(t *testing.T) {
+		var lines []string
+		for i := 0; i < 100; i++ {
+			lines = append(lines, fmt.Sprintf("Day %d: visit temple number %d and have lunch nearby", i, i))
+		}
+
+		chunks := Split([]Section{{Text: strings.Join(lines, "\n")}})
+		if len(chunks) < 5 {
+			t.Fatalf("expected several chunks, got %d", len(chunks))
+		}
+
+		for i, c := range chunks {
+			if n := utf8.RuneCountInString(c.Text); n > chunkSize {
+				t.Errorf("chunk %d has %d characters, max %d", i, n, chunkSize)
+			}
+			if i == 0 {
+				continue
+			}
+			// Each chunk starts with the last lines of the one before
+			first, _, _ := strings.Cut(c.Text, "\n")
+			if !strings.HasSuffix(chunks[i-1].Text, "\n"+first) && !strings.Contains(chunks[i-1].Text, "\n"+first+"\n") {
+				t.Errorf("chunk %d doesn't overlap with the one before: %q", i, c.Text[:40])
+			}
+		}
+
+		if first, last := chunks[0].Text, chunks[len(chunks)-1].Text; !strings.HasPrefix(first, "Day 0:") || !strings.HasSuffix(last, "Day 99: visit temple number 99 and have lunch nearby") {
+			t.Errorf("text is lost at the edges: %q ... %q", first[:20], last)
+		}
+	})
+
+	t.Run("long lines are wrapped", func(t *testing.T) {
+		word := strings.Repeat("x", 2*chunkSize+10)
+		chunks := Split([]Section{{Text: "start " + word + " end"}})
+
+		var total int
+		for _, c := range chunks {
+			if n := utf8.RuneCountInString(c.Text); n > chunkSize {
+				t.Errorf("chunk has %d characters, max %d", n, chunkSize)
+			}
+			total += strings.Count(c.Text, "x")
+		}
+		if total < len(word) {
+			t.Errorf("expected the whole word to be kept, got %d of %d characters", total, len(word))
+		}
+	})
+}
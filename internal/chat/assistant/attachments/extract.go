@@ -0,0 +1,178 @@
+// Package attachments turns files users send into searchable passages: text is
+// extracted (plain text, Markdown and PDF), split into overlapping chunks and
+// embedded, so the assistant can find and cite the parts a question is about.
+package attachments
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Supported content types
+const (
+	TypeText     = "text/plain"
+	TypeMarkdown = "text/markdown"
+	TypePDF      = "application/pdf"
+)
+
+var (
+	// ErrUnsupportedType is returned for files whose text can't be extracted
+	ErrUnsupportedType = errors.New("unsupported file type, expected plain text, Markdown or PDF")
+
+	// ErrUnreadable is returned for damaged or encrypted files
+	ErrUnreadable = errors.New("file can't be read")
+
+	// ErrNoText is returned for files without text, such as scanned PDFs
+	ErrNoText = errors.New("file contains no text")
+
+	// ErrDisabled is returned when the assistant has no attachment library
+	ErrDisabled = errors.New("attachments are not enabled")
+)
+
+// Section is the text of one page of a file; Page is 0 for files without pages
+type Section struct {
+	Page int
+	Text string
+}
+
+// ContentType resolves the type of a file from its declared MIME type, or its
+// extension when the declared type is missing or generic
+func ContentType(filename, declared string) (string, error) {
+	if t, _, err := mime.ParseMediaType(declared); err == nil {
+		switch t {
+		case TypeText, TypeMarkdown, TypePDF:
+			return t, nil
+		case "text/x-markdown":
+			return TypeMarkdown, nil
+		case "application/octet-stream":
+			// Unknown to the sender, so look at the name
+		default:
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt", ".text":
+		return TypeText, nil
+	case ".md", ".markdown":
+		return TypeMarkdown, nil
+	case ".pdf":
+		return TypePDF, nil
+	}
+
+	return "", ErrUnsupportedType
+}
+
+// Extract returns the text of a file of a supported type, with the number of
+// pages of a PDF (0 for other files)
+func Extract(contentType string, data []byte) ([]Section, int, error) {
+	switch contentType {
+	case TypeText, TypeMarkdown:
+		if !utf8.Valid(data) {
+			return nil, 0, fmt.Errorf("%w: text is not UTF-8", ErrUnsupportedType)
+		}
+		text := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+		if text == "" {
+			return nil, 0, ErrNoText
+		}
+		return []Section{{Text: text}}, 0, nil
+
+	case TypePDF:
+		return extractPDF(data)
+
+	default:
+		return nil, 0, ErrUnsupportedType
+	}
+}
+
+// extractPDF returns the text of each page of a PDF, rebuilding lines from the
+// positions of the characters
+func extractPDF(data []byte) (sections []Section, pages int, err error) {
+	// The PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			sections, pages, err = nil, 0, fmt.Errorf("%w: invalid PDF: %v", ErrUnreadable, r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid PDF: %v", ErrUnreadable, err)
+	}
+
+	pages = r.NumPage()
+	for i := 1; i <= pages; i++ {
+		p := r.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		if text := pageText(p.Content().Text); text != "" {
+			sections = append(sections, Section{Page: i, Text: text})
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil, pages, ErrNoText
+	}
+
+	return sections, pages, nil
+}
+
+// pageText joins the characters of a page into lines, top to bottom
+func pageText(chars []pdf.Text) string {
+	chars = slices.Clone(chars)
+	slices.SortStableFunc(chars, func(a, b pdf.Text) int { return cmp.Compare(b.Y, a.Y) })
+
+	// Characters whose baselines are close, such as superscripts, share a line
+	var rows [][]pdf.Text
+	for _, c := range chars {
+		if n := len(rows); n > 0 && math.Abs(rows[n-1][0].Y-c.Y) <= lineTolerance(rows[n-1][0], c) {
+			rows[n-1] = append(rows[n-1], c)
+			continue
+		}
+		rows = append(rows, []pdf.Text{c})
+	}
+
+	var lines []string
+	for _, row := range rows {
+		slices.SortStableFunc(row, func(a, b pdf.Text) int { return cmp.Compare(a.X, b.X) })
+
+		var line strings.Builder
+		for i, c := range row {
+			// A visible gap without a space character still separates words
+			if i > 0 && c.X-(row[i-1].X+charWidth(row[i-1])) > 0.2*max(c.FontSize, 1) {
+				line.WriteByte(' ')
+			}
+			line.WriteString(c.S)
+		}
+
+		if s := strings.Join(strings.Fields(line.String()), " "); s != "" {
+			lines = append(lines, s)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// lineTolerance is how far apart baselines of one line can be
+func lineTolerance(a, b pdf.Text) float64 {
+	return 0.5 * max(a.FontSize, b.FontSize, 1)
+}
+
+// charWidth is the width of a character, estimated when the font doesn't say
+func charWidth(c pdf.Text) float64 {
+	if c.W > 0 {
+		return c.W
+	}
+	return 0.5 * c.FontSize
+}
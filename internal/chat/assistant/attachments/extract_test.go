@@ -0,0 +1,144 @@
+package attachments
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// makePDF builds a PDF with one page per entry of pages, each line of text
+// shown below the one before
+func makePDF(pages ...[]string) []byte {
+	var objects []string
+	add := func(obj string) int {
+		objects = append(objects, obj)
+		return len(objects)
+	}
+
+	catalog := add("")
+	tree := add("")
+	font := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+
+	var kids []string
+	for _, lines := range pages {
+		var content strings.Builder
+		content.WriteString("BT /F1 12 Tf 14 TL 72 720 Td\n")
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", line)
+		}
+		content.WriteString("ET")
+
+		stream := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>", tree, font, stream))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", tree)
+	objects[tree-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, xref)
+	return b.Bytes()
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		filename, declared string
+		want               string
+		wantErr            bool
+	}{
+		{"itinerary.pdf", "application/pdf", TypePDF, false},
+		{"notes.md", "text/markdown; charset=utf-8", TypeMarkdown, false},
+		{"notes.md", "", TypeMarkdown, false},
+		{"booking.PDF", "application/octet-stream", TypePDF, false},
+		{"readme", "text/plain", TypeText, false},
+		{"todo.txt", "", TypeText, false},
+		{"photo.jpg", "image/jpeg", "", true},
+		{"archive.zip", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename+" "+tt.declared, func(t *testing.T) {
+			got, err := ContentType(tt.filename, tt.declared)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedType) {
+					t.Errorf("expected ErrUnsupportedType, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ContentType() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		sections, pages, err := Extract(TypeMarkdown, []byte("# Kyoto\r\n\r\nRyokan Yachiyo, check-in 15:00\r\n"))
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if pages != 0 || len(sections) != 1 || sections[0].Text != "# Kyoto\n\nRyokan Yachiyo, check-in 15:00" {
+			t.Errorf("unexpected sections %+v (%d pages)", sections, pages)
+		}
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		data := makePDF(
+			[]string{"Booking confirmation IB3166", "Madrid to Tokyo"},
+			[]string{},
+			[]string{"Departure 2 September 2025 09:15"},
+		)
+
+		sections, pages, err := Extract(TypePDF, data)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if pages != 3 {
+			t.Errorf("expected 3 pages, got %d", pages)
+		}
+		want := []Section{
+			{Page: 1, Text: "Booking confirmation IB3166\nMadrid to Tokyo"},
+			{Page: 3, Text: "Departure 2 September 2025 09:15"},
+		}
+		if fmt.Sprint(sections) != fmt.Sprint(want) {
+			t.Errorf("Extract() = %q, want %q", sections, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			contentType string
+			data        []byte
+			want        error
+		}{
+			{"blank text", TypeText, []byte(" \n\t"), ErrNoText},
+			{"binary text", TypeText, []byte{0xff, 0xfe, 0x00}, ErrUnsupportedType},
+			{"pdf without text", TypePDF, makePDF([]string{}), ErrNoText},
+			{"damaged pdf", TypePDF, []byte("%PDF-1.4\nnot really"), ErrUnreadable},
+			{"other type", "image/png", []byte("png"), ErrUnsupportedType},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, _, err := Extract(tt.contentType, tt.data); !errors.Is(err, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, err)
+				}
+			})
+		}
+	})
+}
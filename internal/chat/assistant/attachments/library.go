@@ -0,0 +1,205 @@
+package attachments
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/recall"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxChunks bounds how many chunks one file is split into, about 1.5 MB of text
+	maxChunks = 1500
+
+	// embedBatch is how many chunks are embedded per request
+	embedBatch = 100
+
+	// DefaultLimit is how many passages Search returns unless asked otherwise
+	DefaultLimit = 4
+
+	// MaxLimit bounds how many passages one search returns
+	MaxLimit = 10
+)
+
+// ErrTooLong is returned for files with more text than is indexed
+var ErrTooLong = errors.New("file has too much text")
+
+// Store persists attachments and the chunks of their text
+type Store interface {
+	CreateAttachment(ctx context.Context, a *model.Attachment, chunks []*model.AttachmentChunk) error
+	ListConversationAttachments(ctx context.Context, userID string, conversationID primitive.ObjectID) ([]*model.Attachment, error)
+	ListAttachmentChunks(ctx context.Context, attachmentIDs []primitive.ObjectID) ([]*model.AttachmentChunk, error)
+}
+
+// Passage is a chunk of an attachment found by a search
+type Passage struct {
+	Attachment *model.Attachment
+	Page       int
+	Text       string
+	Score      float64
+}
+
+// Query selects the attachments a search ranks
+type Query struct {
+	UserID         string
+	ConversationID primitive.ObjectID
+	Text           string
+	Limit          int
+}
+
+// Library indexes attachments and searches those of a conversation
+type Library struct {
+	embedder recall.Embedder
+	store    Store
+	redactor *pii.Redactor
+}
+
+// Option configures a Library
+type Option func(*Library)
+
+// WithRedactor masks the personal data r detects in passages and queries
+// before they are sent to the embedder
+func WithRedactor(r *pii.Redactor) Option {
+	return func(l *Library) {
+		l.redactor = r
+	}
+}
+
+// NewLibrary creates a library over the given store
+func NewLibrary(embedder recall.Embedder, store Store, opts ...Option) *Library {
+	l := &Library{
+		embedder: embedder,
+		store:    store,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Add extracts the text of a new attachment, embeds its chunks and stores
+// them with the file. The content type must be resolved with ContentType;
+// Pages is set from the file.
+func (l *Library) Add(ctx context.Context, a *model.Attachment) error {
+	sections, pages, err := Extract(a.ContentType, a.Data)
+	if err != nil {
+		return err
+	}
+	a.Pages = pages
+
+	split := Split(sections)
+	if len(split) > maxChunks {
+		return fmt.Errorf("%w (%d passages, max %d)", ErrTooLong, len(split), maxChunks)
+	}
+
+	chunks := make([]*model.AttachmentChunk, len(split))
+	for i, c := range split {
+		chunks[i] = &model.AttachmentChunk{
+			ID:           primitive.NewObjectID(),
+			AttachmentID: a.ID,
+			Index:        i,
+			Page:         c.Page,
+			Text:         c.Text,
+		}
+	}
+
+	for start := 0; start < len(chunks); start += embedBatch {
+		batch := chunks[start:min(start+embedBatch, len(chunks))]
+
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = l.redactor.Mask(c.Text)
+		}
+
+		vectors, err := l.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed attachment: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return fmt.Errorf("expected %d embeddings, got %d", len(batch), len(vectors))
+		}
+		for i, c := range batch {
+			c.Embedding = vectors[i]
+		}
+	}
+
+	if err := l.store.CreateAttachment(ctx, a, chunks); err != nil {
+		return fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	return nil
+}
+
+// Attachments returns the files sent in a conversation
+func (l *Library) Attachments(ctx context.Context, userID string, conversationID primitive.ObjectID) ([]*model.Attachment, error) {
+	return l.store.ListConversationAttachments(ctx, userID, conversationID)
+}
+
+// Search returns the passages of the files sent in a conversation closest in
+// meaning to the query, closest first. A conversation has few files, so all
+// their chunks are compared with the query.
+func (l *Library) Search(ctx context.Context, q Query) ([]Passage, error) {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	q.Limit = min(q.Limit, MaxLimit)
+
+	files, err := l.store.ListConversationAttachments(ctx, q.UserID, q.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	byID := map[primitive.ObjectID]*model.Attachment{}
+	ids := make([]primitive.ObjectID, len(files))
+	for i, f := range files {
+		byID[f.ID] = f
+		ids[i] = f.ID
+	}
+
+	chunks, err := l.store.ListAttachmentChunks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachment passages: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	vectors, err := l.embedder.Embed(ctx, []string{l.redactor.Mask(q.Text)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(vectors))
+	}
+
+	passages := make([]Passage, len(chunks))
+	for i, c := range chunks {
+		passages[i] = Passage{
+			Attachment: byID[c.AttachmentID],
+			Page:       c.Page,
+			Text:       c.Text,
+			Score:      recall.Cosine(vectors[0], c.Embedding),
+		}
+	}
+
+	// Stable, so equally close passages keep their order in the files
+	slices.SortStableFunc(passages, func(a, b Passage) int { return cmp.Compare(b.Score, a.Score) })
+
+	return passages[:min(len(passages), q.Limit)], nil
+}
@@ -0,0 +1,184 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keywordEmbedder embeds texts as counts of a few keywords, so texts sharing
+// keywords are close
+type keywordEmbedder struct {
+	calls int
+	texts []string
+	err   error
+}
+
+var keywords = []string{"check-in", "flight", "breakfast", "tokyo", "kyoto"}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	e.calls++
+	e.texts = append(e.texts, texts...)
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float64, len(keywords))
+		for j, k := range keywords {
+			vectors[i][j] = float64(strings.Count(strings.ToLower(text), k))
+		}
+	}
+	return vectors, nil
+}
+
+// memoryStore is an in-memory Store
+type memoryStore struct {
+	attachments []*model.Attachment
+	chunks      []*model.AttachmentChunk
+}
+
+func (s *memoryStore) CreateAttachment(ctx context.Context, a *model.Attachment, chunks []*model.AttachmentChunk) error {
+	s.attachments = append(s.attachments, a)
+	s.chunks = append(s.chunks, chunks...)
+	return nil
+}
+
+func (s *memoryStore) ListConversationAttachments(ctx context.Context, userID string, conversationID primitive.ObjectID) ([]*model.Attachment, error) {
+	var out []*model.Attachment
+	for _, a := range s.attachments {
+		if a.UserID == userID && a.ConversationID == conversationID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStore) ListAttachmentChunks(ctx context.Context, ids []primitive.ObjectID) ([]*model.AttachmentChunk, error) {
+	var out []*model.AttachmentChunk
+	for _, c := range s.chunks {
+		for _, id := range ids {
+			if c.AttachmentID == id {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestLibrary(t *testing.T) {
+	ctx := context.Background()
+	conversation := primitive.NewObjectID()
+	store := &memoryStore{}
+	embedder := &keywordEmbedder{}
+	library := NewLibrary(embedder, store)
+
+	itinerary := &model.Attachment{
+		ID:             primitive.NewObjectID(),
+		UserID:         "ana",
+		Filename:       "itinerary.pdf",
+		ContentType:    TypePDF,
+		ConversationID: conversation,
+		Data: makePDF(
+			[]string{"Flight IB3166 Madrid to Tokyo", "Departs 2 September 09:15"},
+			[]string{"Ryokan Yachiyo, Kyoto", "Check-in from 15:00, breakfast included"},
+		),
+	}
+	if err := library.Add(ctx, itinerary); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if itinerary.Pages != 2 || len(store.chunks) != 2 || store.chunks[1].Page != 2 || store.chunks[1].Embedding == nil {
+		t.Fatalf("unexpected attachment (%d pages) or chunks %+v", itinerary.Pages, store.chunks)
+	}
+
+	// Another user's file in the same conversation isn't searched
+	other := &model.Attachment{
+		ID:             primitive.NewObjectID(),
+		UserID:         "bob",
+		Filename:       "bob.txt",
+		ContentType:    TypeText,
+		ConversationID: conversation,
+		Data:           []byte("Check-in at the Kyoto hotel from 14:00"),
+	}
+	if err := library.Add(ctx, other); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	passages, err := library.Search(ctx, Query{UserID: "ana", ConversationID: conversation, Text: "hotel check-in time in Kyoto", Limit: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(passages) != 1 {
+		t.Fatalf("expected 1 passage, got %d", len(passages))
+	}
+	if p := passages[0]; p.Attachment != itinerary || p.Page != 2 || !strings.Contains(p.Text, "Check-in from 15:00") {
+		t.Errorf("unexpected passage %+v", p)
+	}
+
+	t.Run("conversations without files", func(t *testing.T) {
+		calls := embedder.calls
+		passages, err := library.Search(ctx, Query{UserID: "ana", ConversationID: primitive.NewObjectID(), Text: "check-in"})
+		if err != nil || len(passages) != 0 {
+			t.Errorf("expected no passages, got %v, %v", passages, err)
+		}
+		if embedder.calls != calls {
+			t.Error("expected the query not to be embedded")
+		}
+	})
+
+	t.Run("personal data is masked before embedding", func(t *testing.T) {
+		embedder := &keywordEmbedder{}
+		library := NewLibrary(embedder, &memoryStore{}, WithRedactor(pii.New()))
+		contacts := &model.Attachment{
+			ID:             primitive.NewObjectID(),
+			UserID:         "ana",
+			ContentType:    TypeText,
+			ConversationID: conversation,
+			Data:           []byte("Kyoto check-in questions: ryokan@example.jp"),
+		}
+		if err := library.Add(ctx, contacts); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if _, err := library.Search(ctx, Query{UserID: "ana", ConversationID: conversation, Text: "mail ryokan@example.jp"}); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+
+		for _, text := range embedder.texts {
+			if strings.Contains(text, "ryokan@example.jp") {
+				t.Errorf("expected personal data masked, got %q", text)
+			}
+		}
+	})
+
+	t.Run("files that can't be indexed", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			a        *model.Attachment
+			embedder *keywordEmbedder
+			want     error
+		}{
+			{"no text", &model.Attachment{ContentType: TypeText, Data: []byte("  ")}, &keywordEmbedder{}, ErrNoText},
+			{"too long", &model.Attachment{ContentType: TypeText, Data: []byte(strings.Repeat("Breakfast included. ", 100000))}, &keywordEmbedder{}, ErrTooLong},
+			{"embedding fails", &model.Attachment{ContentType: TypeText, Data: []byte("Flight")}, &keywordEmbedder{err: errors.New("rate limited")}, nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				store := &memoryStore{}
+				err := NewLibrary(tt.embedder, store).Add(ctx, tt.a)
+				if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+					t.Errorf("expected %v, got %v", tt.want, err)
+				}
+				if len(store.attachments) != 0 {
+					t.Error("expected nothing to be stored")
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,219 @@
+// Package cache keeps language model completions and results of pure tools, so
+// identical questions don't call the model or the tool again. Values live in a
+// Store: an in-memory LRU or a MongoDB collection with a TTL index.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultTTL is how long values are kept unless configured
+	DefaultTTL = time.Hour
+
+	// DefaultSize bounds the entries of the in-memory store unless configured
+	DefaultSize = 1000
+
+	// indexTimeout bounds creating the TTL index of the MongoDB store at startup
+	indexTimeout = 10 * time.Second
+)
+
+// Kinds of cached values, recorded on the hit and miss metrics
+const (
+	KindCompletion = "completion"
+	KindTool       = "tool"
+)
+
+// Store keeps values by key until they expire
+type Store interface {
+	// Get returns the value stored under key, reporting false when there is
+	// none or it expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl, replacing any value there
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Cache stores values for a fixed time and counts hits and misses. A nil
+// Cache caches nothing, so callers don't need to check whether caching is on.
+type Cache struct {
+	store  Store
+	ttl    time.Duration
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithTTL sets how long values are kept
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// New creates a cache keeping values in store
+func New(store Store, opts ...Option) *Cache {
+	c := &Cache{store: store, ttl: DefaultTTL}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	meter := otel.Meter("github.com/isabermoussa/personal-assistant-API")
+
+	var err error
+	if c.hits, err = meter.Int64Counter(
+		"assistant.cache.hits",
+		metric.WithDescription("Completions and tool results answered from the cache"),
+		metric.WithUnit("{hit}"),
+	); err != nil {
+		slog.Warn("Failed to create cache hit counter", "error", err)
+	}
+	if c.misses, err = meter.Int64Counter(
+		"assistant.cache.misses",
+		metric.WithDescription("Completions and tool results not found in the cache"),
+		metric.WithUnit("{miss}"),
+	); err != nil {
+		slog.Warn("Failed to create cache miss counter", "error", err)
+	}
+
+	return c
+}
+
+// NewFromEnv creates the cache RESPONSE_CACHE selects: "memory" for an LRU of
+// RESPONSE_CACHE_SIZE entries, "mongo" for the response_cache collection of db.
+// RESPONSE_CACHE_TTL sets how long values are kept, e.g. "30m". Caching is
+// off, and the returned cache nil, when RESPONSE_CACHE isn't set.
+func NewFromEnv(db *mongo.Database) *Cache {
+	ttl := DefaultTTL
+	if v := os.Getenv("RESPONSE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ttl = d
+		} else {
+			slog.Warn("Ignoring invalid RESPONSE_CACHE_TTL", "value", v)
+		}
+	}
+
+	switch backend := os.Getenv("RESPONSE_CACHE"); backend {
+	case "":
+		return nil
+	case "memory":
+		size := DefaultSize
+		if v := os.Getenv("RESPONSE_CACHE_SIZE"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				size = n
+			} else {
+				slog.Warn("Ignoring invalid RESPONSE_CACHE_SIZE", "value", v)
+			}
+		}
+		slog.Info("Caching responses in memory", "size", size, "ttl", ttl)
+		return New(NewLRU(size), WithTTL(ttl))
+	case "mongo":
+		store := NewMongoStore(db)
+
+		ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+		defer cancel()
+		if err := store.CreateIndex(ctx); err != nil {
+			// Expired values are still ignored, only no longer deleted
+			slog.Warn("Failed to create the response cache TTL index", "error", err)
+		}
+
+		slog.Info("Caching responses in MongoDB", "collection", cacheCollection, "ttl", ttl)
+		return New(store, WithTTL(ttl))
+	default:
+		slog.Warn("Unknown response cache, caching is off", "backend", backend)
+		return nil
+	}
+}
+
+// Get returns the value cached under key, recording a hit or a miss of kind.
+// Store failures are logged and count as misses.
+func (c *Cache) Get(ctx context.Context, kind, key string) ([]byte, bool) {
+	if c == nil || Bypassed(ctx) {
+		return nil, false
+	}
+
+	value, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read from the response cache", "kind", kind, "error", err)
+	}
+
+	counter := c.misses
+	if ok {
+		counter = c.hits
+	}
+	if counter != nil {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", kind)))
+	}
+
+	return value, ok
+}
+
+// Set caches value under key. Store failures are logged.
+func (c *Cache) Set(ctx context.Context, kind, key string, value []byte) {
+	if c == nil || Bypassed(ctx) {
+		return
+	}
+
+	if err := c.store.Set(ctx, key, value, c.ttl); err != nil {
+		slog.WarnContext(ctx, "Failed to write to the response cache", "kind", kind, "error", err)
+	}
+}
+
+// Key hashes the JSON encoding of parts into a cache key
+func Key(parts ...any) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, p := range parts {
+		if err := enc.Encode(p); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type bypassKey struct{}
+
+// WithBypass returns a context whose calls neither read nor fill the cache
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Bypassed reports whether ctx skips the cache
+func Bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
+
+// Middleware skips the cache for requests with a "Cache-Control: no-cache" or
+// "no-store" header, e.g. to get a fresh answer
+func Middleware() func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+				switch strings.ToLower(strings.TrimSpace(directive)) {
+				case "no-cache", "no-store":
+					r = r.WithContext(WithBypass(r.Context()))
+				}
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores values by key", func(t *testing.T) {
+		c := New(NewLRU(10))
+		key, err := Key(KindTool, "convert_units", `{"value":1}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := c.Get(ctx, KindTool, key); ok {
+			t.Fatal("expected a miss before the value is set")
+		}
+		c.Set(ctx, KindTool, key, []byte("1 km = 1000 m"))
+		if v, ok := c.Get(ctx, KindTool, key); !ok || string(v) != "1 km = 1000 m" {
+			t.Errorf("expected the stored value, got %q, %v", v, ok)
+		}
+	})
+
+	t.Run("a nil cache caches nothing", func(t *testing.T) {
+		var c *Cache
+		c.Set(ctx, KindCompletion, "k", []byte("v"))
+		if _, ok := c.Get(ctx, KindCompletion, "k"); ok {
+			t.Error("expected a nil cache to miss")
+		}
+	})
+
+	t.Run("bypassed contexts neither read nor fill the cache", func(t *testing.T) {
+		c := New(NewLRU(10))
+		c.Set(ctx, KindCompletion, "k", []byte("v"))
+
+		bypass := WithBypass(ctx)
+		if _, ok := c.Get(bypass, KindCompletion, "k"); ok {
+			t.Error("expected a bypassed read to miss")
+		}
+		c.Set(bypass, KindCompletion, "other", []byte("v"))
+		if _, ok := c.Get(ctx, KindCompletion, "other"); ok {
+			t.Error("expected a bypassed write to be dropped")
+		}
+	})
+}
+
+func TestKey(t *testing.T) {
+	a, _ := Key(KindTool, "convert_units", "x")
+	b, _ := Key(KindTool, "convert_units", "x")
+	c, _ := Key(KindTool, "convert_unitsx", "")
+	if a != b {
+		t.Error("expected equal parts to give equal keys")
+	}
+	if a == c {
+		t.Error("expected parts to be kept apart")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"max-age=0", false},
+		{"no-cache", true},
+		{"max-age=0, No-Store", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var bypassed bool
+			handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bypassed = Bypassed(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/twirp/acai.chat.ChatService/ContinueConversation", nil)
+			if tt.header != "" {
+				req.Header.Set("Cache-Control", tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if bypassed != tt.want {
+				t.Errorf("expected bypass %v, got %v", tt.want, bypassed)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts the least recently used value", func(t *testing.T) {
+		l := NewLRU(2)
+		_ = l.Set(ctx, "a", []byte("1"), time.Hour)
+		_ = l.Set(ctx, "b", []byte("2"), time.Hour)
+
+		// Reading "a" makes "b" the least recently used
+		if _, ok, _ := l.Get(ctx, "a"); !ok {
+			t.Fatal("expected a to be stored")
+		}
+		_ = l.Set(ctx, "c", []byte("3"), time.Hour)
+
+		if _, ok, _ := l.Get(ctx, "b"); ok {
+			t.Error("expected b to be evicted")
+		}
+		for _, key := range []string{"a", "c"} {
+			if _, ok, _ := l.Get(ctx, key); !ok {
+				t.Errorf("expected %s to be kept", key)
+			}
+		}
+		if l.Len() != 2 {
+			t.Errorf("expected 2 values, got %d", l.Len())
+		}
+	})
+
+	t.Run("expires values", func(t *testing.T) {
+		now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+		l := NewLRU(10)
+		l.now = func() time.Time { return now }
+
+		_ = l.Set(ctx, "a", []byte("1"), time.Minute)
+		now = now.Add(59 * time.Second)
+		if _, ok, _ := l.Get(ctx, "a"); !ok {
+			t.Fatal("expected a to be stored before it expires")
+		}
+
+		now = now.Add(time.Second)
+		if _, ok, _ := l.Get(ctx, "a"); ok {
+			t.Error("expected a to expire")
+		}
+		if l.Len() != 0 {
+			t.Errorf("expected the expired value to be removed, got %d values", l.Len())
+		}
+	})
+
+	t.Run("replaces values", func(t *testing.T) {
+		l := NewLRU(10)
+		_ = l.Set(ctx, "a", []byte("1"), time.Hour)
+		_ = l.Set(ctx, "a", []byte("2"), time.Hour)
+
+		if v, _, _ := l.Get(ctx, "a"); string(v) != "2" || l.Len() != 1 {
+			t.Errorf("expected one value 2, got %q of %d", v, l.Len())
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cacheCollection holds one document per cached value
+const cacheCollection = "response_cache"
+
+// MongoStore keeps values in a MongoDB collection, shared by every server
+// instance. MongoDB deletes expired documents through a TTL index on
+// "expires_at", see CreateIndex.
+type MongoStore struct {
+	coll *mongo.Collection
+	now  func() time.Time
+}
+
+type cacheDocument struct {
+	Key       string    `bson:"_id"`
+	Value     []byte    `bson:"value"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// NewMongoStore creates a store using the response_cache collection of db
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{
+		coll: db.Collection(cacheCollection),
+		now:  time.Now,
+	}
+}
+
+// CreateIndex creates the TTL index that deletes expired values
+func (s *MongoStore) CreateIndex(ctx context.Context) error {
+	_, err := s.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Get returns the value stored under key unless it expired. MongoDB removes
+// expired documents only every minute or so, so they are filtered out here.
+func (s *MongoStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var doc cacheDocument
+	err := s.coll.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": s.now()}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return doc.Value, true, nil
+}
+
+// Set stores value under key for ttl
+func (s *MongoStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := s.coll.ReplaceOne(ctx,
+		bson.M{"_id": key},
+		cacheDocument{Key: key, Value: value, ExpiresAt: s.now().Add(ttl)},
+		options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,156 @@
+// Package calc evaluates arithmetic expressions with exact rational numbers.
+// Expressions are parsed by a small recursive descent parser; nothing is ever
+// compiled or executed, and inputs that could grow without bound are rejected.
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Limits on what an expression may cost to evaluate
+const (
+	// MaxInputLength is the longest expression accepted, in bytes
+	MaxInputLength = 2000
+
+	// MaxVariables is the most variables an evaluation may define
+	MaxVariables = 100
+
+	// maxDepth bounds nesting of parentheses, function calls and unary operators
+	maxDepth = 64
+
+	// maxBits bounds the size of any intermediate numerator or denominator
+	maxBits = 4096
+
+	// maxDecimals is how many decimals inexact or long results are rounded to
+	maxDecimals = 12
+)
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Result is the value of the last statement of an expression
+type Result struct {
+	// Value is the exact result, unless Approximate is set
+	Value *big.Rat
+
+	// Approximate is set when an irrational function such as sqrt was rounded
+	Approximate bool
+
+	// Assigned lists the variables assigned by the expression, in order
+	Assigned []Assignment
+}
+
+// Assignment is a variable assigned by a statement such as "total = 12 * 3"
+type Assignment struct {
+	Name  string
+	Value *big.Rat
+}
+
+// Evaluate computes an expression. Statements are separated by ';' or new
+// lines, may assign variables with "name = expr", and the last one is the result.
+// vars seeds variables and is not modified.
+func Evaluate(expr string, vars map[string]*big.Rat) (*Result, error) {
+	if len(expr) > MaxInputLength {
+		return nil, fmt.Errorf("expression is too long (%d bytes, max %d)", len(expr), MaxInputLength)
+	}
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	if len(vars) > MaxVariables {
+		return nil, fmt.Errorf("too many variables (%d, max %d)", len(vars), MaxVariables)
+	}
+
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens: tokens,
+		vars:   make(map[string]*big.Rat, len(vars)),
+	}
+	for name, v := range vars {
+		if !identPattern.MatchString(name) || isReserved(name) {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		if v == nil {
+			return nil, fmt.Errorf("variable %q has no value", name)
+		}
+		if err := checkSize(v); err != nil {
+			return nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+		p.vars[name] = new(big.Rat).Set(v)
+	}
+
+	value, err := p.program()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Value:       value,
+		Approximate: p.approximate,
+		Assigned:    p.assigned,
+	}, nil
+}
+
+// ParseNumber reads a decimal number such as "-12.5" or "1e3" exactly
+func ParseNumber(s string) (*big.Rat, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(s), "-")
+	if n, err := lexNumber(digits); err != nil || n != len(digits) {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	if err := checkSize(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// decimalPlaces returns how many decimals r needs to be printed exactly,
+// or false when its decimal expansion doesn't terminate
+func decimalPlaces(r *big.Rat) (int, bool) {
+	d := new(big.Int).Set(r.Denom())
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+
+	fives := 0
+	five, q, m := big.NewInt(5), new(big.Int), new(big.Int)
+	for d.BitLen() > 1 {
+		q.QuoRem(d, five, m)
+		if m.Sign() != 0 {
+			return 0, false
+		}
+		d.Set(q)
+		fives++
+	}
+	return max(twos, fives), true
+}
+
+// Format prints r as a decimal, exactly when it fits in the given decimals and
+// rounded otherwise; the bool reports whether the output is exact
+func Format(r *big.Rat, decimals int) (string, bool) {
+	if places, ok := decimalPlaces(r); ok && places <= decimals {
+		return r.FloatString(places), true
+	}
+	s := strings.TrimRight(r.FloatString(decimals), "0")
+	return strings.TrimSuffix(s, "."), false
+}
+
+// FormatResult prints a result rounded to a sensible number of decimals;
+// the bool reports whether the output is exact
+func FormatResult(r *big.Rat) (string, bool) {
+	return Format(r, maxDecimals)
+}
+
+func checkSize(r *big.Rat) error {
+	if r.Num().BitLen() > maxBits || r.Denom().BitLen() > maxBits {
+		return fmt.Errorf("number is too large")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package calc
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr        string
+		vars        map[string]*big.Rat
+		want        string
+		approximate bool
+	}{
+		// Exact decimals, no float artefacts
+		{expr: "0.1 + 0.2", want: "0.3"},
+		{expr: "1.10 * 3", want: "3.3"},
+		{expr: "19.99 * 3 - 0.97", want: "59"},
+
+		// Precedence and associativity
+		{expr: "2 + 3 * 4", want: "14"},
+		{expr: "(2 + 3) * 4", want: "20"},
+		{expr: "10 - 4 - 3", want: "3"},
+		{expr: "2 ^ 3 ^ 2", want: "512"},
+		{expr: "-2 ^ 2", want: "-4"},
+		{expr: "2 ^ -2", want: "0.25"},
+		{expr: "--3", want: "3"},
+		{expr: "2×3÷4", want: "1.5"},
+		{expr: "7 mod 3", want: "1"},
+		{expr: "-7 mod 3", want: "2"},
+		{expr: "1.5e3", want: "1500"},
+		{expr: ".5 + 2.", want: "2.5"},
+
+		// Percentages
+		{expr: "15%", want: "0.15"},
+		{expr: "84.50 + 18%", want: "99.71"},
+		{expr: "120 - 25%", want: "90"},
+		{expr: "100 - 10% - 10%", want: "81"},
+		{expr: "18% of 84.50", want: "15.21"},
+		{expr: "200 * 15%", want: "30"},
+		{expr: "80 - (15%)", want: "79.85"},
+
+		// Functions
+		{expr: "round(2.345, 2)", want: "2.35"},
+		{expr: "round(-2.5)", want: "-3"},
+		{expr: "round(1234, -2)", want: "1200"},
+		{expr: "floor(-1.5)", want: "-2"},
+		{expr: "ceil(10.01, 1)", want: "10.1"},
+		{expr: "abs(-3)", want: "3"},
+		{expr: "min(3, 1, 2)", want: "1"},
+		{expr: "max(3, 1, 2)", want: "3"},
+		{expr: "sum(120, 45.5, 80)", want: "245.5"},
+		{expr: "avg(1, 2, 3, 4)", want: "2.5"},
+		{expr: "sqrt(9/4)", want: "1.5"},
+		{expr: "sqrt(2)", want: "1.414213562373", approximate: true},
+
+		// Statements and variables
+		{expr: "total = 84.50 + 18%; total / 4", want: "24.9275"},
+		{expr: "hotel = 3 * 140\nflights = 2 * 389.99\n\nhotel + flights", want: "1199.98"},
+		{expr: "people * share", vars: map[string]*big.Rat{"people": big.NewRat(4, 1), "share": big.NewRat(51, 2)}, want: "102"},
+		{expr: "x = x + 1; x", vars: map[string]*big.Rat{"x": big.NewRat(1, 1)}, want: "2"},
+
+		// Repeating decimals are rounded for display
+		{expr: "100 / 3", want: "33.333333333333"},
+		{expr: "2 / 3", want: "0.666666666667"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Evaluate(tt.expr, tt.vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s, _ := FormatResult(got.Value); s != tt.want {
+				t.Errorf("got %s (%s), want %s", s, got.Value.RatString(), tt.want)
+			}
+			if got.Approximate != tt.approximate {
+				t.Errorf("Approximate = %v, want %v", got.Approximate, tt.approximate)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Exact(t *testing.T) {
+	got, err := Evaluate("1/3 * 3", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Value.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("1/3 * 3 = %s, want 1", got.Value.RatString())
+	}
+}
+
+func TestEvaluate_Assigned(t *testing.T) {
+	vars := map[string]*big.Rat{"tip": big.NewRat(18, 100)}
+	got, err := Evaluate("meal = 84.5; tip = 20%; meal * tip", vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.Assigned) != 2 || got.Assigned[0].Name != "meal" || got.Assigned[1].Name != "tip" {
+		t.Fatalf("unexpected assignments: %+v", got.Assigned)
+	}
+	if vars["tip"].Cmp(big.NewRat(18, 100)) != 0 {
+		t.Errorf("caller's variables were modified: tip = %s", vars["tip"].RatString())
+	}
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		vars    map[string]*big.Rat
+		wantErr string
+	}{
+		{name: "empty", expr: " ; ", wantErr: "empty"},
+		{name: "too long", expr: strings.Repeat("1+", MaxInputLength), wantErr: "too long"},
+		{name: "too deep", expr: strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), wantErr: "nested too deeply"},
+		{name: "too deep unary", expr: strings.Repeat("-", 100) + "1", wantErr: "nested too deeply"},
+		{name: "huge power", expr: "9^9^9", wantErr: "too large"},
+		{name: "repeated squaring", expr: "x = 10^400; x = x*x; x = x*x; x = x*x; x*x", wantErr: "too large"},
+		{name: "huge exponent literal", expr: "1e99999", wantErr: "exponent"},
+		{name: "fractional exponent", expr: "2^0.5", wantErr: "whole numbers"},
+		{name: "division by zero", expr: "1 / (2 - 2)", wantErr: "division by zero"},
+		{name: "zero to a negative power", expr: "0^-1", wantErr: "division by zero"},
+		{name: "modulo by zero", expr: "5 mod 0", wantErr: "modulo by zero"},
+		{name: "negative square root", expr: "sqrt(-4)", wantErr: "negative"},
+		{name: "unknown variable", expr: "price * 2", wantErr: `unknown variable "price"`},
+		{name: "unknown function", expr: "exec(1)", wantErr: `unknown function "exec"`},
+		{name: "wrong arity", expr: "abs(1, 2)", wantErr: "expects abs(x)"},
+		{name: "bad round decimals", expr: "round(1.5, 0.5)", wantErr: "whole number"},
+		{name: "unexpected character", expr: "2 & 3", wantErr: "position 3"},
+		{name: "code", expr: `exec("rm -rf /")`, wantErr: "unexpected character"},
+		{name: "missing operand", expr: "1 +", wantErr: "end of expression"},
+		{name: "unbalanced parenthesis", expr: "(1 + 2", wantErr: `expected ")"`},
+		{name: "adjacent numbers", expr: "1 2", wantErr: `unexpected "2"`},
+		{name: "assign to function", expr: "sqrt = 4", wantErr: "reserved"},
+		{name: "bad variable name", expr: "1", vars: map[string]*big.Rat{"a b": big.NewRat(1, 1)}, wantErr: "invalid variable name"},
+		{name: "reserved variable name", expr: "1", vars: map[string]*big.Rat{"of": big.NewRat(1, 1)}, wantErr: "invalid variable name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.expr, tt.vars)
+			if err == nil {
+				t.Fatalf("expected error, got %s", got.Value.RatString())
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		r         *big.Rat
+		decimals  int
+		want      string
+		wantExact bool
+	}{
+		{big.NewRat(7, 1), 2, "7", true},
+		{big.NewRat(-7, 4), 2, "-1.75", true},
+		{big.NewRat(1, 8), 2, "0.13", false},
+		{big.NewRat(1, 3), 4, "0.3333", false},
+		{big.NewRat(2, 3), 4, "0.6667", false},
+		{big.NewRat(1, 1024), 12, "0.0009765625", true},
+		{big.NewRat(1, 8192), 12, "0.000122070313", false},
+		{big.NewRat(1, 1000000), 12, "0.000001", true},
+	}
+
+	for _, tt := range tests {
+		got, exact := Format(tt.r, tt.decimals)
+		if got != tt.want || exact != tt.wantExact {
+			t.Errorf("Format(%s, %d) = %s, %v; want %s, %v", tt.r.RatString(), tt.decimals, got, exact, tt.want, tt.wantExact)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	valid := map[string]string{
+		"12":     "12",
+		"-12.5":  "-25/2",
+		"0.1":    "1/10",
+		"1e3":    "1000",
+		" 2.50 ": "5/2",
+	}
+	for in, want := range valid {
+		got, err := ParseNumber(in)
+		if err != nil {
+			t.Errorf("ParseNumber(%q): %v", in, err)
+			continue
+		}
+		if got.RatString() != want {
+			t.Errorf("ParseNumber(%q) = %s, want %s", in, got.RatString(), want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1/3", "0x10", "1e99999", "--1", "1.2.3"} {
+		if _, err := ParseNumber(in); err == nil {
+			t.Errorf("ParseNumber(%q): expected error", in)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// sqrtPrecision is the binary precision of square roots that aren't exact
+const sqrtPrecision = 128
+
+type function struct {
+	minArgs int
+	maxArgs int // -1 for any number
+	usage   string
+	eval    func(p *parser, args []*big.Rat) (*big.Rat, error)
+}
+
+var functions = map[string]function{
+	"abs": {1, 1, "expects abs(x)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return new(big.Rat).Abs(a[0]), nil
+	}},
+	"round": {1, 2, "expects round(x) or round(x, decimals)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return scaled(a, roundHalfAway)
+	}},
+	"floor": {1, 2, "expects floor(x) or floor(x, decimals)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return scaled(a, floor)
+	}},
+	"ceil": {1, 2, "expects ceil(x) or ceil(x, decimals)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return scaled(a, ceil)
+	}},
+	"min": {1, -1, "expects min(x, ...)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return slices.MinFunc(a, (*big.Rat).Cmp), nil
+	}},
+	"max": {1, -1, "expects max(x, ...)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return slices.MaxFunc(a, (*big.Rat).Cmp), nil
+	}},
+	"sum": {1, -1, "expects sum(x, ...)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return sum(a), nil
+	}},
+	"avg": {1, -1, "expects avg(x, ...)", func(_ *parser, a []*big.Rat) (*big.Rat, error) {
+		return sum(a).Quo(sum(a), big.NewRat(int64(len(a)), 1)), nil
+	}},
+	"sqrt": {1, 1, "expects sqrt(x)", func(p *parser, a []*big.Rat) (*big.Rat, error) {
+		r, exact, err := sqrt(a[0])
+		if !exact {
+			p.approximate = true
+		}
+		return r, err
+	}},
+}
+
+// isReserved reports whether name is a function or operator keyword
+func isReserved(name string) bool {
+	_, ok := functions[name]
+	return ok || name == "mod" || name == "of"
+}
+
+func sum(a []*big.Rat) *big.Rat {
+	total := new(big.Rat)
+	for _, r := range a {
+		total.Add(total, r)
+	}
+	return total
+}
+
+// floor returns the largest integer not above r
+func floor(r *big.Rat) *big.Rat {
+	// Euclidean division by the positive denominator rounds down
+	q := new(big.Int).Div(r.Num(), r.Denom())
+	return new(big.Rat).SetInt(q)
+}
+
+func ceil(r *big.Rat) *big.Rat {
+	f := floor(new(big.Rat).Neg(r))
+	return f.Neg(f)
+}
+
+// roundHalfAway rounds to the nearest integer, halves away from zero
+func roundHalfAway(r *big.Rat) *big.Rat {
+	half := big.NewRat(1, 2)
+	if r.Sign() < 0 {
+		f := floor(new(big.Rat).Add(new(big.Rat).Neg(r), half))
+		return f.Neg(f)
+	}
+	return floor(new(big.Rat).Add(r, half))
+}
+
+// scaled applies an integer rounding to args[0] at args[1] decimals (0 by default)
+func scaled(args []*big.Rat, round func(*big.Rat) *big.Rat) (*big.Rat, error) {
+	if len(args) == 1 {
+		return round(args[0]), nil
+	}
+
+	d := args[1]
+	if !d.IsInt() || d.Num().CmpAbs(big.NewInt(20)) > 0 {
+		return nil, fmt.Errorf("decimals must be a whole number between -20 and 20")
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), new(big.Int).Abs(d.Num()), nil))
+	if d.Sign() < 0 {
+		scale.Inv(scale)
+	}
+
+	r := round(new(big.Rat).Mul(args[0], scale))
+	return r.Quo(r, scale), nil
+}
+
+// pow raises base to a whole exponent
+func pow(base, exp *big.Rat) (*big.Rat, error) {
+	if !exp.IsInt() {
+		return nil, fmt.Errorf("exponents must be whole numbers")
+	}
+	if base.Sign() == 0 {
+		if exp.Sign() < 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if exp.Sign() == 0 {
+			return big.NewRat(1, 1), nil
+		}
+		return new(big.Rat), nil
+	}
+
+	// Estimate the size first so huge powers are refused before computing them
+	e := new(big.Int).Abs(exp.Num())
+	bits := max(base.Num().BitLen(), base.Denom().BitLen())
+	if e.BitLen() > 32 || (bits > 1 && int64(bits-1)*e.Int64() > maxBits) {
+		return nil, fmt.Errorf("result is too large")
+	}
+
+	num := new(big.Int).Exp(base.Num(), e, nil)
+	den := new(big.Int).Exp(base.Denom(), e, nil)
+	if exp.Sign() < 0 {
+		num, den = den, num
+	}
+	r := new(big.Rat).SetFrac(num, den)
+	if err := checkSize(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// sqrt returns the square root of r, exactly when r is a square of a rational
+func sqrt(r *big.Rat) (*big.Rat, bool, error) {
+	if r.Sign() < 0 {
+		return nil, false, fmt.Errorf("square root of a negative number")
+	}
+
+	num := new(big.Int).Sqrt(r.Num())
+	den := new(big.Int).Sqrt(r.Denom())
+	if new(big.Int).Mul(num, num).Cmp(r.Num()) == 0 && new(big.Int).Mul(den, den).Cmp(r.Denom()) == 0 {
+		return new(big.Rat).SetFrac(num, den), true, nil
+	}
+
+	f := new(big.Float).SetPrec(sqrtPrecision).SetRat(r)
+	root, _ := f.Sqrt(f).Rat(nil)
+	return root, false, nil
+}
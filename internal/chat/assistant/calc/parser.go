@@ -0,0 +1,449 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEnd tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(op string) bool {
+	return t.kind == tokOp && t.text == op
+}
+
+func (t token) String() string {
+	if t.kind == tokEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+// Typographic operators are read as their ASCII equivalents
+var opAliases = map[rune]string{
+	'×': "*",
+	'÷': "/",
+	'−': "-",
+}
+
+// lex splits an expression into tokens
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{kind: tokOp, text: ";", pos: i})
+			i += size
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9' || r == '.':
+			n, err := lexNumber(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d", err, i+1)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: s[i : i+n], pos: i})
+			i += n
+		case r == '_' || unicode.IsLetter(r) && r < utf8.RuneSelf:
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] >= '0' && s[j] <= '9' || unicode.IsLetter(rune(s[j])) && s[j] < utf8.RuneSelf) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: s[i:j], pos: i})
+			i = j
+		case strings.ContainsRune("+-*/^%(),=;", r):
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: i})
+			i += size
+		default:
+			if op, ok := opAliases[r]; ok {
+				tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+				i += size
+				continue
+			}
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+		}
+	}
+	return append(tokens, token{kind: tokEnd, pos: len(s)}), nil
+}
+
+// lexNumber returns the length of the decimal number at the start of s,
+// e.g. "12", "0.5", ".5" or "1.2e3"
+func lexNumber(s string) (int, error) {
+	n, digits := 0, 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+		digits++
+	}
+	if n < len(s) && s[n] == '.' {
+		n++
+		for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("invalid number")
+	}
+
+	// An exponent needs at least one digit; "2e" is a number followed by a name
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		start := m
+		for m < len(s) && s[m] >= '0' && s[m] <= '9' {
+			m++
+		}
+		if m > start {
+			if m-start > 3 {
+				return 0, fmt.Errorf("exponent is too large")
+			}
+			n = m
+		}
+	}
+	return n, nil
+}
+
+// value is an intermediate result; percent marks values written as "n%",
+// so "a + n%" can add n percent of a
+type value struct {
+	r       *big.Rat
+	percent bool
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	depth       int
+	vars        map[string]*big.Rat
+	assigned    []Assignment
+	approximate bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); !t.is(op) {
+		return fmt.Errorf("expected %q, got %s", op, t)
+	}
+	return nil
+}
+
+// enter guards against deeply nested input
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return fmt.Errorf("expression is nested too deeply (max %d levels)", maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// program := statement { ";" statement }
+func (p *parser) program() (*big.Rat, error) {
+	var last *big.Rat
+	for {
+		for p.peek().is(";") {
+			p.next()
+		}
+		if p.peek().kind == tokEnd {
+			break
+		}
+
+		v, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		last = v
+
+		if t := p.peek(); t.kind != tokEnd && !t.is(";") {
+			return nil, fmt.Errorf("unexpected %s", t)
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	return last, nil
+}
+
+// statement := [ ident "=" ] expr
+func (p *parser) statement() (*big.Rat, error) {
+	if t := p.peek(); t.kind == tokIdent && p.tokens[p.pos+1].is("=") {
+		if isReserved(t.text) {
+			return nil, fmt.Errorf("cannot assign to %q, it is a reserved name", t.text)
+		}
+		if _, ok := p.vars[t.text]; !ok && len(p.vars) >= MaxVariables {
+			return nil, fmt.Errorf("too many variables (max %d)", MaxVariables)
+		}
+		p.pos += 2
+
+		v, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		p.vars[t.text] = v.r
+		p.assigned = append(p.assigned, Assignment{Name: t.text, Value: v.r})
+		return v.r, nil
+	}
+
+	v, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return v.r, nil
+}
+
+// expr := term { ("+" | "-") term }
+func (p *parser) expr() (value, error) {
+	left, err := p.term()
+	if err != nil {
+		return value{}, err
+	}
+
+	for p.peek().is("+") || p.peek().is("-") {
+		op := p.next().text
+		right, err := p.term()
+		if err != nil {
+			return value{}, err
+		}
+
+		// "80 + 15%" is 80 plus 15% of 80, as on a calculator
+		delta := right.r
+		if right.percent && !left.percent {
+			delta = new(big.Rat).Mul(left.r, right.r)
+		}
+
+		r := new(big.Rat)
+		if op == "+" {
+			r.Add(left.r, delta)
+		} else {
+			r.Sub(left.r, delta)
+		}
+		if err := checkSize(r); err != nil {
+			return value{}, err
+		}
+		left = value{r: r, percent: left.percent && right.percent}
+	}
+	return left, nil
+}
+
+// term := unary { ("*" | "/" | "mod" | "of") unary }
+func (p *parser) term() (value, error) {
+	left, err := p.unary()
+	if err != nil {
+		return value{}, err
+	}
+
+	for {
+		t := p.peek()
+		var op string
+		switch {
+		case t.is("*"), t.is("/"):
+			op = t.text
+		case t.kind == tokIdent && (t.text == "mod" || t.text == "of"):
+			op = t.text
+		default:
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.unary()
+		if err != nil {
+			return value{}, err
+		}
+
+		r := new(big.Rat)
+		switch op {
+		case "*", "of":
+			r.Mul(left.r, right.r)
+		case "/":
+			if right.r.Sign() == 0 {
+				return value{}, fmt.Errorf("division by zero")
+			}
+			r.Quo(left.r, right.r)
+		case "mod":
+			if right.r.Sign() == 0 {
+				return value{}, fmt.Errorf("modulo by zero")
+			}
+			// Floored modulo, so the result has the sign of the divisor
+			q := floor(new(big.Rat).Quo(left.r, right.r))
+			r.Sub(left.r, q.Mul(q, right.r))
+		}
+		if err := checkSize(r); err != nil {
+			return value{}, err
+		}
+		left = value{r: r}
+	}
+}
+
+// unary := ("-" | "+") unary | power
+func (p *parser) unary() (value, error) {
+	if err := p.enter(); err != nil {
+		return value{}, err
+	}
+	defer p.leave()
+
+	if p.peek().is("-") || p.peek().is("+") {
+		op := p.next().text
+		v, err := p.unary()
+		if err != nil {
+			return value{}, err
+		}
+		if op == "-" {
+			v.r = new(big.Rat).Neg(v.r)
+		}
+		return v, nil
+	}
+	return p.power()
+}
+
+// power := postfix [ "^" unary ]
+func (p *parser) power() (value, error) {
+	base, err := p.postfix()
+	if err != nil {
+		return value{}, err
+	}
+	if !p.peek().is("^") {
+		return base, nil
+	}
+	p.next()
+
+	exp, err := p.unary()
+	if err != nil {
+		return value{}, err
+	}
+	r, err := pow(base.r, exp.r)
+	if err != nil {
+		return value{}, err
+	}
+	return value{r: r}, nil
+}
+
+// postfix := primary { "%" }
+func (p *parser) postfix() (value, error) {
+	v, err := p.primary()
+	if err != nil {
+		return value{}, err
+	}
+	for p.peek().is("%") {
+		p.next()
+		v = value{r: new(big.Rat).Quo(v.r, big.NewRat(100, 1)), percent: true}
+	}
+	return v, nil
+}
+
+// primary := number | ident | ident "(" args ")" | "(" expr ")"
+func (p *parser) primary() (value, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokNumber:
+		r, ok := new(big.Rat).SetString(t.text)
+		if !ok {
+			return value{}, fmt.Errorf("invalid number %s", t)
+		}
+		if err := checkSize(r); err != nil {
+			return value{}, err
+		}
+		return value{r: r}, nil
+
+	case t.is("("):
+		if err := p.enter(); err != nil {
+			return value{}, err
+		}
+		defer p.leave()
+
+		v, err := p.expr()
+		if err != nil {
+			return value{}, err
+		}
+		if err := p.expect(")"); err != nil {
+			return value{}, err
+		}
+		// Parentheses make a plain number: "80 - (15%)" subtracts 0.15
+		return value{r: v.r}, nil
+
+	case t.kind == tokIdent && p.peek().is("("):
+		return p.call(t)
+
+	case t.kind == tokIdent && !isReserved(t.text):
+		r, ok := p.vars[t.text]
+		if !ok {
+			return value{}, fmt.Errorf("unknown variable %q", t.text)
+		}
+		return value{r: r}, nil
+	}
+	return value{}, fmt.Errorf("unexpected %s", t)
+}
+
+// call evaluates a function call; the name has been read
+func (p *parser) call(name token) (value, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return value{}, fmt.Errorf("unknown function %q", name.text)
+	}
+	if err := p.enter(); err != nil {
+		return value{}, err
+	}
+	defer p.leave()
+	p.next() // "("
+
+	var args []*big.Rat
+	if !p.peek().is(")") {
+		for {
+			v, err := p.expr()
+			if err != nil {
+				return value{}, err
+			}
+			args = append(args, v.r)
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return value{}, err
+	}
+
+	if len(args) < fn.minArgs || fn.maxArgs >= 0 && len(args) > fn.maxArgs {
+		return value{}, fmt.Errorf("%s: %s", name.text, fn.usage)
+	}
+	r, err := fn.eval(p, args)
+	if err != nil {
+		return value{}, fmt.Errorf("%s: %w", name.text, err)
+	}
+	if err := checkSize(r); err != nil {
+		return value{}, err
+	}
+	return value{r: r}, nil
+}
@@ -0,0 +1,150 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// declinedResult tells the model the user didn't let a tool call run
+const declinedResult = "The user declined this action, it was not carried out. Don't try it again unless the user asks."
+
+// ToolPolicyStore holds the tool policy each user sets for their conversations
+type ToolPolicyStore interface {
+	UserToolPolicy(ctx context.Context, userID string) (model.ToolPolicy, error)
+}
+
+// WithToolPolicy restricts the tools of every conversation
+func WithToolPolicy(policy model.ToolPolicy) Option {
+	return func(a *Assistant) {
+		a.toolPolicy = policy
+	}
+}
+
+// WithToolPolicyStore applies the tool policy of the calling user to their replies
+func WithToolPolicyStore(store ToolPolicyStore) Option {
+	return func(a *Assistant) {
+		a.policyStore = store
+	}
+}
+
+// ToolPolicyFromEnv reads the deployment's tool policy from comma-separated
+// tool names: TOOLS_ALLOW limits the tools to those listed, TOOLS_DENY
+// removes tools and TOOLS_CONFIRM makes calls wait for the user's approval
+func ToolPolicyFromEnv() model.ToolPolicy {
+	return model.ToolPolicy{
+		Allow:   model.ParseToolNames(os.Getenv("TOOLS_ALLOW")),
+		Deny:    model.ParseToolNames(os.Getenv("TOOLS_DENY")),
+		Confirm: model.ParseToolNames(os.Getenv("TOOLS_CONFIRM")),
+	}
+}
+
+// toolPolicies returns the policies that apply to a reply: the deployment's,
+// the user's, the persona's and the conversation's. A user policy that can't
+// be loaded fails the reply rather than let tools the user denied run.
+func (a *Assistant) toolPolicies(ctx context.Context, conv *model.Conversation, persona *model.Persona) (model.ToolPolicies, error) {
+	policies := model.ToolPolicies{a.toolPolicy, persona.ToolPolicy(), conv.Tools}
+
+	if a.policyStore != nil {
+		user, err := a.policyStore.UserToolPolicy(ctx, auth.FromContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the user's tool policy: %w", err)
+		}
+		policies = append(policies, user)
+	}
+
+	return policies, nil
+}
+
+// toolCall records a call the model made. Calls to tools the policies confirm
+// wait for the user; the others run right away.
+func (a *Assistant) toolCall(ctx context.Context, allowed []tools.Tool, policies model.ToolPolicies, call openai.ChatCompletionMessageToolCallUnion, vault *pii.Vault) *model.ToolCall {
+	c := &model.ToolCall{ID: call.ID}
+
+	// Tools, and users confirming calls, get the personal data the model only knows as placeholders
+	switch call.Type {
+	case "function":
+		c.Name, c.Arguments = call.Function.Name, vault.Restore(call.Function.Arguments)
+	case "custom":
+		c.Name, c.Arguments = call.Custom.Name, vault.Restore(call.Custom.Input)
+	default:
+		slog.WarnContext(ctx, "Unknown tool call type", "type", call.Type)
+		c.Status, c.Result = model.ToolCallDone, fmt.Sprintf("Unknown tool call type: %s", call.Type)
+		return c
+	}
+
+	slog.InfoContext(ctx, "Tool call received", "name", c.Name, "args", a.redactor.Mask(c.Arguments))
+
+	available := slices.ContainsFunc(allowed, func(t tools.Tool) bool { return t.Name() == c.Name })
+	if available && policies.Confirms(c.Name) {
+		c.Status = model.ToolCallPending
+		return c
+	}
+
+	c.Status, c.Result = model.ToolCallDone, a.runTool(ctx, allowed, c.Name, c.Arguments)
+	return c
+}
+
+// runTool runs a tool and returns its result for the model, with
+// instruction-like text quarantined since it may come from remote sources
+func (a *Assistant) runTool(ctx context.Context, allowed []tools.Tool, name, arguments string) string {
+	return a.guard.Quarantine(ctx, name, tools.Result(ctx, allowed, name, arguments))
+}
+
+// resume picks up a reply that waited for the user: approved calls run,
+// declined ones are reported as such, and the messages of every step so far
+// are returned to go on from, redacted with vault
+func (a *Assistant) resume(ctx context.Context, allowed []tools.Tool, pending *model.PendingReply, vault *pii.Vault) ([]openai.ChatCompletionMessageParamUnion, error) {
+	if len(pending.Waiting()) > 0 {
+		return nil, model.ErrConfirmationRequired
+	}
+
+	for _, c := range pending.Steps[len(pending.Steps)-1] {
+		switch c.Status {
+		case model.ToolCallApproved:
+			slog.InfoContext(ctx, "Running approved tool call", "name", c.Name, "args", a.redactor.Mask(c.Arguments))
+			c.Status, c.Result = model.ToolCallDone, a.runTool(ctx, allowed, c.Name, c.Arguments)
+		case model.ToolCallDeclined:
+			c.Status, c.Result = model.ToolCallDone, declinedResult
+		}
+	}
+
+	var msgs []openai.ChatCompletionMessageParamUnion
+	for _, step := range pending.Steps {
+		msgs = append(msgs, stepMessages(step, vault)...)
+	}
+	return msgs, nil
+}
+
+// stepMessages rebuilds the messages of one round of tool calls: the model's
+// calls, then their results, redacted with vault
+func stepMessages(step []*model.ToolCall, vault *pii.Vault) []openai.ChatCompletionMessageParamUnion {
+	calls := make([]openai.ChatCompletionMessageToolCallUnionParam, len(step))
+	for i, c := range step {
+		calls[i] = openai.ChatCompletionMessageToolCallUnionParam{
+			OfFunction: &openai.ChatCompletionMessageFunctionToolCallParam{
+				ID: c.ID,
+				Function: openai.ChatCompletionMessageFunctionToolCallFunctionParam{
+					Name:      c.Name,
+					Arguments: vault.Redact(c.Arguments),
+				},
+			},
+		}
+	}
+
+	msgs := []openai.ChatCompletionMessageParamUnion{
+		{OfAssistant: &openai.ChatCompletionAssistantMessageParam{ToolCalls: calls}},
+	}
+	for _, c := range step {
+		msgs = append(msgs, openai.ToolMessage(vault.Redact(c.Result), c.ID))
+	}
+	return msgs
+}
@@ -0,0 +1,185 @@
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+// call is a tool call the scripted model makes
+type call struct {
+	id, name, arguments string
+}
+
+// scriptedOpenAI answers chat completion requests in turn with the given
+// answers, each either the reply text or the tool calls to make, and records
+// the requests
+func scriptedOpenAI(t *testing.T, answers ...any) (openai.Client, *[]chatRequest) {
+	t.Helper()
+
+	var requests []chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		requests = append(requests, req)
+
+		if len(requests) > len(answers) {
+			t.Errorf("unexpected request %d", len(requests))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		message := map[string]any{"role": "assistant"}
+		switch answer := answers[len(requests)-1].(type) {
+		case string:
+			message["content"] = answer
+		case []call:
+			var calls []map[string]any
+			for _, c := range answer {
+				calls = append(calls, map[string]any{
+					"id":       c.id,
+					"type":     "function",
+					"function": map[string]any{"name": c.name, "arguments": c.arguments},
+				})
+			}
+			message["tool_calls"] = calls
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": message}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return openai.NewClient(option.WithBaseURL(srv.URL), option.WithAPIKey("test"), option.WithMaxRetries(0)), &requests
+}
+
+// toolPolicyStore holds one policy per user
+type toolPolicyStore map[string]model.ToolPolicy
+
+func (s toolPolicyStore) UserToolPolicy(ctx context.Context, userID string) (model.ToolPolicy, error) {
+	return s[userID], nil
+}
+
+func TestAssistant_ReplyToolPolicies(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), "ana")
+	cli, requests := scriptedOpenAI(t, "Hello!")
+	a := New(
+		WithOpenAIClient(cli),
+		WithToolPolicy(model.ToolPolicy{Deny: []string{"get_weather"}}),
+		WithToolPolicyStore(toolPolicyStore{"ana": {Deny: []string{"convert_currency"}}}),
+	)
+
+	conv := conversation("Hi")
+	conv.Tools = model.ToolPolicy{Allow: []string{"get_weather", "convert_currency", "calculate", "get_today_date"}}
+	if _, err := a.Reply(ctx, conv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, tool := range (*requests)[0].Tools {
+		names = append(names, tool.Function.Name)
+	}
+	if want := []string{"get_today_date", "calculate"}; !slices.Equal(names, want) {
+		t.Errorf("expected tools %q, got %q", want, names)
+	}
+}
+
+func TestAssistant_ReplyConfirmsToolCalls(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), "ana")
+	confirm := WithToolPolicy(model.ToolPolicy{Confirm: []string{"calculate"}})
+	calls := []call{
+		{"call_date", "get_today_date", `{}`},
+		{"call_calc", "calculate", `{"expression": "2 * 21"}`},
+	}
+
+	t.Run("approved calls run when the reply resumes", func(t *testing.T) {
+		cli, requests := scriptedOpenAI(t, calls, "2 × 21 is 42.")
+		a := New(WithOpenAIClient(cli), confirm)
+		conv := conversation("What is 2 times 21?")
+
+		if _, err := a.Reply(ctx, conv); !errors.Is(err, model.ErrConfirmationRequired) {
+			t.Fatalf("expected ErrConfirmationRequired, got %v", err)
+		}
+		waiting := conv.Pending.Waiting()
+		if len(waiting) != 1 || waiting[0].ID != "call_calc" || waiting[0].Result != "" {
+			t.Fatalf("expected the calculation to wait, got %+v", waiting)
+		}
+		if date := conv.Pending.Call("call_date"); date.Status != model.ToolCallDone || date.Result == "" {
+			t.Errorf("expected the date to be looked up right away, got %+v", date)
+		}
+
+		// Nothing runs while a call is still waiting
+		if _, err := a.Reply(ctx, conv); !errors.Is(err, model.ErrConfirmationRequired) || len(*requests) != 1 {
+			t.Fatalf("expected the reply to keep waiting, got %v after %d requests", err, len(*requests))
+		}
+
+		waiting[0].Status = model.ToolCallApproved
+		reply, err := a.Reply(ctx, conv)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reply != "2 × 21 is 42." || conv.Pending != nil {
+			t.Errorf("unexpected reply %q, pending %+v", reply, conv.Pending)
+		}
+
+		results := map[string]string{}
+		for _, m := range (*requests)[1].Messages {
+			if m.Role == "tool" {
+				results[m.ToolCallID] = m.Content
+			}
+		}
+		if results["call_calc"] != "Result: 42" || results["call_date"] == "" {
+			t.Errorf("expected both results to be sent, got %q", results)
+		}
+	})
+
+	t.Run("declined calls don't run", func(t *testing.T) {
+		cli, requests := scriptedOpenAI(t, calls, "Alright, I won't calculate it.")
+		a := New(WithOpenAIClient(cli), confirm)
+		conv := conversation("What is 2 times 21?")
+
+		if _, err := a.Reply(ctx, conv); !errors.Is(err, model.ErrConfirmationRequired) {
+			t.Fatalf("expected ErrConfirmationRequired, got %v", err)
+		}
+
+		conv.Pending.Call("call_calc").Status = model.ToolCallDeclined
+		if _, err := a.Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, m := range (*requests)[1].Messages {
+			if m.ToolCallID == "call_calc" && m.Content != declinedResult {
+				t.Errorf("expected the call to be reported as declined, got %q", m.Content)
+			}
+		}
+	})
+
+	t.Run("calls to tools that aren't available don't wait", func(t *testing.T) {
+		cli, _ := scriptedOpenAI(t, calls, "I can't calculate here.")
+		a := New(WithOpenAIClient(cli), confirm)
+		conv := conversation("What is 2 times 21?")
+		conv.Tools = model.ToolPolicy{Deny: []string{"calculate"}}
+
+		if _, err := a.Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package currency
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long rates are kept; reference rates change once per working day
+const DefaultTTL = 6 * time.Hour
+
+// retryDelay is how long stale rates are served before the next refresh attempt
+const retryDelay = 5 * time.Minute
+
+// Cache is a RateProvider that keeps the rates of the wrapped provider for a while.
+// When a refresh fails, the previous rates are served and marked stale.
+type Cache struct {
+	next RateProvider
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	rates   *Rates
+	expires time.Time
+}
+
+// CacheOption configures a Cache
+type CacheOption func(*Cache)
+
+// WithTTL overrides how long rates are kept
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// NewCache wraps a provider with a TTL cache
+func NewCache(next RateProvider, opts ...CacheOption) *Cache {
+	c := &Cache{
+		next: next,
+		ttl:  DefaultTTL,
+		now:  time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name returns the name of the wrapped provider
+func (c *Cache) Name() string {
+	return c.next.Name()
+}
+
+// Rates returns cached rates or fetches them from the wrapped provider
+func (c *Cache) Rates(ctx context.Context) (*Rates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && c.now().Before(c.expires) {
+		return c.rates, nil
+	}
+
+	rates, err := c.next.Rates(ctx)
+	if err != nil {
+		if c.rates == nil {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "Failed to refresh exchange rates, serving cached rates",
+			"provider", c.next.Name(),
+			"fetched_at", c.rates.FetchedAt,
+			"error", err,
+		)
+		stale := *c.rates
+		stale.Stale = true
+		c.rates = &stale
+		c.expires = c.now().Add(retryDelay)
+		return c.rates, nil
+	}
+
+	c.rates = rates
+	c.expires = c.now().Add(c.ttl)
+	return rates, nil
+}
@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeProvider counts calls and returns canned rates or an error
+type fakeProvider struct {
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) Rates(ctx context.Context) (*Rates, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Rates{Base: "EUR", Rates: map[string]float64{"USD": 1.1}, Source: "fake"}, nil
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses rates until they expire", func(t *testing.T) {
+		p := &fakeProvider{}
+		c := NewCache(p, WithTTL(time.Hour))
+		now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		c.now = func() time.Time { return now }
+
+		_, _ = c.Rates(ctx)
+		_, _ = c.Rates(ctx)
+		if p.calls != 1 {
+			t.Errorf("expected 1 upstream call, got %d", p.calls)
+		}
+
+		now = now.Add(time.Hour + time.Second)
+		_, _ = c.Rates(ctx)
+		if p.calls != 2 {
+			t.Errorf("expected a refresh after the TTL, got %d calls", p.calls)
+		}
+	})
+
+	t.Run("serves stale rates when a refresh fails", func(t *testing.T) {
+		p := &fakeProvider{}
+		c := NewCache(p)
+		now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		c.now = func() time.Time { return now }
+
+		if _, err := c.Rates(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		p.err = errors.New("down")
+		now = now.Add(DefaultTTL + time.Second)
+
+		rates, err := c.Rates(ctx)
+		if err != nil {
+			t.Fatalf("expected stale rates, got error: %v", err)
+		}
+		if !rates.Stale {
+			t.Error("expected rates to be marked stale")
+		}
+
+		// The failed source isn't hammered on every question
+		_, _ = c.Rates(ctx)
+		if p.calls != 2 {
+			t.Errorf("expected 2 upstream calls, got %d", p.calls)
+		}
+
+		// A later refresh succeeds and clears the flag
+		p.err = nil
+		now = now.Add(retryDelay + time.Second)
+		if rates, _ := c.Rates(ctx); rates.Stale {
+			t.Error("expected fresh rates after a successful refresh")
+		}
+	})
+
+	t.Run("returns errors without cached rates", func(t *testing.T) {
+		p := &fakeProvider{err: errors.New("down")}
+		if _, err := NewCache(p).Rates(ctx); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestCache_WithStandIn(t *testing.T) {
+	ecb, calls := newECBStandIn(t, http.StatusOK, ecbFeed)
+	c := NewCache(ecb)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Rates(context.Background()); err != nil {
+			t.Fatalf("Rates failed: %v", err)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 request to the feed, got %d", *calls)
+	}
+}
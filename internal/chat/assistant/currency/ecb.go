@@ -0,0 +1,101 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ecbDailyURL is the ECB euro foreign exchange reference rates feed,
+// published on working days around 16:00 CET
+const ecbDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECBClient loads the European Central Bank daily reference rates
+type ECBClient struct {
+	httpClient *http.Client
+	url        string
+	now        func() time.Time
+}
+
+// NewECBClient creates a client for the ECB daily feed
+func NewECBClient() *ECBClient {
+	return &ECBClient{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		url: ecbDailyURL,
+		now: time.Now,
+	}
+}
+
+// Name identifies the provider in logs
+func (c *ECBClient) Name() string {
+	return "ecb"
+}
+
+// ecbEnvelope is the feed layout: Cube > Cube[time] > Cube[currency, rate]
+type ecbEnvelope struct {
+	Sender string `xml:"Sender>name"`
+	Cube   struct {
+		Day struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// Rates fetches the latest reference rates, quoted against EUR
+func (c *ECBClient) Rates(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ECB rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("ECB feed returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var env ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("failed to decode ECB rates: %w", err)
+	}
+
+	date, err := time.Parse(time.DateOnly, env.Cube.Day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECB rates date %q: %w", env.Cube.Day.Time, err)
+	}
+	if len(env.Cube.Day.Rates) == 0 {
+		return nil, fmt.Errorf("ECB feed for %s has no rates", env.Cube.Day.Time)
+	}
+
+	source := env.Sender
+	if source == "" {
+		source = "European Central Bank"
+	}
+
+	rates := &Rates{
+		Base:      "EUR",
+		Rates:     make(map[string]float64, len(env.Cube.Day.Rates)),
+		Date:      date,
+		Source:    source,
+		FetchedAt: c.now(),
+	}
+	for _, r := range env.Cube.Day.Rates {
+		rates.Rates[r.Currency] = r.Rate
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,86 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ecbFeed is a trimmed copy of the daily feed
+const ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2026-10-16'>
+			<Cube currency='USD' rate='1.1650'/>
+			<Cube currency='JPY' rate='174.20'/>
+			<Cube currency='GBP' rate='0.86930'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func newECBStandIn(t *testing.T, status int, body string) (*ECBClient, *int) {
+	t.Helper()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewECBClient()
+	c.url = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) }
+	return c, &calls
+}
+
+func TestECBClient_Rates(t *testing.T) {
+	c, _ := newECBStandIn(t, http.StatusOK, ecbFeed)
+
+	rates, err := c.Rates(context.Background())
+	if err != nil {
+		t.Fatalf("Rates failed: %v", err)
+	}
+
+	if rates.Base != "EUR" || rates.Source != "European Central Bank" {
+		t.Errorf("unexpected base %q and source %q", rates.Base, rates.Source)
+	}
+	if got := rates.Date.Format(time.DateOnly); got != "2026-10-16" {
+		t.Errorf("expected as-of date 2026-10-16, got %s", got)
+	}
+	if rates.FetchedAt.IsZero() {
+		t.Error("expected fetch time to be set")
+	}
+	if len(rates.Rates) != 3 || rates.Rates["JPY"] != 174.20 {
+		t.Errorf("unexpected rates %v", rates.Rates)
+	}
+}
+
+func TestECBClient_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "server error", status: http.StatusServiceUnavailable, body: "down"},
+		{name: "not XML", status: http.StatusOK, body: "{}"},
+		{name: "no rates", status: http.StatusOK, body: `<Envelope><Cube><Cube time="2026-10-16"></Cube></Cube></Envelope>`},
+		{name: "bad date", status: http.StatusOK, body: `<Envelope><Cube><Cube time="16/10/2026"><Cube currency="USD" rate="1.1"/></Cube></Cube></Envelope>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newECBStandIn(t, tt.status, tt.body)
+			if _, err := c.Rates(context.Background()); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileProvider loads rates from a local JSON file, for offline use and tests
+type FileProvider struct {
+	path string
+	now  func() time.Time
+}
+
+// NewFileProvider creates a provider reading the given rates file
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// Name identifies the provider in logs
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+// rateFile is the file layout, e.g.
+// {"base": "EUR", "date": "2026-10-16", "source": "...", "rates": {"USD": 1.09}}
+type rateFile struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"`
+	Source string             `json:"source,omitempty"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// Rates reads the file on every call; wrap the provider in a Cache
+func (p *FileProvider) Rates(ctx context.Context) (*Rates, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+
+	var f rateFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %s: %w", p.path, err)
+	}
+
+	if f.Base == "" || len(f.Rates) == 0 {
+		return nil, fmt.Errorf("rates file %s needs a base currency and rates", p.path)
+	}
+	date, err := time.Parse(time.DateOnly, f.Date)
+	if err != nil {
+		return nil, fmt.Errorf("rates file %s: invalid date %q, expected YYYY-MM-DD", p.path, f.Date)
+	}
+
+	rates := &Rates{
+		Base:      strings.ToUpper(f.Base),
+		Rates:     make(map[string]float64, len(f.Rates)),
+		Date:      date,
+		Source:    f.Source,
+		FetchedAt: p.now(),
+	}
+	if rates.Source == "" {
+		rates.Source = "rates file " + p.path
+	}
+	for code, rate := range f.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("rates file %s: rate for %s must be positive", p.path, code)
+		}
+		rates.Rates[strings.ToUpper(code)] = rate
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,72 @@
+package currency
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileProvider_Rates(t *testing.T) {
+	t.Run("bundled sample file", func(t *testing.T) {
+		rates, err := NewFileProvider("../../../../data/currency/rates.json").Rates(context.Background())
+		if err != nil {
+			t.Fatalf("Rates failed: %v", err)
+		}
+		if rates.Base != "EUR" || rates.Rates["USD"] == 0 || rates.Date.IsZero() || rates.Source == "" {
+			t.Errorf("unexpected rates %+v", rates)
+		}
+	})
+
+	t.Run("normalises codes and defaults the source", func(t *testing.T) {
+		path := writeRates(t, `{"base": "usd", "date": "2026-10-16", "rates": {"eur": 0.86}}`)
+
+		rates, err := NewFileProvider(path).Rates(context.Background())
+		if err != nil {
+			t.Fatalf("Rates failed: %v", err)
+		}
+		if rates.Base != "USD" || rates.Rates["EUR"] != 0.86 {
+			t.Errorf("unexpected rates %+v", rates)
+		}
+		if rates.Source != "rates file "+path {
+			t.Errorf("unexpected source %q", rates.Source)
+		}
+		if rates.Date != time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) {
+			t.Errorf("unexpected date %v", rates.Date)
+		}
+	})
+
+	errs := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid JSON", body: `{`},
+		{name: "no base", body: `{"date": "2026-10-16", "rates": {"USD": 1.1}}`},
+		{name: "no rates", body: `{"base": "EUR", "date": "2026-10-16"}`},
+		{name: "bad date", body: `{"base": "EUR", "date": "yesterday", "rates": {"USD": 1.1}}`},
+		{name: "negative rate", body: `{"base": "EUR", "date": "2026-10-16", "rates": {"USD": -1.1}}`},
+	}
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFileProvider(writeRates(t, tt.body)).Rates(context.Background()); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := NewFileProvider(filepath.Join(t.TempDir(), "none.json")).Rates(context.Background()); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func writeRates(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
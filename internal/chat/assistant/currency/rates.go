@@ -0,0 +1,100 @@
+// Package currency provides exchange rates from pluggable sources
+// (a local rates file and the ECB daily reference rates), with caching.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RateProvider is a source of exchange rates
+type RateProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// Rates returns the latest rates the source publishes
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+// Rates is a table of exchange rates against a base currency
+type Rates struct {
+	// Base is the ISO 4217 code rates are quoted against, e.g. "EUR"
+	Base string
+
+	// Rates holds units of each currency per one unit of Base
+	Rates map[string]float64
+
+	// Date is the as-of date published by the source
+	Date time.Time
+
+	// Source names where the rates come from, e.g. "European Central Bank"
+	Source string
+
+	// FetchedAt is when the rates were loaded
+	FetchedAt time.Time
+
+	// Stale is set by Cache when a refresh failed and older rates are served
+	Stale bool
+}
+
+// Rate returns how many units of to one unit of from buys
+func (r *Rates) Rate(from, to string) (float64, error) {
+	f, err := r.perBase(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := r.perBase(to)
+	if err != nil {
+		return 0, err
+	}
+	return t / f, nil
+}
+
+// Convert converts an amount between two currencies
+func (r *Rates) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// Currencies lists the supported ISO codes, sorted
+func (r *Rates) Currencies() []string {
+	codes := []string{r.Base}
+	for code := range r.Rates {
+		if code != r.Base {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+func (r *Rates) perBase(code string) (float64, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == r.Base {
+		return 1, nil
+	}
+	if rate, ok := r.Rates[code]; ok && rate > 0 {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("unsupported currency %q, supported: %s", code, strings.Join(r.Currencies(), ", "))
+}
+
+// NewProviderFromEnv builds the rate provider from the environment.
+// CURRENCY_RATES_FILE points to a JSON rates file for offline use
+// (see data/currency/rates.json); otherwise the ECB daily feed is used.
+// The provider is wrapped in a cache.
+func NewProviderFromEnv() RateProvider {
+	if path := os.Getenv("CURRENCY_RATES_FILE"); path != "" {
+		slog.Info("Using exchange rates from file", "path", path)
+		return NewCache(NewFileProvider(path))
+	}
+	return NewCache(NewECBClient())
+}
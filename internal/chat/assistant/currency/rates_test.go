@@ -0,0 +1,67 @@
+package currency
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRates_Convert(t *testing.T) {
+	rates := &Rates{Base: "EUR", Rates: map[string]float64{"USD": 1.25, "JPY": 160, "GBP": 0.8}}
+
+	tests := []struct {
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{amount: 100, from: "EUR", to: "USD", want: 125},
+		{amount: 125, from: "USD", to: "EUR", want: 100},
+		{amount: 10, from: "usd", to: " jpy ", want: 1280},
+		{amount: 80, from: "GBP", to: "USD", want: 125},
+		{amount: 5, from: "GBP", to: "GBP", want: 5},
+		{amount: 1, from: "EUR", to: "XYZ", wantErr: true},
+		{amount: 1, from: "", to: "EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := rates.Convert(tt.amount, tt.from, tt.to)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%v %s to %s: expected error, got %v", tt.amount, tt.from, tt.to, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v %s to %s: unexpected error: %v", tt.amount, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%v %s to %s = %v, want %v", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRates_Currencies(t *testing.T) {
+	rates := &Rates{Base: "EUR", Rates: map[string]float64{"USD": 1.25, "GBP": 0.8}}
+
+	got := rates.Currencies()
+	if len(got) != 3 || got[0] != "EUR" || got[1] != "GBP" || got[2] != "USD" {
+		t.Errorf("unexpected currencies %v", got)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	t.Run("uses the rates file when configured", func(t *testing.T) {
+		t.Setenv("CURRENCY_RATES_FILE", "rates.json")
+		if name := NewProviderFromEnv().Name(); name != "file" {
+			t.Errorf("expected file provider, got %q", name)
+		}
+	})
+
+	t.Run("defaults to the ECB feed", func(t *testing.T) {
+		t.Setenv("CURRENCY_RATES_FILE", "")
+		if name := NewProviderFromEnv().Name(); name != "ecb" {
+			t.Errorf("expected ECB provider, got %q", name)
+		}
+	})
+}
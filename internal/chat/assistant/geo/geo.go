@@ -0,0 +1,311 @@
+// Package geo is an offline gazetteer: a table of well-known cities, islands and
+// countries embedded in the binary, used to resolve place names and coordinates
+// to IANA time zones without calling an external geocoding service.
+package geo
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	// Zones in the tables must resolve on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed places.csv
+var placesCSV []byte
+
+//go:embed countries.csv
+var countriesCSV []byte
+
+// Place is a city, island or resort in the gazetteer
+type Place struct {
+	Name        string
+	Region      string // state, province or island, may be empty
+	RegionCode  string // postal abbreviation, e.g. "TX", may be empty
+	Country     string
+	CountryCode string // ISO 3166-1 alpha-2
+	Lat         float64
+	Lon         float64
+	TimeZone    string // IANA name, e.g. "America/Chicago"
+	Population  int    // thousands, ranks namesakes
+}
+
+// Label describes the place for tool output, e.g. "Austin, Texas, United States"
+func (p Place) Label() string {
+	parts := []string{p.Name}
+	if p.Region != "" && p.Region != p.Name {
+		parts = append(parts, p.Region)
+	}
+	if p.Country != p.Name {
+		parts = append(parts, p.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Coordinates formats the position as "lat,lon", the form weather providers accept
+func (p Place) Coordinates() string {
+	return strconv.FormatFloat(p.Lat, 'f', 4, 64) + "," + strconv.FormatFloat(p.Lon, 'f', 4, 64)
+}
+
+// Country is a country in the gazetteer
+type Country struct {
+	Code      string
+	Name      string
+	Capital   Place
+	TimeZones []string // distinct zones of the country's places, sorted
+}
+
+// Gazetteer resolves place names and coordinates
+type Gazetteer struct {
+	places    []Place
+	names     map[string][]int    // normalised name or alias -> places, most populous first
+	countries map[string]*Country // normalised name, alias or code -> country
+}
+
+var defaultGazetteer = sync.OnceValue(func() *Gazetteer {
+	g, err := parse(bytes.NewReader(placesCSV), bytes.NewReader(countriesCSV))
+	if err != nil {
+		panic(fmt.Sprintf("geo: invalid embedded gazetteer: %v", err))
+	}
+	return g
+})
+
+// Default returns the gazetteer built from the embedded tables
+func Default() *Gazetteer {
+	return defaultGazetteer()
+}
+
+// Len returns the number of places
+func (g *Gazetteer) Len() int {
+	return len(g.places)
+}
+
+// Lookup finds places by name or alias, most populous first. The name may be
+// qualified after commas with a region, region code, country or country code,
+// e.g. "Austin, TX" or "San Jose, Costa Rica". Returns nil when nothing matches.
+func (g *Gazetteer) Lookup(query string) []Place {
+	parts := strings.Split(query, ",")
+	name := normalize(parts[0])
+	if name == "" {
+		return nil
+	}
+
+	var matches []Place
+	for _, i := range g.names[name] {
+		p := g.places[i]
+		if g.qualifies(p, parts[1:]) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// qualifies reports whether every qualifier names the place's region or country
+func (g *Gazetteer) qualifies(p Place, qualifiers []string) bool {
+	for _, q := range qualifiers {
+		q = normalize(q)
+		if q == "" {
+			continue
+		}
+		if q == normalize(p.Region) || q == normalize(p.RegionCode) {
+			continue
+		}
+		if c, ok := g.countries[q]; ok && c.Code == p.CountryCode {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// Country finds a country by name, alias or ISO code
+func (g *Gazetteer) Country(query string) (*Country, bool) {
+	c, ok := g.countries[normalize(query)]
+	return c, ok
+}
+
+// Nearest returns the known place closest to the given coordinates
+// and its distance in kilometres
+func (g *Gazetteer) Nearest(lat, lon float64) (Place, float64) {
+	best, bestKm := Place{}, math.Inf(1)
+	for _, p := range g.places {
+		if km := Distance(lat, lon, p.Lat, p.Lon); km < bestKm {
+			best, bestKm = p, km
+		}
+	}
+	return best, bestKm
+}
+
+// ParseCoordinates parses "lat,lon" in decimal degrees
+func ParseCoordinates(s string) (lat, lon float64, ok bool) {
+	latStr, lonStr, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// earthRadiusKm is the mean radius used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// Distance returns the great-circle distance between two points in kilometres
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// parse builds a gazetteer from the places and countries tables
+func parse(placesData, countriesData io.Reader) (*Gazetteer, error) {
+	countryRows, err := readCSV(countriesData, 4)
+	if err != nil {
+		return nil, fmt.Errorf("countries: %w", err)
+	}
+	placeRows, err := readCSV(placesData, 9)
+	if err != nil {
+		return nil, fmt.Errorf("places: %w", err)
+	}
+
+	g := &Gazetteer{
+		names:     make(map[string][]int),
+		countries: make(map[string]*Country),
+	}
+
+	countryNames := make(map[string]string, len(countryRows))
+	for _, row := range countryRows {
+		countryNames[row[0]] = row[1]
+	}
+
+	for n, row := range placeRows {
+		p := Place{
+			Name:        row[0],
+			Region:      row[2],
+			RegionCode:  row[3],
+			CountryCode: row[4],
+			Country:     countryNames[row[4]],
+			TimeZone:    row[7],
+		}
+		if p.Country == "" {
+			return nil, fmt.Errorf("places line %d: unknown country code %q", n+2, p.CountryCode)
+		}
+		if p.Lat, err = strconv.ParseFloat(row[5], 64); err != nil {
+			return nil, fmt.Errorf("places line %d: invalid latitude: %w", n+2, err)
+		}
+		if p.Lon, err = strconv.ParseFloat(row[6], 64); err != nil {
+			return nil, fmt.Errorf("places line %d: invalid longitude: %w", n+2, err)
+		}
+		if p.Population, err = strconv.Atoi(row[8]); err != nil {
+			return nil, fmt.Errorf("places line %d: invalid population: %w", n+2, err)
+		}
+
+		g.places = append(g.places, p)
+		for _, name := range append([]string{p.Name}, splitAliases(row[1])...) {
+			key := normalize(name)
+			if !slices.Contains(g.names[key], len(g.places)-1) {
+				g.names[key] = append(g.names[key], len(g.places)-1)
+			}
+		}
+	}
+
+	for _, idx := range g.names {
+		slices.SortStableFunc(idx, func(a, b int) int {
+			return g.places[b].Population - g.places[a].Population
+		})
+	}
+
+	for n, row := range countryRows {
+		c := &Country{Code: row[0], Name: row[1]}
+		for _, p := range g.places {
+			if p.CountryCode != c.Code {
+				continue
+			}
+			if p.Name == row[3] {
+				c.Capital = p
+			}
+			if !slices.Contains(c.TimeZones, p.TimeZone) {
+				c.TimeZones = append(c.TimeZones, p.TimeZone)
+			}
+		}
+		if c.Capital.Name == "" {
+			return nil, fmt.Errorf("countries line %d: capital %q of %s is not a known place", n+2, row[3], c.Name)
+		}
+		slices.Sort(c.TimeZones)
+
+		for _, name := range append([]string{c.Code, c.Name}, splitAliases(row[2])...) {
+			g.countries[normalize(name)] = c
+		}
+	}
+
+	return g, nil
+}
+
+// readCSV reads a table with a header row and a fixed number of columns
+func readCSV(r io.Reader, columns int) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = columns
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("no rows")
+	}
+	return rows[1:], nil
+}
+
+func splitAliases(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "|")
+}
+
+// normalize makes spellings of a name comparable: case, accents, punctuation
+// and spacing are ignored, e.g. "St. John's" and "st johns"
+func normalize(s string) string {
+	// Strip accents, so "São Paulo" matches "sao paulo"; transformers keep state,
+	// so each call builds its own chain
+	stripMarks := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(stripMarks, s)
+	if err != nil {
+		folded = s
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(folded) {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+		case r == '-' || r == '_':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
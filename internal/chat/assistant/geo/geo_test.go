@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefault_EmbeddedTables(t *testing.T) {
+	g := Default()
+	if g.Len() < 300 {
+		t.Errorf("expected at least 300 places, got %d", g.Len())
+	}
+
+	for _, p := range g.places {
+		if _, err := time.LoadLocation(p.TimeZone); err != nil {
+			t.Errorf("%s: %v", p.Label(), err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	g := Default()
+
+	tests := []struct {
+		query     string
+		wantLabel string
+		wantZone  string
+		wantCount int
+	}{
+		{query: "Austin", wantLabel: "Austin, Texas, United States", wantZone: "America/Chicago", wantCount: 1},
+		{query: "Bali", wantLabel: "Denpasar, Bali, Indonesia", wantZone: "Asia/Makassar", wantCount: 1},
+		{query: "sao paulo", wantLabel: "São Paulo, Brazil", wantZone: "America/Sao_Paulo", wantCount: 1},
+		{query: "  st johns ", wantZone: "America/St_Johns", wantCount: 1},
+		{query: "Xian", wantZone: "Asia/Shanghai", wantCount: 1},
+		{query: "Bombay", wantLabel: "Mumbai, Maharashtra, India", wantZone: "Asia/Kolkata", wantCount: 1},
+		{query: "Singapore", wantLabel: "Singapore", wantZone: "Asia/Singapore", wantCount: 1},
+		{query: "San Jose", wantLabel: "San Jose, California, United States", wantZone: "America/Los_Angeles", wantCount: 2},
+		{query: "San Jose, Costa Rica", wantLabel: "San José, Costa Rica", wantZone: "America/Costa_Rica", wantCount: 1},
+		{query: "Austin, TX", wantZone: "America/Chicago", wantCount: 1},
+		{query: "Austin, Texas, USA", wantZone: "America/Chicago", wantCount: 1},
+		{query: "Austin, France", wantCount: 0},
+		{query: "Atlantis", wantCount: 0},
+		{query: "", wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches := g.Lookup(tt.query)
+			if len(matches) != tt.wantCount {
+				t.Fatalf("expected %d matches, got %+v", tt.wantCount, matches)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			if tt.wantLabel != "" && matches[0].Label() != tt.wantLabel {
+				t.Errorf("expected %q, got %q", tt.wantLabel, matches[0].Label())
+			}
+			if matches[0].TimeZone != tt.wantZone {
+				t.Errorf("expected zone %s, got %s", tt.wantZone, matches[0].TimeZone)
+			}
+		})
+	}
+}
+
+func TestCountry(t *testing.T) {
+	g := Default()
+
+	tests := []struct {
+		query       string
+		wantName    string
+		wantCapital string
+		wantZones   int
+	}{
+		{query: "Japan", wantName: "Japan", wantCapital: "Tokyo", wantZones: 1},
+		{query: "uk", wantName: "United Kingdom", wantCapital: "London", wantZones: 1},
+		{query: "Türkiye", wantName: "Turkey", wantCapital: "Ankara", wantZones: 1},
+		{query: "USA", wantName: "United States", wantCapital: "Washington", wantZones: 11},
+		{query: "Spain", wantName: "Spain", wantCapital: "Madrid", wantZones: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, ok := g.Country(tt.query)
+			if !ok {
+				t.Fatal("expected a country")
+			}
+			if c.Name != tt.wantName || c.Capital.Name != tt.wantCapital || len(c.TimeZones) != tt.wantZones {
+				t.Errorf("unexpected country %s, capital %s, zones %v", c.Name, c.Capital.Name, c.TimeZones)
+			}
+		})
+	}
+
+	if _, ok := g.Country("Atlantis"); ok {
+		t.Error("expected no match for an unknown country")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	g := Default()
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+		maxKm    float64
+	}{
+		{name: "Sagrada Família", lat: 41.4036, lon: 2.1744, want: "Barcelona", maxKm: 5},
+		{name: "Ubud", lat: -8.5069, lon: 115.2625, want: "Denpasar", maxKm: 25},
+		{name: "Round Rock", lat: 30.5083, lon: -97.6789, want: "Austin", maxKm: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, km := g.Nearest(tt.lat, tt.lon)
+			if p.Name != tt.want || km > tt.maxKm {
+				t.Errorf("expected %s within %.0f km, got %s at %.1f km", tt.want, tt.maxKm, p.Name, km)
+			}
+		})
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lon float64
+		ok       bool
+	}{
+		{in: "48.8567,2.3508", lat: 48.8567, lon: 2.3508, ok: true},
+		{in: " -33.87 , 151.21 ", lat: -33.87, lon: 151.21, ok: true},
+		{in: "Paris, France"},
+		{in: "91,0"},
+		{in: "0,181"},
+		{in: "48.8567"},
+	}
+
+	for _, tt := range tests {
+		lat, lon, ok := ParseCoordinates(tt.in)
+		if ok != tt.ok || lat != tt.lat || lon != tt.lon {
+			t.Errorf("ParseCoordinates(%q) = %v, %v, %v", tt.in, lat, lon, ok)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	countries := "code,name,aliases,capital\nFR,France,,Paris\n"
+	header := "name,aliases,region,region_code,country_code,lat,lon,timezone,population\n"
+
+	tests := []struct {
+		name      string
+		places    string
+		countries string
+		wantErr   string
+	}{
+		{name: "unknown country", places: header + "Paris,,,,XX,48.85,2.35,Europe/Paris,11000\n", countries: countries, wantErr: "unknown country code"},
+		{name: "bad latitude", places: header + "Paris,,,,FR,north,2.35,Europe/Paris,11000\n", countries: countries, wantErr: "invalid latitude"},
+		{name: "missing capital", places: header + "Lyon,,,,FR,45.76,4.83,Europe/Paris,2300\n", countries: countries, wantErr: "capital"},
+		{name: "wrong column count", places: header + "Paris,FR\n", countries: countries, wantErr: "places"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(strings.NewReader(tt.places), strings.NewReader(tt.countries))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
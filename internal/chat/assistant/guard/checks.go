@@ -0,0 +1,97 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// Policies of the built-in checks, returned as the code of their violations
+const (
+	PolicyMessageSize = "message_size"
+	PolicyBlocklist   = "blocklist"
+	PolicyModeration  = "moderation"
+	PolicyInjection   = "prompt_injection"
+)
+
+// MaxSize rejects texts longer than size bytes
+func MaxSize(size int) Check {
+	return CheckFunc(func(ctx context.Context, text string) error {
+		if len(text) <= size {
+			return nil
+		}
+		return &model.PolicyViolation{
+			Policy: PolicyMessageSize,
+			Reason: fmt.Sprintf("it is %d bytes long, at most %d are allowed", len(text), size),
+		}
+	})
+}
+
+// Blocklist rejects texts containing one of the terms as whole words, ignoring
+// case. It returns nil when no term is given.
+func Blocklist(terms ...string) Check {
+	var quoted []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			// Whitespace inside a term matches any run of it
+			quoted = append(quoted, strings.Join(strings.Fields(regexp.QuoteMeta(term)), `\s+`))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	re := regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])(` + strings.Join(quoted, "|") + `)(?:[^\pL\pN_]|$)`)
+	return CheckFunc(func(ctx context.Context, text string) error {
+		if !re.MatchString(text) {
+			return nil
+		}
+		// The term isn't repeated, so the error doesn't spread the blocklist
+		return &model.PolicyViolation{Policy: PolicyBlocklist, Reason: "contains a blocked term"}
+	})
+}
+
+// Moderation rejects texts the moderation model of cli flags, such as
+// harassment, hate or violence
+func Moderation(cli openai.Client, moderationModel string) Check {
+	return CheckFunc(func(ctx context.Context, text string) error {
+		resp, err := cli.Moderations.New(ctx, openai.ModerationNewParams{
+			Input: openai.ModerationNewParamsInputUnion{OfString: openai.String(text)},
+			Model: openai.ModerationModel(moderationModel),
+		})
+		if err != nil {
+			return fmt.Errorf("moderation failed: %w", err)
+		}
+
+		var flagged []string
+		for _, result := range resp.Results {
+			if !result.Flagged {
+				continue
+			}
+			var categories map[string]bool
+			if err := json.Unmarshal([]byte(result.Categories.RawJSON()), &categories); err != nil {
+				return fmt.Errorf("invalid moderation categories: %w", err)
+			}
+			for category, on := range categories {
+				if on && !slices.Contains(flagged, category) {
+					flagged = append(flagged, category)
+				}
+			}
+			if len(flagged) == 0 {
+				flagged = append(flagged, "unspecified")
+			}
+		}
+		if len(flagged) == 0 {
+			return nil
+		}
+
+		slices.Sort(flagged)
+		return &model.PolicyViolation{Policy: PolicyModeration, Reason: "flagged for " + strings.Join(flagged, ", ")}
+	})
+}
@@ -0,0 +1,229 @@
+// Package guard screens what goes in and out of the assistant. User messages
+// and replies pass through checks that reject policy violations, and
+// instruction-like text in tool results is quarantined before the model reads
+// it, so data fetched from calendars or weather APIs can't steer the assistant.
+package guard
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultMaxMessageSize bounds user messages unless configured, in bytes
+const DefaultMaxMessageSize = 32 << 10
+
+// excerptLength bounds the text logged with a violation, in bytes
+const excerptLength = 200
+
+// Check screens a text
+type Check interface {
+	// Check returns a *model.PolicyViolation when text breaks the policy, or
+	// another error when it can't tell
+	Check(ctx context.Context, text string) error
+}
+
+// CheckFunc adapts a function to a Check
+type CheckFunc func(ctx context.Context, text string) error
+
+func (f CheckFunc) Check(ctx context.Context, text string) error {
+	return f(ctx, text)
+}
+
+// Guard runs the checks of user messages and replies and quarantines tool
+// results. A nil Guard lets everything through.
+type Guard struct {
+	input      []Check
+	output     []Check
+	redactor   *pii.Redactor
+	violations metric.Int64Counter
+}
+
+// Option configures a Guard
+type Option func(*Guard)
+
+// WithInputChecks adds checks of user messages
+func WithInputChecks(checks ...Check) Option {
+	return func(g *Guard) {
+		g.input = append(g.input, checks...)
+	}
+}
+
+// WithOutputChecks adds checks of the assistant's replies
+func WithOutputChecks(checks ...Check) Option {
+	return func(g *Guard) {
+		g.output = append(g.output, checks...)
+	}
+}
+
+// WithRedactor masks the personal data r detects in the excerpts logged with
+// violations and quarantined text
+func WithRedactor(r *pii.Redactor) Option {
+	return func(g *Guard) {
+		g.redactor = r
+	}
+}
+
+// New creates a guard running the given checks
+func New(opts ...Option) *Guard {
+	g := &Guard{}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	var err error
+	if g.violations, err = otel.Meter("github.com/isabermoussa/personal-assistant-API").Int64Counter(
+		"assistant.guard.violations",
+		metric.WithDescription("Messages and replies rejected and tool results quarantined by guardrails"),
+		metric.WithUnit("{violation}"),
+	); err != nil {
+		slog.Warn("Failed to create guardrail violation counter", "error", err)
+	}
+
+	return g
+}
+
+// NewFromEnv creates the deployment's guard. User messages are limited to
+// GUARD_MAX_MESSAGE_SIZE bytes (32 KiB by default). Messages and replies
+// containing a term of GUARD_BLOCKLIST (comma-separated) or GUARD_BLOCKLIST_FILE
+// (one per line) are rejected, and so are those OpenAI's moderation model flags
+// when GUARD_MODERATION is set to "true" or a moderation model name.
+// opts are applied last, e.g. WithRedactor.
+func NewFromEnv(opts ...Option) *Guard {
+	size := DefaultMaxMessageSize
+	if v := os.Getenv("GUARD_MAX_MESSAGE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			size = n
+		} else {
+			slog.Warn("Ignoring invalid GUARD_MAX_MESSAGE_SIZE", "value", v)
+		}
+	}
+
+	opts = append([]Option{WithInputChecks(MaxSize(size))}, opts...)
+
+	terms := strings.Split(os.Getenv("GUARD_BLOCKLIST"), ",")
+	if path := os.Getenv("GUARD_BLOCKLIST_FILE"); path != "" {
+		fileTerms, err := readLines(path)
+		if err != nil {
+			slog.Warn("Failed to read GUARD_BLOCKLIST_FILE", "path", path, "error", err)
+		}
+		terms = append(terms, fileTerms...)
+	}
+	if blocklist := Blocklist(terms...); blocklist != nil {
+		opts = append(opts, WithInputChecks(blocklist), WithOutputChecks(blocklist))
+	}
+
+	switch v := os.Getenv("GUARD_MODERATION"); v {
+	case "", "false":
+	default:
+		name := string(openai.ModerationModelOmniModerationLatest)
+		if v != "true" {
+			name = v
+		}
+		slog.Info("Moderating messages and replies", "model", name)
+		moderation := Moderation(openai.NewClient(), name)
+		opts = append(opts, WithInputChecks(moderation), WithOutputChecks(moderation))
+	}
+
+	return New(opts...)
+}
+
+// CheckInput screens a user message
+func (g *Guard) CheckInput(ctx context.Context, text string) error {
+	if g == nil {
+		return nil
+	}
+	return g.check(ctx, model.StageInput, g.input, text)
+}
+
+// CheckOutput screens a reply of the assistant
+func (g *Guard) CheckOutput(ctx context.Context, text string) error {
+	if g == nil {
+		return nil
+	}
+	return g.check(ctx, model.StageOutput, g.output, text)
+}
+
+// check runs checks in turn and returns the first violation, logged for
+// review. Checks that can't tell, e.g. with the moderation API down, are
+// logged and let the text through.
+func (g *Guard) check(ctx context.Context, stage string, checks []Check, text string) error {
+	for _, c := range checks {
+		err := c.Check(ctx, text)
+		if err == nil {
+			continue
+		}
+
+		var v *model.PolicyViolation
+		if !errors.As(err, &v) {
+			slog.WarnContext(ctx, "Guardrail check failed", "stage", stage, "error", err)
+			continue
+		}
+
+		v.Stage = stage
+		slog.WarnContext(ctx, "Guardrail violation", "stage", stage, "policy", v.Policy, "reason", v.Reason, "excerpt", g.excerpt(text))
+		g.count(ctx, stage, v.Policy)
+		return v
+	}
+	return nil
+}
+
+// Quarantine replaces instruction-like text in the result of a tool with a
+// notice, logging what was removed for review
+func (g *Guard) Quarantine(ctx context.Context, tool, result string) string {
+	if g == nil {
+		return result
+	}
+
+	quarantined, removed := Quarantine(result)
+	if len(removed) > 0 {
+		slog.WarnContext(ctx, "Quarantined instruction-like text in a tool result", "tool", tool, "lines", len(removed), "excerpt", g.excerpt(strings.Join(removed, "\n")))
+		g.count(ctx, "tool", PolicyInjection)
+	}
+	return quarantined
+}
+
+func (g *Guard) count(ctx context.Context, stage, policy string) {
+	if g.violations != nil {
+		g.violations.Add(ctx, 1, metric.WithAttributes(attribute.String("stage", stage), attribute.String("policy", policy)))
+	}
+}
+
+// excerpt shortens text for the logs, with its personal data masked
+func (g *Guard) excerpt(text string) string {
+	text = g.redactor.Mask(text)
+	if len(text) <= excerptLength {
+		return text
+	}
+	return strings.ToValidUTF8(text[:excerptLength], "") + "…"
+}
+
+// readLines returns the non-empty lines of a file, skipping # comments
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,169 @@
+package holidays
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Holiday is a single holiday of a calendar
+type Holiday struct {
+	Date     time.Time
+	Name     string
+	Calendar string // name of the source calendar, e.g. "Spain - Catalonia"
+}
+
+// Calendars loads holiday calendars from their sources and caches them.
+// A calendar is reloaded after its refresh interval; if reloading fails
+// the previous copy keeps being served.
+type Calendars struct {
+	sources    []Source
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]calendarEntry
+}
+
+type calendarEntry struct {
+	holidays []Holiday
+	loaded   time.Time
+}
+
+// NewCalendars creates a calendar cache for the given sources
+func NewCalendars(sources []Source) *Calendars {
+	return &Calendars{
+		sources: sources,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		now:     time.Now,
+		entries: make(map[string]calendarEntry),
+	}
+}
+
+// NewCalendarsFromEnv creates a calendar cache for the sources configured in the environment,
+// falling back to the default sources when the configuration can't be read
+func NewCalendarsFromEnv() *Calendars {
+	sources, err := SourcesFromEnv()
+	if err != nil {
+		slog.Error("Failed to load holiday sources, using defaults", "error", err)
+		sources = DefaultSources
+	}
+	return NewCalendars(sources)
+}
+
+// Sources returns the configured calendars
+func (c *Calendars) Sources() []Source {
+	return c.sources
+}
+
+// Holidays returns the holidays of the calendar for country and region, sorted by date
+func (c *Calendars) Holidays(ctx context.Context, country, region string) ([]Holiday, error) {
+	src, err := Find(c.sources, country, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.load(ctx, src)
+}
+
+func (c *Calendars) load(ctx context.Context, src Source) ([]Holiday, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[src.URL]
+	c.mu.Unlock()
+
+	if ok && c.now().Sub(entry.loaded) < src.refresh() {
+		return entry.holidays, nil
+	}
+
+	holidays, err := c.fetch(ctx, src)
+	if err != nil {
+		if ok {
+			slog.WarnContext(ctx, "Failed to refresh holiday calendar, serving cached copy",
+				"calendar", src.Name(), "loaded", entry.loaded, "error", err)
+			return entry.holidays, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[src.URL] = calendarEntry{holidays: holidays, loaded: c.now()}
+	c.mu.Unlock()
+
+	return holidays, nil
+}
+
+// fetch loads and parses the ICS file of a source
+func (c *Calendars) fetch(ctx context.Context, src Source) ([]Holiday, error) {
+	slog.InfoContext(ctx, "Loading calendar", "calendar", src.Name(), "link", src.URL)
+
+	body, err := c.open(ctx, src.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	cal, err := ics.ParseCalendar(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse calendar %s: %w", src.Name(), err)
+	}
+
+	var holidays []Holiday
+	for _, event := range cal.Events() {
+		date, err := event.GetAllDayStartAt()
+		if err != nil {
+			continue
+		}
+
+		var name string
+		if p := event.GetProperty(ics.ComponentPropertySummary); p != nil {
+			name = p.Value
+		}
+
+		holidays = append(holidays, Holiday{Date: date, Name: name, Calendar: src.Name()})
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays, nil
+}
+
+// open returns the contents of an http(s) or file:// link
+func (c *Calendars) open(ctx context.Context, link string) (io.ReadCloser, error) {
+	if path, ok := strings.CutPrefix(link, "file://"); ok {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open calendar: %w", err)
+		}
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("calendar %s returned status %d", link, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,101 @@
+package holidays
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// bundled returns a source for one of the ICS files shipped in data/holidays
+func bundled(country, file string) Source {
+	return Source{Country: country, URL: "file://../../../../data/holidays/" + file}
+}
+
+func TestCalendars_BundledFiles(t *testing.T) {
+	tests := []struct {
+		source    Source
+		wantCount int
+		wantFirst string
+		wantLast  string
+	}{
+		{source: bundled("Spain", "es-catalonia.ics"), wantCount: 13, wantFirst: "New Year's Day", wantLast: "St Stephen's Day"},
+		{source: bundled("United Kingdom", "gb-england.ics"), wantCount: 8, wantFirst: "New Year's Day", wantLast: "Boxing Day (substitute day)"},
+		{source: bundled("United States", "us.ics"), wantCount: 11, wantFirst: "New Year's Day", wantLast: "Christmas Day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source.Country, func(t *testing.T) {
+			list, err := NewCalendars([]Source{tt.source}).Holidays(context.Background(), "", "")
+			if err != nil {
+				t.Fatalf("Holidays failed: %v", err)
+			}
+			if len(list) != tt.wantCount {
+				t.Fatalf("expected %d holidays, got %d", tt.wantCount, len(list))
+			}
+			if list[0].Name != tt.wantFirst || list[len(list)-1].Name != tt.wantLast {
+				t.Errorf("unexpected order: first %q, last %q", list[0].Name, list[len(list)-1].Name)
+			}
+			if list[0].Calendar != tt.source.Country {
+				t.Errorf("expected calendar name %q, got %q", tt.source.Country, list[0].Calendar)
+			}
+		})
+	}
+}
+
+func TestCalendars_Cache(t *testing.T) {
+	ics, err := os.ReadFile("../../../../data/holidays/us.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		calls  int
+		broken bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if broken {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(ics)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := NewCalendars([]Source{{Country: "United States", Code: "US", URL: srv.URL, Refresh: "1h"}})
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := c.Holidays(ctx, "US", ""); err != nil {
+		t.Fatalf("Holidays failed: %v", err)
+	}
+	if _, err := c.Holidays(ctx, "US", ""); err != nil {
+		t.Fatalf("Holidays failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected cached calendar within refresh interval, got %d fetches", calls)
+	}
+
+	// After the refresh interval the calendar is reloaded; a failing source serves the cached copy
+	now = now.Add(2 * time.Hour)
+	broken = true
+
+	list, err := c.Holidays(ctx, "US", "")
+	if err != nil {
+		t.Fatalf("expected stale calendar on refresh failure, got %v", err)
+	}
+	if calls != 2 || len(list) != 11 {
+		t.Errorf("unexpected refresh: %d fetches, %d holidays", calls, len(list))
+	}
+
+	t.Run("fails without a cached copy", func(t *testing.T) {
+		fresh := NewCalendars([]Source{{Country: "United States", URL: srv.URL}})
+		if _, err := fresh.Holidays(ctx, "", ""); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
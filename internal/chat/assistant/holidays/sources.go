@@ -0,0 +1,172 @@
+// Package holidays loads bank and public holiday calendars (ICS) for a table of
+// countries and regions, and caches the parsed calendars.
+package holidays
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNoCalendar is returned when no calendar matches a country or region
+var ErrNoCalendar = errors.New("no holiday calendar")
+
+// DefaultRefresh is how often a calendar is reloaded when its source doesn't say
+const DefaultRefresh = 24 * time.Hour
+
+// Source is a holiday calendar for a country or one of its regions
+type Source struct {
+	// Country is the country name, e.g. "Spain"
+	Country string `json:"country"`
+
+	// Code is the ISO 3166-1 alpha-2 country code, e.g. "ES"
+	Code string `json:"code"`
+
+	// Region is the region name, empty for national calendars
+	Region string `json:"region,omitempty"`
+
+	// URL is an http(s) link or a file:// path to an ICS file;
+	// relative file paths are resolved from the working directory
+	URL string `json:"url"`
+
+	// Refresh is how often the calendar is reloaded, e.g. "12h" (default 24h)
+	Refresh string `json:"refresh,omitempty"`
+
+	// Weekend overrides the country's weekend days, e.g. ["friday", "saturday"]
+	Weekend []string `json:"weekend,omitempty"`
+}
+
+// Name identifies the calendar in tool output, e.g. "Spain - Catalonia"
+func (s Source) Name() string {
+	if s.Region == "" {
+		return s.Country
+	}
+	return s.Country + " - " + s.Region
+}
+
+func (s Source) refresh() time.Duration {
+	d, err := time.ParseDuration(s.Refresh)
+	if err != nil || d <= 0 {
+		return DefaultRefresh
+	}
+	return d
+}
+
+// DefaultSources are the calendars available without configuration.
+// The first one is used when no country is given.
+var DefaultSources = []Source{
+	{Country: "Spain", Code: "ES", Region: "Catalonia", URL: "https://www.officeholidays.com/ics/spain/catalonia"},
+	{Country: "Spain", Code: "ES", Region: "Madrid", URL: "https://www.officeholidays.com/ics/spain/madrid"},
+	{Country: "Spain", Code: "ES", URL: "https://www.officeholidays.com/ics/spain"},
+	{Country: "United Kingdom", Code: "GB", Region: "England", URL: "https://www.officeholidays.com/ics/united-kingdom/england"},
+	{Country: "United Kingdom", Code: "GB", Region: "Scotland", URL: "https://www.officeholidays.com/ics/united-kingdom/scotland"},
+	{Country: "United States", Code: "US", URL: "https://www.officeholidays.com/ics/usa"},
+	{Country: "France", Code: "FR", URL: "https://www.officeholidays.com/ics/france"},
+	{Country: "Germany", Code: "DE", URL: "https://www.officeholidays.com/ics/germany"},
+	{Country: "Italy", Code: "IT", URL: "https://www.officeholidays.com/ics/italy"},
+	{Country: "Portugal", Code: "PT", URL: "https://www.officeholidays.com/ics/portugal"},
+}
+
+// SourcesFromEnv returns the calendar table.
+// HOLIDAY_SOURCES_FILE points to a JSON array of sources replacing the defaults
+// (see data/holidays/sources.json for an offline table).
+// HOLIDAY_CALENDAR_LINK overrides the URL of the default calendar.
+func SourcesFromEnv() ([]Source, error) {
+	sources := DefaultSources
+
+	if path := os.Getenv("HOLIDAY_SOURCES_FILE"); path != "" {
+		var err error
+		if sources, err = LoadSources(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if link := os.Getenv("HOLIDAY_CALENDAR_LINK"); link != "" {
+		sources = append([]Source(nil), sources...)
+		sources[0].URL = link
+	}
+
+	return sources, nil
+}
+
+// LoadSources reads a JSON array of sources
+func LoadSources(path string) ([]Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday sources: %w", err)
+	}
+
+	var sources []Source
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse holiday sources %s: %w", path, err)
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no holiday sources in %s", path)
+	}
+
+	for i, s := range sources {
+		if s.Country == "" || s.URL == "" {
+			return nil, fmt.Errorf("holiday source %d in %s needs a country and a url", i, path)
+		}
+		if s.Refresh != "" {
+			if _, err := time.ParseDuration(s.Refresh); err != nil {
+				return nil, fmt.Errorf("holiday source %q: invalid refresh: %w", s.Name(), err)
+			}
+		}
+		if _, err := parseWeekdays(s.Weekend); err != nil {
+			return nil, fmt.Errorf("holiday source %q: invalid weekend: %w", s.Name(), err)
+		}
+	}
+
+	return sources, nil
+}
+
+// Find returns the calendar for a country (name or ISO code) and optional region.
+// Without a region the national calendar is preferred, then the first regional one.
+// Without a country the default (first) calendar is returned.
+func Find(sources []Source, country, region string) (Source, error) {
+	if len(sources) == 0 {
+		return Source{}, fmt.Errorf("%w: none configured", ErrNoCalendar)
+	}
+
+	country, region = strings.TrimSpace(country), strings.TrimSpace(region)
+	if country == "" && region == "" {
+		return sources[0], nil
+	}
+
+	var candidates []Source
+	for _, s := range sources {
+		if country == "" || strings.EqualFold(s.Country, country) || strings.EqualFold(s.Code, country) {
+			candidates = append(candidates, s)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Source{}, fmt.Errorf("%w for country %q, available: %s", ErrNoCalendar, country, Available(sources))
+	}
+
+	for _, s := range candidates {
+		if strings.EqualFold(s.Region, region) {
+			return s, nil
+		}
+	}
+
+	if region != "" {
+		return Source{}, fmt.Errorf("%w for region %q, available: %s", ErrNoCalendar, region, Available(candidates))
+	}
+
+	return candidates[0], nil
+}
+
+// Available lists the calendar names, e.g. "Spain - Catalonia, France"
+func Available(sources []Source) string {
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, ", ")
+}
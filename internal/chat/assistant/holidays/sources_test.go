@@ -0,0 +1,120 @@
+package holidays
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	sources := []Source{
+		{Country: "Spain", Code: "ES", Region: "Catalonia", URL: "a"},
+		{Country: "Spain", Code: "ES", Region: "Madrid", URL: "b"},
+		{Country: "Spain", Code: "ES", URL: "c"},
+		{Country: "United Kingdom", Code: "GB", Region: "England", URL: "d"},
+	}
+
+	tests := []struct {
+		name    string
+		country string
+		region  string
+		wantURL string
+		wantErr string
+	}{
+		{name: "default calendar", wantURL: "a"},
+		{name: "country prefers national calendar", country: "spain", wantURL: "c"},
+		{name: "country code and region", country: "ES", region: "madrid", wantURL: "b"},
+		{name: "region without country", region: "England", wantURL: "d"},
+		{name: "falls back to first regional calendar", country: "GB", wantURL: "d"},
+		{name: "unknown country", country: "Atlantis", wantErr: "available: Spain - Catalonia"},
+		{name: "unknown region", country: "Spain", region: "Galicia", wantErr: "region \"Galicia\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(sources, tt.country, tt.region)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.URL != tt.wantURL {
+				t.Errorf("got calendar %q (%s), want %s", got.Name(), got.URL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestSourcesFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("HOLIDAY_SOURCES_FILE", "")
+		t.Setenv("HOLIDAY_CALENDAR_LINK", "")
+
+		sources, err := SourcesFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sources) != len(DefaultSources) || sources[0].Name() != "Spain - Catalonia" {
+			t.Errorf("unexpected sources: %v", Available(sources))
+		}
+	})
+
+	t.Run("link overrides the default calendar without changing the defaults", func(t *testing.T) {
+		t.Setenv("HOLIDAY_SOURCES_FILE", "")
+		t.Setenv("HOLIDAY_CALENDAR_LINK", "https://example.com/custom.ics")
+
+		sources, err := SourcesFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sources[0].URL != "https://example.com/custom.ics" {
+			t.Errorf("expected overridden link, got %s", sources[0].URL)
+		}
+		if DefaultSources[0].URL == sources[0].URL {
+			t.Error("expected DefaultSources to be left untouched")
+		}
+	})
+
+	t.Run("bundled offline table", func(t *testing.T) {
+		t.Setenv("HOLIDAY_SOURCES_FILE", "../../../../data/holidays/sources.json")
+		t.Setenv("HOLIDAY_CALENDAR_LINK", "")
+
+		sources, err := SourcesFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, s := range sources {
+			if !strings.HasPrefix(s.URL, "file://data/holidays/") {
+				t.Errorf("expected bundled file source, got %s", s.URL)
+			}
+		}
+	})
+}
+
+func TestLoadSources_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":           `{`,
+		"empty table":        `[]`,
+		"missing url":        `[{"country": "Spain"}]`,
+		"invalid refresh":    `[{"country": "Spain", "url": "file://x.ics", "refresh": "weekly"}]`,
+		"invalid weekend":    `[{"country": "Spain", "url": "file://x.ics", "weekend": ["caturday"]}]`,
+		"whole week weekend": `[{"country": "Spain", "url": "file://x.ics", "weekend": ["monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"]}]`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sources.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadSources(path); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package holidays
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// saturdaySunday is the weekend of countries not listed in weekends
+var saturdaySunday = []time.Weekday{time.Saturday, time.Sunday}
+
+// weekends lists countries whose weekend is not Saturday and Sunday
+var weekends = []struct {
+	code, name string
+	days       []time.Weekday
+}{
+	{"SA", "Saudi Arabia", []time.Weekday{time.Friday, time.Saturday}},
+	{"IL", "Israel", []time.Weekday{time.Friday, time.Saturday}},
+	{"EG", "Egypt", []time.Weekday{time.Friday, time.Saturday}},
+	{"QA", "Qatar", []time.Weekday{time.Friday, time.Saturday}},
+	{"KW", "Kuwait", []time.Weekday{time.Friday, time.Saturday}},
+	{"BH", "Bahrain", []time.Weekday{time.Friday, time.Saturday}},
+	{"OM", "Oman", []time.Weekday{time.Friday, time.Saturday}},
+	{"JO", "Jordan", []time.Weekday{time.Friday, time.Saturday}},
+	{"DZ", "Algeria", []time.Weekday{time.Friday, time.Saturday}},
+	{"IQ", "Iraq", []time.Weekday{time.Friday, time.Saturday}},
+	{"BD", "Bangladesh", []time.Weekday{time.Friday, time.Saturday}},
+	{"IR", "Iran", []time.Weekday{time.Friday}},
+}
+
+// Weekend returns the weekend days of a country (name or ISO code), Saturday and Sunday by default
+func Weekend(country string) []time.Weekday {
+	for _, w := range weekends {
+		if strings.EqualFold(w.code, country) || strings.EqualFold(w.name, country) {
+			return w.days
+		}
+	}
+	return saturdaySunday
+}
+
+// parseWeekdays parses weekday names like "friday". A weekend covering
+// the whole week is rejected, as no day could ever be a working day.
+func parseWeekdays(names []string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range names {
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(d.String(), strings.TrimSpace(name)) {
+				days = append(days, d)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+	}
+
+	distinct := slices.Clone(days)
+	slices.Sort(distinct)
+	if len(slices.Compact(distinct)) == 7 {
+		return nil, fmt.Errorf("weekend can't cover every day of the week")
+	}
+	return days, nil
+}
+
+// WorkCalendar tells working days apart from weekends and holidays.
+// Dates are compared by their calendar day only.
+type WorkCalendar struct {
+	// Name of the holiday calendar, or the country when there is none
+	Name    string
+	Weekend []time.Weekday
+
+	holidays map[string]Holiday
+	years    map[int]bool
+}
+
+// NewWorkCalendar creates a work calendar from a weekend and a list of holidays
+func NewWorkCalendar(name string, weekend []time.Weekday, list []Holiday) *WorkCalendar {
+	w := &WorkCalendar{
+		Name:     name,
+		Weekend:  weekend,
+		holidays: make(map[string]Holiday, len(list)),
+		years:    make(map[int]bool),
+	}
+	for _, h := range list {
+		w.holidays[h.Date.Format(time.DateOnly)] = h
+		w.years[h.Date.Year()] = true
+	}
+	return w
+}
+
+// WorkCalendar returns the work calendar for a country and region, with the
+// country's weekend (or the source's own) and the holidays of its calendar
+func (c *Calendars) WorkCalendar(ctx context.Context, country, region string) (*WorkCalendar, error) {
+	src, err := Find(c.sources, country, region)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.load(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	weekend := Weekend(src.Code)
+	if len(src.Weekend) > 0 {
+		// Validated by LoadSources
+		weekend, _ = parseWeekdays(src.Weekend)
+	}
+
+	return NewWorkCalendar(src.Name(), weekend, list), nil
+}
+
+// Holiday returns the holiday on day, if any
+func (w *WorkCalendar) Holiday(day time.Time) (Holiday, bool) {
+	h, ok := w.holidays[day.Format(time.DateOnly)]
+	return h, ok
+}
+
+// IsWorkingDay reports whether day is neither a weekend day nor a holiday
+func (w *WorkCalendar) IsWorkingDay(day time.Time) bool {
+	if slices.Contains(w.Weekend, day.Weekday()) {
+		return false
+	}
+	_, holiday := w.Holiday(day)
+	return !holiday
+}
+
+// Covers reports whether holiday data is available for the year of day
+func (w *WorkCalendar) Covers(day time.Time) bool {
+	return w.years[day.Year()]
+}
+
+// HasHolidays reports whether the calendar knows any holidays
+func (w *WorkCalendar) HasHolidays() bool {
+	return len(w.years) > 0
+}
+
+// Count returns the number of working days after start up to and including end,
+// so that Add(start, n) and Count(start, result) agree. It is negative when end is before start.
+// Holidays falling on otherwise working days in that range are returned as well.
+func (w *WorkCalendar) Count(start, end time.Time) (int, []Holiday) {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+
+	var (
+		n       int
+		skipped []Holiday
+	)
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if w.IsWorkingDay(day) {
+			n++
+		} else if h, ok := w.Holiday(day); ok && !slices.Contains(w.Weekend, day.Weekday()) {
+			skipped = append(skipped, h)
+		}
+	}
+
+	return sign * n, skipped
+}
+
+// Add moves n working days from start (backwards when n is negative) and
+// returns the resulting day with the holidays skipped on the way
+func (w *WorkCalendar) Add(start time.Time, n int) (time.Time, []Holiday) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	var skipped []Holiday
+	day := start
+	for n > 0 {
+		day = day.AddDate(0, 0, step)
+		if w.IsWorkingDay(day) {
+			n--
+		} else if h, ok := w.Holiday(day); ok && !slices.Contains(w.Weekend, day.Weekday()) {
+			skipped = append(skipped, h)
+		}
+	}
+
+	return day, skipped
+}
@@ -0,0 +1,100 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestWorkCalendar(t *testing.T) {
+	easter := NewWorkCalendar("Spain - Catalonia", Weekend("ES"), []Holiday{
+		{Date: date("2026-04-03"), Name: "Good Friday"},
+		{Date: date("2026-04-06"), Name: "Easter Monday"},
+		{Date: date("2026-04-11"), Name: "Saturday holiday"},
+	})
+	saudi := NewWorkCalendar("Saudi Arabia", Weekend("Saudi Arabia"), nil)
+
+	tests := []struct {
+		name        string
+		cal         *WorkCalendar
+		start, end  string
+		wantCount   int
+		wantSkipped int
+	}{
+		{name: "skips weekend and holidays", cal: easter, start: "2026-03-30", end: "2026-04-10", wantCount: 7, wantSkipped: 2},
+		{name: "start day is not counted", cal: easter, start: "2026-03-30", end: "2026-03-31", wantCount: 1},
+		{name: "same day", cal: easter, start: "2026-03-30", end: "2026-03-30", wantCount: 0},
+		{name: "holidays on weekends are not reported", cal: easter, start: "2026-04-10", end: "2026-04-13", wantCount: 1},
+		{name: "reverse range is negative", cal: easter, start: "2026-04-10", end: "2026-03-30", wantCount: -7, wantSkipped: 2},
+		{name: "friday-saturday weekend", cal: saudi, start: "2026-01-01", end: "2026-01-08", wantCount: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, skipped := tt.cal.Count(date(tt.start), date(tt.end))
+			if n != tt.wantCount || len(skipped) != tt.wantSkipped {
+				t.Errorf("Count() = %d (%d skipped), want %d (%d skipped)", n, len(skipped), tt.wantCount, tt.wantSkipped)
+			}
+
+			// Adding the count back lands on the last working day of the range
+			if n != 0 {
+				got, _ := tt.cal.Add(date(tt.start), n)
+				if gotN, _ := tt.cal.Count(date(tt.start), got); gotN != n {
+					t.Errorf("Add(%s, %d) = %s, which counts as %d", tt.start, n, got.Format(time.DateOnly), gotN)
+				}
+			}
+		})
+	}
+
+	t.Run("add", func(t *testing.T) {
+		got, skipped := easter.Add(date("2026-03-30"), 7)
+		if got.Format(time.DateOnly) != "2026-04-10" || len(skipped) != 2 {
+			t.Errorf("Add forward = %s (%d skipped)", got.Format(time.DateOnly), len(skipped))
+		}
+
+		got, _ = easter.Add(date("2026-04-10"), -7)
+		if got.Format(time.DateOnly) != "2026-03-30" {
+			t.Errorf("Add backward = %s", got.Format(time.DateOnly))
+		}
+	})
+
+	t.Run("coverage", func(t *testing.T) {
+		if !easter.Covers(date("2026-12-31")) || easter.Covers(date("2027-01-01")) {
+			t.Error("expected holiday data to cover 2026 only")
+		}
+		if saudi.HasHolidays() {
+			t.Error("expected weekend-only calendar to have no holidays")
+		}
+	})
+}
+
+func TestWeekend(t *testing.T) {
+	tests := map[string][]time.Weekday{
+		"ES":           {time.Saturday, time.Sunday},
+		"":             {time.Saturday, time.Sunday},
+		"sa":           {time.Friday, time.Saturday},
+		"Israel":       {time.Friday, time.Saturday},
+		"IR":           {time.Friday},
+		"Saudi Arabia": {time.Friday, time.Saturday},
+	}
+
+	for country, want := range tests {
+		got := Weekend(country)
+		if len(got) != len(want) {
+			t.Errorf("Weekend(%q) = %v, want %v", country, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Weekend(%q) = %v, want %v", country, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+// RetryPolicy bounds how calls to the language model are retried
+type RetryPolicy struct {
+	// Attempts is the number of calls made to each model, the first included
+	Attempts int
+
+	// BaseDelay is the wait before the first retry, doubled for each one after
+	BaseDelay time.Duration
+
+	// MaxDelay bounds the wait between attempts. A Retry-After longer than it
+	// moves on to the fallback models instead of waiting.
+	MaxDelay time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	Attempts:  3,
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  20 * time.Second,
+}
+
+// Fallback is a model tried when the ones before it are down, possibly served
+// by another OpenAI-compatible provider
+type Fallback struct {
+	Client openai.Client
+
+	// Model replaces the model of the request, which is kept when empty
+	Model string
+}
+
+// WithRetryPolicy sets how calls to the language model are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(a *Assistant) {
+		a.retryPolicy = policy
+	}
+}
+
+// WithFallbacks sets the models tried in order when the primary one is down
+func WithFallbacks(fallbacks ...Fallback) Option {
+	return func(a *Assistant) {
+		a.fallbacks = fallbacks
+	}
+}
+
+// FallbacksFromEnv reads the fallback model from the environment:
+// OPENAI_FALLBACK_MODEL names it, and OPENAI_FALLBACK_BASE_URL and
+// OPENAI_FALLBACK_API_KEY point to another OpenAI-compatible provider serving it
+func FallbacksFromEnv() []Fallback {
+	name, baseURL := os.Getenv("OPENAI_FALLBACK_MODEL"), os.Getenv("OPENAI_FALLBACK_BASE_URL")
+	if name == "" && baseURL == "" {
+		return nil
+	}
+
+	var opts []option.RequestOption
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	if key := os.Getenv("OPENAI_FALLBACK_API_KEY"); key != "" {
+		opts = append(opts, option.WithAPIKey(key))
+	}
+
+	slog.Info("Using a fallback language model", "model", name, "base_url", baseURL)
+	return []Fallback{{Client: openai.NewClient(opts...), Model: name}}
+}
+
+// complete calls the chat completions API, or answers from the response cache
+// when the same request was answered before. Errors that may pass are retried
+// with backoff, then the fallback models are tried in turn. Failures are
+// wrapped in the model package's errors.
+func (a *Assistant) complete(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	if a.cache == nil {
+		return a.call(ctx, params)
+	}
+
+	// The model, messages, tools and sampling settings all make up the key
+	key, err := cache.Key(cache.KindCompletion, params)
+	if err != nil {
+		return a.call(ctx, params)
+	}
+
+	if raw, ok := a.cache.Get(ctx, cache.KindCompletion, key); ok {
+		var resp openai.ChatCompletion
+		if err := json.Unmarshal(raw, &resp); err == nil {
+			return &resp, nil
+		}
+	}
+
+	resp, err := a.call(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	a.cache.Set(ctx, cache.KindCompletion, key, []byte(resp.RawJSON()))
+	return resp, nil
+}
+
+// call calls the chat completions API, retrying and falling back
+func (a *Assistant) call(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	targets := append([]Fallback{{Client: a.cli}}, a.fallbacks...)
+
+	var err error
+	for i, target := range targets {
+		p := params
+		if target.Model != "" {
+			p.Model = target.Model
+		}
+		if i > 0 {
+			slog.WarnContext(ctx, "Language model unavailable, falling back", "model", p.Model, "error", err)
+		}
+
+		var resp *openai.ChatCompletion
+		resp, err = a.retry(ctx, target.Client, p)
+		if err == nil {
+			return resp, nil
+		}
+
+		// Another model won't accept a request this one rejected, nor help a caller that gave up
+		if ctx.Err() != nil || errors.Is(modelError(err), model.ErrModelRejected) {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	slog.ErrorContext(ctx, "Language model call failed", "model", params.Model, "error", err)
+	return nil, fmt.Errorf("%w: %s", modelError(err), errorMessage(err))
+}
+
+// retry calls one model until it answers, the error can't pass or the
+// attempts of the retry policy are used up
+func (a *Assistant) retry(ctx context.Context, cli openai.Client, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	for attempt := 1; ; attempt++ {
+		// The client's own retries would multiply with these
+		resp, err := cli.Chat.Completions.New(ctx, params, option.WithMaxRetries(0))
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt >= a.retryPolicy.Attempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		delay, ok := a.retryPolicy.delay(attempt, err)
+		if !ok {
+			return nil, err
+		}
+
+		slog.WarnContext(ctx, "Language model call failed, retrying", "model", params.Model, "attempt", attempt, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// delay returns the wait before the retry following attempt: the Retry-After
+// of the error when it has one, exponential backoff with jitter otherwise. It
+// reports false when the provider asks to wait longer than MaxDelay.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	if after, ok := retryAfter(err); ok {
+		return after, after <= p.MaxDelay
+	}
+
+	backoff := min(p.BaseDelay<<(attempt-1), p.MaxDelay)
+	if backoff <= 0 {
+		return 0, true
+	}
+	// Half fixed, half random, so clients failing together don't retry together
+	return backoff/2 + rand.N(backoff/2+1), true
+}
+
+// retryable reports whether a failed call may succeed if repeated: timeouts,
+// conflicts, rate limits, server errors and failed connections
+func retryable(err error) bool {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		// An exhausted quota doesn't come back in seconds
+		return apiErr.Code != "insufficient_quota"
+	case apiErr.StatusCode == http.StatusRequestTimeout, apiErr.StatusCode == http.StatusConflict:
+		return true
+	default:
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+// retryAfter returns the wait the provider asked for, from the retry-after-ms
+// or Retry-After header
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return 0, false
+	}
+
+	if ms, err := strconv.ParseFloat(apiErr.Response.Header.Get("Retry-After-Ms"), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+
+	header := apiErr.Response.Header.Get("Retry-After")
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// modelError returns the model package's error matching a failed call
+func modelError(err error) error {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return model.ErrModelUnavailable
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return model.ErrModelRateLimited
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return model.ErrModelRejected
+	default:
+		return model.ErrModelUnavailable
+	}
+}
+
+// errorMessage returns the provider's explanation of a failed call, without
+// the request details the error also holds
+func errorMessage(err error) string {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		return apiErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,191 @@
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+// failure is an error answer of flakyOpenAI
+type failure struct {
+	status     int
+	code       string
+	retryAfter string
+}
+
+// flakyOpenAI answers chat completion requests in turn with the given
+// failures, then with "Hello!", and records the requests
+func flakyOpenAI(t *testing.T, failures ...failure) (openai.Client, *[]chatRequest) {
+	t.Helper()
+
+	var requests []chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if n := len(requests); n <= len(failures) {
+			f := failures[n-1]
+			if f.retryAfter != "" {
+				w.Header().Set("Retry-After", f.retryAfter)
+			}
+			w.WriteHeader(f.status)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]any{"message": http.StatusText(f.status), "type": "error", "code": f.code},
+			})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]any{{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       map[string]any{"role": "assistant", "content": "Hello!"},
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return openai.NewClient(option.WithBaseURL(srv.URL), option.WithAPIKey("test")), &requests
+}
+
+func TestAssistant_Complete(t *testing.T) {
+	ctx := context.Background()
+	fast := WithRetryPolicy(RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	params := openai.ChatCompletionNewParams{
+		Model:    openai.ChatModelGPT4_1,
+		Messages: []openai.ChatCompletionMessageParamUnion{openai.UserMessage("Hi")},
+	}
+
+	t.Run("retries server errors and rate limits", func(t *testing.T) {
+		cli, requests := flakyOpenAI(t, failure{status: 503}, failure{status: 429, retryAfter: "0"})
+		a := New(WithOpenAIClient(cli), fast)
+
+		resp, err := a.complete(ctx, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Choices[0].Message.Content != "Hello!" || len(*requests) != 3 {
+			t.Errorf("expected an answer after 3 requests, got %q after %d", resp.Choices[0].Message.Content, len(*requests))
+		}
+	})
+
+	t.Run("falls back when the model stays down", func(t *testing.T) {
+		cli, requests := flakyOpenAI(t, failure{status: 500}, failure{status: 502}, failure{status: 503})
+		fallback, fallbackRequests := flakyOpenAI(t)
+		a := New(WithOpenAIClient(cli), fast, WithFallbacks(Fallback{Client: fallback, Model: "gpt-4.1-mini"}))
+
+		if _, err := a.complete(ctx, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*requests) != 3 || len(*fallbackRequests) != 1 || (*fallbackRequests)[0].Model != "gpt-4.1-mini" {
+			t.Errorf("expected 3 requests then one to the fallback model, got %d and %+v", len(*requests), *fallbackRequests)
+		}
+	})
+
+	t.Run("falls back rather than wait longer than allowed", func(t *testing.T) {
+		cli, requests := flakyOpenAI(t, failure{status: 429, retryAfter: "60"})
+		fallback, fallbackRequests := flakyOpenAI(t)
+		a := New(WithOpenAIClient(cli), fast, WithFallbacks(Fallback{Client: fallback}))
+
+		if _, err := a.complete(ctx, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*requests) != 1 || len(*fallbackRequests) != 1 || (*fallbackRequests)[0].Model != "gpt-4.1" {
+			t.Errorf("expected one request to each provider with the same model, got %d and %+v", len(*requests), *fallbackRequests)
+		}
+	})
+
+	t.Run("maps failures to model errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			failures []failure
+			requests int
+			want     error
+		}{
+			{"rejected requests aren't retried", []failure{{status: 400, code: "context_length_exceeded"}}, 1, model.ErrModelRejected},
+			{"exhausted quota isn't retried", []failure{{status: 429, code: "insufficient_quota"}}, 1, model.ErrModelRateLimited},
+			{"rate limited until the last attempt", []failure{{status: 429}, {status: 429}, {status: 429}}, 3, model.ErrModelRateLimited},
+			{"down until the last attempt", []failure{{status: 500}, {status: 500}, {status: 503}}, 3, model.ErrModelUnavailable},
+			{"bad credentials", []failure{{status: 401}}, 1, model.ErrModelUnavailable},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				cli, requests := flakyOpenAI(t, tt.failures...)
+				a := New(WithOpenAIClient(cli), fast)
+
+				_, err := a.complete(ctx, params)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, err)
+				}
+				if len(*requests) != tt.requests {
+					t.Errorf("expected %d requests, got %d", tt.requests, len(*requests))
+				}
+			})
+		}
+	})
+}
+
+func TestAssistant_CompleteCache(t *testing.T) {
+	ctx := context.Background()
+	params := func(content string) openai.ChatCompletionNewParams {
+		return openai.ChatCompletionNewParams{
+			Model:    openai.ChatModelGPT4_1,
+			Messages: []openai.ChatCompletionMessageParamUnion{openai.UserMessage(content)},
+		}
+	}
+
+	cli, requests := fakeOpenAI(t, "Hello!")
+	a := New(WithOpenAIClient(cli), WithCache(cache.New(cache.NewLRU(10))))
+
+	for range 2 {
+		resp, err := a.complete(ctx, params("Hi"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Choices[0].Message.Content != "Hello!" {
+			t.Errorf("expected the cached answer, got %q", resp.Choices[0].Message.Content)
+		}
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("expected the second call to be answered from the cache, got %d requests", len(*requests))
+	}
+
+	if _, err := a.complete(ctx, params("Hi there")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := a.complete(cache.WithBypass(ctx), params("Hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*requests) != 3 {
+		t.Errorf("expected other messages and bypassed calls to reach the model, got %d requests", len(*requests))
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{Attempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second} {
+		delay, ok := p.delay(attempt, errors.New("connection reset"))
+		if !ok || delay < limit/2 || delay > limit {
+			t.Errorf("delay(%d) = %v, want between %v and %v", attempt, delay, limit/2, limit)
+		}
+	}
+}
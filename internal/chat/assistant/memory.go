@@ -0,0 +1,234 @@
+package assistant
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/shared"
+)
+
+const (
+	// maxMemories caps how many facts are kept per user
+	maxMemories = 200
+
+	// maxMemoryLength bounds one fact, in characters
+	maxMemoryLength = 300
+
+	// promptMemories is how many facts go into the system prompt
+	promptMemories = 30
+
+	// memoryWindow is how many of the latest messages facts are extracted from
+	memoryWindow = 6
+)
+
+// MemoryStore persists facts about users across conversations
+type MemoryStore interface {
+	SaveMemory(ctx context.Context, m *model.Memory) error
+	ListMemories(ctx context.Context, userID string) ([]*model.Memory, error)
+	DeleteMemory(ctx context.Context, userID, id string) error
+}
+
+const memoryPrompt = `You maintain long-term memory about a user of a personal assistant.
+Read the latest turn of the conversation and decide what is worth remembering in future conversations.
+
+Remember only durable facts and preferences the USER stated about themselves, for example:
+"Is vegetarian", "Home city is Barcelona", "Prefers window seats", "Has a daughter called Ana".
+Do NOT remember: one-off requests, questions, facts about the world, anything the assistant said,
+temporary plans, or secrets such as passwords and card numbers.
+
+Write each fact as one short sentence in English, at most 300 characters, without the word "user".
+Known facts are listed with their IDs. Don't repeat them; if the turn shows one is no longer true,
+list its ID in "remove" and add the corrected fact.
+
+Answer with JSON only: {"add": ["..."], "remove": ["<id>"]}. Use empty lists when nothing changes.`
+
+// memoryUpdate is the extraction model's answer
+type memoryUpdate struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// Remember extracts durable facts about the user from the latest turn of conv
+// and stores them. It does nothing without a memory store.
+func (a *Assistant) Remember(ctx context.Context, conv *model.Conversation) error {
+	if a.memories == nil || len(conv.Messages) == 0 {
+		return nil
+	}
+
+	userID := auth.FromContext(ctx)
+	known, err := a.memories.ListMemories(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load memories: %w", err)
+	}
+
+	vault := a.redactor.NewVault()
+
+	var b strings.Builder
+	b.WriteString("Known facts:")
+	if len(known) == 0 {
+		b.WriteString(" none")
+	}
+	for _, m := range known {
+		fmt.Fprintf(&b, "\n- [%s] %s", m.ID.Hex(), vault.Redact(m.Text))
+	}
+	b.WriteString("\n\nConversation:")
+	for _, m := range conv.Messages[max(0, len(conv.Messages)-memoryWindow):] {
+		fmt.Fprintf(&b, "\n%s: %s", strings.ToUpper(string(m.Role)), vault.Redact(m.Content))
+	}
+
+	resp, err := a.complete(ctx, openai.ChatCompletionNewParams{
+		Model: openai.ChatModelGPT4_1Mini,
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(memoryPrompt),
+			openai.UserMessage(b.String()),
+		},
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &shared.ResponseFormatJSONObjectParam{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to extract memories: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no choices returned by OpenAI for memory extraction")
+	}
+
+	var update memoryUpdate
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &update); err != nil {
+		return fmt.Errorf("invalid memory extraction answer: %w", err)
+	}
+
+	// Only facts of this user can be removed, whatever IDs the model makes up.
+	// Facts already known by key are refreshed and don't take up more room.
+	keys := make(map[string]bool, len(known))
+	for _, m := range known {
+		keys[m.Key] = true
+	}
+
+	var added, removed int
+	for _, id := range update.Remove {
+		i := slices.IndexFunc(known, func(m *model.Memory) bool { return m.ID.Hex() == id })
+		if i < 0 {
+			continue
+		}
+		if err := a.memories.DeleteMemory(ctx, userID, id); err != nil {
+			return fmt.Errorf("failed to forget memory %s: %w", id, err)
+		}
+		delete(keys, known[i].Key)
+		removed++
+	}
+
+	for _, text := range update.Add {
+		text = strings.TrimSpace(vault.Restore(text))
+		if text == "" || utf8.RuneCountInString(text) > maxMemoryLength {
+			continue
+		}
+		key := model.MemoryKey(text)
+		if !keys[key] && len(keys) >= maxMemories {
+			slog.WarnContext(ctx, "Memory is full, dropping new facts", "user_id", userID, "dropped", a.redactor.Mask(text))
+			break
+		}
+
+		m := &model.Memory{UserID: userID, Text: text, Key: key, ConversationID: conv.ID}
+		if err := a.memories.SaveMemory(ctx, m); err != nil {
+			return fmt.Errorf("failed to save memory: %w", err)
+		}
+		if !keys[key] {
+			keys[key] = true
+			added++
+		}
+	}
+
+	slog.InfoContext(ctx, "Updated user memory", "user_id", userID, "conversation_id", conv.ID, "added", added, "removed", removed)
+	return nil
+}
+
+// recall returns the system prompt section with what is remembered about the
+// user, or "" when nothing is
+func (a *Assistant) recall(ctx context.Context, conv *model.Conversation) string {
+	if a.memories == nil {
+		return ""
+	}
+
+	memories, err := a.memories.ListMemories(ctx, auth.FromContext(ctx))
+	if err != nil {
+		// Replying without memories beats not replying
+		slog.ErrorContext(ctx, "Failed to load memories", "error", err)
+		return ""
+	}
+	if len(memories) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nWhat you remember about the user from earlier conversations; use it when relevant, without reciting it:")
+	for _, m := range relevantMemories(memories, conv, promptMemories) {
+		b.WriteString("\n- " + m.Text)
+	}
+	return b.String()
+}
+
+// relevantMemories picks up to n memories, preferring those sharing words with
+// the user's messages, then the most recent
+func relevantMemories(memories []*model.Memory, conv *model.Conversation, n int) []*model.Memory {
+	if len(memories) <= n {
+		return memories
+	}
+
+	words := map[string]bool{}
+	for _, m := range conv.Messages {
+		if m.Role == model.RoleUser {
+			for _, w := range significantWords(m.Content) {
+				words[w] = true
+			}
+		}
+	}
+
+	type scored struct {
+		memory *model.Memory
+		score  int
+	}
+	ranked := make([]scored, len(memories))
+	for i, m := range memories {
+		ranked[i].memory = m
+		for _, w := range significantWords(m.Text) {
+			if words[w] {
+				ranked[i].score++
+			}
+		}
+	}
+
+	// Stable, so equally relevant memories keep the store's most-recent-first order
+	slices.SortStableFunc(ranked, func(x, y scored) int { return cmp.Compare(y.score, x.score) })
+
+	out := make([]*model.Memory, n)
+	for i := range out {
+		out[i] = ranked[i].memory
+	}
+	return out
+}
+
+// significantWords returns the lower-cased words of s worth matching on
+func significantWords(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	var words []string
+	for _, w := range fields {
+		if utf8.RuneCountInString(w) > 3 {
+			words = append(words, w)
+		}
+	}
+	return words
+}
@@ -0,0 +1,294 @@
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore is an in-memory MemoryStore
+type memoryStore struct {
+	memories []*model.Memory
+}
+
+func (s *memoryStore) SaveMemory(ctx context.Context, m *model.Memory) error {
+	m.Key = model.MemoryKey(m.Text)
+	for _, existing := range s.memories {
+		if existing.UserID == m.UserID && existing.Key == m.Key {
+			*m = *existing
+			return nil
+		}
+	}
+	m.ID = primitive.NewObjectID()
+	s.memories = append([]*model.Memory{m}, s.memories...)
+	return nil
+}
+
+func (s *memoryStore) ListMemories(ctx context.Context, userID string) ([]*model.Memory, error) {
+	var out []*model.Memory
+	for _, m := range s.memories {
+		if m.UserID == userID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStore) DeleteMemory(ctx context.Context, userID, id string) error {
+	for i, m := range s.memories {
+		if m.UserID == userID && m.ID.Hex() == id {
+			s.memories = slices.Delete(s.memories, i, i+1)
+			return nil
+		}
+	}
+	return fmt.Errorf("memory not found")
+}
+
+func (s *memoryStore) add(userID, text string) *model.Memory {
+	m := &model.Memory{ID: primitive.NewObjectID(), UserID: userID, Text: text, Key: model.MemoryKey(text)}
+	s.memories = append(s.memories, m)
+	return m
+}
+
+func (s *memoryStore) texts(userID string) []string {
+	var out []string
+	for _, m := range s.memories {
+		if m.UserID == userID {
+			out = append(out, m.Text)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// chatRequest is the part of a chat completion request the tests look at
+type chatRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role       string `json:"role"`
+		Content    string `json:"content"`
+		ToolCallID string `json:"tool_call_id"`
+	} `json:"messages"`
+	ResponseFormat struct {
+		Type string `json:"type"`
+	} `json:"response_format"`
+	Temperature *float64 `json:"temperature"`
+	Tools       []struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	} `json:"tools"`
+}
+
+// fakeOpenAI stands in for the chat completions API, answering every request
+// with content and recording the requests
+func fakeOpenAI(t *testing.T, content string) (openai.Client, *[]chatRequest) {
+	t.Helper()
+
+	var requests []chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]any{{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       map[string]any{"role": "assistant", "content": content},
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return openai.NewClient(option.WithBaseURL(srv.URL), option.WithAPIKey("test"), option.WithMaxRetries(0)), &requests
+}
+
+func conversation(messages ...string) *model.Conversation {
+	conv := &model.Conversation{ID: primitive.NewObjectID()}
+	for i, content := range messages {
+		role := model.RoleUser
+		if i%2 == 1 {
+			role = model.RoleAssistant
+		}
+		conv.Messages = append(conv.Messages, &model.Message{ID: primitive.NewObjectID(), Role: role, Content: content})
+	}
+	return conv
+}
+
+func TestAssistant_Remember(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), "ana")
+	conv := conversation(
+		"I moved to Madrid last month. Any vegetarian restaurants near Sol?",
+		"Here are a few vegetarian places near Puerta del Sol...",
+	)
+
+	t.Run("adds new facts and replaces outdated ones", func(t *testing.T) {
+		store := &memoryStore{}
+		barcelona := store.add("ana", "Home city is Barcelona")
+		store.add("ana", "Prefers window seats")
+		store.add("bob", "Home city is Lisbon")
+		bobs := store.memories[2].ID.Hex()
+
+		answer := fmt.Sprintf(`{"add": ["Home city is Madrid", "Is vegetarian", "prefers window seats.", " ", %q], "remove": [%q, %q, "not-an-id"]}`,
+			strings.Repeat("x", maxMemoryLength+1), barcelona.ID.Hex(), bobs)
+		cli, requests := fakeOpenAI(t, answer)
+		a := New(WithOpenAIClient(cli), WithMemoryStore(store))
+
+		if err := a.Remember(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := store.texts("ana"), []string{"Home city is Madrid", "Is vegetarian", "Prefers window seats"}; !slices.Equal(got, want) {
+			t.Errorf("remembered %q, want %q", got, want)
+		}
+		if got := store.texts("bob"); len(got) != 1 {
+			t.Errorf("another user's memory was changed: %q", got)
+		}
+		for _, m := range store.memories {
+			if m.Text == "Is vegetarian" && m.ConversationID != conv.ID {
+				t.Errorf("expected the conversation to be recorded, got %s", m.ConversationID.Hex())
+			}
+		}
+
+		if len(*requests) != 1 {
+			t.Fatalf("expected one extraction request, got %d", len(*requests))
+		}
+		req := (*requests)[0]
+		if req.ResponseFormat.Type != "json_object" || len(req.Messages) != 2 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		prompt := req.Messages[1].Content
+		for _, want := range []string{"[" + barcelona.ID.Hex() + "] Home city is Barcelona", "USER: I moved to Madrid", "ASSISTANT: Here are a few"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected prompt to contain '%s', got: %s", want, prompt)
+			}
+		}
+		if strings.Contains(prompt, "Lisbon") {
+			t.Errorf("prompt contains another user's memories: %s", prompt)
+		}
+	})
+
+	t.Run("stops adding when memory is full", func(t *testing.T) {
+		store := &memoryStore{}
+		for i := 0; i < maxMemories-1; i++ {
+			store.add("ana", fmt.Sprintf("Fact %d", i))
+		}
+		cli, _ := fakeOpenAI(t, `{"add": ["Is vegetarian", "Home city is Madrid"], "remove": []}`)
+		a := New(WithOpenAIClient(cli), WithMemoryStore(store))
+
+		if err := a.Remember(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(store.texts("ana")); n != maxMemories {
+			t.Errorf("expected %d memories, got %d", maxMemories, n)
+		}
+	})
+
+	t.Run("known facts don't use up room", func(t *testing.T) {
+		store := &memoryStore{}
+		for i := 0; i < maxMemories-1; i++ {
+			store.add("ana", fmt.Sprintf("Fact %d", i))
+		}
+		cli, _ := fakeOpenAI(t, `{"add": ["fact 0.", "Fact 1", "Is vegetarian"], "remove": []}`)
+		a := New(WithOpenAIClient(cli), WithMemoryStore(store))
+
+		if err := a.Remember(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := store.texts("ana"); len(got) != maxMemories || !slices.Contains(got, "Is vegetarian") {
+			t.Errorf("expected the new fact to fit in memory, got %d memories", len(got))
+		}
+	})
+
+	t.Run("invalid answers are errors", func(t *testing.T) {
+		store := &memoryStore{}
+		cli, _ := fakeOpenAI(t, "Sure! The user is vegetarian.")
+		a := New(WithOpenAIClient(cli), WithMemoryStore(store))
+
+		if err := a.Remember(ctx, conv); err == nil || !strings.Contains(err.Error(), "invalid memory extraction answer") {
+			t.Errorf("expected an invalid answer error, got %v", err)
+		}
+		if len(store.memories) != 0 {
+			t.Errorf("expected nothing remembered, got %d memories", len(store.memories))
+		}
+	})
+
+	t.Run("does nothing without a store", func(t *testing.T) {
+		cli, requests := fakeOpenAI(t, `{}`)
+		a := New(WithOpenAIClient(cli))
+
+		if err := a.Remember(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*requests) != 0 {
+			t.Errorf("expected no requests, got %d", len(*requests))
+		}
+	})
+}
+
+func TestAssistant_ReplyRecallsMemories(t *testing.T) {
+	store := &memoryStore{}
+	store.add("ana", "Is vegetarian")
+	store.add("bob", "Home city is Lisbon")
+
+	cli, requests := fakeOpenAI(t, "Try Rayén Vegano near Sol.")
+	a := New(WithOpenAIClient(cli), WithMemoryStore(store))
+
+	reply, err := a.Reply(auth.WithUser(context.Background(), "ana"), conversation("Where should I have dinner tonight?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply != "Try Rayén Vegano near Sol." {
+		t.Errorf("unexpected reply %q", reply)
+	}
+
+	system := (*requests)[0].Messages[0]
+	if system.Role != "system" || !strings.Contains(system.Content, "What you remember about the user") || !strings.Contains(system.Content, "- Is vegetarian") {
+		t.Errorf("expected memories in the system prompt, got: %s", system.Content)
+	}
+	if strings.Contains(system.Content, "Lisbon") {
+		t.Errorf("system prompt contains another user's memories: %s", system.Content)
+	}
+}
+
+func TestRelevantMemories(t *testing.T) {
+	var memories []*model.Memory
+	for _, text := range []string{"Has a cat called Miso", "Works as a nurse", "Home city is Barcelona", "Is vegetarian", "Prefers window seats"} {
+		memories = append(memories, &model.Memory{Text: text})
+	}
+	conv := conversation("Book me a flight from Barcelona, window seat please")
+
+	got := relevantMemories(memories, conv, 3)
+
+	var texts []string
+	for _, m := range got {
+		texts = append(texts, m.Text)
+	}
+	if want := []string{"Home city is Barcelona", "Prefers window seats", "Has a cat called Miso"}; !slices.Equal(texts, want) {
+		t.Errorf("relevantMemories() = %q, want %q", texts, want)
+	}
+
+	if got := relevantMemories(memories, conv, 10); len(got) != len(memories) {
+		t.Errorf("expected all %d memories when they fit, got %d", len(memories), len(got))
+	}
+}
@@ -0,0 +1,231 @@
+package assistant
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// DefaultPersona is the persona of conversations that don't pick one
+const DefaultPersona = "default"
+
+const (
+	// replyModel generates replies of personas that don't pick a model
+	replyModel = openai.ChatModelGPT4_1
+
+	// titlePrompt instructs title generation unless the persona replaces it
+	titlePrompt = "You are a title generator. Extract the main topic from the user's message and create a short, descriptive title. Do NOT answer the question. Examples: 'What is the weather like in Barcelona?' → 'Weather in Barcelona'. Maximum 80 characters, no quotes."
+)
+
+// PersonaStore holds personas defined at runtime, which take precedence over
+// the built-in and configured ones of the same name
+type PersonaStore interface {
+	// FindPersona returns the persona with the given name, nil if there is none
+	FindPersona(ctx context.Context, name string) (*model.Persona, error)
+	ListPersonas(ctx context.Context) ([]*model.Persona, error)
+}
+
+// builtinPersonas are available without configuration
+var builtinPersonas = []*model.Persona{
+	{
+		Name:         DefaultPersona,
+		Description:  "Helpful and concise general assistant",
+		SystemPrompt: "You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses.",
+	},
+	{
+		Name:        "travel_planner",
+		Description: "Plans trips: itineraries, weather, holidays, time zones and budgets",
+		SystemPrompt: "You are an experienced travel planner. Help the user plan trips: suggest itineraries day by day, " +
+			"check the weather, public holidays and opening days at the destination, convert times, currencies and units, " +
+			"and put flights and bookings in their calendar when asked. Be practical and mention what to book in advance.",
+		Temperature: openai.Ptr(0.7),
+		Tools: []string{
+			"get_weather", "get_today_date", "get_holidays", "business_days", "convert_timezone", "lookup_location",
+			"convert_units", "convert_currency", "calculate", "notes", "todos", "create_reminder",
+			"create_event", "list_events", "find_free_time", "search_past_conversations", "search_attachments",
+		},
+	},
+	{
+		Name:         "concise",
+		Description:  "Answers in as few words as possible",
+		SystemPrompt: "You are a terse assistant. Answer in one or two short sentences, or a short list when asked for several items. No greetings, caveats or follow-up offers.",
+		Temperature:  openai.Ptr(0.2),
+	},
+	{
+		Name:        "formal",
+		Description: "Formal, professional register for work correspondence",
+		SystemPrompt: "You are a professional executive assistant. Write in a formal, courteous register, " +
+			"use complete sentences and avoid slang, emojis and exclamation marks. Structure longer answers with short headings.",
+		Temperature: openai.Ptr(0.3),
+	},
+	{
+		Name:         "spanish",
+		Description:  "Helpful general assistant that always replies in Spanish",
+		SystemPrompt: "You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses.",
+		Language:     "Spanish",
+	},
+}
+
+// WithPersonas adds personas to the built-in ones, replacing those with the same name
+func WithPersonas(personas ...*model.Persona) Option {
+	return func(a *Assistant) {
+		for _, p := range personas {
+			a.personas[p.Name] = p
+		}
+	}
+}
+
+// WithPersonaStore adds the personas of a store, looked up by name on every
+// conversation so they can change without a restart
+func WithPersonaStore(store PersonaStore) Option {
+	return func(a *Assistant) {
+		a.personaStore = store
+	}
+}
+
+// LoadPersonas reads personas from a JSON file holding a list of them, e.g.
+// [{"name": "german", "description": "...", "system_prompt": "...", "language": "German"}]
+func LoadPersonas(path string) ([]*model.Persona, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read personas file: %w", err)
+	}
+
+	var personas []*model.Persona
+	if err := json.Unmarshal(data, &personas); err != nil {
+		return nil, fmt.Errorf("failed to parse personas file %s: %w", path, err)
+	}
+
+	for _, p := range personas {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("personas file %s: %w", path, err)
+		}
+	}
+
+	return personas, nil
+}
+
+// LoadPersonasFromEnv reads the personas file PERSONAS_FILE points to, if any
+func LoadPersonasFromEnv() ([]*model.Persona, error) {
+	path := os.Getenv("PERSONAS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	slog.Info("Loading personas from file", "path", path)
+	return LoadPersonas(path)
+}
+
+// Persona returns the persona with the given name, the default one when name
+// is empty, or model.ErrUnknownPersona
+func (a *Assistant) Persona(ctx context.Context, name string) (*model.Persona, error) {
+	if name == "" {
+		name = DefaultPersona
+	}
+
+	if a.personaStore != nil {
+		p, err := a.personaStore.FindPersona(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load persona %q: %w", name, err)
+		}
+		if p != nil {
+			// Stored personas can be edited behind SavePersona's back
+			if err := p.Validate(); err != nil {
+				return nil, fmt.Errorf("stored persona: %w", err)
+			}
+			a.checkTools(ctx, p)
+			return p, nil
+		}
+	}
+
+	if p, ok := a.personas[name]; ok {
+		return p, nil
+	}
+
+	return nil, fmt.Errorf("%w %q", model.ErrUnknownPersona, name)
+}
+
+// Personas returns every persona a conversation can be started with, by name
+func (a *Assistant) Personas(ctx context.Context) ([]*model.Persona, error) {
+	byName := map[string]*model.Persona{}
+	for name, p := range a.personas {
+		byName[name] = p
+	}
+
+	if a.personaStore != nil {
+		stored, err := a.personaStore.ListPersonas(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load personas: %w", err)
+		}
+		for _, p := range stored {
+			byName[p.Name] = p
+		}
+	}
+
+	personas := make([]*model.Persona, 0, len(byName))
+	for _, p := range byName {
+		personas = append(personas, p)
+	}
+	slices.SortFunc(personas, func(a, b *model.Persona) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return personas, nil
+}
+
+// personaFor returns the persona of a conversation. A persona that can't be
+// found any more, e.g. removed from the database, falls back to the default
+// one so the conversation can go on.
+func (a *Assistant) personaFor(ctx context.Context, conv *model.Conversation) *model.Persona {
+	p, err := a.Persona(ctx, conv.Persona)
+	if err != nil {
+		slog.WarnContext(ctx, "Using the default persona", "persona", conv.Persona, "error", err)
+		return a.personas[DefaultPersona]
+	}
+	return p
+}
+
+// systemPrompt returns the persona's instructions for replies
+func systemPrompt(p *model.Persona) string {
+	prompt := p.SystemPrompt
+	if p.Language != "" {
+		prompt += " Always reply in " + p.Language + ", whatever language the user writes in."
+	}
+	return prompt
+}
+
+// titleInstructions returns the persona's instructions for conversation titles
+func titleInstructions(p *model.Persona) string {
+	prompt := cmp.Or(p.TitlePrompt, titlePrompt)
+	if p.Language != "" {
+		prompt += " Write the title in " + p.Language + "."
+	}
+	return prompt
+}
+
+// checkPersonaTools warns about tools configured personas name that the
+// assistant doesn't have, which are most likely typos or tools that need a store
+func (a *Assistant) checkPersonaTools() {
+	for _, p := range a.personas {
+		if !slices.Contains(builtinPersonas, p) {
+			a.checkTools(context.Background(), p)
+		}
+	}
+}
+
+// checkTools warns about the tools a persona names that the assistant doesn't have
+func (a *Assistant) checkTools(ctx context.Context, p *model.Persona) {
+	for _, name := range p.Tools {
+		if !slices.ContainsFunc(a.tools, func(t tools.Tool) bool { return t.Name() == name }) {
+			slog.WarnContext(ctx, "Persona names a tool the assistant doesn't have", "persona", p.Name, "tool", name)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// personaStore is an in-memory PersonaStore
+type personaStore struct {
+	personas []*model.Persona
+	err      error
+}
+
+func (s *personaStore) FindPersona(ctx context.Context, name string) (*model.Persona, error) {
+	for _, p := range s.personas {
+		if p.Name == name {
+			return p, s.err
+		}
+	}
+	return nil, s.err
+}
+
+func (s *personaStore) ListPersonas(ctx context.Context) ([]*model.Persona, error) {
+	return s.personas, s.err
+}
+
+func TestAssistant_Persona(t *testing.T) {
+	ctx := context.Background()
+	configured := &model.Persona{Name: "concise", SystemPrompt: "Answer in five words at most."}
+	stored := &model.Persona{Name: "pirate", SystemPrompt: "Talk like a pirate."}
+	a := New(WithPersonas(configured), WithPersonaStore(&personaStore{personas: []*model.Persona{stored}}))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "You are a helpful, concise AI assistant. Provide accurate, safe, and clear responses."},
+		{"travel_planner", "You are an experienced travel planner."},
+		{"concise", "Answer in five words at most."},
+		{"pirate", "Talk like a pirate."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Persona(ctx, tt.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(p.SystemPrompt, tt.want) {
+				t.Errorf("unexpected persona %+v", p)
+			}
+		})
+	}
+
+	if _, err := a.Persona(ctx, "butler"); !errors.Is(err, model.ErrUnknownPersona) {
+		t.Errorf("expected ErrUnknownPersona, got %v", err)
+	}
+
+	personas, err := a.Personas(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, p := range personas {
+		names = append(names, p.Name)
+	}
+	if want := []string{"concise", "default", "formal", "pirate", "spanish", "travel_planner"}; !slices.Equal(names, want) {
+		t.Errorf("Personas() = %q, want %q", names, want)
+	}
+
+	t.Run("store errors", func(t *testing.T) {
+		a := New(WithPersonaStore(&personaStore{err: errors.New("database unavailable")}))
+		if _, err := a.Persona(ctx, "travel_planner"); err == nil || errors.Is(err, model.ErrUnknownPersona) {
+			t.Errorf("expected the store error, got %v", err)
+		}
+	})
+
+	t.Run("invalid stored personas", func(t *testing.T) {
+		broken := &model.Persona{Name: "broken", SystemPrompt: " "}
+		a := New(WithPersonaStore(&personaStore{personas: []*model.Persona{broken}}))
+		if _, err := a.Persona(ctx, "broken"); err == nil || !strings.Contains(err.Error(), "needs a system prompt") {
+			t.Errorf("expected a validation error, got %v", err)
+		}
+		if p := a.personaFor(ctx, &model.Conversation{Persona: "broken"}); p.Name != DefaultPersona {
+			t.Errorf("expected the default persona, got %q", p.Name)
+		}
+	})
+}
+
+func TestAssistant_ReplyUsesPersona(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), "ana")
+
+	t.Run("prompt, model, temperature and tools", func(t *testing.T) {
+		cli, requests := fakeOpenAI(t, "Día 1: Fushimi Inari temprano.")
+		a := New(WithOpenAIClient(cli), WithPersonas(&model.Persona{
+			Name:         "planificador",
+			SystemPrompt: "You plan trips.",
+			Language:     "Spanish",
+			Model:        "gpt-4.1-mini",
+			Temperature:  openai.Ptr(0.9),
+			Tools:        []string{"get_weather", "convert_currency", "not_a_tool"},
+		}))
+
+		conv := conversation("Plan two days in Kyoto")
+		conv.Persona = "planificador"
+		if _, err := a.Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		req := (*requests)[0]
+		if req.Model != "gpt-4.1-mini" || req.Temperature == nil || *req.Temperature != 0.9 {
+			t.Errorf("unexpected model %q or temperature %v", req.Model, req.Temperature)
+		}
+		if prompt := req.Messages[0].Content; !strings.HasPrefix(prompt, "You plan trips. Always reply in Spanish") {
+			t.Errorf("unexpected system prompt: %s", prompt)
+		}
+		var tools []string
+		for _, tool := range req.Tools {
+			tools = append(tools, tool.Function.Name)
+		}
+		if want := []string{"get_weather", "convert_currency"}; !slices.Equal(tools, want) {
+			t.Errorf("expected tools %q, got %q", want, tools)
+		}
+	})
+
+	t.Run("unknown personas fall back to the default", func(t *testing.T) {
+		cli, requests := fakeOpenAI(t, "Hello!")
+		a := New(WithOpenAIClient(cli))
+
+		conv := conversation("Hi")
+		conv.Persona = "removed"
+		if _, err := a.Reply(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		req := (*requests)[0]
+		if req.Model != string(replyModel) || req.Temperature != nil || len(req.Tools) != len(a.Tools()) {
+			t.Errorf("expected the default persona, got model %q, temperature %v and %d tools", req.Model, req.Temperature, len(req.Tools))
+		}
+		if prompt := req.Messages[0].Content; !strings.HasPrefix(prompt, "You are a helpful, concise AI assistant.") {
+			t.Errorf("unexpected system prompt: %s", prompt)
+		}
+	})
+
+	t.Run("titles follow the persona's language", func(t *testing.T) {
+		cli, requests := fakeOpenAI(t, "Dos días en Kioto")
+		a := New(WithOpenAIClient(cli))
+
+		conv := conversation("Plan two days in Kyoto")
+		conv.Persona = "spanish"
+		if _, err := a.Title(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if prompt := (*requests)[0].Messages[0].Content; !strings.HasSuffix(prompt, "Write the title in Spanish.") {
+			t.Errorf("unexpected title prompt: %s", prompt)
+		}
+	})
+}
+
+func TestLoadPersonas(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "personas.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	personas, err := LoadPersonas(write(t, `[
+		{"name": "german", "description": "Replies in German", "system_prompt": "You are a helpful assistant.", "language": "German"},
+		{"name": "chef", "system_prompt": "You are a chef.", "model": "gpt-4o", "temperature": 1.1, "tools": ["convert_units"]}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(personas) != 2 || personas[0].Language != "German" || *personas[1].Temperature != 1.1 || !personas[1].ToolPolicy().Allows("convert_units") || personas[1].ToolPolicy().Allows("get_weather") {
+		t.Errorf("unexpected personas %+v", personas)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not json", `name: german`},
+		{"no prompt", `[{"name": "german"}]`},
+		{"invalid name", `[{"name": "Travel Planner", "system_prompt": "You plan trips."}]`},
+		{"temperature out of range", `[{"name": "wild", "system_prompt": "Be creative.", "temperature": 3}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPersonas(write(t, tt.content)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package pii finds personal data such as emails, phone, card and passport
+// numbers in text. The assistant replaces it with placeholders before text is
+// sent to the language model and restores it in replies, and conversations can
+// be stored with it redacted or encrypted.
+package pii
+
+import (
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Kinds of personal data the default detectors find
+const (
+	KindEmail    = "EMAIL"
+	KindCard     = "CARD"
+	KindIBAN     = "IBAN"
+	KindPassport = "PASSPORT"
+	KindPhone    = "PHONE"
+)
+
+// Detector finds one kind of personal data
+type Detector struct {
+	// Kind names the data in placeholders, e.g. "EMAIL" in [EMAIL_1]
+	Kind string
+
+	// Pattern matches candidates
+	Pattern *regexp.Regexp
+
+	// Group is the submatch holding the data, 0 for the whole match. It lets a
+	// pattern require context, like the word "passport", without redacting it.
+	Group int
+
+	// Valid filters out candidates that only look like the data, e.g. by
+	// checksum. Nil keeps every candidate.
+	Valid func(value string) bool
+}
+
+// Detectors are the default detectors. Where matches overlap, the earlier
+// detector wins, so card numbers aren't taken for phone numbers.
+var Detectors = []Detector{
+	{
+		Kind:    KindEmail,
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	},
+	{
+		Kind:    KindCard,
+		Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Valid:   luhn,
+	},
+	{
+		Kind:    KindIBAN,
+		Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		Valid:   validIBAN,
+	},
+	{
+		Kind:    KindPassport,
+		Pattern: regexp.MustCompile(`(?i:passport|pasaporte|passeport|reisepass)(?:\s+(?i:number|num|no\.?|#))?\s*(?::|\s(?i:is))?\s*\b([A-Z0-9]{6,9})\b`),
+		Group:   1,
+		Valid:   func(v string) bool { return strings.ContainsFunc(v, unicode.IsDigit) },
+	},
+	{
+		Kind:    KindPhone,
+		Pattern: regexp.MustCompile(`\+\d{8,14}\b|(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?|\b)\d{2,4}(?:[ .-]?\d{2,4}){1,4}\b`),
+		Valid:   validPhone,
+	},
+}
+
+// Match is personal data found in a text
+type Match struct {
+	Kind       string
+	Start, End int
+	Value      string
+}
+
+// Detect returns the personal data the detectors find in text, in order and
+// without overlaps
+func Detect(text string, detectors []Detector) []Match {
+	var found []Match
+	for _, d := range detectors {
+		for _, loc := range d.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*d.Group], loc[2*d.Group+1]
+			if start < 0 {
+				continue
+			}
+
+			m := Match{Kind: d.Kind, Start: start, End: end, Value: text[start:end]}
+			if d.Valid != nil && !d.Valid(m.Value) {
+				continue
+			}
+			if slices.ContainsFunc(found, func(f Match) bool { return f.Start < m.End && m.Start < f.End }) {
+				continue
+			}
+			found = append(found, m)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b Match) int { return a.Start - b.Start })
+	return found
+}
+
+// digits returns the digits of s
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum of card numbers
+func luhn(s string) bool {
+	d := digits(s)
+	if len(d) < 13 || len(d) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := range len(d) {
+		n := int(d[len(d)-1-i] - '0')
+		if i%2 == 1 {
+			if n *= 2; n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether s passes the mod-97 check of account numbers
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+
+	// The country and check digits move to the end, letters become numbers
+	var b strings.Builder
+	for _, r := range s[4:] + s[:4] {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(b.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// isoDate matches dates, whose digits can pass for a phone number
+var isoDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// validPhone keeps candidates with as many digits as phone numbers have
+func validPhone(s string) bool {
+	n := len(digits(s))
+	return n >= 9 && n <= 15 && !isoDate.MatchString(s)
+}
@@ -0,0 +1,77 @@
+package pii
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Match
+	}{
+		{
+			name: "email",
+			text: "Write to ana.garcia+trips@example.co.uk today",
+			want: []Match{{Kind: KindEmail, Start: 9, End: 39, Value: "ana.garcia+trips@example.co.uk"}},
+		},
+		{
+			name: "card number with spaces",
+			text: "Card: 4111 1111 1111 1111.",
+			want: []Match{{Kind: KindCard, Start: 6, End: 25, Value: "4111 1111 1111 1111"}},
+		},
+		{
+			name: "number failing the card checksum",
+			text: "Order 4111111111111112 shipped",
+		},
+		{
+			name: "IBAN",
+			text: "Pay ES91 2100 0418 4502 0005 1332 by Friday",
+			want: []Match{{Kind: KindIBAN, Start: 4, End: 33, Value: "ES91 2100 0418 4502 0005 1332"}},
+		},
+		{
+			name: "account number failing the IBAN checksum",
+			text: "Ref ES00 2100 0418 4502 0005 1332",
+		},
+		{
+			name: "passport number",
+			text: "My passport number is X1234567, expiring soon",
+			want: []Match{{Kind: KindPassport, Start: 22, End: 30, Value: "X1234567"}},
+		},
+		{
+			name: "passport number of digits only",
+			text: "Passport: 123456789",
+			want: []Match{{Kind: KindPassport, Start: 10, End: 19, Value: "123456789"}},
+		},
+		{
+			name: "passport without a number",
+			text: "My passport is expired",
+		},
+		{
+			name: "international phone",
+			text: "Call +34 612 345 678 or +14155550123",
+			want: []Match{
+				{Kind: KindPhone, Start: 5, End: 20, Value: "+34 612 345 678"},
+				{Kind: KindPhone, Start: 24, End: 36, Value: "+14155550123"},
+			},
+		},
+		{
+			name: "national phone",
+			text: "Office: (555) 123-4567",
+			want: []Match{{Kind: KindPhone, Start: 8, End: 22, Value: "(555) 123-4567"}},
+		},
+		{
+			name: "dates, times and amounts aren't personal data",
+			text: "On 2025-12-15 14:00 convert 1 000 000 JPY, 25 km",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text, Detectors); !slices.Equal(got, tt.want) {
+				t.Errorf("Detect(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderPattern matches the placeholders a Vault writes
+var placeholderPattern = regexp.MustCompile(`\[([A-Z]+)_(\d+)\]`)
+
+// Redactor replaces personal data with placeholders. A nil Redactor redacts
+// nothing.
+type Redactor struct {
+	detectors []Detector
+}
+
+// New creates a redactor using detectors, the default ones when none is given
+func New(detectors ...Detector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = Detectors
+	}
+	return &Redactor{detectors: detectors}
+}
+
+// Detect returns the personal data in text
+func (r *Redactor) Detect(text string) []Match {
+	if r == nil {
+		return nil
+	}
+	return Detect(text, r.detectors)
+}
+
+// Mask replaces personal data in text for good, e.g. "[redacted email]"
+func (r *Redactor) Mask(text string) string {
+	return replace(text, r.Detect(text), func(m Match) string {
+		return "[redacted " + strings.ToLower(m.Kind) + "]"
+	})
+}
+
+// NewVault returns an empty vault redacting with the detectors of r
+func (r *Redactor) NewVault() *Vault {
+	if r == nil {
+		return nil
+	}
+	return &Vault{
+		redactor:     r,
+		placeholders: map[string]string{},
+		values:       map[string]string{},
+		counts:       map[string]int{},
+	}
+}
+
+// Vault remembers which placeholder stands for which value. The same value
+// always gets the same placeholder, so the model can tell values apart and
+// refer to them. A nil Vault leaves texts unchanged.
+type Vault struct {
+	redactor     *Redactor
+	placeholders map[string]string // kind and value to placeholder
+	values       map[string]string // placeholder to value
+	counts       map[string]int    // placeholders per kind
+}
+
+// Redact replaces the personal data in text with placeholders like [EMAIL_1]
+func (v *Vault) Redact(text string) string {
+	if v == nil {
+		return text
+	}
+
+	return replace(text, v.redactor.Detect(text), func(m Match) string {
+		key := m.Kind + "\x00" + m.Value
+		if p, ok := v.placeholders[key]; ok {
+			return p
+		}
+
+		v.counts[m.Kind]++
+		p := fmt.Sprintf("[%s_%d]", m.Kind, v.counts[m.Kind])
+		v.placeholders[key], v.values[p] = p, m.Value
+		return p
+	})
+}
+
+// Restore puts back the values of the placeholders in text. Placeholders the
+// vault didn't write are left as they are.
+func (v *Vault) Restore(text string) string {
+	if v == nil || len(v.values) == 0 {
+		return text
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(text, func(p string) string {
+		if value, ok := v.values[p]; ok {
+			return value
+		}
+		return p
+	})
+}
+
+// replace replaces the matches in text, which must be ordered and not overlap
+func replace(text string, matches []Match, with func(Match) string) string {
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(with(m))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package pii
+
+import "testing"
+
+func TestVault(t *testing.T) {
+	v := New().NewVault()
+
+	redacted := v.Redact("Email ana@example.com and bo@example.com, then ana@example.com again. Call +34 612 345 678.")
+	if want := "Email [EMAIL_1] and [EMAIL_2], then [EMAIL_1] again. Call [PHONE_1]."; redacted != want {
+		t.Fatalf("Redact() = %q, want %q", redacted, want)
+	}
+
+	// Placeholders stay the same across the texts of a conversation
+	if got := v.Redact("Is ana@example.com right?"); got != "Is [EMAIL_1] right?" {
+		t.Errorf("expected the same placeholder, got %q", got)
+	}
+
+	restored := v.Restore("I emailed [EMAIL_2] and called [PHONE_1]; [EMAIL_9] and [NAME_1] are unknown.")
+	if want := "I emailed bo@example.com and called +34 612 345 678; [EMAIL_9] and [NAME_1] are unknown."; restored != want {
+		t.Errorf("Restore() = %q, want %q", restored, want)
+	}
+}
+
+func TestVault_Nil(t *testing.T) {
+	var r *Redactor
+	v := r.NewVault()
+
+	if got := v.Redact("ana@example.com"); got != "ana@example.com" {
+		t.Errorf("expected a nil vault to leave text unchanged, got %q", got)
+	}
+	if got := v.Restore("[EMAIL_1]"); got != "[EMAIL_1]" {
+		t.Errorf("expected a nil vault to leave text unchanged, got %q", got)
+	}
+}
+
+func TestRedactor_Mask(t *testing.T) {
+	got := New().Mask("Passport X1234567, card 4111-1111-1111-1111")
+	if want := "Passport [redacted passport], card [redacted card]"; got != want {
+		t.Errorf("Mask() = %q, want %q", got, want)
+	}
+}
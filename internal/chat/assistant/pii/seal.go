@@ -0,0 +1,173 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+// sealedPattern matches the values an Encrypter sealed
+var sealedPattern = regexp.MustCompile(`\[pii:([A-Za-z0-9_-]+)\]`)
+
+// Masker stores conversations with their personal data replaced for good
+type Masker struct {
+	redactor *Redactor
+}
+
+// NewMasker creates a sealer masking what r detects
+func NewMasker(r *Redactor) *Masker {
+	return &Masker{redactor: r}
+}
+
+func (m *Masker) Seal(text string) (string, error) {
+	return m.redactor.Mask(text), nil
+}
+
+func (m *Masker) Open(text string) (string, error) {
+	return text, nil
+}
+
+// Encrypter stores conversations with their personal data encrypted with
+// AES-GCM, so only servers holding the key can read it back. The rest of the
+// text stays searchable.
+type Encrypter struct {
+	redactor *Redactor
+	aead     cipher.AEAD
+}
+
+// NewEncrypter creates a sealer encrypting what r detects with key, of 16, 24
+// or 32 bytes
+func NewEncrypter(r *Redactor, key []byte) (*Encrypter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Encrypter{redactor: r, aead: aead}, nil
+}
+
+// Seal replaces personal data with [pii:...] tokens holding it encrypted.
+// Tokens already in text are left as they are.
+func (e *Encrypter) Seal(text string) (string, error) {
+	var (
+		b    strings.Builder
+		err  error
+		last = 0
+	)
+	seal := func(part string) {
+		b.WriteString(replace(part, e.redactor.Detect(part), func(m Match) string {
+			nonce := make([]byte, e.aead.NonceSize())
+			if _, rerr := rand.Read(nonce); rerr != nil {
+				err = rerr
+			}
+			return "[pii:" + base64.RawURLEncoding.EncodeToString(e.aead.Seal(nonce, nonce, []byte(m.Value), nil)) + "]"
+		}))
+	}
+
+	for _, loc := range sealedPattern.FindAllStringIndex(text, -1) {
+		seal(text[last:loc[0]])
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	seal(text[last:])
+
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt personal data: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Open decrypts the tokens Seal wrote
+func (e *Encrypter) Open(text string) (string, error) {
+	var err error
+	opened := sealedPattern.ReplaceAllStringFunc(text, func(token string) string {
+		sealed, derr := base64.RawURLEncoding.DecodeString(sealedPattern.FindStringSubmatch(token)[1])
+		if derr != nil || len(sealed) < e.aead.NonceSize() {
+			err = errors.New("invalid encrypted personal data")
+			return token
+		}
+
+		nonce, ciphertext := sealed[:e.aead.NonceSize()], sealed[e.aead.NonceSize():]
+		value, derr := e.aead.Open(nil, nonce, ciphertext, nil)
+		if derr != nil {
+			err = fmt.Errorf("failed to decrypt personal data: %w", derr)
+			return token
+		}
+		return string(value)
+	})
+	return opened, err
+}
+
+// RedactorFromEnv creates the redactor of text sent to the language model.
+// PII_DETECTORS limits it to some kinds of data, e.g. "email,phone", and
+// PII_REDACTION=off turns redaction off, returning nil.
+func RedactorFromEnv() *Redactor {
+	if os.Getenv("PII_REDACTION") == "off" {
+		slog.Warn("Personal data redaction is off, messages go to the language model as they are")
+		return nil
+	}
+	return New(detectorsFromEnv()...)
+}
+
+// SealerFromEnv creates the sealer of stored conversations PII_STORAGE
+// selects: "redacted" masks personal data, "encrypted" encrypts it with the
+// base64 key in PII_ENCRYPTION_KEY. It returns nil when conversations are
+// stored as they are, the default.
+func SealerFromEnv() (model.Sealer, error) {
+	switch storage := os.Getenv("PII_STORAGE"); storage {
+	case "", "plain":
+		return nil, nil
+	case "redacted":
+		slog.Info("Storing conversations with personal data redacted")
+		return NewMasker(New(detectorsFromEnv()...)), nil
+	case "encrypted":
+		key, err := base64.StdEncoding.DecodeString(os.Getenv("PII_ENCRYPTION_KEY"))
+		if err != nil || len(key) == 0 {
+			return nil, errors.New("PII_STORAGE=encrypted needs PII_ENCRYPTION_KEY, a base64 key of 16, 24 or 32 bytes")
+		}
+		e, err := NewEncrypter(New(detectorsFromEnv()...), key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PII_ENCRYPTION_KEY: %w", err)
+		}
+		slog.Info("Storing conversations with personal data encrypted")
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unknown PII_STORAGE %q, expected plain, redacted or encrypted", storage)
+	}
+}
+
+// detectorsFromEnv returns the default detectors of the kinds PII_DETECTORS
+// lists, all of them when it isn't set
+func detectorsFromEnv() []Detector {
+	v := os.Getenv("PII_DETECTORS")
+	if v == "" {
+		return Detectors
+	}
+
+	var detectors []Detector
+	for _, kind := range strings.Split(v, ",") {
+		kind = strings.ToUpper(strings.TrimSpace(kind))
+		found := false
+		for _, d := range Detectors {
+			if d.Kind == kind {
+				detectors, found = append(detectors, d), true
+			}
+		}
+		if !found && kind != "" {
+			slog.Warn("Ignoring unknown personal data detector", "kind", kind)
+		}
+	}
+	return detectors
+}
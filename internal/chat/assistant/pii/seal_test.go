@@ -0,0 +1,65 @@
+package pii
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncrypter(t *testing.T) {
+	e, err := NewEncrypter(New(), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := "Book it for ana@example.com, passport X1234567."
+	sealed, err := e.Seal(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(sealed, "ana@example.com") || strings.Contains(sealed, "X1234567") || !strings.HasPrefix(sealed, "Book it for [pii:") {
+		t.Fatalf("expected the personal data encrypted, got %q", sealed)
+	}
+
+	// Sealing again leaves the tokens alone
+	resealed, err := e.Seal(sealed)
+	if err != nil || resealed != sealed {
+		t.Errorf("expected sealed text unchanged, got %q, %v", resealed, err)
+	}
+
+	opened, err := e.Open(sealed)
+	if err != nil || opened != text {
+		t.Errorf("Open() = %q, %v, want %q", opened, err, text)
+	}
+
+	other, _ := NewEncrypter(New(), bytes.Repeat([]byte{8}, 32))
+	if _, err := other.Open(sealed); err == nil {
+		t.Error("expected another key to fail")
+	}
+}
+
+func TestSealerFromEnv(t *testing.T) {
+	tests := []struct {
+		storage, key string
+		wantErr      bool
+		wantNil      bool
+	}{
+		{"", "", false, true},
+		{"plain", "", false, true},
+		{"redacted", "", false, false},
+		{"encrypted", "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc=", false, false},
+		{"encrypted", "", true, true},
+		{"encrypted", "c2hvcnQ=", true, true},
+		{"hashed", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("PII_STORAGE", tt.storage)
+		t.Setenv("PII_ENCRYPTION_KEY", tt.key)
+
+		s, err := SealerFromEnv()
+		if (err != nil) != tt.wantErr || (s == nil) != tt.wantNil {
+			t.Errorf("SealerFromEnv() with %q = %v, %v", tt.storage, s, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package assistant
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+// noteRecorder keeps the notes created
+type noteRecorder struct {
+	notes []*model.Note
+}
+
+func (s *noteRecorder) CreateNote(ctx context.Context, n *model.Note) error {
+	s.notes = append(s.notes, n)
+	return nil
+}
+
+func (s *noteRecorder) ListNotes(ctx context.Context, userID, query string) ([]*model.Note, error) {
+	return s.notes, nil
+}
+
+func (s *noteRecorder) DeleteNote(ctx context.Context, userID, id string) error { return nil }
+
+func TestAssistant_ReplyRedactsPersonalData(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	ctx := auth.WithUser(context.Background(), "ana")
+	notes := &noteRecorder{}
+	cli, requests := scriptedOpenAI(t,
+		[]call{{"call_note", "notes", `{"operation": "create", "title": "Contact", "content": "Email [EMAIL_1], passport [PASSPORT_1]"}`}},
+		"Saved [EMAIL_1] and passport [PASSPORT_1] to your notes.",
+	)
+	a := New(WithOpenAIClient(cli), WithNoteStore(notes), WithRedactor(pii.New()))
+
+	conv := conversation("Note that my email is ana@example.com and my passport number is X1234567")
+	reply, err := a.Reply(ctx, conv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reply != "Saved ana@example.com and passport X1234567 to your notes." {
+		t.Errorf("expected the personal data restored in the reply, got %q", reply)
+	}
+	if len(notes.notes) != 1 || notes.notes[0].Content != "Email ana@example.com, passport X1234567" {
+		t.Errorf("expected the tool to get the personal data, got %+v", notes.notes)
+	}
+
+	for i, req := range *requests {
+		for _, m := range req.Messages {
+			if strings.Contains(m.Content, "ana@example.com") || strings.Contains(m.Content, "X1234567") {
+				t.Errorf("request %d sent personal data to the model: %q", i, m.Content)
+			}
+		}
+	}
+	if user := (*requests)[0].Messages[1].Content; user != "Note that my email is [EMAIL_1] and my passport number is [PASSPORT_1]" {
+		t.Errorf("unexpected redacted message %q", user)
+	}
+	if strings.Contains(logs.String(), "ana@example.com") || strings.Contains(logs.String(), "X1234567") {
+		t.Errorf("expected the logs masked, got %s", logs.String())
+	}
+}
+
+func TestAssistant_TitleRedactsPersonalData(t *testing.T) {
+	cli, requests := fakeOpenAI(t, "Flight booking for [EMAIL_1]")
+	a := New(WithOpenAIClient(cli), WithRedactor(pii.New()))
+
+	title, err := a.Title(context.Background(), conversation("Book a flight and send it to ana@example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title != "Flight booking for ana@example.com" {
+		t.Errorf("expected the personal data restored in the title, got %q", title)
+	}
+	if content := (*requests)[0].Messages[1].Content; content != "Book a flight and send it to [EMAIL_1]" {
+		t.Errorf("unexpected redacted message %q", content)
+	}
+}
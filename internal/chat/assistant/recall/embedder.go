@@ -0,0 +1,64 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go/v2"
+)
+
+// OpenAIEmbedder embeds texts with the OpenAI embeddings API
+type OpenAIEmbedder struct {
+	cli   openai.Client
+	model openai.EmbeddingModel
+}
+
+// EmbedderOption configures an OpenAIEmbedder
+type EmbedderOption func(*OpenAIEmbedder)
+
+// WithEmbeddingClient sets a custom OpenAI client
+func WithEmbeddingClient(cli openai.Client) EmbedderOption {
+	return func(e *OpenAIEmbedder) {
+		e.cli = cli
+	}
+}
+
+// NewOpenAIEmbedder creates an embedder using text-embedding-3-small
+func NewOpenAIEmbedder(opts ...EmbedderOption) *OpenAIEmbedder {
+	e := &OpenAIEmbedder{
+		cli:   openai.NewClient(),
+		model: openai.EmbeddingModelTextEmbedding3Small,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Embed returns one vector per text, in the order of texts
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	resp, err := e.cli.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model: e.model,
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || int(d.Index) >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("no embedding returned for text %d", i)
+		}
+	}
+
+	return vectors, nil
+}
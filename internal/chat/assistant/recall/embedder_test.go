@@ -0,0 +1,54 @@
+package recall
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	var req struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+
+		// Out of order, as the API doesn't promise any
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "model": "text-embedding-3-small", "data": [
+			{"object": "embedding", "index": 1, "embedding": [0, 1]},
+			{"object": "embedding", "index": 0, "embedding": [1, 0]}
+		], "usage": {"prompt_tokens": 4, "total_tokens": 4}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	e := NewOpenAIEmbedder(WithEmbeddingClient(openai.NewClient(option.WithBaseURL(srv.URL), option.WithAPIKey("test"), option.WithMaxRetries(0))))
+
+	vectors, err := e.Embed(context.Background(), []string{"hotel in Lisbon", "flight to Tokyo"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	if req.Model != "text-embedding-3-small" || !slices.Equal(req.Input, []string{"hotel in Lisbon", "flight to Tokyo"}) {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if len(vectors) != 2 || !slices.Equal(vectors[0], []float64{1, 0}) || !slices.Equal(vectors[1], []float64{0, 1}) {
+		t.Errorf("unexpected vectors %v", vectors)
+	}
+
+	if _, err := e.Embed(context.Background(), []string{"a", "b", "c"}); err == nil {
+		t.Error("expected an error when an embedding is missing")
+	}
+}
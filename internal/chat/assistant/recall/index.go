@@ -0,0 +1,290 @@
+// Package recall indexes conversation messages by meaning, so the assistant can
+// search what was said in a user's past conversations. Messages are embedded
+// with a pluggable Embedder and kept in a pluggable vector Store (in memory,
+// or MongoDB Atlas Vector Search).
+package recall
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// maxEmbedLength bounds the text embedded per message, in characters,
+	// well within the embedding model's input limit
+	maxEmbedLength = 8000
+
+	// embedBatch is how many messages are embedded per request
+	embedBatch = 100
+
+	// DefaultLimit is how many matches Search returns unless asked otherwise
+	DefaultLimit = 5
+
+	// MaxLimit bounds how many matches one search returns
+	MaxLimit = 20
+)
+
+// Document is one indexed message
+type Document struct {
+	// ID is the ID of the message
+	ID             primitive.ObjectID `bson:"_id"`
+	UserID         string             `bson:"user_id"`
+	ConversationID primitive.ObjectID `bson:"conversation_id"`
+
+	// Title is the title of the conversation, to tell readers where the message is from
+	Title     string     `bson:"title"`
+	Role      model.Role `bson:"role"`
+	Text      string     `bson:"text"`
+	CreatedAt time.Time  `bson:"created_at"`
+	Embedding []float64  `bson:"embedding"`
+}
+
+// Match is a document found by a search
+type Match struct {
+	Document
+
+	// Score is the similarity to the query; higher is closer. Its scale
+	// depends on the store, so only compare scores from one search.
+	Score float64
+}
+
+// Query selects the documents a search ranks
+type Query struct {
+	UserID string
+	Text   string
+
+	// ExcludeConversationID leaves out one conversation, usually the current one
+	ExcludeConversationID primitive.ObjectID
+
+	Limit int
+}
+
+// Embedder turns texts into vectors that are close when the texts mean similar things
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// Store keeps documents and finds the ones nearest to a vector
+type Store interface {
+	// Upsert adds documents, replacing those with the same ID
+	Upsert(ctx context.Context, docs []*Document) error
+
+	// Indexed reports which of the given message IDs are already stored
+	Indexed(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error)
+
+	// Search returns up to q.Limit documents of q.UserID nearest to vector, nearest first
+	Search(ctx context.Context, vector []float64, q Query) ([]Match, error)
+}
+
+// Index embeds conversation messages into a store and searches them
+type Index struct {
+	embedder Embedder
+	store    Store
+	redactor *pii.Redactor
+	sealer   model.Sealer
+}
+
+// Option configures an Index
+type Option func(*Index)
+
+// WithRedactor masks the personal data r detects in messages and queries
+// before they are sent to the embedder
+func WithRedactor(r *pii.Redactor) Option {
+	return func(x *Index) {
+		x.redactor = r
+	}
+}
+
+// WithSealer seals the text and title of stored documents, and opens them in
+// search results
+func WithSealer(s model.Sealer) Option {
+	return func(x *Index) {
+		x.sealer = s
+	}
+}
+
+// NewIndex creates an index over the given store
+func NewIndex(embedder Embedder, store Store, opts ...Option) *Index {
+	x := &Index{
+		embedder: embedder,
+		store:    store,
+	}
+
+	for _, opt := range opts {
+		opt(x)
+	}
+
+	return x
+}
+
+// NewIndexFromEnv builds the index from the environment. VECTOR_STORE=atlas
+// keeps vectors in MongoDB and searches them with Atlas Vector Search, using
+// the index named by VECTOR_SEARCH_INDEX (see ARCHITECTURE.md for its
+// definition); otherwise vectors are kept in memory and lost on restart.
+// Messages are embedded with the default OpenAI client.
+func NewIndexFromEnv(db *mongo.Database, opts ...Option) *Index {
+	var store Store
+	switch os.Getenv("VECTOR_STORE") {
+	case "atlas":
+		name := os.Getenv("VECTOR_SEARCH_INDEX")
+		if name == "" {
+			name = DefaultSearchIndex
+		}
+		slog.Info("Using Atlas Vector Search for past conversations", "index", name)
+		store = NewMongoStore(db, name)
+	default:
+		store = NewMemoryStore()
+	}
+
+	return NewIndex(NewOpenAIEmbedder(), store, opts...)
+}
+
+// Add indexes the messages of a conversation that aren't indexed yet, on behalf of userID
+func (x *Index) Add(ctx context.Context, userID string, conv *model.Conversation) error {
+	var ids []primitive.ObjectID
+	for _, m := range conv.Messages {
+		if indexable(m) {
+			ids = append(ids, m.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	indexed, err := x.store.Indexed(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to check indexed messages: %w", err)
+	}
+
+	var pending []*Document
+	for _, m := range conv.Messages {
+		if !indexable(m) || indexed[m.ID] {
+			continue
+		}
+		pending = append(pending, &Document{
+			ID:             m.ID,
+			UserID:         userID,
+			ConversationID: conv.ID,
+			Title:          conv.Title,
+			Role:           m.Role,
+			Text:           m.Content,
+			CreatedAt:      m.CreatedAt,
+		})
+	}
+
+	for start := 0; start < len(pending); start += embedBatch {
+		batch := pending[start:min(start+embedBatch, len(pending))]
+
+		texts := make([]string, len(batch))
+		for i, d := range batch {
+			texts[i] = clip(x.redactor.Mask(d.Text), maxEmbedLength)
+		}
+
+		vectors, err := x.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed messages: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return fmt.Errorf("expected %d embeddings, got %d", len(batch), len(vectors))
+		}
+		for i, d := range batch {
+			d.Embedding = vectors[i]
+			if err := x.seal(d); err != nil {
+				return err
+			}
+		}
+
+		if err := x.store.Upsert(ctx, batch); err != nil {
+			return fmt.Errorf("failed to store embeddings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Search returns the messages of q.UserID closest in meaning to q.Text, closest first
+func (x *Index) Search(ctx context.Context, q Query) ([]Match, error) {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	q.Limit = min(q.Limit, MaxLimit)
+
+	vectors, err := x.embedder.Embed(ctx, []string{clip(x.redactor.Mask(q.Text), maxEmbedLength)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(vectors))
+	}
+
+	matches, err := x.store.Search(ctx, vectors[0], q)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range matches {
+		if err := x.open(&matches[i].Document); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// seal seals the texts of a document about to be stored
+func (x *Index) seal(d *Document) error {
+	if x.sealer == nil {
+		return nil
+	}
+
+	var err error
+	if d.Text, err = x.sealer.Seal(d.Text); err != nil {
+		return fmt.Errorf("failed to seal message: %w", err)
+	}
+	if d.Title, err = x.sealer.Seal(d.Title); err != nil {
+		return fmt.Errorf("failed to seal message: %w", err)
+	}
+	return nil
+}
+
+// open reads back the texts of a stored document, in place
+func (x *Index) open(d *Document) error {
+	if x.sealer == nil {
+		return nil
+	}
+
+	var err error
+	if d.Text, err = x.sealer.Open(d.Text); err != nil {
+		return fmt.Errorf("failed to open message: %w", err)
+	}
+	if d.Title, err = x.sealer.Open(d.Title); err != nil {
+		return fmt.Errorf("failed to open message: %w", err)
+	}
+	return nil
+}
+
+// indexable reports whether a message is worth finding later
+func indexable(m *model.Message) bool {
+	return (m.Role == model.RoleUser || m.Role == model.RoleAssistant) && strings.TrimSpace(m.Content) != ""
+}
+
+// clip shortens s to at most n characters
+func clip(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
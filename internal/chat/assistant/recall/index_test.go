@@ -0,0 +1,208 @@
+package recall
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keywordEmbedder embeds texts as counts of a few keywords, so texts sharing
+// keywords are close
+type keywordEmbedder struct {
+	calls int
+	texts []string
+}
+
+var keywords = []string{"hotel", "lisbon", "flight", "tokyo", "vegetarian"}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	e.calls++
+	e.texts = append(e.texts, texts...)
+
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float64, len(keywords))
+		for j, k := range keywords {
+			vectors[i][j] = float64(strings.Count(strings.ToLower(text), k))
+		}
+	}
+	return vectors, nil
+}
+
+func conversation(title string, day int, messages ...string) *model.Conversation {
+	conv := &model.Conversation{ID: primitive.NewObjectID(), Title: title}
+	for i, content := range messages {
+		role := model.RoleUser
+		if i%2 == 1 {
+			role = model.RoleAssistant
+		}
+		conv.Messages = append(conv.Messages, &model.Message{
+			ID:        primitive.NewObjectID(),
+			Role:      role,
+			Content:   content,
+			CreatedAt: time.Date(2026, 9, day, 10, i, 0, 0, time.UTC),
+		})
+	}
+	return conv
+}
+
+func TestIndex_Add(t *testing.T) {
+	ctx := context.Background()
+	embedder := &keywordEmbedder{}
+	store := NewMemoryStore()
+	index := NewIndex(embedder, store)
+
+	conv := conversation("Trip to Lisbon", 12,
+		"Can you suggest a hotel in Lisbon?",
+		"Memmo Alfama is a boutique hotel with views over Lisbon.",
+	)
+	if err := index.Add(ctx, "ana", conv); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if embedder.calls != 1 || len(embedder.texts) != 2 {
+		t.Fatalf("expected one request embedding 2 messages, got %d requests for %d texts", embedder.calls, len(embedder.texts))
+	}
+
+	// Only the new turn is embedded when the conversation goes on
+	conv.Messages = append(conv.Messages,
+		&model.Message{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "And a vegetarian restaurant nearby?"},
+		&model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "  "},
+	)
+	if err := index.Add(ctx, "ana", conv); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if embedder.calls != 2 || len(embedder.texts) != 3 {
+		t.Errorf("expected only the new message to be embedded, got %d requests for %d texts", embedder.calls, len(embedder.texts))
+	}
+
+	indexed, err := store.Indexed(ctx, []primitive.ObjectID{conv.Messages[0].ID, conv.Messages[2].ID, conv.Messages[3].ID})
+	if err != nil {
+		t.Fatalf("Indexed failed: %v", err)
+	}
+	if !indexed[conv.Messages[0].ID] || !indexed[conv.Messages[2].ID] || indexed[conv.Messages[3].ID] {
+		t.Errorf("unexpected indexed messages: %v", indexed)
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex(&keywordEmbedder{}, NewMemoryStore())
+
+	lisbon := conversation("Trip to Lisbon", 12,
+		"Can you suggest a hotel in Lisbon?",
+		"Memmo Alfama is a boutique hotel with views over Lisbon.",
+	)
+	tokyo := conversation("Tokyo flight", 20,
+		"When is my flight to Tokyo?",
+		"Your flight to Tokyo leaves on 2 September.",
+	)
+	current := conversation("Planning", 30, "What hotel did you recommend in Lisbon?")
+	others := conversation("Bob's trip", 5, "Book a hotel in Lisbon for me")
+
+	for _, conv := range []*model.Conversation{lisbon, tokyo, current} {
+		if err := index.Add(ctx, "ana", conv); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := index.Add(ctx, "bob", others); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	matches, err := index.Search(ctx, Query{UserID: "ana", Text: "hotel recommendation in Lisbon", ExcludeConversationID: current.ID, Limit: 2})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+
+	// Equally close messages come most recent first
+	if matches[0].ID != lisbon.Messages[1].ID || matches[1].ID != lisbon.Messages[0].ID {
+		t.Errorf("expected the Lisbon messages, got %q and %q", matches[0].Text, matches[1].Text)
+	}
+	if matches[0].Title != "Trip to Lisbon" || matches[0].ConversationID != lisbon.ID || matches[0].Role != model.RoleAssistant {
+		t.Errorf("unexpected match: %+v", matches[0].Document)
+	}
+	if matches[0].Score < matches[1].Score-1e-9 || matches[0].Score <= 0 {
+		t.Errorf("unexpected scores %f and %f", matches[0].Score, matches[1].Score)
+	}
+
+	matches, err = index.Search(ctx, Query{UserID: "ana", Text: "hotel", ExcludeConversationID: current.ID, Limit: 100})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, m := range matches {
+		if m.UserID != "ana" || m.ConversationID == current.ID {
+			t.Errorf("unexpected match from user %s in conversation %q", m.UserID, m.Title)
+		}
+	}
+	if len(matches) != 4 {
+		t.Errorf("expected all 4 of ana's other messages, got %d", len(matches))
+	}
+
+	if _, err := index.Search(ctx, Query{UserID: "ana", Text: "  "}); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"same direction", []float64{1, 2}, []float64{2, 4}, 1},
+		{"orthogonal", []float64{1, 0}, []float64{0, 3}, 0},
+		{"opposite", []float64{1, 1}, []float64{-1, -1}, -1},
+		{"zero vector", []float64{0, 0}, []float64{1, 1}, 0},
+		{"different lengths", []float64{1}, []float64{1, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("Cosine() = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_PersonalData(t *testing.T) {
+	ctx := context.Background()
+	embedder := &keywordEmbedder{}
+	store := NewMemoryStore()
+	sealer, err := pii.NewEncrypter(pii.New(), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	index := NewIndex(embedder, store, WithRedactor(pii.New()), WithSealer(sealer))
+
+	conv := conversation("Hotel for ana@example.com", 12, "Book a hotel in Lisbon and mail ana@example.com")
+	if err := index.Add(ctx, "ana", conv); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	matches, err := index.Search(ctx, Query{UserID: "ana", Text: "hotel for ana@example.com"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	for _, text := range embedder.texts {
+		if strings.Contains(text, "ana@example.com") {
+			t.Errorf("expected personal data masked before embedding, got %q", text)
+		}
+	}
+	for _, d := range store.docs {
+		if strings.Contains(d.Text+d.Title, "ana@example.com") {
+			t.Errorf("expected the stored document sealed, got %q in %q", d.Text, d.Title)
+		}
+	}
+	if len(matches) != 1 || matches[0].Text != conv.Messages[0].Content || matches[0].Title != conv.Title {
+		t.Errorf("expected the match opened, got %+v", matches)
+	}
+}
@@ -0,0 +1,94 @@
+package recall
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryStore keeps documents in memory and searches them by brute force,
+// comparing the query with every document of the user. It suits tests and
+// single-instance deployments with modest histories.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	docs map[primitive.ObjectID]*Document
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		docs: map[primitive.ObjectID]*Document{},
+	}
+}
+
+// Upsert adds documents, replacing those with the same ID
+func (s *MemoryStore) Upsert(ctx context.Context, docs []*Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range docs {
+		doc := *d
+		s.docs[d.ID] = &doc
+	}
+	return nil
+}
+
+// Indexed reports which of the given message IDs are stored
+func (s *MemoryStore) Indexed(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	indexed := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		if _, ok := s.docs[id]; ok {
+			indexed[id] = true
+		}
+	}
+	return indexed, nil
+}
+
+// Search ranks the user's documents by cosine similarity to vector
+func (s *MemoryStore) Search(ctx context.Context, vector []float64, q Query) ([]Match, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []Match
+	for _, d := range s.docs {
+		if d.UserID != q.UserID || d.ConversationID == q.ExcludeConversationID {
+			continue
+		}
+		matches = append(matches, Match{Document: *d, Score: Cosine(vector, d.Embedding)})
+	}
+
+	// Ties go to the most recent message
+	slices.SortFunc(matches, func(a, b Match) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return matches[:min(len(matches), q.Limit)], nil
+}
+
+// Cosine returns the cosine similarity of two vectors, 0 when they can't be compared
+func Cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,121 @@
+package recall
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// vectorCollection holds one document per indexed message
+	vectorCollection = "message_vectors"
+
+	// DefaultSearchIndex is the name of the Atlas Vector Search index used unless configured
+	DefaultSearchIndex = "message_vectors"
+
+	// candidatesPerResult is how many nearest neighbours Atlas considers per
+	// result; more is slower but more accurate
+	candidatesPerResult = 20
+)
+
+// MongoStore keeps documents in MongoDB and searches them with the
+// $vectorSearch stage of MongoDB Atlas. The collection needs a vector search
+// index over "embedding", with "user_id" and "conversation_id" as filters.
+type MongoStore struct {
+	coll  *mongo.Collection
+	index string
+}
+
+// NewMongoStore creates a store using the named Atlas Vector Search index
+func NewMongoStore(db *mongo.Database, index string) *MongoStore {
+	return &MongoStore{
+		coll:  db.Collection(vectorCollection),
+		index: index,
+	}
+}
+
+// Upsert adds documents, replacing those with the same ID
+func (s *MongoStore) Upsert(ctx context.Context, docs []*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(docs))
+	for i, d := range docs {
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": d.ID}).
+			SetReplacement(d).
+			SetUpsert(true)
+	}
+
+	_, err := s.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Indexed reports which of the given message IDs are stored
+func (s *MongoStore) Indexed(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	cursor, err := s.coll.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var found []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[primitive.ObjectID]bool, len(found))
+	for _, f := range found {
+		indexed[f.ID] = true
+	}
+	return indexed, nil
+}
+
+// Search runs an approximate nearest neighbour search over the user's documents
+func (s *MongoStore) Search(ctx context.Context, vector []float64, q Query) ([]Match, error) {
+	filter := bson.M{"user_id": q.UserID}
+	if !q.ExcludeConversationID.IsZero() {
+		filter["conversation_id"] = bson.M{"$ne": q.ExcludeConversationID}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$vectorSearch", Value: bson.M{
+			"index":         s.index,
+			"path":          "embedding",
+			"queryVector":   vector,
+			"numCandidates": q.Limit * candidatesPerResult,
+			"limit":         q.Limit,
+			"filter":        filter,
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"embedding": 0,
+			"score":     bson.M{"$meta": "vectorSearchScore"},
+		}}},
+	}
+
+	cursor, err := s.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Document `bson:",inline"`
+		Score    float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, len(results))
+	for i, r := range results {
+		matches[i] = Match{Document: r.Document, Score: r.Score}
+	}
+	return matches, nil
+}
@@ -0,0 +1,529 @@
+// Package schema validates JSON documents against JSON Schemas supplied by API
+// callers. It covers the keywords describing the shape of data: type, enum,
+// const, properties, required, additionalProperties, items, prefixItems,
+// length and range bounds, pattern, allOf, anyOf, oneOf, not and local $ref.
+// Annotations and other keywords, e.g. format, are accepted and ignored.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxProblems bounds the mismatches a validation reports
+	maxProblems = 10
+
+	// maxDepth bounds how deep schemas nest through $ref, which stops
+	// references to themselves
+	maxDepth = 64
+)
+
+var types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Schema is a parsed JSON Schema
+type Schema struct {
+	raw      json.RawMessage
+	root     any
+	patterns map[string]*regexp.Regexp
+}
+
+// Error lists where a document doesn't match a schema
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Parse reads a JSON Schema, checking that its keywords are well formed and
+// its references resolve
+func Parse(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	if _, ok := root.(map[string]any); !ok {
+		return nil, errors.New("schema must be a JSON object")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+
+	s := &Schema{raw: compact.Bytes(), root: root, patterns: map[string]*regexp.Regexp{}}
+	if err := s.check(root, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalJSON returns the schema as it was parsed
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return s.raw, nil
+}
+
+func (s *Schema) String() string {
+	return string(s.raw)
+}
+
+// Embed returns the schema as a value to nest in another schema at the given
+// JSON pointer, e.g. "/properties/data", with its references rewritten to
+// resolve from there
+func (s *Schema) Embed(at string) any {
+	return embed(s.root, at)
+}
+
+func embed(node any, at string) any {
+	switch n := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(n))
+		for k, v := range n {
+			if ref, ok := v.(string); ok && k == "$ref" && strings.HasPrefix(ref, "#") {
+				out[k] = "#" + at + strings.TrimPrefix(ref, "#")
+				continue
+			}
+			out[k] = embed(v, at)
+		}
+		return out
+	case []any:
+		out := make([]any, len(n))
+		for i, v := range n {
+			out[i] = embed(v, at)
+		}
+		return out
+	}
+	return node
+}
+
+// Validate checks that data is a JSON document matching the schema. Mismatches
+// are reported as an *Error.
+func (s *Schema) Validate(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("not valid JSON: %w", err)
+	}
+
+	v := &validator{schema: s}
+	v.validate(s.root, value, "$", 0)
+	if len(v.problems) > 0 {
+		return &Error{Problems: v.problems}
+	}
+	return nil
+}
+
+// check walks a schema, compiling its patterns and resolving its references
+func (s *Schema) check(node any, at string) error {
+	if _, ok := node.(bool); ok {
+		return nil
+	}
+	obj, ok := node.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: a schema must be an object or a boolean", at)
+	}
+
+	if t, ok := obj["type"]; ok {
+		names, ok := typeNames(t)
+		if !ok {
+			return fmt.Errorf("%s: type must be a type name or a list of them", at)
+		}
+		for _, name := range names {
+			if !slices.Contains(types, name) {
+				return fmt.Errorf("%s: unknown type %q", at, name)
+			}
+		}
+	}
+
+	if p, ok := obj["pattern"]; ok {
+		pattern, ok := p.(string)
+		if !ok {
+			return fmt.Errorf("%s: pattern must be a string", at)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", at, err)
+		}
+		s.patterns[pattern] = re
+	}
+
+	if ref, ok := obj["$ref"]; ok {
+		r, ok := ref.(string)
+		if !ok {
+			return fmt.Errorf("%s: $ref must be a string", at)
+		}
+		if _, err := s.resolve(r); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+	}
+
+	for _, key := range []string{"required", "enum"} {
+		if list, ok := obj[key]; ok {
+			if _, ok := list.([]any); !ok {
+				return fmt.Errorf("%s: %s must be a list", at, key)
+			}
+		}
+	}
+
+	for _, key := range []string{"properties", "patternProperties", "$defs", "definitions"} {
+		if sub, ok := obj[key]; ok {
+			m, ok := sub.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: %s must be an object", at, key)
+			}
+			for name, child := range m {
+				if key == "patternProperties" {
+					re, err := regexp.Compile(name)
+					if err != nil {
+						return fmt.Errorf("%s: invalid pattern: %w", at, err)
+					}
+					s.patterns[name] = re
+				}
+				if err := s.check(child, at+"/"+key+"/"+name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, key := range []string{"additionalProperties", "items", "not"} {
+		if child, ok := obj[key]; ok {
+			if err := s.check(child, at+"/"+key); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range []string{"prefixItems", "allOf", "anyOf", "oneOf"} {
+		if sub, ok := obj[key]; ok {
+			list, ok := sub.([]any)
+			if !ok || len(list) == 0 {
+				return fmt.Errorf("%s: %s must be a non-empty list", at, key)
+			}
+			for i, child := range list {
+				if err := s.check(child, fmt.Sprintf("%s/%s/%d", at, key, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve follows a reference within the schema, e.g. "#/$defs/day"
+func (s *Schema) resolve(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("only references within the schema are supported, got %q", ref)
+	}
+
+	node := s.root
+	if pointer == "" {
+		return node, nil
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("reference %q doesn't resolve", ref)
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("reference %q doesn't resolve", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("reference %q doesn't resolve", ref)
+		}
+	}
+	return node, nil
+}
+
+type validator struct {
+	schema   *Schema
+	problems []string
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	if len(v.problems) < maxProblems {
+		v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// matches reports whether value matches node, without recording why not
+func (v *validator) matches(node, value any, depth int) bool {
+	sub := &validator{schema: v.schema}
+	sub.validate(node, value, "$", depth)
+	return len(sub.problems) == 0
+}
+
+func (v *validator) validate(node, value any, path string, depth int) {
+	if depth > maxDepth {
+		v.fail(path, "schema nests too deeply")
+		return
+	}
+
+	if b, ok := node.(bool); ok {
+		if !b {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	}
+	s := node.(map[string]any)
+
+	if ref, ok := s["$ref"].(string); ok {
+		target, _ := v.schema.resolve(ref)
+		v.validate(target, value, path, depth+1)
+	}
+
+	if t, ok := s["type"]; ok {
+		names, _ := typeNames(t)
+		if !slices.ContainsFunc(names, func(name string) bool { return hasType(value, name) }) {
+			v.fail(path, "expected %s, got %s", strings.Join(names, " or "), typeOf(value))
+			return
+		}
+	}
+
+	if c, ok := s["const"]; ok && !reflect.DeepEqual(c, value) {
+		v.fail(path, "expected %s", encode(c))
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return reflect.DeepEqual(e, value) }) {
+		values := make([]string, len(enum))
+		for i, e := range enum {
+			values[i] = encode(e)
+		}
+		v.fail(path, "expected one of %s, got %s", strings.Join(values, ", "), encode(value))
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.validateString(s, val, path)
+	case float64:
+		v.validateNumber(s, val, path)
+	case []any:
+		v.validateArray(s, val, path, depth)
+	case map[string]any:
+		v.validateObject(s, val, path, depth)
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(sub, value, path, depth+1)
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		if !slices.ContainsFunc(anyOf, func(sub any) bool { return v.matches(sub, value, depth+1) }) {
+			v.fail(path, "doesn't match any of the allowed schemas")
+		}
+	}
+
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		var n int
+		for _, sub := range oneOf {
+			if v.matches(sub, value, depth+1) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(path, "matches %d of the schemas instead of exactly one", n)
+		}
+	}
+
+	if not, ok := s["not"]; ok && v.matches(not, value, depth+1) {
+		v.fail(path, "matches a schema it must not match")
+	}
+}
+
+func (v *validator) validateString(s map[string]any, val, path string) {
+	n := utf8.RuneCountInString(val)
+	if limit, ok := number(s["minLength"]); ok && float64(n) < limit {
+		v.fail(path, "expected at least %v characters, got %d", limit, n)
+	}
+	if limit, ok := number(s["maxLength"]); ok && float64(n) > limit {
+		v.fail(path, "expected at most %v characters, got %d", limit, n)
+	}
+	if pattern, ok := s["pattern"].(string); ok && !v.schema.patterns[pattern].MatchString(val) {
+		v.fail(path, "%q doesn't match the pattern %s", val, pattern)
+	}
+}
+
+func (v *validator) validateNumber(s map[string]any, val float64, path string) {
+	if limit, ok := number(s["minimum"]); ok && val < limit {
+		v.fail(path, "expected at least %v, got %v", limit, val)
+	}
+	if limit, ok := number(s["maximum"]); ok && val > limit {
+		v.fail(path, "expected at most %v, got %v", limit, val)
+	}
+	if limit, ok := number(s["exclusiveMinimum"]); ok && val <= limit {
+		v.fail(path, "expected more than %v, got %v", limit, val)
+	}
+	if limit, ok := number(s["exclusiveMaximum"]); ok && val >= limit {
+		v.fail(path, "expected less than %v, got %v", limit, val)
+	}
+	if m, ok := number(s["multipleOf"]); ok && m > 0 {
+		if q := val / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "expected a multiple of %v, got %v", m, val)
+		}
+	}
+}
+
+func (v *validator) validateArray(s map[string]any, val []any, path string, depth int) {
+	if limit, ok := number(s["minItems"]); ok && float64(len(val)) < limit {
+		v.fail(path, "expected at least %v items, got %d", limit, len(val))
+	}
+	if limit, ok := number(s["maxItems"]); ok && float64(len(val)) > limit {
+		v.fail(path, "expected at most %v items, got %d", limit, len(val))
+	}
+
+	prefix, _ := s["prefixItems"].([]any)
+	for i, item := range val {
+		at := fmt.Sprintf("%s[%d]", path, i)
+		if i < len(prefix) {
+			v.validate(prefix[i], item, at, depth+1)
+		} else if items, ok := s["items"]; ok {
+			v.validate(items, item, at, depth+1)
+		}
+	}
+
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := range val {
+			for j := i + 1; j < len(val); j++ {
+				if reflect.DeepEqual(val[i], val[j]) {
+					v.fail(path, "items %d and %d are the same", i, j)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateObject(s map[string]any, val map[string]any, path string, depth int) {
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := val[name]; !ok {
+					v.fail(path, "missing required property %q", name)
+				}
+			}
+		}
+	}
+
+	if limit, ok := number(s["minProperties"]); ok && float64(len(val)) < limit {
+		v.fail(path, "expected at least %v properties, got %d", limit, len(val))
+	}
+	if limit, ok := number(s["maxProperties"]); ok && float64(len(val)) > limit {
+		v.fail(path, "expected at most %v properties, got %d", limit, len(val))
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+	patterns, _ := s["patternProperties"].(map[string]any)
+
+	// Sorted so the same document always reports the same problems
+	names := make([]string, 0, len(val))
+	for name := range val {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		at := path + "." + name
+		matched := false
+
+		if sub, ok := properties[name]; ok {
+			v.validate(sub, val[name], at, depth+1)
+			matched = true
+		}
+		for pattern, sub := range patterns {
+			if v.schema.patterns[pattern].MatchString(name) {
+				v.validate(sub, val[name], at, depth+1)
+				matched = true
+			}
+		}
+
+		if additional, ok := s["additionalProperties"]; ok && !matched {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.fail(path, "unexpected property %q", name)
+				continue
+			}
+			v.validate(additional, val[name], at, depth+1)
+		}
+	}
+}
+
+// typeNames returns the names of a type keyword, a name or a list of them
+func typeNames(t any) ([]string, bool) {
+	switch t := t.(type) {
+	case string:
+		return []string{t}, true
+	case []any:
+		names := make([]string, 0, len(t))
+		for _, name := range t {
+			s, ok := name.(string)
+			if !ok {
+				return nil, false
+			}
+			names = append(names, s)
+		}
+		return names, len(names) > 0
+	}
+	return nil, false
+}
+
+func hasType(value any, name string) bool {
+	switch name {
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return typeOf(value) == name
+}
+
+func typeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func encode(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const itinerary = `{
+	"type": "object",
+	"properties": {
+		"destination": {"type": "string", "minLength": 1},
+		"nights": {"type": "integer", "minimum": 1, "maximum": 30},
+		"budget": {"enum": ["low", "medium", "high"]},
+		"days": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/day"}}
+	},
+	"required": ["destination", "days"],
+	"additionalProperties": false,
+	"$defs": {
+		"day": {
+			"type": "object",
+			"properties": {
+				"date": {"type": "string", "pattern": "^\\d{4}-\\d{2}-\\d{2}$", "format": "date"},
+				"activities": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["date", "activities"]
+		}
+	}
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Parse([]byte(itinerary))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		problems []string
+	}{
+		{
+			name:     "matching",
+			document: `{"destination": "Kyoto", "nights": 3, "budget": "medium", "days": [{"date": "2025-09-02", "activities": ["Fushimi Inari"]}]}`,
+		},
+		{
+			name:     "wrong types",
+			document: `{"destination": 12, "nights": 2.5, "days": [{"date": "2025-09-02", "activities": "temples"}]}`,
+			problems: []string{
+				"$.days[0].activities: expected array, got string",
+				"$.destination: expected string, got integer",
+				"$.nights: expected integer, got number",
+			},
+		},
+		{
+			name:     "missing and unexpected properties",
+			document: `{"destination": "Kyoto", "hotel": "Ryokan", "days": [{"date": "2 Sep"}]}`,
+			problems: []string{
+				`$.days[0]: missing required property "activities"`,
+				`$.days[0].date: "2 Sep" doesn't match the pattern ^\d{4}-\d{2}-\d{2}$`,
+				`$: unexpected property "hotel"`,
+			},
+		},
+		{
+			name:     "bounds and enum",
+			document: `{"destination": "", "nights": 40, "budget": "luxury", "days": []}`,
+			problems: []string{
+				`$.budget: expected one of "low", "medium", "high", got "luxury"`,
+				"$.days: expected at least 1 items, got 0",
+				"$.destination: expected at least 1 characters, got 0",
+				"$.nights: expected at most 30, got 40",
+			},
+		},
+		{
+			name:     "not an object",
+			document: `["Kyoto"]`,
+			problems: []string{"$: expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.document))
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Validate() error = %v, want an *Error", err)
+			}
+			if got, want := strings.Join(e.Problems, "\n"), strings.Join(tt.problems, "\n"); got != want {
+				t.Errorf("Validate() problems:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	if err := s.Validate([]byte(`{"destination": `)); err == nil || errors.As(err, new(*Error)) {
+		t.Errorf("Validate() of broken JSON = %v, want a syntax error", err)
+	}
+}
+
+func TestSchema_Embed(t *testing.T) {
+	s, err := Parse([]byte(itinerary))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	wrapper, err := json.Marshal(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"data": s.Embed("/properties/data")},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// The wrapper must still check the days through the rewritten reference
+	w, err := Parse(wrapper)
+	if err != nil {
+		t.Fatalf("Parse() of the wrapper error = %v", err)
+	}
+	err = w.Validate([]byte(`{"data": {"destination": "Kyoto", "days": [{"date": "2025-09-02"}]}}`))
+	if err == nil || !strings.Contains(err.Error(), `$.data.days[0]: missing required property "activities"`) {
+		t.Errorf("Validate() error = %v, want the day checked", err)
+	}
+}
+
+func TestSchema_Combinators(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"oneOf": [
+			{"type": "number", "multipleOf": 5},
+			{"type": "number", "multipleOf": 3}
+		],
+		"not": {"const": 0}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for document, valid := range map[string]bool{`10`: true, `9`: true, `15`: false, `7`: false, `0`: false, `"5"`: false} {
+		if err := s.Validate([]byte(document)); (err == nil) != valid {
+			t.Errorf("Validate(%s) error = %v, want valid %v", document, err, valid)
+		}
+	}
+
+	s, err = Parse([]byte(`{"anyOf": [{"type": "string"}, {"type": "null"}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for document, valid := range map[string]bool{`"Kyoto"`: true, `null`: true, `3`: false} {
+		if err := s.Validate([]byte(document)); (err == nil) != valid {
+			t.Errorf("Validate(%s) error = %v, want valid %v", document, err, valid)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, schema := range []string{
+		`not json`,
+		`["object"]`,
+		`{"type": "text"}`,
+		`{"type": "string", "pattern": "(unclosed"}`,
+		`{"properties": {"day": {"$ref": "#/$defs/day"}}}`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"properties": {"nights": 3}}`,
+		`{"anyOf": []}`,
+	} {
+		if _, err := Parse([]byte(schema)); err == nil {
+			t.Errorf("Parse(%s) succeeded, want an error", schema)
+		}
+	}
+
+	// A schema referring to itself can't loop forever
+	s, err := Parse([]byte(`{"$ref": "#"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := s.Validate([]byte(`{}`)); err == nil || !strings.Contains(err.Error(), "nests too deeply") {
+		t.Errorf("Validate() error = %v, want a nesting error", err)
+	}
+
+	if got := s.String(); got != `{"$ref":"#"}` {
+		t.Errorf("String() = %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package assistant
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/schema"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/shared"
+)
+
+// structuredRetryPrompt asks the model to fix an answer that didn't match the
+// caller's schema, given the problems found
+const structuredRetryPrompt = "Your answer doesn't match the requested schema: %s. Answer again with the same information, making data match the schema exactly."
+
+// structuredAnswer is the model's answer to a message asking for structured
+// output: the reply for the user and the document the caller's schema describes
+type structuredAnswer struct {
+	Reply string          `json:"reply"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// responseSchema returns the schema the last message of conv asks the reply to
+// follow, or nil
+func responseSchema(conv *model.Conversation) (*schema.Schema, error) {
+	last := conv.Messages[len(conv.Messages)-1]
+	if last.Role != model.RoleUser || last.ResponseSchema == "" {
+		return nil, nil
+	}
+
+	s, err := schema.Parse([]byte(last.ResponseSchema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid response schema: %w", err)
+	}
+	return s, nil
+}
+
+// responseFormat asks the model for the reply as text together with the
+// document matching s. Strict mode only supports a subset of JSON Schema, so
+// the answer is validated after the fact instead.
+func responseFormat(s *schema.Schema) openai.ChatCompletionNewParamsResponseFormatUnion {
+	return openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+			JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:        "structured_reply",
+				Description: openai.String("The answer written for the user, and the same answer as data following the requested schema"),
+				Schema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"reply": map[string]any{"type": "string", "description": "The answer for the user, written as usual"},
+						"data":  s.Embed("/properties/data"),
+					},
+					"required":             []string{"reply", "data"},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+}
+
+// parseStructured splits the model's answer into the reply and the document,
+// checking the document against s
+func parseStructured(s *schema.Schema, content string) (string, string, error) {
+	var answer structuredAnswer
+	if err := json.Unmarshal([]byte(content), &answer); err != nil {
+		return "", "", fmt.Errorf("the answer is not valid JSON: %w", err)
+	}
+	if len(answer.Data) == 0 {
+		return "", "", errors.New(`the answer has no "data"`)
+	}
+
+	if err := s.Validate(answer.Data); err != nil {
+		return "", "", err
+	}
+
+	var data bytes.Buffer
+	if err := json.Compact(&data, answer.Data); err != nil {
+		return "", "", err
+	}
+
+	// The document speaks for itself when the model wrote nothing else
+	reply := answer.Reply
+	if strings.TrimSpace(reply) == "" {
+		var indented bytes.Buffer
+		_ = json.Indent(&indented, data.Bytes(), "", "  ")
+		reply = indented.String()
+	}
+
+	return reply, data.String(), nil
+}
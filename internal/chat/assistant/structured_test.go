@@ -0,0 +1,83 @@
+package assistant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+)
+
+const itinerarySchema = `{
+	"type": "object",
+	"properties": {
+		"days": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/day"}}
+	},
+	"required": ["days"],
+	"$defs": {
+		"day": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}
+	}
+}`
+
+func TestAssistant_StructuredReply(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), "ana")
+
+	t.Run("retries an answer that doesn't match", func(t *testing.T) {
+		cli, requests := scriptedOpenAI(t,
+			`{"reply": "Day 1: temples.", "data": {"days": [{"town": "Kyoto"}]}}`,
+			`{"reply": "Day 1: temples in Kyoto.", "data": {"days": [{"city": "Kyoto"}]}}`,
+		)
+		a := New(WithOpenAIClient(cli))
+
+		conv := conversation("Plan a day in Kyoto")
+		conv.Messages[0].ResponseSchema = itinerarySchema
+
+		reply, structured, err := a.StructuredReply(ctx, conv)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reply != "Day 1: temples in Kyoto." || structured != `{"days":[{"city":"Kyoto"}]}` {
+			t.Errorf("unexpected reply %q, %q", reply, structured)
+		}
+
+		if len(*requests) != 2 {
+			t.Fatalf("expected 2 requests, got %d", len(*requests))
+		}
+		if got := (*requests)[0].ResponseFormat.Type; got != "json_schema" {
+			t.Errorf("expected a json_schema response format, got %q", got)
+		}
+		retry := (*requests)[1].Messages
+		if last := retry[len(retry)-1]; last.Role != "user" || !strings.Contains(last.Content, `missing required property "city"`) {
+			t.Errorf("expected the retry to explain the mismatch, got %+v", last)
+		}
+	})
+
+	t.Run("fails after the retry", func(t *testing.T) {
+		cli, _ := scriptedOpenAI(t, `{"reply": "Kyoto", "data": {}}`, `not json`)
+		a := New(WithOpenAIClient(cli))
+
+		conv := conversation("Plan a day in Kyoto")
+		conv.Messages[0].ResponseSchema = itinerarySchema
+
+		if _, _, err := a.StructuredReply(ctx, conv); err == nil || !strings.Contains(err.Error(), "doesn't match the response schema") {
+			t.Errorf("expected a schema mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("replies as usual without a schema", func(t *testing.T) {
+		cli, requests := scriptedOpenAI(t, "Kyoto is lovely in autumn.")
+		a := New(WithOpenAIClient(cli))
+
+		// Only the last message's schema applies
+		conv := conversation("Plan a day in Kyoto", "Day 1: temples.", "Is it nice in autumn?")
+		conv.Messages[0].ResponseSchema = itinerarySchema
+
+		reply, structured, err := a.StructuredReply(ctx, conv)
+		if err != nil || reply != "Kyoto is lovely in autumn." || structured != "" {
+			t.Errorf("unexpected reply %q, %q, %v", reply, structured, err)
+		}
+		if got := (*requests)[0].ResponseFormat.Type; got != "" {
+			t.Errorf("expected no response format, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// AttachmentSearcher finds passages of the files sent in a conversation
+type AttachmentSearcher interface {
+	Search(ctx context.Context, q attachments.Query) ([]attachments.Passage, error)
+}
+
+// SearchAttachmentsTool lets the model read the parts of the conversation's
+// files a question is about
+type SearchAttachmentsTool struct {
+	searcher AttachmentSearcher
+}
+
+// NewSearchAttachmentsTool creates a new attachment search tool
+func NewSearchAttachmentsTool(searcher AttachmentSearcher) *SearchAttachmentsTool {
+	return &SearchAttachmentsTool{
+		searcher: searcher,
+	}
+}
+
+func (t *SearchAttachmentsTool) Name() string {
+	return "search_attachments"
+}
+
+func (t *SearchAttachmentsTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "search_attachments",
+		Description: openai.String("Search the files the user sent in this conversation (itineraries, booking confirmations, documents) " +
+			"for the passages relevant to a question. Always use it before answering questions about an attached file, " +
+			"and cite the file and page of the passages you use, e.g. (itinerary.pdf, p. 2)."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]string{
+					"type":        "string",
+					"description": "What to look for, e.g. 'check-in time of the hotel in Kyoto'",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("How many passages to return, default %d, at most %d", attachments.DefaultLimit, attachments.MaxLimit),
+				},
+			},
+			"required": []string{"query"},
+		},
+	})
+}
+
+func (t *SearchAttachmentsTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid search parameters: %w", err)
+	}
+
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+
+	conversationID, ok := model.ConversationIDFromContext(ctx)
+	if !ok {
+		return "No files have been sent in this conversation.", nil
+	}
+
+	passages, err := t.searcher.Search(ctx, attachments.Query{
+		UserID:         auth.FromContext(ctx),
+		ConversationID: conversationID,
+		Text:           query,
+		Limit:          params.Limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search attachments: %w", err)
+	}
+	if len(passages) == 0 {
+		return "No files have been sent in this conversation.", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d passage(s) from the files in this conversation, closest first:", len(passages))
+	for _, p := range passages {
+		b.WriteString("\n\n[" + p.Attachment.Filename)
+		if p.Page > 0 {
+			fmt.Fprintf(&b, ", p. %d", p.Page)
+		}
+		b.WriteString("]\n" + p.Text)
+	}
+	return b.String(), nil
+}
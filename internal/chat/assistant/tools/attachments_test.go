@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fixedPassages returns the same passages for every query and records the last one
+type fixedPassages struct {
+	passages []attachments.Passage
+	err      error
+	query    attachments.Query
+}
+
+func (s *fixedPassages) Search(ctx context.Context, q attachments.Query) ([]attachments.Passage, error) {
+	s.query = q
+	return s.passages, s.err
+}
+
+func TestSearchAttachmentsTool_Handle(t *testing.T) {
+	conversation := primitive.NewObjectID()
+	ctx := model.WithConversationID(auth.WithUser(context.Background(), "ana"), conversation)
+
+	itinerary := &model.Attachment{Filename: "itinerary.pdf"}
+	notes := &model.Attachment{Filename: "notes.md"}
+	searcher := &fixedPassages{passages: []attachments.Passage{
+		{Attachment: itinerary, Page: 2, Text: "Ryokan Yachiyo, Kyoto\nCheck-in from 15:00", Score: 0.9},
+		{Attachment: notes, Text: "Ask for a room facing the garden", Score: 0.6},
+	}}
+	tool := NewSearchAttachmentsTool(searcher)
+
+	out, err := tool.Handle(ctx, `{"query": "check-in time in Kyoto", "limit": 2}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q := searcher.query; q.UserID != "ana" || q.ConversationID != conversation || q.Text != "check-in time in Kyoto" || q.Limit != 2 {
+		t.Errorf("unexpected query %+v", q)
+	}
+	want := "2 passage(s) from the files in this conversation, closest first:\n\n" +
+		"[itinerary.pdf, p. 2]\nRyokan Yachiyo, Kyoto\nCheck-in from 15:00\n\n" +
+		"[notes.md]\nAsk for a room facing the garden"
+	if out != want {
+		t.Errorf("unexpected result:\n%s\nwant:\n%s", out, want)
+	}
+
+	t.Run("no files", func(t *testing.T) {
+		out, err := NewSearchAttachmentsTool(&fixedPassages{}).Handle(ctx, `{"query": "hotel"}`)
+		if err != nil || out != "No files have been sent in this conversation." {
+			t.Errorf("unexpected result %q, %v", out, err)
+		}
+
+		// Outside a conversation there is nothing to search
+		s := &fixedPassages{passages: searcher.passages}
+		out, err = NewSearchAttachmentsTool(s).Handle(auth.WithUser(context.Background(), "ana"), `{"query": "hotel"}`)
+		if err != nil || out != "No files have been sent in this conversation." || s.query.Text != "" {
+			t.Errorf("unexpected result %q, %v", out, err)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, args := range []string{`{"query": " "}`, `not json`} {
+			if _, err := tool.Handle(ctx, args); err == nil {
+				t.Errorf("expected an error for %s", args)
+			}
+		}
+
+		failing := NewSearchAttachmentsTool(&fixedPassages{err: errors.New("embeddings unavailable")})
+		if _, err := failing.Handle(ctx, `{"query": "hotel"}`); err == nil || !strings.Contains(err.Error(), "embeddings unavailable") {
+			t.Errorf("expected the search error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
+	"github.com/openai/openai-go/v2"
+)
+
+// Limits keep a single call from looping over centuries of days
+const (
+	maxBusinessDays  = 2000
+	maxBusinessYears = 10
+)
+
+// BusinessDaysTool counts and adds working days, and checks working hours,
+// honouring each country's weekend and holiday calendar
+type BusinessDaysTool struct {
+	calendars *holidays.Calendars
+}
+
+// NewBusinessDaysTool creates a new business days tool backed by the given holiday calendars
+func NewBusinessDaysTool(calendars *holidays.Calendars) *BusinessDaysTool {
+	return &BusinessDaysTool{
+		calendars: calendars,
+	}
+}
+
+func (t *BusinessDaysTool) Name() string {
+	return "business_days"
+}
+
+func (t *BusinessDaysTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "business_days",
+		Description: openai.String("Working-day calculator that skips weekends and public holidays of a country or region. " +
+			"Operations: 'count' working days between two dates (e.g. 'how many working days until my trip'), " +
+			"'add' N working days to a date (e.g. '10 business days from now in Madrid', negative N goes back), " +
+			"'is_working_time' checks whether a local date and time falls on a working day within working hours."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"operation": map[string]any{
+					"type": "string",
+					"enum": []string{"count", "add", "is_working_time"},
+				},
+				"country": map[string]string{
+					"type":        "string",
+					"description": "Country name or ISO code (e.g. 'Spain', 'US'). Defaults to the default holiday calendar.",
+				},
+				"region": map[string]string{
+					"type":        "string",
+					"description": "Optional region within the country (e.g. 'Madrid').",
+				},
+				"start_date": map[string]string{
+					"type":        "string",
+					"description": "Start date (YYYY-MM-DD) for 'count' and 'add'. Working days are counted after this date.",
+				},
+				"end_date": map[string]string{
+					"type":        "string",
+					"description": "End date (YYYY-MM-DD) for 'count', included in the count.",
+				},
+				"days": map[string]string{
+					"type":        "integer",
+					"description": "Number of working days to add for 'add', negative to subtract.",
+				},
+				"datetime": map[string]string{
+					"type":        "string",
+					"description": "Date and time for 'is_working_time': local time 'YYYY-MM-DDTHH:MM' in the given timezone, or RFC3339.",
+				},
+				"timezone": map[string]string{
+					"type":        "string",
+					"description": "IANA timezone of the location for 'is_working_time' (e.g. 'Europe/Madrid').",
+				},
+				"work_start": map[string]string{
+					"type":        "string",
+					"description": "Start of working hours, 'HH:MM' (default '09:00').",
+				},
+				"work_end": map[string]string{
+					"type":        "string",
+					"description": "End of working hours, 'HH:MM' (default '17:00').",
+				},
+			},
+			"required": []string{"operation"},
+		},
+	})
+}
+
+func (t *BusinessDaysTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Operation string `json:"operation"`
+		Country   string `json:"country,omitempty"`
+		Region    string `json:"region,omitempty"`
+		StartDate string `json:"start_date,omitempty"`
+		EndDate   string `json:"end_date,omitempty"`
+		Days      int    `json:"days,omitempty"`
+		Datetime  string `json:"datetime,omitempty"`
+		Timezone  string `json:"timezone,omitempty"`
+		WorkStart string `json:"work_start,omitempty"`
+		WorkEnd   string `json:"work_end,omitempty"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid business days parameters: %w", err)
+	}
+
+	cal, note, err := t.workCalendar(ctx, params.Country, params.Region)
+	if err != nil {
+		return "", err
+	}
+
+	var result string
+	switch params.Operation {
+	case "count":
+		start, err := parseDate("start_date", params.StartDate)
+		if err != nil {
+			return "", err
+		}
+		end, err := parseDate("end_date", params.EndDate)
+		if err != nil {
+			return "", err
+		}
+		if years := end.Sub(start).Hours() / 24 / 365; years > maxBusinessYears || years < -maxBusinessYears {
+			return "", fmt.Errorf("date range too large, at most %d years are supported", maxBusinessYears)
+		}
+
+		n, skipped := cal.Count(start, end)
+		result = fmt.Sprintf("Working days in %s after %s up to and including %s: %d",
+			cal.Name, params.StartDate, params.EndDate, n)
+		result += formatSkipped(skipped) + coverageNote(cal, start, end)
+
+	case "add":
+		start, err := parseDate("start_date", params.StartDate)
+		if err != nil {
+			return "", err
+		}
+		if params.Days > maxBusinessDays || params.Days < -maxBusinessDays {
+			return "", fmt.Errorf("days must be between -%d and %d", maxBusinessDays, maxBusinessDays)
+		}
+
+		day, skipped := cal.Add(start, params.Days)
+		direction := "after"
+		if params.Days < 0 {
+			direction = "before"
+		}
+		result = fmt.Sprintf("%d working days %s %s in %s: %s (%s)",
+			abs(params.Days), direction, params.StartDate, cal.Name, day.Format(time.DateOnly), day.Weekday())
+		result += formatSkipped(skipped) + coverageNote(cal, start, day)
+
+	case "is_working_time":
+		result, err = isWorkingTime(cal, params.Datetime, params.Timezone, params.WorkStart, params.WorkEnd)
+		if err != nil {
+			return "", err
+		}
+
+	default:
+		return "", fmt.Errorf("unknown operation '%s', expected count, add or is_working_time", params.Operation)
+	}
+
+	if note != "" {
+		result += "\n" + note
+	}
+
+	return result, nil
+}
+
+// workCalendar loads the work calendar of a location. Countries without a holiday
+// calendar still get their weekend, with a note that holidays are not excluded;
+// calendars that exist but fail to load are reported as errors.
+func (t *BusinessDaysTool) workCalendar(ctx context.Context, country, region string) (*holidays.WorkCalendar, string, error) {
+	cal, err := t.calendars.WorkCalendar(ctx, country, region)
+	if err == nil {
+		return cal, "", nil
+	}
+
+	if country == "" || !errors.Is(err, holidays.ErrNoCalendar) {
+		return nil, "", fmt.Errorf("failed to load holiday calendar: %w", err)
+	}
+
+	slog.WarnContext(ctx, "No holiday calendar, counting weekends only", "country", country, "region", region, "error", err)
+	note := fmt.Sprintf("Note: no holiday calendar is available for %s, only weekends were excluded.", strings.TrimSpace(country+" "+region))
+	return holidays.NewWorkCalendar(country, holidays.Weekend(country), nil), note, nil
+}
+
+// isWorkingTime checks a local date and time against the work calendar and working hours
+func isWorkingTime(cal *holidays.WorkCalendar, datetime, timezone, workStart, workEnd string) (string, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return "", fmt.Errorf("invalid timezone '%s': %w", timezone, err)
+		}
+	}
+
+	var at time.Time
+	switch {
+	case datetime == "" || datetime == "now":
+		at = time.Now().In(loc)
+	default:
+		parsed, err := time.Parse(time.RFC3339, datetime)
+		if err == nil {
+			if timezone != "" {
+				parsed = parsed.In(loc)
+			}
+		} else if parsed, err = time.ParseInLocation("2006-01-02T15:04", datetime, loc); err != nil {
+			return "", fmt.Errorf("invalid datetime '%s', expected 'YYYY-MM-DDTHH:MM' or RFC3339: %w", datetime, err)
+		}
+		at = parsed
+	}
+
+	start, err := parseClock("work_start", workStart, "09:00")
+	if err != nil {
+		return "", err
+	}
+	end, err := parseClock("work_end", workEnd, "17:00")
+	if err != nil {
+		return "", err
+	}
+
+	when := fmt.Sprintf("%s (%s) in %s", at.Format("2006-01-02 15:04 MST"), at.Weekday(), cal.Name)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	if h, ok := cal.Holiday(day); ok {
+		return fmt.Sprintf("Not working time: %s is a holiday (%s).", when, h.Name), nil
+	}
+	if !cal.IsWorkingDay(day) {
+		return fmt.Sprintf("Not working time: %s is a weekend day.", when), nil
+	}
+
+	clock := at.Hour()*60 + at.Minute()
+	if clock < start || clock >= end {
+		return fmt.Sprintf("Not working time: %s is outside working hours (%s-%s).", when, formatClock(start), formatClock(end)), nil
+	}
+
+	return fmt.Sprintf("Working time: %s is a working day within working hours (%s-%s).", when, formatClock(start), formatClock(end)), nil
+}
+
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s is required", name)
+	}
+	d, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s '%s', expected YYYY-MM-DD: %w", name, value, err)
+	}
+	return d, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(name, value, fallback string) (int, error) {
+	if value == "" {
+		value = fallback
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s', expected HH:MM: %w", name, value, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func formatSkipped(skipped []holidays.Holiday) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nHolidays skipped:")
+	for _, h := range skipped {
+		fmt.Fprintf(&b, "\n  %s: %s", h.Date.Format(time.DateOnly), h.Name)
+	}
+	return b.String()
+}
+
+// coverageNote warns when the holiday calendar has no data for part of a date range
+func coverageNote(cal *holidays.WorkCalendar, from, to time.Time) string {
+	// A calendar without any holidays is explained by the caller
+	if !cal.HasHolidays() {
+		return ""
+	}
+
+	if from.After(to) {
+		from, to = to, from
+	}
+
+	var missing []string
+	for year := from.Year(); year <= to.Year(); year++ {
+		if !cal.Covers(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			missing = append(missing, fmt.Sprint(year))
+		}
+	}
+
+	if len(missing) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\nNote: the holiday calendar has no data for %s, only weekends were excluded there.", strings.Join(missing, ", "))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
+)
+
+func TestBusinessDaysTool_Handle(t *testing.T) {
+	tool := NewBusinessDaysTool(bundledCalendars())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantErr     bool
+	}{
+		{
+			name:        "count skips Easter holidays",
+			args:        `{"operation": "count", "country": "Spain", "region": "Catalonia", "start_date": "2026-03-30", "end_date": "2026-04-10"}`,
+			wantContain: []string{"Working days in Spain - Catalonia after 2026-03-30 up to and including 2026-04-10: 7", "2026-04-03: Good Friday", "2026-04-06: Easter Monday"},
+		},
+		{
+			name:        "count warns about years without holiday data",
+			args:        `{"operation": "count", "country": "US", "start_date": "2026-12-01", "end_date": "2027-01-15"}`,
+			wantContain: []string{"no data for 2027"},
+		},
+		{
+			name:        "add skips Christmas",
+			args:        `{"operation": "add", "country": "US", "start_date": "2026-12-23", "days": 2}`,
+			wantContain: []string{"2 working days after 2026-12-23 in United States: 2026-12-28 (Monday)", "Christmas Day"},
+		},
+		{
+			name:        "subtract",
+			args:        `{"operation": "add", "country": "Spain", "start_date": "2026-04-10", "days": -7}`,
+			wantContain: []string{"7 working days before 2026-04-10", "2026-03-30 (Monday)"},
+		},
+		{
+			name:        "country without calendar uses its weekend",
+			args:        `{"operation": "count", "country": "Saudi Arabia", "start_date": "2026-01-01", "end_date": "2026-01-08"}`,
+			wantContain: []string{"Working days in Saudi Arabia", ": 5", "only weekends were excluded"},
+		},
+		{
+			name:        "holiday is not working time",
+			args:        `{"operation": "is_working_time", "country": "Spain", "datetime": "2026-09-11T10:00", "timezone": "Europe/Madrid"}`,
+			wantContain: []string{"Not working time", "holiday (National Day of Catalonia)"},
+		},
+		{
+			name:        "weekend is not working time",
+			args:        `{"operation": "is_working_time", "country": "Spain", "datetime": "2026-09-12T10:00", "timezone": "Europe/Madrid"}`,
+			wantContain: []string{"weekend day"},
+		},
+		{
+			name:        "outside working hours",
+			args:        `{"operation": "is_working_time", "country": "Spain", "datetime": "2026-09-14T08:30", "timezone": "Europe/Madrid"}`,
+			wantContain: []string{"outside working hours (09:00-17:00)"},
+		},
+		{
+			name:        "RFC3339 time is converted to the local timezone",
+			args:        `{"operation": "is_working_time", "country": "Spain", "datetime": "2026-09-14T06:30:00Z", "timezone": "Europe/Madrid", "work_start": "08:00"}`,
+			wantContain: []string{"Working time: 2026-09-14 08:30 CEST (Monday)", "(08:00-17:00)"},
+		},
+		{
+			name:    "missing end date",
+			args:    `{"operation": "count", "start_date": "2026-03-30"}`,
+			wantErr: true,
+		},
+		{
+			name:    "too many days",
+			args:    `{"operation": "add", "start_date": "2026-03-30", "days": 100000}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			args:    `{"operation": "is_working_time", "datetime": "2026-09-14T10:00", "timezone": "Mars/Olympus"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown operation",
+			args:    `{"operation": "multiply"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestBusinessDaysTool_HandleReportsLoadErrors(t *testing.T) {
+	tool := NewBusinessDaysTool(holidays.NewCalendars([]holidays.Source{
+		{Country: "Spain", Code: "ES", URL: "file://missing.ics"},
+	}))
+
+	_, err := tool.Handle(context.Background(), `{"operation": "count", "country": "Spain", "start_date": "2026-03-30", "end_date": "2026-04-10"}`)
+	if err == nil || !strings.Contains(err.Error(), "failed to load holiday calendar") {
+		t.Errorf("expected the load error, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+)
+
+// Cacheable is implemented by tools whose result only depends on their
+// arguments, so the result for the same arguments can be reused
+type Cacheable interface {
+	// CacheKey returns the arguments in a normal form, so equal arguments
+	// written differently share a result. It reports false for arguments the
+	// result depends on more than, e.g. the current time.
+	CacheKey(args string) (string, bool)
+}
+
+// Cached wraps the cacheable tools so their results are reused from c
+func Cached(tools []Tool, c *cache.Cache) []Tool {
+	if c == nil {
+		return tools
+	}
+
+	wrapped := make([]Tool, len(tools))
+	for i, t := range tools {
+		if cacheable, ok := t.(Cacheable); ok {
+			t = &cachedTool{Tool: t, key: cacheable.CacheKey, cache: c}
+		}
+		wrapped[i] = t
+	}
+	return wrapped
+}
+
+type cachedTool struct {
+	Tool
+	key   func(args string) (string, bool)
+	cache *cache.Cache
+}
+
+// Handle returns the cached result for the arguments, or runs the tool and
+// caches its result. Failures aren't cached.
+func (t *cachedTool) Handle(ctx context.Context, args string) (string, error) {
+	normal, ok := t.key(args)
+	if !ok {
+		return t.Tool.Handle(ctx, args)
+	}
+
+	key, err := cache.Key(cache.KindTool, t.Name(), normal)
+	if err != nil {
+		return t.Tool.Handle(ctx, args)
+	}
+
+	if result, ok := t.cache.Get(ctx, cache.KindTool, key); ok {
+		return string(result), nil
+	}
+
+	result, err := t.Tool.Handle(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	t.cache.Set(ctx, cache.KindTool, key, []byte(result))
+	return result, nil
+}
+
+// normalArgs encodes normalised arguments as a cache key
+func normalArgs(params any) (string, bool) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/cache"
+)
+
+// countedTool counts its runs and caches arguments other than "now"
+type countedTool struct {
+	namedTool
+	runs int
+	err  error
+}
+
+func (t *countedTool) CacheKey(args string) (string, bool) {
+	return args, args != "now"
+}
+
+func (t *countedTool) Handle(ctx context.Context, args string) (string, error) {
+	t.runs++
+	if t.err != nil {
+		return "", t.err
+	}
+	return "result of " + args, nil
+}
+
+func TestCached(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses results of cacheable tools", func(t *testing.T) {
+		tool := &countedTool{namedTool: "convert_units"}
+		wrapped := Cached([]Tool{tool, namedTool("notes")}, cache.New(cache.NewLRU(10)))
+
+		for range 2 {
+			if result, err := wrapped[0].Handle(ctx, "1 km"); err != nil || result != "result of 1 km" {
+				t.Fatalf("unexpected result %q, %v", result, err)
+			}
+		}
+		if tool.runs != 1 {
+			t.Errorf("expected one run, got %d", tool.runs)
+		}
+		if _, ok := wrapped[1].(namedTool); !ok {
+			t.Errorf("expected tools that aren't cacheable to be left alone, got %T", wrapped[1])
+		}
+	})
+
+	t.Run("runs tools for arguments that aren't cacheable", func(t *testing.T) {
+		tool := &countedTool{namedTool: "convert_timezone"}
+		wrapped := Cached([]Tool{tool}, cache.New(cache.NewLRU(10)))
+
+		_, _ = wrapped[0].Handle(ctx, "now")
+		_, _ = wrapped[0].Handle(ctx, "now")
+		if tool.runs != 2 {
+			t.Errorf("expected two runs, got %d", tool.runs)
+		}
+	})
+
+	t.Run("doesn't cache failures", func(t *testing.T) {
+		tool := &countedTool{namedTool: "get_holidays", err: errors.New("calendar unavailable")}
+		wrapped := Cached([]Tool{tool}, cache.New(cache.NewLRU(10)))
+
+		for range 2 {
+			if _, err := wrapped[0].Handle(ctx, "ES"); err == nil {
+				t.Fatal("expected an error")
+			}
+		}
+		if tool.runs != 2 {
+			t.Errorf("expected two runs, got %d", tool.runs)
+		}
+	})
+
+	t.Run("a nil cache leaves tools alone", func(t *testing.T) {
+		tool := &countedTool{namedTool: "convert_units"}
+		if wrapped := Cached([]Tool{tool}, nil); wrapped[0] != tool {
+			t.Errorf("expected the tool itself, got %T", wrapped[0])
+		}
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	tz := NewTimeZoneTool(nil)
+
+	a, ok := tz.CacheKey(`{"time": "2025-12-15T15:00:00+01:00", "from_timezone": "UTC", "to_timezone": "Asia/Tokyo"}`)
+	b, _ := tz.CacheKey(`{"to_timezone":"Asia/Tokyo","from_timezone":"UTC","time":"2025-12-15T14:00:00Z"}`)
+	if !ok || a != b {
+		t.Errorf("expected the same instant to share a key, got %q and %q", a, b)
+	}
+
+	for _, args := range []string{`{"time": "now", "from_timezone": "UTC", "to_timezone": "Asia/Tokyo"}`, `{"from_timezone": "UTC", "to_timezone": "Asia/Tokyo"}`} {
+		if _, ok := tz.CacheKey(args); ok {
+			t.Errorf("expected conversions of the current time not to be cached: %s", args)
+		}
+	}
+}
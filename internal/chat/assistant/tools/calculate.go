@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/calc"
+	"github.com/openai/openai-go/v2"
+)
+
+// CalculateTool evaluates arithmetic exactly, so bills, tips and budgets add up
+type CalculateTool struct{}
+
+// NewCalculateTool creates a new calculator tool
+func NewCalculateTool() *CalculateTool {
+	return &CalculateTool{}
+}
+
+func (t *CalculateTool) Name() string {
+	return "calculate"
+}
+
+func (t *CalculateTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "calculate",
+		Description: openai.String("Evaluate an arithmetic expression exactly. Use this instead of doing arithmetic yourself for " +
+			"splitting bills, tips, discounts, budgets and itinerary totals. Supports + - * / ^, parentheses, 'mod', " +
+			"percentages ('84.50 + 18%' adds 18% of 84.50, '18% of 84.50'), the functions abs, round(x, decimals), floor, ceil, " +
+			"min, max, sum, avg and sqrt, and variables: statements separated by ';' or new lines may assign 'name = expr', " +
+			"and the last statement is the result. Numbers are plain decimals without currency symbols or thousands separators."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"expression": map[string]string{
+					"type":        "string",
+					"description": "Expression to evaluate, e.g. 'meal = 84.50 + 18%; meal / 3'",
+				},
+				"variables": map[string]any{
+					"type":                 "object",
+					"description":          "Optional named values used by the expression, e.g. {\"people\": 4}",
+					"additionalProperties": map[string]string{"type": "number"},
+				},
+			},
+			"required": []string{"expression"},
+		},
+	})
+}
+
+func (t *CalculateTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Expression string                 `json:"expression"`
+		Variables  map[string]json.Number `json:"variables"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid calculate parameters: %w", err)
+	}
+
+	vars := make(map[string]*big.Rat, len(params.Variables))
+	for name, n := range params.Variables {
+		v, err := calc.ParseNumber(n.String())
+		if err != nil {
+			return "", fmt.Errorf("variable %q: %w", name, err)
+		}
+		vars[name] = v
+	}
+
+	result, err := calc.Evaluate(params.Expression, vars)
+	if err != nil {
+		return "", err
+	}
+
+	value, exact := calc.FormatResult(result.Value)
+
+	var b strings.Builder
+	switch {
+	case result.Approximate:
+		fmt.Fprintf(&b, "Result: ≈ %s (square roots are rounded)", value)
+	case !exact:
+		fmt.Fprintf(&b, "Result: ≈ %s (rounded; exactly %s)", value, result.Value.RatString())
+	default:
+		fmt.Fprintf(&b, "Result: %s", value)
+	}
+
+	if len(result.Assigned) > 0 {
+		assigned := make([]string, len(result.Assigned))
+		for i, a := range result.Assigned {
+			v, exact := calc.FormatResult(a.Value)
+			if !exact || result.Approximate {
+				v = "≈ " + v
+			}
+			assigned[i] = a.Name + " = " + v
+		}
+		fmt.Fprintf(&b, "\nWhere %s", strings.Join(assigned, ", "))
+	}
+	return b.String(), nil
+}
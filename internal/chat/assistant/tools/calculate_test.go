@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCalculateTool_Handle(t *testing.T) {
+	ctx := context.Background()
+	tool := NewCalculateTool()
+
+	tests := []struct {
+		name        string
+		args        string
+		want        string
+		wantContain []string
+		wantErr     bool
+	}{
+		{
+			name: "exact decimals",
+			args: `{"expression": "0.1 + 0.2"}`,
+			want: "Result: 0.3",
+		},
+		{
+			name: "tip on a bill",
+			args: `{"expression": "84.50 + 18%"}`,
+			want: "Result: 99.71",
+		},
+		{
+			name: "split with variables",
+			args: `{"expression": "total / people", "variables": {"total": 245.5, "people": 4}}`,
+			want: "Result: 61.375",
+		},
+		{
+			name: "assignments are reported",
+			args: `{"expression": "hotel = 3 * 140\nflights = 2 * 389.99\nhotel + flights"}`,
+			want: "Result: 1199.98\nWhere hotel = 420, flights = 779.98",
+		},
+		{
+			name:        "repeating decimal",
+			args:        `{"expression": "100 / 3"}`,
+			wantContain: []string{"Result: ≈ 33.333333333333", "exactly 100/3"},
+		},
+		{
+			name:        "square root",
+			args:        `{"expression": "side = sqrt(2); side * 10"}`,
+			wantContain: []string{"Result: ≈ 14.142135623731", "square roots are rounded", "side = ≈ 1.414213562373"},
+		},
+		{
+			name:    "division by zero",
+			args:    `{"expression": "10 / 0"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not arithmetic",
+			args:    `{"expression": "import os; os.system('ls')"}`,
+			wantErr: true,
+		},
+		{
+			name:    "oversized input",
+			args:    `{"expression": "` + strings.Repeat("1+", 2000) + `1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid variable",
+			args:    `{"expression": "x", "variables": {"x": 1e99999}}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty expression",
+			args:    `{"expression": ""}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want != "" && result != tt.want {
+				t.Errorf("got %q, want %q", result, tt.want)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/calendar"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxEventTitleLength bounds event titles, in characters
+	maxEventTitleLength = 200
+
+	// maxEventLength bounds how long one event lasts
+	maxEventLength = 31 * 24 * time.Hour
+
+	// maxFreeTimeDays bounds the range find_free_time searches
+	maxFreeTimeDays = 31
+
+	// maxFreeSlots caps how many free slots are listed
+	maxFreeSlots = 20
+)
+
+// CalendarStore persists calendar events per user
+type CalendarStore interface {
+	CreateEvent(ctx context.Context, e *model.Event) error
+	ListEvents(ctx context.Context, userID string, f model.EventFilter) ([]*model.Event, error)
+}
+
+// CreateEventTool adds events to the user's calendar
+type CreateEventTool struct {
+	store  CalendarStore
+	places *geo.Gazetteer
+	now    func() time.Time
+}
+
+// NewCreateEventTool creates a new tool adding events to the calendar in store
+func NewCreateEventTool(store CalendarStore, places *geo.Gazetteer) *CreateEventTool {
+	return &CreateEventTool{
+		store:  store,
+		places: places,
+		now:    time.Now,
+	}
+}
+
+func (t *CreateEventTool) Name() string {
+	return "create_event"
+}
+
+func (t *CreateEventTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "create_event",
+		Description: openai.String("Add an event to the user's personal calendar. Timed events need the local start time " +
+			"and its time zone; all-day events take dates only. Reports events the new one overlaps."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"title": map[string]string{
+					"type":        "string",
+					"description": "What the event is",
+				},
+				"start": map[string]string{
+					"type":        "string",
+					"description": "Local start in YYYY-MM-DDTHH:MM format, or YYYY-MM-DD for all-day events",
+				},
+				"end": map[string]string{
+					"type":        "string",
+					"description": "Optional local end in the same format as start; for all-day events the last day",
+				},
+				"duration_minutes": map[string]string{
+					"type":        "integer",
+					"description": "Length of a timed event when end is not given (default: 60)",
+				},
+				"time_zone": map[string]string{
+					"type":        "string",
+					"description": "IANA time zone (e.g., 'Europe/Madrid') or city of the start and end times",
+				},
+				"location": map[string]string{
+					"type":        "string",
+					"description": "Optional place of the event",
+				},
+				"description": map[string]string{
+					"type":        "string",
+					"description": "Optional notes, such as a booking reference",
+				},
+			},
+			"required": []string{"title", "start"},
+		},
+	})
+}
+
+func (t *CreateEventTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Title           string `json:"title"`
+		Start           string `json:"start"`
+		End             string `json:"end"`
+		DurationMinutes int    `json:"duration_minutes"`
+		TimeZone        string `json:"time_zone"`
+		Location        string `json:"location"`
+		Description     string `json:"description"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid create_event parameters: %w", err)
+	}
+
+	title := strings.TrimSpace(params.Title)
+	switch {
+	case title == "":
+		return "", fmt.Errorf("title is required to create an event")
+	case utf8.RuneCountInString(title) > maxEventTitleLength:
+		return "", fmt.Errorf("title is too long, the limit is %d characters", maxEventTitleLength)
+	}
+
+	loc, label := time.UTC, "UTC"
+	if params.TimeZone != "" {
+		var err error
+		if loc, label, err = resolveZone(t.places, params.TimeZone); err != nil {
+			return "", fmt.Errorf("invalid time_zone '%s': %w", params.TimeZone, err)
+		}
+	}
+
+	now := t.now()
+	e := &model.Event{
+		ID:          primitive.NewObjectID(),
+		UserID:      auth.FromContext(ctx),
+		Title:       title,
+		Description: strings.TrimSpace(params.Description),
+		Location:    strings.TrimSpace(params.Location),
+		TimeZone:    loc.String(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	e.UID = e.ID.Hex() + "@personal-assistant"
+
+	if len(params.Start) == len(time.DateOnly) {
+		start, err := parseDate("start", params.Start)
+		if err != nil {
+			return "", err
+		}
+		last := start
+		if params.End != "" {
+			if last, err = parseDate("end", params.End); err != nil {
+				return "", err
+			}
+		}
+		e.Start, e.End, e.AllDay = start, last.AddDate(0, 0, 1), true
+	} else {
+		if params.TimeZone == "" {
+			return "", fmt.Errorf("time_zone is required for timed events, ask the user where they are")
+		}
+		start, err := parseLocalTime(params.Start, loc)
+		if err != nil {
+			return "", fmt.Errorf("invalid start: %w", err)
+		}
+
+		end := start.Add(time.Hour)
+		switch {
+		case params.End != "":
+			if end, err = parseLocalTime(params.End, loc); err != nil {
+				return "", fmt.Errorf("invalid end: %w", err)
+			}
+		case params.DurationMinutes < 0:
+			return "", fmt.Errorf("duration_minutes must be positive")
+		case params.DurationMinutes > 0:
+			end = start.Add(time.Duration(params.DurationMinutes) * time.Minute)
+		}
+		e.Start, e.End = start, end
+	}
+
+	switch {
+	case !e.End.After(e.Start):
+		return "", fmt.Errorf("the event must end after it starts")
+	case e.End.Sub(e.Start) > maxEventLength:
+		return "", fmt.Errorf("events can last at most 31 days")
+	}
+
+	// Look for clashes before saving, so the new event isn't one of them
+	overlapping, err := t.store.ListEvents(ctx, e.UserID, model.EventFilter{From: e.Start, To: e.End})
+	if err != nil {
+		return "", fmt.Errorf("failed to check the calendar: %w", err)
+	}
+
+	if err := t.store.CreateEvent(ctx, e); err != nil {
+		return "", fmt.Errorf("failed to save event: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Added to the calendar: %s, %s", e.Title, formatEventTime(e, loc))
+	if !e.AllDay {
+		fmt.Fprintf(&b, " (%s)", label)
+	}
+	fmt.Fprintf(&b, " (id %s)", e.ID.Hex())
+
+	var clashes []string
+	for _, o := range overlapping {
+		if !o.AllDay && !e.AllDay {
+			clashes = append(clashes, fmt.Sprintf("%s, %s", o.Title, formatEventTime(o, loc)))
+		}
+	}
+	if len(clashes) > 0 {
+		b.WriteString("\nOverlaps with: " + strings.Join(clashes, "; "))
+	}
+
+	return b.String(), nil
+}
+
+// ListEventsTool lists and searches the user's calendar
+type ListEventsTool struct {
+	store  CalendarStore
+	places *geo.Gazetteer
+	now    func() time.Time
+}
+
+// NewListEventsTool creates a new tool listing events of the calendar in store
+func NewListEventsTool(store CalendarStore, places *geo.Gazetteer) *ListEventsTool {
+	return &ListEventsTool{
+		store:  store,
+		places: places,
+		now:    time.Now,
+	}
+}
+
+func (t *ListEventsTool) Name() string {
+	return "list_events"
+}
+
+func (t *ListEventsTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "list_events",
+		Description: openai.String("List events in the user's personal calendar, including imported bookings such as " +
+			"flights and hotels. Use 'query' to answer questions like 'when is my flight?'. " +
+			"Without dates, lists upcoming events for the next 30 days, or the next year when searching."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"from": map[string]string{
+					"type":        "string",
+					"description": "First day in YYYY-MM-DD format (default: today)",
+				},
+				"to": map[string]string{
+					"type":        "string",
+					"description": "Last day in YYYY-MM-DD format",
+				},
+				"query": map[string]string{
+					"type":        "string",
+					"description": "Text to look for in titles, descriptions and locations, ignoring case",
+				},
+				"time_zone": map[string]string{
+					"type":        "string",
+					"description": "IANA time zone or city to show times in (default: each event's own zone)",
+				},
+			},
+		},
+	})
+}
+
+func (t *ListEventsTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Query    string `json:"query"`
+		TimeZone string `json:"time_zone"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid list_events parameters: %w", err)
+	}
+
+	var loc *time.Location
+	if params.TimeZone != "" {
+		var err error
+		if loc, _, err = resolveZone(t.places, params.TimeZone); err != nil {
+			return "", fmt.Errorf("invalid time_zone '%s': %w", params.TimeZone, err)
+		}
+	}
+
+	filter := model.EventFilter{Query: strings.TrimSpace(params.Query), From: t.now()}
+	days := 30
+	if filter.Query != "" {
+		days = 365
+	}
+
+	var err error
+	if params.From != "" {
+		if filter.From, err = parseDay("from", params.From, loc); err != nil {
+			return "", err
+		}
+	}
+	filter.To = filter.From.AddDate(0, 0, days)
+	if params.To != "" {
+		if filter.To, err = parseDay("to", params.To, loc); err != nil {
+			return "", err
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	if !filter.To.After(filter.From) {
+		return "", fmt.Errorf("to must not be before from")
+	}
+
+	events, err := t.store.ListEvents(ctx, auth.FromContext(ctx), filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to list events: %w", err)
+	}
+
+	period := fmt.Sprintf("between %s and %s", filter.From.Format(time.DateOnly), filter.To.AddDate(0, 0, -1).Format(time.DateOnly))
+	if len(events) == 0 {
+		if filter.Query != "" {
+			return fmt.Sprintf("No events matching %q %s.", filter.Query, period), nil
+		}
+		return fmt.Sprintf("No events %s.", period), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d event(s) %s:", len(events), period)
+	for _, e := range events {
+		fmt.Fprintf(&b, "\n- %s: %s", formatEventTime(e, loc), e.Title)
+		if e.Location != "" {
+			fmt.Fprintf(&b, " @ %s", e.Location)
+		}
+		fmt.Fprintf(&b, " (id %s)", e.ID.Hex())
+		if e.Description != "" {
+			fmt.Fprintf(&b, "\n  %s", strings.ReplaceAll(truncate(e.Description, notePreviewLength), "\n", " / "))
+		}
+	}
+	return b.String(), nil
+}
+
+// FindFreeTimeTool finds free slots between the user's calendar events
+type FindFreeTimeTool struct {
+	store  CalendarStore
+	places *geo.Gazetteer
+	now    func() time.Time
+}
+
+// NewFindFreeTimeTool creates a new tool finding free time in the calendar in store
+func NewFindFreeTimeTool(store CalendarStore, places *geo.Gazetteer) *FindFreeTimeTool {
+	return &FindFreeTimeTool{
+		store:  store,
+		places: places,
+		now:    time.Now,
+	}
+}
+
+func (t *FindFreeTimeTool) Name() string {
+	return "find_free_time"
+}
+
+func (t *FindFreeTimeTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "find_free_time",
+		Description: openai.String("Find free slots of at least a given length in the user's personal calendar, within " +
+			"working hours on each day. Searches up to 31 days; all-day events don't block time."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"duration_minutes": map[string]string{
+					"type":        "integer",
+					"description": "Minimum length of a free slot",
+				},
+				"time_zone": map[string]string{
+					"type":        "string",
+					"description": "IANA time zone (e.g., 'Europe/Madrid') or city the working hours are in",
+				},
+				"from": map[string]string{
+					"type":        "string",
+					"description": "First day in YYYY-MM-DD format (default: today, from now on)",
+				},
+				"to": map[string]string{
+					"type":        "string",
+					"description": "Last day in YYYY-MM-DD format (default: 7 days from the first)",
+				},
+				"work_start": map[string]string{
+					"type":        "string",
+					"description": "Start of the day in HH:MM (default: 09:00)",
+				},
+				"work_end": map[string]string{
+					"type":        "string",
+					"description": "End of the day in HH:MM (default: 18:00)",
+				},
+				"include_weekends": map[string]string{
+					"type":        "boolean",
+					"description": "Also look on Saturdays and Sundays",
+				},
+			},
+			"required": []string{"duration_minutes", "time_zone"},
+		},
+	})
+}
+
+func (t *FindFreeTimeTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		DurationMinutes int    `json:"duration_minutes"`
+		TimeZone        string `json:"time_zone"`
+		From            string `json:"from"`
+		To              string `json:"to"`
+		WorkStart       string `json:"work_start"`
+		WorkEnd         string `json:"work_end"`
+		IncludeWeekends bool   `json:"include_weekends"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid find_free_time parameters: %w", err)
+	}
+
+	if params.DurationMinutes <= 0 {
+		return "", fmt.Errorf("duration_minutes is required and must be positive")
+	}
+	length := time.Duration(params.DurationMinutes) * time.Minute
+
+	if params.TimeZone == "" {
+		return "", fmt.Errorf("time_zone is required, ask the user where they are")
+	}
+	loc, label, err := resolveZone(t.places, params.TimeZone)
+	if err != nil {
+		return "", fmt.Errorf("invalid time_zone '%s': %w", params.TimeZone, err)
+	}
+
+	hours := calendar.Hours{Weekends: params.IncludeWeekends}
+	if hours.Start, err = parseClock("work_start", params.WorkStart, "09:00"); err != nil {
+		return "", err
+	}
+	if hours.End, err = parseClock("work_end", params.WorkEnd, "18:00"); err != nil {
+		return "", err
+	}
+	if hours.End <= hours.Start {
+		return "", fmt.Errorf("work_end must be after work_start")
+	}
+
+	now := t.now()
+	from := now
+	if params.From != "" {
+		if from, err = parseDay("from", params.From, loc); err != nil {
+			return "", err
+		}
+		if from.Before(now) {
+			from = now
+		}
+	}
+	first := from.In(loc)
+	to := time.Date(first.Year(), first.Month(), first.Day()+7, 0, 0, 0, 0, loc)
+	if params.To != "" {
+		if to, err = parseDay("to", params.To, loc); err != nil {
+			return "", err
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	switch {
+	case !to.After(from):
+		return "", fmt.Errorf("the range has no time left to search, it ends before %s", from.In(loc).Format("Mon 2 Jan 2006 15:04"))
+	case to.Sub(from) > maxFreeTimeDays*24*time.Hour:
+		return "", fmt.Errorf("find_free_time searches at most %d days at a time", maxFreeTimeDays)
+	}
+
+	events, err := t.store.ListEvents(ctx, auth.FromContext(ctx), model.EventFilter{From: from, To: to, Limit: 1000})
+	if err != nil {
+		return "", fmt.Errorf("failed to list events: %w", err)
+	}
+
+	slots := calendar.FreeTime(events, from, to, loc, hours, length)
+	window := fmt.Sprintf("%s–%s %s", formatClock(hours.Start), formatClock(hours.End), label)
+	if len(slots) == 0 {
+		return fmt.Sprintf("No free slot of %d minutes between %s and %s (%s).",
+			params.DurationMinutes, from.In(loc).Format("Mon 2 Jan"), to.In(loc).AddDate(0, 0, -1).Format("Mon 2 Jan 2006"), window), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Free slots of at least %d minutes (%s):", params.DurationMinutes, window)
+	for i, s := range slots {
+		if i == maxFreeSlots {
+			fmt.Fprintf(&b, "\n...and %d more", len(slots)-maxFreeSlots)
+			break
+		}
+		start, end := s.Start.In(loc), s.End.In(loc)
+		fmt.Fprintf(&b, "\n- %s %s–%s (%s)", start.Format("Mon 2 Jan 2006"), start.Format("15:04"), end.Format("15:04 MST"), describeDelay(end.Sub(start)))
+	}
+	return b.String(), nil
+}
+
+// parseDay parses a YYYY-MM-DD date as midnight in loc, UTC when loc is nil
+func parseDay(name, value string, loc *time.Location) (time.Time, error) {
+	d, err := parseDate(name, value)
+	if err != nil || loc == nil {
+		return d, err
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc), nil
+}
+
+// formatEventTime renders when an event is, in loc or the event's own zone when loc is nil
+func formatEventTime(e *model.Event, loc *time.Location) string {
+	if e.AllDay {
+		first, last := e.Start.UTC(), e.End.UTC().AddDate(0, 0, -1)
+		if !last.After(first) {
+			return first.Format("Mon 2 Jan 2006") + " (all day)"
+		}
+		return first.Format("Mon 2 Jan") + " – " + last.Format("Mon 2 Jan 2006") + " (all day)"
+	}
+
+	if loc == nil {
+		loc = e.Zone()
+	}
+	start, end := e.Start.In(loc), e.End.In(loc)
+	if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+		return start.Format("Mon 2 Jan 2006 15:04") + "–" + end.Format("15:04 MST")
+	}
+	return start.Format("Mon 2 Jan 2006 15:04 MST") + " – " + end.Format("Mon 2 Jan 2006 15:04 MST")
+}
@@ -0,0 +1,381 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryEvents is an in-memory CalendarStore
+type memoryEvents struct {
+	events []*model.Event
+}
+
+func (m *memoryEvents) CreateEvent(ctx context.Context, e *model.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func (m *memoryEvents) ListEvents(ctx context.Context, userID string, f model.EventFilter) ([]*model.Event, error) {
+	var out []*model.Event
+	for _, e := range m.events {
+		if e.UserID != userID || !f.From.IsZero() && !e.End.After(f.From) || !f.To.IsZero() && !e.Start.Before(f.To) {
+			continue
+		}
+		if f.Query != "" && !containsFold(e.Title, f.Query) && !containsFold(e.Description, f.Query) && !containsFold(e.Location, f.Query) {
+			continue
+		}
+		out = append(out, e)
+	}
+	slices.SortFunc(out, func(a, b *model.Event) int { return a.Start.Compare(b.Start) })
+	return out, nil
+}
+
+func (m *memoryEvents) add(userID, title string, start, end time.Time, zone string) *model.Event {
+	e := &model.Event{ID: primitive.NewObjectID(), UserID: userID, Title: title, Start: start, End: end, TimeZone: zone}
+	m.events = append(m.events, e)
+	return e
+}
+
+// calendarNow is a Monday morning in Madrid
+var calendarNow = time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+
+func TestCreateEventTool_Handle(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+
+	tests := []struct {
+		name        string
+		args        string
+		wantStart   time.Time
+		wantEnd     time.Time
+		wantAllDay  bool
+		wantContain []string
+		wantErr     string
+	}{
+		{
+			name:        "timed event with end",
+			args:        `{"title": "Dentist", "start": "2025-09-10T09:30", "end": "2025-09-10T10:15", "time_zone": "Europe/Madrid", "location": "Carrer de Mallorca 120"}`,
+			wantStart:   time.Date(2025, 9, 10, 9, 30, 0, 0, madrid),
+			wantEnd:     time.Date(2025, 9, 10, 10, 15, 0, 0, madrid),
+			wantContain: []string{"Added to the calendar: Dentist, Wed 10 Sep 2025 09:30–10:15 CEST (Europe/Madrid)"},
+		},
+		{
+			name:        "default duration and city",
+			args:        `{"title": "Call with Kenji", "start": "2025-09-04T18:00", "time_zone": "Tokyo"}`,
+			wantStart:   time.Date(2025, 9, 4, 9, 0, 0, 0, time.UTC),
+			wantEnd:     time.Date(2025, 9, 4, 10, 0, 0, 0, time.UTC),
+			wantContain: []string{"Thu 4 Sep 2025 18:00–19:00 JST", "Asia/Tokyo"},
+		},
+		{
+			name:        "duration",
+			args:        `{"title": "Run", "start": "2025-09-02T07:00", "duration_minutes": 45, "time_zone": "Europe/Madrid"}`,
+			wantStart:   time.Date(2025, 9, 2, 7, 0, 0, 0, madrid),
+			wantEnd:     time.Date(2025, 9, 2, 7, 45, 0, 0, madrid),
+			wantContain: []string{"07:00–07:45 CEST"},
+		},
+		{
+			name:        "all-day event over several days",
+			args:        `{"title": "Hotel Gracery", "start": "2025-09-03", "end": "2025-09-06"}`,
+			wantStart:   time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC),
+			wantEnd:     time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC),
+			wantAllDay:  true,
+			wantContain: []string{"Hotel Gracery, Wed 3 Sep – Sat 6 Sep 2025 (all day)"},
+		},
+		{
+			name:        "single all-day event",
+			args:        `{"title": "Day off", "start": "2025-09-12"}`,
+			wantStart:   time.Date(2025, 9, 12, 0, 0, 0, 0, time.UTC),
+			wantEnd:     time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC),
+			wantAllDay:  true,
+			wantContain: []string{"Fri 12 Sep 2025 (all day)"},
+		},
+		{
+			name:    "timed event without a zone",
+			args:    `{"title": "Dentist", "start": "2025-09-10T09:30"}`,
+			wantErr: "time_zone is required",
+		},
+		{
+			name:    "end before start",
+			args:    `{"title": "Dentist", "start": "2025-09-10T09:30", "end": "2025-09-10T09:00", "time_zone": "UTC"}`,
+			wantErr: "must end after it starts",
+		},
+		{
+			name:    "too long",
+			args:    `{"title": "Sabbatical", "start": "2025-09-01", "end": "2025-12-01"}`,
+			wantErr: "at most 31 days",
+		},
+		{
+			name:    "bad start",
+			args:    `{"title": "Dentist", "start": "next week", "time_zone": "UTC"}`,
+			wantErr: "invalid start",
+		},
+		{
+			name:    "negative duration",
+			args:    `{"title": "Dentist", "start": "2025-09-10T09:30", "duration_minutes": -5, "time_zone": "UTC"}`,
+			wantErr: "must be positive",
+		},
+		{
+			name:    "no title",
+			args:    `{"start": "2025-09-10"}`,
+			wantErr: "title is required",
+		},
+		{
+			name:    "invalid json",
+			args:    `{"title":`,
+			wantErr: "invalid create_event parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryEvents{}
+			tool := NewCreateEventTool(store, geo.Default())
+			tool.now = func() time.Time { return calendarNow }
+
+			result, err := tool.Handle(auth.WithUser(context.Background(), "ana"), tt.args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+
+			if len(store.events) != 1 {
+				t.Fatalf("expected one stored event, got %d", len(store.events))
+			}
+			e := store.events[0]
+			if !e.Start.Equal(tt.wantStart) || !e.End.Equal(tt.wantEnd) || e.AllDay != tt.wantAllDay || e.UserID != "ana" {
+				t.Errorf("stored %s–%s all-day %v for %q, want %s–%s all-day %v", e.Start, e.End, e.AllDay, e.UserID, tt.wantStart, tt.wantEnd, tt.wantAllDay)
+			}
+			if !strings.Contains(result, e.ID.Hex()) || !strings.HasPrefix(e.UID, e.ID.Hex()) {
+				t.Errorf("expected the event ID in the result and UID, got %s and %q", result, e.UID)
+			}
+		})
+	}
+}
+
+func TestCreateEventTool_Overlaps(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	store := &memoryEvents{}
+	store.add("ana", "Standup", time.Date(2025, 9, 10, 9, 0, 0, 0, madrid), time.Date(2025, 9, 10, 9, 45, 0, 0, madrid), "Europe/Madrid")
+	store.add("ana", "Conference", time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 11, 0, 0, 0, 0, time.UTC), "Europe/Madrid").AllDay = true
+	store.add("bob", "Bob's standup", time.Date(2025, 9, 10, 9, 0, 0, 0, madrid), time.Date(2025, 9, 10, 10, 0, 0, 0, madrid), "Europe/Madrid")
+
+	tool := NewCreateEventTool(store, nil)
+	tool.now = func() time.Time { return calendarNow }
+
+	result, err := tool.Handle(auth.WithUser(context.Background(), "ana"), `{"title": "Dentist", "start": "2025-09-10T09:30", "time_zone": "Europe/Madrid"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "Overlaps with: Standup, Wed 10 Sep 2025 09:00–09:45 CEST"; !strings.HasSuffix(result, want) {
+		t.Errorf("expected result to end with '%s', got: %s", want, result)
+	}
+	if strings.Contains(result, "Conference") || strings.Contains(result, "Bob") {
+		t.Errorf("expected only timed events of the user as overlaps, got: %s", result)
+	}
+}
+
+func TestListEventsTool_Handle(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	store := &memoryEvents{}
+	store.add("ana", "Yesterday's lunch", time.Date(2025, 8, 31, 12, 0, 0, 0, time.UTC), time.Date(2025, 8, 31, 13, 0, 0, 0, time.UTC), "Europe/Madrid")
+	dentist := store.add("ana", "Dentist", time.Date(2025, 9, 10, 9, 30, 0, 0, madrid), time.Date(2025, 9, 10, 10, 15, 0, 0, madrid), "Europe/Madrid")
+	dentist.Location = "Carrer de Mallorca 120"
+	flight := store.add("ana", "Flight IB3166 Madrid → Tokyo", time.Date(2026, 2, 2, 7, 15, 0, 0, time.UTC), time.Date(2026, 2, 2, 19, 30, 0, 0, time.UTC), "Europe/Madrid")
+	flight.Description = "Booking reference XK7P2Q\nSeat 34A"
+	store.add("bob", "Bob's flight", time.Date(2025, 9, 5, 7, 0, 0, 0, time.UTC), time.Date(2025, 9, 5, 9, 0, 0, 0, time.UTC), "UTC")
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantMissing []string
+		wantErr     string
+	}{
+		{
+			name:        "upcoming month",
+			args:        `{}`,
+			wantContain: []string{"1 event(s) between 2025-09-01 and 2025-09-30", "Wed 10 Sep 2025 09:30–10:15 CEST: Dentist @ Carrer de Mallorca 120 (id " + dentist.ID.Hex()},
+			wantMissing: []string{"lunch", "Flight", "Bob"},
+		},
+		{
+			name:        "search looks a year ahead",
+			args:        `{"query": "flight"}`,
+			wantContain: []string{"Mon 2 Feb 2026 08:15–20:30 CET: Flight IB3166 Madrid → Tokyo", "Booking reference XK7P2Q / Seat 34A"},
+			wantMissing: []string{"Dentist", "Bob"},
+		},
+		{
+			name:        "shown in another zone",
+			args:        `{"query": "flight", "time_zone": "Tokyo"}`,
+			wantContain: []string{"Mon 2 Feb 2026 16:15 JST – Tue 3 Feb 2026 04:30 JST"},
+		},
+		{
+			name:        "date range",
+			args:        `{"from": "2025-08-31", "to": "2025-08-31"}`,
+			wantContain: []string{"between 2025-08-31 and 2025-08-31", "Yesterday's lunch"},
+			wantMissing: []string{"Dentist"},
+		},
+		{
+			name:        "nothing found",
+			args:        `{"query": "opera"}`,
+			wantContain: []string{`No events matching "opera" between 2025-09-01 and 2026-08-31.`},
+		},
+		{
+			name:    "range backwards",
+			args:    `{"from": "2025-09-10", "to": "2025-09-01"}`,
+			wantErr: "to must not be before from",
+		},
+		{
+			name:    "bad date",
+			args:    `{"from": "10/09/2025"}`,
+			wantErr: "expected YYYY-MM-DD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewListEventsTool(store, geo.Default())
+			tool.now = func() time.Time { return calendarNow }
+
+			result, err := tool.Handle(auth.WithUser(context.Background(), "ana"), tt.args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(result, missing) {
+					t.Errorf("expected result not to contain '%s', got: %s", missing, result)
+				}
+			}
+		})
+	}
+}
+
+func TestFindFreeTimeTool_Handle(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 9, day, hour, minute, 0, 0, madrid)
+	}
+
+	store := &memoryEvents{}
+	store.add("ana", "Standup", at(1, 9, 0), at(1, 9, 30), "Europe/Madrid")
+	store.add("ana", "Workshop", at(1, 11, 0), at(1, 17, 0), "Europe/Madrid")
+	store.add("ana", "Offsite", at(2, 8, 0), at(2, 18, 0), "Europe/Madrid")
+	store.add("bob", "Bob's day", at(3, 9, 0), at(3, 18, 0), "Europe/Madrid")
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantMissing []string
+		wantErr     string
+	}{
+		{
+			name: "from now on",
+			args: `{"duration_minutes": 60, "time_zone": "Europe/Madrid", "to": "2025-09-03"}`,
+			wantContain: []string{
+				"Free slots of at least 60 minutes (09:00–18:00 Europe/Madrid):",
+				"- Mon 1 Sep 2025 09:45–11:00 CEST (1 hour 15 minutes)",
+				"- Mon 1 Sep 2025 17:00–18:00 CEST (1 hour)",
+				"- Wed 3 Sep 2025 09:00–18:00 CEST (9 hours)",
+			},
+			wantMissing: []string{"Tue 2 Sep"},
+		},
+		{
+			name:        "custom hours and weekends",
+			args:        `{"duration_minutes": 120, "time_zone": "Europe/Madrid", "from": "2025-09-06", "to": "2025-09-07", "work_start": "10:00", "work_end": "13:00", "include_weekends": true}`,
+			wantContain: []string{"(10:00–13:00 Europe/Madrid)", "Sat 6 Sep 2025 10:00–13:00 CEST (3 hours)", "Sun 7 Sep 2025 10:00–13:00 CEST"},
+		},
+		{
+			name:        "no room",
+			args:        `{"duration_minutes": 300, "time_zone": "Europe/Madrid", "from": "2025-09-02", "to": "2025-09-02"}`,
+			wantContain: []string{"No free slot of 300 minutes between Tue 2 Sep and Tue 2 Sep 2025 (09:00–18:00 Europe/Madrid)."},
+		},
+		{
+			name:    "in the past",
+			args:    `{"duration_minutes": 30, "time_zone": "Europe/Madrid", "from": "2025-08-01", "to": "2025-08-02"}`,
+			wantErr: "no time left to search",
+		},
+		{
+			name:    "range too long",
+			args:    `{"duration_minutes": 30, "time_zone": "Europe/Madrid", "to": "2025-12-01"}`,
+			wantErr: "at most 31 days",
+		},
+		{
+			name:    "no duration",
+			args:    `{"time_zone": "Europe/Madrid"}`,
+			wantErr: "duration_minutes is required",
+		},
+		{
+			name:    "no zone",
+			args:    `{"duration_minutes": 30}`,
+			wantErr: "time_zone is required",
+		},
+		{
+			name:    "hours backwards",
+			args:    `{"duration_minutes": 30, "time_zone": "UTC", "work_start": "18:00", "work_end": "09:00"}`,
+			wantErr: "work_end must be after work_start",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewFindFreeTimeTool(store, geo.Default())
+			tool.now = func() time.Time { return at(1, 9, 45) }
+
+			result, err := tool.Handle(auth.WithUser(context.Background(), "ana"), tt.args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(result, missing) {
+					t.Errorf("expected result not to contain '%s', got: %s", missing, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+	"github.com/openai/openai-go/v2"
+)
+
+// significantDigits is the precision of converted measurements
+const significantDigits = 6
+
+// ConvertUnitsTool converts measurements and clothing sizes with fixed tables,
+// so the model doesn't have to do the arithmetic
+type ConvertUnitsTool struct{}
+
+// NewConvertUnitsTool creates a new unit conversion tool
+func NewConvertUnitsTool() *ConvertUnitsTool {
+	return &ConvertUnitsTool{}
+}
+
+func (t *ConvertUnitsTool) Name() string {
+	return "convert_units"
+}
+
+func (t *ConvertUnitsTool) Definition() openai.ChatCompletionToolUnionParam {
+	var (
+		categories []string
+		ids        []string
+		lines      []string
+	)
+	for _, c := range units.Categories() {
+		categories = append(categories, string(c))
+		lines = append(lines, fmt.Sprintf("%s: %s", c, strings.Join(units.Units(c), ", ")))
+		for _, id := range units.Units(c) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "convert_units",
+		Description: openai.String("Convert a measurement or a clothing/shoe size exactly. Use this instead of doing the arithmetic " +
+			"for distances, weights, cooking volumes and oven temperatures, speeds, areas, fuel economy and size charts. " +
+			"Volumes without a uk_ prefix are US customary. Units per category: " + strings.Join(lines, "; ") + "."),
+		Strict: openai.Bool(true),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"category": map[string]any{
+					"type":        "string",
+					"description": "What is being converted",
+					"enum":        categories,
+				},
+				"value": map[string]any{
+					"type":        "number",
+					"description": "Amount or size to convert, e.g. 5 or 9.5",
+				},
+				"from": map[string]any{
+					"type":        "string",
+					"description": "Unit or sizing system of value; must belong to the category",
+					"enum":        ids,
+				},
+				"to": map[string]any{
+					"type":        "string",
+					"description": "Unit or sizing system to convert to; must belong to the category",
+					"enum":        ids,
+				},
+			},
+			"required":             []string{"category", "value", "from", "to"},
+			"additionalProperties": false,
+		},
+	})
+}
+
+type convertUnitsArgs struct {
+	Category units.Category `json:"category"`
+	Value    *float64       `json:"value"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+}
+
+// decodeConvertUnitsArgs matches the strict schema: unknown fields are
+// mistakes, not extras
+func decodeConvertUnitsArgs(args string) (convertUnitsArgs, error) {
+	var params convertUnitsArgs
+	dec := json.NewDecoder(bytes.NewReader([]byte(args)))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&params)
+	return params, err
+}
+
+// CacheKey normalises the JSON of the arguments; conversions only depend on them
+func (t *ConvertUnitsTool) CacheKey(args string) (string, bool) {
+	params, err := decodeConvertUnitsArgs(args)
+	if err != nil {
+		return "", false
+	}
+	return normalArgs(params)
+}
+
+func (t *ConvertUnitsTool) Handle(ctx context.Context, args string) (string, error) {
+	params, err := decodeConvertUnitsArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("invalid conversion parameters: %w", err)
+	}
+	if params.Value == nil {
+		return "", fmt.Errorf("value is required")
+	}
+	if !slices.Contains(units.Categories(), params.Category) {
+		return "", fmt.Errorf("unknown category %q", params.Category)
+	}
+
+	value := *params.Value
+
+	if units.IsSizeChart(params.Category) {
+		size, err := units.ConvertSize(params.Category, value, params.From, params.To)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s size %s %s = %s %s\nSize charts differ between brands; check the brand's own chart when buying.",
+			capitalize(units.SizeChartName(params.Category)),
+			strings.ToUpper(params.From), formatNumber(value),
+			strings.ToUpper(params.To), formatNumber(size),
+		), nil
+	}
+
+	result, err := units.Convert(params.Category, value, params.From, params.To)
+	if err != nil {
+		return "", err
+	}
+
+	// Errors were already reported by Convert
+	from, _ := units.LookupUnit(params.Category, params.From)
+	to, _ := units.LookupUnit(params.Category, params.To)
+
+	out := fmt.Sprintf("%s %s (%s) = %s %s (%s)",
+		formatNumber(value), from.Name, from.ID,
+		formatNumber(units.Round(result, significantDigits)), to.Name, to.ID,
+	)
+	if to.ID == "gas_mark" {
+		out += fmt.Sprintf("\nNearest oven setting: gas mark %.0f", math.Round(result))
+	}
+	return out, nil
+}
+
+// formatNumber prints a number without trailing zeros or exponents
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,334 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConvertUnitsTool_Name(t *testing.T) {
+	tool := NewConvertUnitsTool()
+	if tool.Name() != "convert_units" {
+		t.Errorf("expected 'convert_units', got '%s'", tool.Name())
+	}
+}
+
+func TestConvertUnitsTool_Handle(t *testing.T) {
+	tool := NewConvertUnitsTool()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantErr     bool
+	}{
+		// Length
+		{
+			name:        "kilometres to miles",
+			args:        `{"category": "length", "value": 10, "from": "km", "to": "mi"}`,
+			wantContain: []string{"10 kilometres (km) = 6.21371 miles (mi)"},
+		},
+		{
+			name:        "marathon in kilometres",
+			args:        `{"category": "length", "value": 26.21875, "from": "mi", "to": "km"}`,
+			wantContain: []string{"= 42.195 kilometres"},
+		},
+		{
+			name:        "feet to metres",
+			args:        `{"category": "length", "value": 6, "from": "ft", "to": "m"}`,
+			wantContain: []string{"6 feet (ft) = 1.8288 metres (m)"},
+		},
+		{
+			name:        "inches to centimetres",
+			args:        `{"category": "length", "value": 1, "from": "in", "to": "cm"}`,
+			wantContain: []string{"= 2.54 centimetres"},
+		},
+		{
+			name:        "nautical miles to kilometres",
+			args:        `{"category": "length", "value": 1, "from": "nmi", "to": "km"}`,
+			wantContain: []string{"= 1.852 kilometres"},
+		},
+		{
+			name:        "same unit",
+			args:        `{"category": "length", "value": 3, "from": "yd", "to": "yd"}`,
+			wantContain: []string{"3 yards (yd) = 3 yards (yd)"},
+		},
+		// Mass
+		{
+			name:        "kilograms to pounds",
+			args:        `{"category": "mass", "value": 23, "from": "kg", "to": "lb"}`,
+			wantContain: []string{"23 kilograms (kg) = 50.7063 pounds (lb)"},
+		},
+		{
+			name:        "stone to kilograms",
+			args:        `{"category": "mass", "value": 11, "from": "st", "to": "kg"}`,
+			wantContain: []string{"= 69.8532 kilograms"},
+		},
+		{
+			name:        "ounces to grams",
+			args:        `{"category": "mass", "value": 8, "from": "oz", "to": "g"}`,
+			wantContain: []string{"= 226.796 grams"},
+		},
+		// Volume
+		{
+			name:        "litres to US gallons",
+			args:        `{"category": "volume", "value": 50, "from": "l", "to": "gal"}`,
+			wantContain: []string{"50 litres (l) = 13.2086 US gallons (gal)"},
+		},
+		{
+			name:        "imperial gallons to litres",
+			args:        `{"category": "volume", "value": 1, "from": "uk_gal", "to": "l"}`,
+			wantContain: []string{"= 4.54609 litres"},
+		},
+		{
+			name:        "cups to millilitres",
+			args:        `{"category": "volume", "value": 2, "from": "cup", "to": "ml"}`,
+			wantContain: []string{"= 473.176 millilitres"},
+		},
+		{
+			name:        "tablespoons to teaspoons",
+			args:        `{"category": "volume", "value": 1, "from": "tbsp", "to": "tsp"}`,
+			wantContain: []string{"= 3 US teaspoons"},
+		},
+		{
+			name:        "pint of beer",
+			args:        `{"category": "volume", "value": 1, "from": "uk_pt", "to": "ml"}`,
+			wantContain: []string{"= 568.261 millilitres"},
+		},
+		// Temperature
+		{
+			name:        "oven temperature to Fahrenheit",
+			args:        `{"category": "temperature", "value": 180, "from": "c", "to": "f"}`,
+			wantContain: []string{"180 degrees Celsius (c) = 356 degrees Fahrenheit (f)"},
+		},
+		{
+			name:        "body temperature to Celsius",
+			args:        `{"category": "temperature", "value": 98.6, "from": "f", "to": "c"}`,
+			wantContain: []string{"= 37 degrees Celsius"},
+		},
+		{
+			name:        "negative temperatures",
+			args:        `{"category": "temperature", "value": -40, "from": "c", "to": "f"}`,
+			wantContain: []string{"= -40 degrees Fahrenheit"},
+		},
+		{
+			name:        "kelvin",
+			args:        `{"category": "temperature", "value": 0, "from": "k", "to": "c"}`,
+			wantContain: []string{"= -273.15 degrees Celsius"},
+		},
+		{
+			name:        "gas mark to Celsius",
+			args:        `{"category": "temperature", "value": 4, "from": "gas_mark", "to": "c"}`,
+			wantContain: []string{"4 gas mark (gas_mark) = 176.667 degrees Celsius"},
+		},
+		{
+			name:        "Fahrenheit to gas mark",
+			args:        `{"category": "temperature", "value": 425, "from": "f", "to": "gas_mark"}`,
+			wantContain: []string{"= 7 gas mark", "Nearest oven setting: gas mark 7"},
+		},
+		{
+			name:        "Celsius to nearest gas mark",
+			args:        `{"category": "temperature", "value": 200, "from": "c", "to": "gas_mark"}`,
+			wantContain: []string{"= 5.68 gas mark", "Nearest oven setting: gas mark 6"},
+		},
+		{
+			name:    "below absolute zero",
+			args:    `{"category": "temperature", "value": -300, "from": "c", "to": "k"}`,
+			wantErr: true,
+		},
+		{
+			name:    "too cool for a gas mark",
+			args:    `{"category": "temperature", "value": 80, "from": "c", "to": "gas_mark"}`,
+			wantErr: true,
+		},
+		{
+			name:    "gas mark off the scale",
+			args:    `{"category": "temperature", "value": 12, "from": "gas_mark", "to": "c"}`,
+			wantErr: true,
+		},
+		// Speed
+		{
+			name:        "speed limit in mph",
+			args:        `{"category": "speed", "value": 120, "from": "km_h", "to": "mph"}`,
+			wantContain: []string{"120 kilometres per hour (km_h) = 74.5645 miles per hour (mph)"},
+		},
+		{
+			name:        "knots to km/h",
+			args:        `{"category": "speed", "value": 20, "from": "kn", "to": "km_h"}`,
+			wantContain: []string{"= 37.04 kilometres per hour"},
+		},
+		{
+			name:        "metres per second to km/h",
+			args:        `{"category": "speed", "value": 10, "from": "m_s", "to": "km_h"}`,
+			wantContain: []string{"= 36 kilometres per hour"},
+		},
+		// Area
+		{
+			name:        "square metres to square feet",
+			args:        `{"category": "area", "value": 80, "from": "m2", "to": "ft2"}`,
+			wantContain: []string{"80 square metres (m2) = 861.113 square feet (ft2)"},
+		},
+		{
+			name:        "acres to hectares",
+			args:        `{"category": "area", "value": 10, "from": "ac", "to": "ha"}`,
+			wantContain: []string{"= 4.04686 hectares"},
+		},
+		{
+			name:        "square miles to square kilometres",
+			args:        `{"category": "area", "value": 1, "from": "mi2", "to": "km2"}`,
+			wantContain: []string{"= 2.58999 square kilometres"},
+		},
+		// Fuel economy
+		{
+			name:        "litres per 100 km to US mpg",
+			args:        `{"category": "fuel_economy", "value": 6, "from": "l_100km", "to": "mpg"}`,
+			wantContain: []string{"6 litres per 100 km (l_100km) = 39.2024 US miles per gallon (mpg)"},
+		},
+		{
+			name:        "US mpg to litres per 100 km",
+			args:        `{"category": "fuel_economy", "value": 30, "from": "mpg", "to": "l_100km"}`,
+			wantContain: []string{"= 7.84049 litres per 100 km"},
+		},
+		{
+			name:        "imperial mpg to US mpg",
+			args:        `{"category": "fuel_economy", "value": 50, "from": "uk_mpg", "to": "mpg"}`,
+			wantContain: []string{"= 41.6337 US miles per gallon"},
+		},
+		{
+			name:        "kilometres per litre to litres per 100 km",
+			args:        `{"category": "fuel_economy", "value": 20, "from": "km_l", "to": "l_100km"}`,
+			wantContain: []string{"= 5 litres per 100 km"},
+		},
+		{
+			name:    "zero fuel consumption",
+			args:    `{"category": "fuel_economy", "value": 0, "from": "l_100km", "to": "mpg"}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero distance per litre",
+			args:    `{"category": "fuel_economy", "value": 0, "from": "km_l", "to": "l_100km"}`,
+			wantErr: true,
+		},
+		// Size charts
+		{
+			name:        "men's shoes US to EU",
+			args:        `{"category": "shoe_men", "value": 10, "from": "us", "to": "eu"}`,
+			wantContain: []string{"Men's shoe size US 10 = EU 44", "differ between brands"},
+		},
+		{
+			name:        "men's shoes EU to UK",
+			args:        `{"category": "shoe_men", "value": 42.5, "from": "eu", "to": "uk"}`,
+			wantContain: []string{"Men's shoe size EU 42.5 = UK 8"},
+		},
+		{
+			name:        "women's shoes UK to US",
+			args:        `{"category": "shoe_women", "value": 5, "from": "uk", "to": "us"}`,
+			wantContain: []string{"Women's shoe size UK 5 = US 7"},
+		},
+		{
+			name:        "women's shoes to foot length",
+			args:        `{"category": "shoe_women", "value": 8, "from": "us", "to": "jp"}`,
+			wantContain: []string{"Women's shoe size US 8 = JP 25"},
+		},
+		{
+			name:        "women's clothing UK to EU",
+			args:        `{"category": "womens_clothing", "value": 12, "from": "uk", "to": "eu"}`,
+			wantContain: []string{"Women's clothing size UK 12 = EU 40"},
+		},
+		{
+			name:        "women's clothing US zero",
+			args:        `{"category": "womens_clothing", "value": 0, "from": "us", "to": "uk"}`,
+			wantContain: []string{"Women's clothing size US 0 = UK 4"},
+		},
+		{
+			name:        "men's suit",
+			args:        `{"category": "mens_suit", "value": 40, "from": "us", "to": "eu"}`,
+			wantContain: []string{"Men's suit size US 40 = EU 50"},
+		},
+		{
+			name:    "size not on the chart",
+			args:    `{"category": "shoe_men", "value": 10.25, "from": "us", "to": "eu"}`,
+			wantErr: true,
+		},
+		{
+			name:    "sizing system of another chart",
+			args:    `{"category": "womens_clothing", "value": 8, "from": "us", "to": "jp"}`,
+			wantErr: true,
+		},
+		// Invalid input
+		{
+			name:    "unit from another category",
+			args:    `{"category": "length", "value": 1, "from": "kg", "to": "m"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown category",
+			args:    `{"category": "currency", "value": 1, "from": "usd", "to": "eur"}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative length",
+			args:    `{"category": "length", "value": -5, "from": "km", "to": "mi"}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			args:    `{"category": "length", "from": "km", "to": "mi"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			args:    `{"category": "length", "value": 1, "from": "km", "to": "mi", "precision": 2}`,
+			wantErr: true,
+		},
+		{
+			name:    "value as string",
+			args:    `{"category": "length", "value": "ten", "from": "km", "to": "mi"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertUnitsTool_Definition(t *testing.T) {
+	def := NewConvertUnitsTool().Definition()
+	fn := def.OfFunction.Function
+
+	if !fn.Strict.Value {
+		t.Error("expected a strict schema")
+	}
+	if fn.Parameters["additionalProperties"] != false {
+		t.Error("expected additional properties to be rejected")
+	}
+	if desc := fn.Description.Value; !strings.Contains(desc, "fuel_economy: km_l, l_100km, mpg, uk_mpg") {
+		t.Errorf("expected description to list units per category, got %q", desc)
+	}
+}
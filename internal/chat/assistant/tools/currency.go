@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/currency"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+	"github.com/openai/openai-go/v2"
+)
+
+// CurrencyTool converts amounts between currencies
+type CurrencyTool struct {
+	rates currency.RateProvider
+}
+
+// NewCurrencyTool creates a new currency converter backed by the given rate provider
+func NewCurrencyTool(rates currency.RateProvider) *CurrencyTool {
+	return &CurrencyTool{
+		rates: rates,
+	}
+}
+
+func (t *CurrencyTool) Name() string {
+	return "convert_currency"
+}
+
+func (t *CurrencyTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name:        "convert_currency",
+		Description: openai.String("Convert an amount of money between currencies using published reference exchange rates, e.g. for trip budgets. The answer includes the rate, its as-of date and source; card and exchange bureau rates differ slightly."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"amount": map[string]string{
+					"type":        "number",
+					"description": "Amount to convert, e.g. 250",
+				},
+				"from": map[string]string{
+					"type":        "string",
+					"description": "ISO 4217 code of the amount's currency (e.g. 'USD', 'EUR', 'JPY')",
+				},
+				"to": map[string]string{
+					"type":        "string",
+					"description": "ISO 4217 code of the target currency (e.g. 'GBP', 'THB')",
+				},
+			},
+			"required": []string{"amount", "from", "to"},
+		},
+	})
+}
+
+func (t *CurrencyTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Amount float64 `json:"amount"`
+		From   string  `json:"from"`
+		To     string  `json:"to"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid currency parameters: %w", err)
+	}
+
+	if params.Amount < 0 {
+		return "", fmt.Errorf("amount must be a non-negative number")
+	}
+
+	rates, err := t.rates.Rates(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load exchange rates", "provider", t.rates.Name(), "error", err)
+		return "", fmt.Errorf("failed to load exchange rates: %w", err)
+	}
+
+	from := strings.ToUpper(strings.TrimSpace(params.From))
+	to := strings.ToUpper(strings.TrimSpace(params.To))
+
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s = %s %s\n", formatMoney(params.Amount), from, formatMoney(params.Amount*rate), to)
+	fmt.Fprintf(&b, "Rate: 1 %s = %s %s\n", from, formatNumber(units.Round(rate, 6)), to)
+	fmt.Fprintf(&b, "Rates as of %s, source: %s", rates.Date.Format(time.DateOnly), rates.Source)
+	if rates.Stale {
+		fmt.Fprintf(&b, "\nThe rates could not be refreshed; these were fetched at %s", rates.FetchedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	return b.String(), nil
+}
+
+// formatMoney prints an amount with two decimals and thousands separators
+func formatMoney(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "." + frac
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/currency"
+)
+
+// stubRates returns fixed rates or an error
+type stubRates struct {
+	rates *currency.Rates
+	err   error
+}
+
+func (s *stubRates) Name() string { return "stub" }
+
+func (s *stubRates) Rates(ctx context.Context) (*currency.Rates, error) {
+	return s.rates, s.err
+}
+
+func TestCurrencyTool_Handle(t *testing.T) {
+	ctx := context.Background()
+	fresh := &currency.Rates{
+		Base:   "EUR",
+		Rates:  map[string]float64{"USD": 1.25, "JPY": 160, "GBP": 0.8},
+		Date:   time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		Source: "European Central Bank",
+	}
+	stale := *fresh
+	stale.Stale = true
+	stale.FetchedAt = time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		provider    *stubRates
+		args        string
+		wantContain []string
+		wantErr     bool
+	}{
+		{
+			name:     "converts through the base currency",
+			provider: &stubRates{rates: fresh},
+			args:     `{"amount": 1500, "from": "USD", "to": "JPY"}`,
+			wantContain: []string{
+				"1,500.00 USD = 192,000.00 JPY",
+				"Rate: 1 USD = 128 JPY",
+				"Rates as of 2026-10-16, source: European Central Bank",
+			},
+		},
+		{
+			name:        "lower case codes",
+			provider:    &stubRates{rates: fresh},
+			args:        `{"amount": 100, "from": "eur", "to": "gbp"}`,
+			wantContain: []string{"100.00 EUR = 80.00 GBP", "Rate: 1 EUR = 0.8 GBP"},
+		},
+		{
+			name:        "stale rates are flagged",
+			provider:    &stubRates{rates: &stale},
+			args:        `{"amount": 20, "from": "GBP", "to": "USD"}`,
+			wantContain: []string{"20.00 GBP = 31.25 USD", "could not be refreshed", "2026-10-17 15:30 UTC"},
+		},
+		{
+			name:     "unsupported currency",
+			provider: &stubRates{rates: fresh},
+			args:     `{"amount": 10, "from": "USD", "to": "XYZ"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "negative amount",
+			provider: &stubRates{rates: fresh},
+			args:     `{"amount": -10, "from": "USD", "to": "EUR"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "rates unavailable",
+			provider: &stubRates{err: errors.New("feed down")},
+			args:     `{"amount": 10, "from": "USD", "to": "EUR"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid JSON",
+			provider: &stubRates{rates: fresh},
+			args:     `{invalid json}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewCurrencyTool(tt.provider).Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := map[float64]string{
+		0:          "0.00",
+		999.999:    "1,000.00",
+		1234.5:     "1,234.50",
+		1234567.89: "1,234,567.89",
+		12:         "12.00",
+	}
+
+	for in, want := range tests {
+		if got := formatMoney(in); got != want {
+			t.Errorf("formatMoney(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -4,21 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
-	"os"
 	"strings"
 	"time"
 
-	ics "github.com/arran4/golang-ical"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
 	"github.com/openai/openai-go/v2"
 )
 
-// HolidaysTool provides information about local bank and public holidays
-type HolidaysTool struct{}
+// HolidaysTool provides information about bank and public holidays
+type HolidaysTool struct {
+	calendars *holidays.Calendars
+}
 
-// NewHolidaysTool creates a new holidays tool
-func NewHolidaysTool() *HolidaysTool {
-	return &HolidaysTool{}
+// NewHolidaysTool creates a new holidays tool backed by the given calendars
+func NewHolidaysTool(calendars *holidays.Calendars) *HolidaysTool {
+	return &HolidaysTool{
+		calendars: calendars,
+	}
 }
 
 func (t *HolidaysTool) Name() string {
@@ -26,12 +28,29 @@ func (t *HolidaysTool) Name() string {
 }
 
 func (t *HolidaysTool) Definition() openai.ChatCompletionToolUnionParam {
+	sources := t.calendars.Sources()
+
+	var defaultCalendar string
+	if len(sources) > 0 {
+		defaultCalendar = sources[0].Name()
+	}
+
 	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
-		Name:        "get_holidays",
-		Description: openai.String("Gets local bank and public holidays. Each line is a single holiday in the format 'YYYY-MM-DD: Holiday Name'."),
+		Name: "get_holidays",
+		Description: openai.String(fmt.Sprintf("Gets bank and public holidays for a country or region. "+
+			"Each line is a single holiday in the format 'YYYY-MM-DD: Holiday Name [Calendar]'. "+
+			"Available calendars: %s. Without a country, %s is used.", holidays.Available(sources), defaultCalendar)),
 		Parameters: openai.FunctionParameters{
 			"type": "object",
 			"properties": map[string]any{
+				"country": map[string]string{
+					"type":        "string",
+					"description": "Optional country name or ISO code (e.g. 'Spain', 'GB').",
+				},
+				"region": map[string]string{
+					"type":        "string",
+					"description": "Optional region within the country (e.g. 'Catalonia'). Omit for national holidays.",
+				},
 				"before_date": map[string]string{
 					"type":        "string",
 					"description": "Optional date in RFC3339 format to get holidays before this date. If not provided, all holidays will be returned.",
@@ -49,72 +68,65 @@ func (t *HolidaysTool) Definition() openai.ChatCompletionToolUnionParam {
 	})
 }
 
-func (t *HolidaysTool) Handle(ctx context.Context, args string) (string, error) {
-	var params struct {
-		BeforeDate time.Time `json:"before_date,omitempty"`
-		AfterDate  time.Time `json:"after_date,omitempty"`
-		MaxCount   int       `json:"max_count,omitempty"`
-	}
+type holidaysArgs struct {
+	Country    string    `json:"country,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	BeforeDate time.Time `json:"before_date,omitempty"`
+	AfterDate  time.Time `json:"after_date,omitempty"`
+	MaxCount   int       `json:"max_count,omitempty"`
+}
 
+// CacheKey normalises the arguments; the calendars behind the tool change a
+// few times a year at most
+func (t *HolidaysTool) CacheKey(args string) (string, bool) {
+	var params holidaysArgs
 	if err := json.Unmarshal([]byte(args), &params); err != nil {
-		return "", fmt.Errorf("invalid holiday parameters: %w", err)
+		return "", false
 	}
 
-	// Get calendar URL from environment or use default
-	link := "https://www.officeholidays.com/ics/spain/catalonia"
-	if v := os.Getenv("HOLIDAY_CALENDAR_LINK"); v != "" {
-		link = v
+	// The same instant written in another offset filters the same
+	params.BeforeDate, params.AfterDate = params.BeforeDate.UTC(), params.AfterDate.UTC()
+	return normalArgs(params)
+}
+
+func (t *HolidaysTool) Handle(ctx context.Context, args string) (string, error) {
+	var params holidaysArgs
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid holiday parameters: %w", err)
 	}
 
 	// Load calendar events
-	events, err := loadCalendar(ctx, link)
+	list, err := t.calendars.Holidays(ctx, params.Country, params.Region)
 	if err != nil {
 		return "", fmt.Errorf("failed to load holiday calendar: %w", err)
 	}
 
 	// Filter and format holidays
-	var holidays []string
-	for _, event := range events {
-		date, err := event.GetAllDayStartAt()
-		if err != nil {
-			continue
-		}
-
+	var lines []string
+	for _, h := range list {
 		// Check max count limit
-		if params.MaxCount > 0 && len(holidays) >= params.MaxCount {
+		if params.MaxCount > 0 && len(lines) >= params.MaxCount {
 			break
 		}
 
 		// Filter by before date
-		if !params.BeforeDate.IsZero() && date.After(params.BeforeDate) {
+		if !params.BeforeDate.IsZero() && h.Date.After(params.BeforeDate) {
 			continue
 		}
 
 		// Filter by after date
-		if !params.AfterDate.IsZero() && date.Before(params.AfterDate) {
+		if !params.AfterDate.IsZero() && h.Date.Before(params.AfterDate) {
 			continue
 		}
 
 		// Format holiday
-		holidayName := event.GetProperty(ics.ComponentPropertySummary).Value
-		holidays = append(holidays, date.Format(time.DateOnly)+": "+holidayName)
+		lines = append(lines, fmt.Sprintf("%s: %s [%s]", h.Date.Format(time.DateOnly), h.Name, h.Calendar))
 	}
 
-	if len(holidays) == 0 {
+	if len(lines) == 0 {
 		return "No holidays found matching the criteria.", nil
 	}
 
-	return strings.Join(holidays, "\n"), nil
-}
-
-// loadCalendar loads calendar events from a given ICS URL
-func loadCalendar(ctx context.Context, link string) ([]*ics.VEvent, error) {
-	slog.InfoContext(ctx, "Loading calendar", "link", link)
-
-	cal, err := ics.ParseCalendarFromUrl(link, ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse calendar: %w", err)
-	}
-
-	return cal.Events(), nil
+	return strings.Join(lines, "\n"), nil
 }
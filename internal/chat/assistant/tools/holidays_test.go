@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
+)
+
+// bundledCalendars serves the ICS files shipped in data/holidays
+func bundledCalendars() *holidays.Calendars {
+	return holidays.NewCalendars([]holidays.Source{
+		{Country: "Spain", Code: "ES", Region: "Catalonia", URL: "file://../../../../data/holidays/es-catalonia.ics"},
+		{Country: "United Kingdom", Code: "GB", Region: "England", URL: "file://../../../../data/holidays/gb-england.ics"},
+		{Country: "United States", Code: "US", URL: "file://../../../../data/holidays/us.ics"},
+	})
+}
+
+func TestHolidaysTool_Handle(t *testing.T) {
+	tool := NewHolidaysTool(bundledCalendars())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		args        string
+		wantLines   int
+		wantContain []string
+		wantErr     bool
+	}{
+		{
+			name:        "default calendar",
+			args:        `{"max_count": 2}`,
+			wantLines:   2,
+			wantContain: []string{"2026-01-01: New Year's Day [Spain - Catalonia]", "2026-01-06: Epiphany [Spain - Catalonia]"},
+		},
+		{
+			name:        "country and region",
+			args:        `{"country": "United Kingdom", "region": "England", "after_date": "2026-05-01T00:00:00Z", "max_count": 1}`,
+			wantLines:   1,
+			wantContain: []string{"2026-05-04: Early May Bank Holiday [United Kingdom - England]"},
+		},
+		{
+			name:        "date range is applied before the count",
+			args:        `{"country": "US", "after_date": "2026-11-01T00:00:00Z", "before_date": "2026-11-30T00:00:00Z", "max_count": 5}`,
+			wantLines:   2,
+			wantContain: []string{"Veterans Day [United States]", "Thanksgiving Day [United States]"},
+		},
+		{
+			name:        "no matches",
+			args:        `{"country": "US", "after_date": "2027-06-01T00:00:00Z"}`,
+			wantLines:   1,
+			wantContain: []string{"No holidays found"},
+		},
+		{
+			name:    "unknown region",
+			args:    `{"country": "Spain", "region": "Galicia"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if lines := strings.Split(result, "\n"); len(lines) != tt.wantLines {
+				t.Errorf("expected %d lines, got %d: %s", tt.wantLines, len(lines), result)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestHolidaysTool_Definition(t *testing.T) {
+	def := NewHolidaysTool(bundledCalendars()).Definition()
+
+	description := def.OfFunction.Function.Description.Value
+	if !strings.Contains(description, "Available calendars: Spain - Catalonia, United Kingdom - England, United States") {
+		t.Errorf("expected description to list calendars, got %q", description)
+	}
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/openai/openai-go/v2"
+)
+
+// maxLocationResults bounds the namesakes listed for an ambiguous name
+const maxLocationResults = 10
+
+// LocationTool looks up places in the offline gazetteer
+type LocationTool struct {
+	places *geo.Gazetteer
+}
+
+// NewLocationTool creates a new location lookup tool backed by the given gazetteer
+func NewLocationTool(places *geo.Gazetteer) *LocationTool {
+	return &LocationTool{
+		places: places,
+	}
+}
+
+func (t *LocationTool) Name() string {
+	return "lookup_location"
+}
+
+func (t *LocationTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name:        "lookup_location",
+		Description: openai.String("Look up a city, island, country or coordinates and get its IANA time zone, current UTC offset and local time, country and coordinates. Use this instead of guessing time zones (e.g. 'Bali', 'Austin'). Ambiguous names list every known match, most populous first."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]string{
+					"type":        "string",
+					"description": "Place name, optionally qualified by region or country (e.g. 'Austin', 'San Jose, Costa Rica', 'Japan'), or coordinates as 'lat,lon'",
+				},
+				"max_results": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of matches to return for ambiguous names (default 3)",
+					"minimum":     1,
+					"maximum":     maxLocationResults,
+				},
+			},
+			"required": []string{"query"},
+		},
+	})
+}
+
+func (t *LocationTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Query      string `json:"query"`
+		MaxResults int    `json:"max_results,omitempty"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid location parameters: %w", err)
+	}
+
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+
+	limit := params.MaxResults
+	if limit <= 0 {
+		limit = 3
+	}
+	limit = min(limit, maxLocationResults)
+
+	now := time.Now()
+
+	if lat, lon, ok := geo.ParseCoordinates(query); ok {
+		p, km := t.places.Nearest(lat, lon)
+		return fmt.Sprintf("Nearest known place to %.4f, %.4f is %.0f km away; its time zone is assumed.\n%s",
+			lat, lon, km, describePlace(p, now)), nil
+	}
+
+	if matches := t.places.Lookup(query); len(matches) > 0 {
+		var b strings.Builder
+		if len(matches) > 1 {
+			fmt.Fprintf(&b, "'%s' matches %d places, most populous first:\n", query, len(matches))
+		}
+		for i, p := range matches[:min(limit, len(matches))] {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(describePlace(p, now))
+		}
+		return b.String(), nil
+	}
+
+	if c, ok := t.places.Country(query); ok {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s (%s), capital %s\n", c.Name, c.Code, c.Capital.Name)
+		if len(c.TimeZones) > 1 {
+			fmt.Fprintf(&b, "Spans %d time zones:\n", len(c.TimeZones))
+			for _, zone := range c.TimeZones {
+				fmt.Fprintf(&b, "  %s\n", describeZone(zone, now))
+			}
+		} else {
+			fmt.Fprintf(&b, "Time zone: %s\n", describeZone(c.Capital.TimeZone, now))
+		}
+		fmt.Fprintf(&b, "Capital coordinates: %.4f, %.4f", c.Capital.Lat, c.Capital.Lon)
+		return b.String(), nil
+	}
+
+	return fmt.Sprintf("No known place matches '%s'. Try a larger nearby city, add the country, or use coordinates.", query), nil
+}
+
+// describePlace formats a place with its zone, country and coordinates
+func describePlace(p geo.Place, now time.Time) string {
+	return fmt.Sprintf("%s (%s)\nTime zone: %s\nCoordinates: %.4f, %.4f",
+		p.Label(), p.CountryCode, describeZone(p.TimeZone, now), p.Lat, p.Lon)
+}
+
+// describeZone formats a zone with its current UTC offset and local time,
+// e.g. "America/Chicago, currently UTC-05:00 (CDT), local time 2026-10-18 04:12"
+func describeZone(zone string, now time.Time) string {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return zone
+	}
+	local := now.In(loc)
+	abbrev, offset := local.Zone()
+
+	// Zones without an abbreviation report the offset again, e.g. "+03"
+	if strings.HasPrefix(abbrev, "+") || strings.HasPrefix(abbrev, "-") {
+		abbrev = ""
+	} else {
+		abbrev = " (" + abbrev + ")"
+	}
+	return fmt.Sprintf("%s, currently UTC%s%s, local time %s",
+		zone, formatOffset(offset), abbrev, local.Format("2006-01-02 15:04"))
+}
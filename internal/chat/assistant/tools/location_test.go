@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+)
+
+func TestLocationTool_Handle(t *testing.T) {
+	tool := NewLocationTool(geo.Default())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name: "city",
+			args: `{"query": "Austin"}`,
+			wantContain: []string{
+				"Austin, Texas, United States (US)",
+				"Time zone: America/Chicago, currently UTC-0",
+				"Coordinates: 30.2672, -97.7431",
+			},
+		},
+		{
+			name:        "island resolves to its main town",
+			args:        `{"query": "Bali"}`,
+			wantContain: []string{"Denpasar, Bali, Indonesia (ID)", "Asia/Makassar, currently UTC+08:00 (WITA)"},
+		},
+		{
+			name:        "ambiguous name lists namesakes",
+			args:        `{"query": "San Jose"}`,
+			wantContain: []string{"matches 2 places", "America/Los_Angeles", "San José, Costa Rica (CR)"},
+		},
+		{
+			name:        "result limit",
+			args:        `{"query": "San Jose", "max_results": 1}`,
+			wantContain: []string{"matches 2 places", "America/Los_Angeles"},
+			wantMissing: []string{"Costa Rica"},
+		},
+		{
+			name:        "qualified name",
+			args:        `{"query": "San Jose, CR"}`,
+			wantContain: []string{"San José, Costa Rica (CR)"},
+			wantMissing: []string{"matches"},
+		},
+		{
+			name:        "country with one zone",
+			args:        `{"query": "Japan"}`,
+			wantContain: []string{"Japan (JP), capital Tokyo", "Time zone: Asia/Tokyo, currently UTC+09:00 (JST)"},
+		},
+		{
+			name:        "country with several zones",
+			args:        `{"query": "Australia"}`,
+			wantContain: []string{"capital Canberra", "Spans 7 time zones", "Australia/Perth"},
+		},
+		{
+			name:        "coordinates",
+			args:        `{"query": "41.4036,2.1744"}`,
+			wantContain: []string{"km away", "Barcelona, Catalonia, Spain (ES)", "Europe/Madrid"},
+		},
+		{
+			name:        "zone without abbreviation",
+			args:        `{"query": "Istanbul"}`,
+			wantContain: []string{"Europe/Istanbul, currently UTC+03:00, local time"},
+		},
+		{
+			name:        "unknown place",
+			args:        `{"query": "Atlantis"}`,
+			wantContain: []string{"No known place matches 'Atlantis'"},
+		},
+		{
+			name:    "empty query",
+			args:    `{"query": " "}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain %q, got: %s", want, result)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(result, missing) {
+					t.Errorf("expected result not to contain %q, got: %s", missing, result)
+				}
+			}
+		})
+	}
+}
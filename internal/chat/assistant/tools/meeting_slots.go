@@ -0,0 +1,351 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/holidays"
+	"github.com/openai/openai-go/v2"
+)
+
+// Meeting search limits
+const (
+	maxMeetingRangeDays    = 31
+	maxMeetingParticipants = 20
+	maxMeetingResults      = 20
+	defaultMeetingResults  = 5
+	meetingSlotStep        = 30 * time.Minute
+)
+
+// MeetingSlotsTool finds times that fall within the working hours of participants in different time zones
+type MeetingSlotsTool struct {
+	calendars *holidays.Calendars
+}
+
+// NewMeetingSlotsTool creates a new meeting planner tool; holidays come from the given calendars
+func NewMeetingSlotsTool(calendars *holidays.Calendars) *MeetingSlotsTool {
+	return &MeetingSlotsTool{
+		calendars: calendars,
+	}
+}
+
+func (t *MeetingSlotsTool) Name() string {
+	return "find_meeting_slots"
+}
+
+func (t *MeetingSlotsTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "find_meeting_slots",
+		Description: openai.String("Find meeting times that fit the working hours of participants in different time zones. " +
+			"Returns ranked slots (best first, those furthest from everyone's start and end of day), shows each participant's local time, " +
+			"flags daylight saving time changes in the date range and can skip local weekends and public holidays."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"participants": map[string]any{
+					"type":        "array",
+					"description": "People or locations to meet with",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"name": map[string]string{
+								"type":        "string",
+								"description": "Optional label, e.g. 'Alice' or 'New York office'",
+							},
+							"timezone": map[string]string{
+								"type":        "string",
+								"description": "IANA timezone (e.g. 'America/New_York')",
+							},
+							"work_start": map[string]string{
+								"type":        "string",
+								"description": "Start of working hours, 'HH:MM' (default '09:00')",
+							},
+							"work_end": map[string]string{
+								"type":        "string",
+								"description": "End of working hours, 'HH:MM' (default '17:00')",
+							},
+							"country": map[string]string{
+								"type":        "string",
+								"description": "Optional country name or ISO code, for local weekends and holidays",
+							},
+							"region": map[string]string{
+								"type":        "string",
+								"description": "Optional region within the country, for regional holidays",
+							},
+						},
+						"required": []string{"timezone"},
+					},
+				},
+				"start_date": map[string]string{
+					"type":        "string",
+					"description": "First day to search (YYYY-MM-DD), in the first participant's timezone",
+				},
+				"end_date": map[string]string{
+					"type":        "string",
+					"description": "Last day to search (YYYY-MM-DD), inclusive, at most 31 days after start_date",
+				},
+				"duration_minutes": map[string]string{
+					"type":        "integer",
+					"description": "Meeting length in minutes",
+				},
+				"exclude_holidays": map[string]string{
+					"type":        "boolean",
+					"description": "Skip public holidays of participants that have a country",
+				},
+				"max_results": map[string]string{
+					"type":        "integer",
+					"description": "Maximum number of slots to return (default 5, max 20)",
+				},
+			},
+			"required": []string{"participants", "start_date", "end_date", "duration_minutes"},
+		},
+	})
+}
+
+// participant is a meeting participant with resolved zone, hours and calendar
+type participant struct {
+	name       string
+	loc        *time.Location
+	start, end int // working hours in minutes since midnight
+	calendar   *holidays.WorkCalendar
+}
+
+// meetingSlot is a candidate start time and how comfortable it is for everyone
+type meetingSlot struct {
+	start time.Time
+	score int // smallest distance in minutes to anyone's start or end of day
+}
+
+func (t *MeetingSlotsTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Participants []struct {
+			Name      string `json:"name,omitempty"`
+			Timezone  string `json:"timezone"`
+			WorkStart string `json:"work_start,omitempty"`
+			WorkEnd   string `json:"work_end,omitempty"`
+			Country   string `json:"country,omitempty"`
+			Region    string `json:"region,omitempty"`
+		} `json:"participants"`
+		StartDate       string `json:"start_date"`
+		EndDate         string `json:"end_date"`
+		DurationMinutes int    `json:"duration_minutes"`
+		ExcludeHolidays bool   `json:"exclude_holidays,omitempty"`
+		MaxResults      int    `json:"max_results,omitempty"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid meeting parameters: %w", err)
+	}
+
+	if len(params.Participants) == 0 || len(params.Participants) > maxMeetingParticipants {
+		return "", fmt.Errorf("between 1 and %d participants are required", maxMeetingParticipants)
+	}
+	if params.DurationMinutes < 5 || params.DurationMinutes > 12*60 {
+		return "", fmt.Errorf("duration_minutes must be between 5 and 720")
+	}
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMeetingResults
+	}
+	maxResults = min(maxResults, maxMeetingResults)
+
+	var (
+		people []participant
+		notes  []string
+	)
+	for i, p := range params.Participants {
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return "", fmt.Errorf("invalid timezone '%s': %w", p.Timezone, err)
+		}
+
+		start, err := parseClock("work_start", p.WorkStart, "09:00")
+		if err != nil {
+			return "", err
+		}
+		end, err := parseClock("work_end", p.WorkEnd, "17:00")
+		if err != nil {
+			return "", err
+		}
+		if end <= start {
+			return "", fmt.Errorf("working hours of %s must end after they start", p.Timezone)
+		}
+
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("Participant %d", i+1)
+		}
+
+		// Without a country only Saturday and Sunday are skipped
+		cal := holidays.NewWorkCalendar(p.Timezone, holidays.Weekend(p.Country), nil)
+		if params.ExcludeHolidays && p.Country != "" {
+			c, err := t.calendars.WorkCalendar(ctx, p.Country, p.Region)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("Holidays of %s were not checked: %v", name, err))
+			} else {
+				cal = c
+			}
+		}
+
+		people = append(people, participant{name: name, loc: loc, start: start, end: end, calendar: cal})
+	}
+
+	// The date range is interpreted in the first participant's timezone
+	first := people[0].loc
+	from, err := time.ParseInLocation(time.DateOnly, params.StartDate, first)
+	if err != nil {
+		return "", fmt.Errorf("invalid start_date '%s', expected YYYY-MM-DD: %w", params.StartDate, err)
+	}
+	to, err := time.ParseInLocation(time.DateOnly, params.EndDate, first)
+	if err != nil {
+		return "", fmt.Errorf("invalid end_date '%s', expected YYYY-MM-DD: %w", params.EndDate, err)
+	}
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return "", fmt.Errorf("end_date must not be before start_date")
+	}
+	if to.Sub(from) > maxMeetingRangeDays*24*time.Hour {
+		return "", fmt.Errorf("date range too large, at most %d days are supported", maxMeetingRangeDays)
+	}
+
+	duration := time.Duration(params.DurationMinutes) * time.Minute
+	slots := findMeetingSlots(people, from, to, duration)
+	if len(slots) > maxResults {
+		slots = slots[:maxResults]
+	}
+
+	var b strings.Builder
+	names := make([]string, len(people))
+	for i, p := range people {
+		names[i] = p.name
+	}
+	fmt.Fprintf(&b, "Meeting slots (%d min) for %s between %s and %s:\n",
+		params.DurationMinutes, strings.Join(names, ", "), params.StartDate, params.EndDate)
+
+	if len(slots) == 0 {
+		b.WriteString("No time fits everyone's working hours. Try a wider date range, longer working hours or a shorter meeting.\n")
+	}
+
+	for i, s := range slots {
+		end := s.start.Add(duration)
+		fmt.Fprintf(&b, "%d. %s-%s UTC\n", i+1, s.start.UTC().Format("2006-01-02 15:04"), end.UTC().Format("15:04"))
+		for _, p := range people {
+			ls, le := s.start.In(p.loc), end.In(p.loc)
+			fmt.Fprintf(&b, "   %s (%s): %s %s-%s %s\n", p.name, p.loc, ls.Format("Mon 2006-01-02"), ls.Format("15:04"), le.Format("15:04"), ls.Format("MST"))
+		}
+	}
+
+	if dst := dstTransitions(people, from, to); len(dst) > 0 {
+		b.WriteString("Daylight saving time changes in this range:\n")
+		for _, d := range dst {
+			fmt.Fprintf(&b, "  %s\n", d)
+		}
+	}
+
+	for _, note := range notes {
+		fmt.Fprintf(&b, "Note: %s\n", note)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// findMeetingSlots returns the best start time of each stretch of time that fits everyone,
+// best first. Consecutive start times would otherwise crowd out other days.
+func findMeetingSlots(people []participant, from, to time.Time, duration time.Duration) []meetingSlot {
+	var (
+		best    []meetingSlot
+		current *meetingSlot
+	)
+
+	for start := from; !start.Add(duration).After(to); start = start.Add(meetingSlotStep) {
+		score, ok := slotScore(people, start, duration)
+		if !ok {
+			if current != nil {
+				best = append(best, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil || score > current.score {
+			current = &meetingSlot{start: start, score: score}
+		}
+	}
+	if current != nil {
+		best = append(best, *current)
+	}
+
+	sort.SliceStable(best, func(i, j int) bool {
+		return best[i].score > best[j].score
+	})
+
+	return best
+}
+
+// slotScore checks that [start, start+duration) is within everyone's working hours on a
+// working day, and returns the smallest margin to anyone's start or end of day
+func slotScore(people []participant, start time.Time, duration time.Duration) (int, bool) {
+	score := -1
+	for _, p := range people {
+		ls := start.In(p.loc)
+		day := time.Date(ls.Year(), ls.Month(), ls.Day(), 0, 0, 0, 0, time.UTC)
+		if !p.calendar.IsWorkingDay(day) {
+			return 0, false
+		}
+
+		startClock := ls.Hour()*60 + ls.Minute()
+		endClock := startClock + int(duration.Minutes())
+		if startClock < p.start || endClock > p.end {
+			return 0, false
+		}
+
+		margin := min(startClock-p.start, p.end-endClock)
+		if score < 0 || margin < score {
+			score = margin
+		}
+	}
+	return score, true
+}
+
+// dstTransitions describes UTC offset changes of the participants' zones within the range
+func dstTransitions(people []participant, from, to time.Time) []string {
+	var (
+		seen  []string
+		lines []string
+	)
+
+	for _, p := range people {
+		if slices.Contains(seen, p.loc.String()) {
+			continue
+		}
+		seen = append(seen, p.loc.String())
+
+		prev := from.In(p.loc)
+		for at := from.Add(time.Hour); !at.After(to); at = at.Add(time.Hour) {
+			cur := at.In(p.loc)
+			prevName, prevOffset := prev.Zone()
+			curName, curOffset := cur.Zone()
+			if curOffset != prevOffset {
+				lines = append(lines, fmt.Sprintf("%s changes from %s (UTC%s) to %s (UTC%s) on %s",
+					p.loc, prevName, formatOffset(prevOffset), curName, formatOffset(curOffset), cur.Format("2006-01-02 15:04 MST")))
+			}
+			prev = cur
+		}
+	}
+
+	return lines
+}
+
+// formatOffset renders a UTC offset in seconds as "+01:00"
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMeetingSlotsTool_Handle(t *testing.T) {
+	tool := NewMeetingSlotsTool(bundledCalendars())
+	ctx := context.Background()
+
+	const newYorkMadrid = `[{"name": "Alice", "timezone": "America/New_York", "country": "US"}, {"name": "Bruno", "timezone": "Europe/Madrid"}]`
+
+	tests := []struct {
+		name        string
+		args        string
+		wantContain []string
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name: "ranks slots and flags DST changes",
+			args: `{"participants": ` + newYorkMadrid + `, "start_date": "2026-03-06", "end_date": "2026-03-10", "duration_minutes": 60}`,
+			wantContain: []string{
+				"1. 2026-03-09 14:00-15:00 UTC",
+				"   Alice (America/New_York): Mon 2026-03-09 10:00-11:00 EDT",
+				"   Bruno (Europe/Madrid): Mon 2026-03-09 15:00-16:00 CET",
+				"2. 2026-03-10 14:00-15:00 UTC",
+				"3. 2026-03-06 14:30-15:30 UTC",
+				"America/New_York changes from EST (UTC-05:00) to EDT (UTC-04:00)",
+			},
+			wantMissing: []string{"2026-03-07", "2026-03-08 1", "Europe/Madrid changes"},
+		},
+		{
+			name:        "holidays are kept by default",
+			args:        `{"participants": ` + newYorkMadrid + `, "start_date": "2026-01-19", "end_date": "2026-01-19", "duration_minutes": 30}`,
+			wantContain: []string{"1. 2026-01-19"},
+		},
+		{
+			name:        "excludes local holidays",
+			args:        `{"participants": ` + newYorkMadrid + `, "start_date": "2026-01-19", "end_date": "2026-01-20", "duration_minutes": 30, "exclude_holidays": true}`,
+			wantContain: []string{"1. 2026-01-20"},
+			wantMissing: []string{"2026-01-19 1"},
+		},
+		{
+			name:        "no overlap",
+			args:        `{"participants": [{"timezone": "America/New_York"}, {"timezone": "Europe/Madrid"}, {"timezone": "Asia/Tokyo"}], "start_date": "2026-03-09", "end_date": "2026-03-13", "duration_minutes": 60}`,
+			wantContain: []string{"Participant 1, Participant 2, Participant 3", "No time fits"},
+		},
+		{
+			name:        "custom working hours and result limit",
+			args:        `{"participants": [{"timezone": "America/New_York", "work_start": "07:00"}, {"timezone": "Asia/Tokyo", "work_end": "22:00"}], "start_date": "2026-03-10", "end_date": "2026-03-13", "duration_minutes": 30, "max_results": 2}`,
+			wantContain: []string{"1. ", "2. "},
+			wantMissing: []string{"3. "},
+		},
+		{
+			name:    "invalid timezone",
+			args:    `{"participants": [{"timezone": "Mars/Olympus"}], "start_date": "2026-03-10", "end_date": "2026-03-13", "duration_minutes": 30}`,
+			wantErr: true,
+		},
+		{
+			name:    "range too large",
+			args:    `{"participants": [{"timezone": "UTC"}], "start_date": "2026-01-01", "end_date": "2026-06-01", "duration_minutes": 30}`,
+			wantErr: true,
+		},
+		{
+			name:    "working hours end before they start",
+			args:    `{"participants": [{"timezone": "UTC", "work_start": "18:00", "work_end": "09:00"}], "start_date": "2026-01-01", "end_date": "2026-01-02", "duration_minutes": 30}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing duration",
+			args:    `{"participants": [{"timezone": "UTC"}], "start_date": "2026-01-01", "end_date": "2026-01-02"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got none: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got:\n%s", want, result)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(result, missing) {
+					t.Errorf("expected result not to contain '%s', got:\n%s", missing, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxNoteLength bounds the content of one note, in characters
+	maxNoteLength = 10000
+
+	// notePreviewLength is how much of each note lists show, in characters
+	notePreviewLength = 300
+)
+
+// NoteStore persists notes per user
+type NoteStore interface {
+	CreateNote(ctx context.Context, n *model.Note) error
+	ListNotes(ctx context.Context, userID, query string) ([]*model.Note, error)
+	DeleteNote(ctx context.Context, userID, id string) error
+}
+
+// NotesTool keeps the user's notes, shared by all of their conversations
+type NotesTool struct {
+	store NoteStore
+}
+
+// NewNotesTool creates a new notes tool backed by the given store
+func NewNotesTool(store NoteStore) *NotesTool {
+	return &NotesTool{
+		store: store,
+	}
+}
+
+func (t *NotesTool) Name() string {
+	return "notes"
+}
+
+func (t *NotesTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "notes",
+		Description: openai.String("Manage the user's personal notes, which persist across all of their conversations. " +
+			"'create' saves a note, 'list' shows the latest notes, 'search' finds notes containing a text, " +
+			"'delete' removes a note by ID. Use it when the user asks to remember, write down or look up something they saved."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"operation": map[string]any{
+					"type":        "string",
+					"description": "What to do",
+					"enum":        []string{"create", "list", "search", "delete"},
+				},
+				"title": map[string]string{
+					"type":        "string",
+					"description": "Short title of a new note; defaults to the start of the content (create)",
+				},
+				"content": map[string]string{
+					"type":        "string",
+					"description": "Text of a new note (create)",
+				},
+				"query": map[string]string{
+					"type":        "string",
+					"description": "Text to look for in titles and contents, ignoring case (search)",
+				},
+				"id": map[string]string{
+					"type":        "string",
+					"description": "ID of the note, as shown by list or search (delete)",
+				},
+			},
+			"required": []string{"operation"},
+		},
+	})
+}
+
+func (t *NotesTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Operation string `json:"operation"`
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+		Query     string `json:"query"`
+		ID        string `json:"id"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid notes parameters: %w", err)
+	}
+
+	userID := auth.FromContext(ctx)
+
+	switch params.Operation {
+	case "create":
+		return t.create(ctx, userID, strings.TrimSpace(params.Title), strings.TrimSpace(params.Content))
+
+	case "list":
+		notes, err := t.store.ListNotes(ctx, userID, "")
+		if err != nil {
+			return "", fmt.Errorf("failed to list notes: %w", err)
+		}
+		if len(notes) == 0 {
+			return "No notes saved yet.", nil
+		}
+		return describeNotes(notes), nil
+
+	case "search":
+		query := strings.TrimSpace(params.Query)
+		if query == "" {
+			return "", fmt.Errorf("query is required to search notes")
+		}
+		notes, err := t.store.ListNotes(ctx, userID, query)
+		if err != nil {
+			return "", fmt.Errorf("failed to search notes: %w", err)
+		}
+		if len(notes) == 0 {
+			return fmt.Sprintf("No notes contain %q.", query), nil
+		}
+		return describeNotes(notes), nil
+
+	case "delete":
+		if params.ID == "" {
+			return "", fmt.Errorf("id is required to delete a note")
+		}
+		if err := t.store.DeleteNote(ctx, userID, params.ID); err != nil {
+			return "", fmt.Errorf("failed to delete note %s: %w", params.ID, err)
+		}
+		return fmt.Sprintf("Deleted note %s.", params.ID), nil
+
+	default:
+		return "", fmt.Errorf("unknown operation '%s', expected create, list, search or delete", params.Operation)
+	}
+}
+
+func (t *NotesTool) create(ctx context.Context, userID, title, content string) (string, error) {
+	if content == "" {
+		return "", fmt.Errorf("content is required to create a note")
+	}
+	if n := utf8.RuneCountInString(content); n > maxNoteLength {
+		return "", fmt.Errorf("note is too long (%d characters, max %d)", n, maxNoteLength)
+	}
+	if title == "" {
+		title, _, _ = strings.Cut(content, "\n")
+		title = truncate(title, 60)
+	}
+
+	now := time.Now()
+	note := &model.Note{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Title:     title,
+		Content:   content,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := t.store.CreateNote(ctx, note); err != nil {
+		return "", fmt.Errorf("failed to save note: %w", err)
+	}
+	return fmt.Sprintf("Saved note %s: %s", note.ID.Hex(), note.Title), nil
+}
+
+// describeNotes lists notes with their IDs, so they can be deleted
+func describeNotes(notes []*model.Note) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d note(s), most recent first:", len(notes))
+	for _, n := range notes {
+		fmt.Fprintf(&b, "\n- %s (id %s, updated %s)\n  %s",
+			n.Title, n.ID.Hex(), n.UpdatedAt.Format(time.DateOnly),
+			strings.ReplaceAll(truncate(n.Content, notePreviewLength), "\n", "\n  "))
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most n characters, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+// memoryNotes is an in-memory NoteStore
+type memoryNotes struct {
+	notes []*model.Note
+	err   error
+}
+
+func (m *memoryNotes) CreateNote(ctx context.Context, n *model.Note) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.notes = append(m.notes, n)
+	return nil
+}
+
+func (m *memoryNotes) ListNotes(ctx context.Context, userID, query string) ([]*model.Note, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []*model.Note
+	for i := len(m.notes) - 1; i >= 0; i-- {
+		n := m.notes[i]
+		if n.UserID == userID && (containsFold(n.Title, query) || containsFold(n.Content, query)) {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryNotes) DeleteNote(ctx context.Context, userID, id string) error {
+	for i, n := range m.notes {
+		if n.UserID == userID && n.ID.Hex() == id {
+			m.notes = append(m.notes[:i], m.notes[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("note not found")
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+var idPattern = regexp.MustCompile(`[0-9a-f]{24}`)
+
+func TestNotesTool_Handle(t *testing.T) {
+	alice := auth.WithUser(context.Background(), "alice")
+	bob := auth.WithUser(context.Background(), "bob")
+
+	store := &memoryNotes{}
+	tool := NewNotesTool(store)
+
+	created, err := tool.Handle(alice, `{"operation": "create", "title": "Passport", "content": "Renew before the Japan trip\nNeeds 2 photos"}`)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if !strings.Contains(created, "Saved note") || !strings.Contains(created, "Passport") {
+		t.Errorf("unexpected create result: %s", created)
+	}
+	id := idPattern.FindString(created)
+
+	if _, err := tool.Handle(alice, `{"operation": "create", "content": "Hotel in Kyoto: Ryokan Yachiyo, check-in 15:00"}`); err != nil {
+		t.Fatalf("create without title failed: %v", err)
+	}
+	if _, err := tool.Handle(bob, `{"operation": "create", "content": "Bob's private note"}`); err != nil {
+		t.Fatalf("create for bob failed: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		args           string
+		wantContain    []string
+		wantNotContain []string
+		wantErr        bool
+	}{
+		{
+			name:           "list shows the user's notes only",
+			ctx:            alice,
+			args:           `{"operation": "list"}`,
+			wantContain:    []string{"2 note(s)", "Passport", "id " + id, "Needs 2 photos", "Hotel in Kyoto: Ryokan Yachiyo, check-in 15:00"},
+			wantNotContain: []string{"Bob's private note"},
+		},
+		{
+			name:           "search ignores case",
+			ctx:            alice,
+			args:           `{"operation": "search", "query": "kyoto"}`,
+			wantContain:    []string{"1 note(s)", "Ryokan"},
+			wantNotContain: []string{"Passport"},
+		},
+		{
+			name:        "search without matches",
+			ctx:         alice,
+			args:        `{"operation": "search", "query": "visa"}`,
+			wantContain: []string{`No notes contain "visa"`},
+		},
+		{
+			name:        "other users' notes are unreachable",
+			ctx:         bob,
+			args:        `{"operation": "search", "query": "passport"}`,
+			wantContain: []string{"No notes contain"},
+		},
+		{
+			name:    "other users can't delete",
+			ctx:     bob,
+			args:    `{"operation": "delete", "id": "` + id + `"}`,
+			wantErr: true,
+		},
+		{
+			name:    "search needs a query",
+			ctx:     alice,
+			args:    `{"operation": "search"}`,
+			wantErr: true,
+		},
+		{
+			name:    "create needs content",
+			ctx:     alice,
+			args:    `{"operation": "create", "title": "Empty"}`,
+			wantErr: true,
+		},
+		{
+			name:    "create rejects long notes",
+			ctx:     alice,
+			args:    `{"operation": "create", "content": "` + strings.Repeat("a", maxNoteLength+1) + `"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown operation",
+			ctx:     alice,
+			args:    `{"operation": "complete", "id": "` + id + `"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			ctx:     alice,
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+		{
+			name:        "delete",
+			ctx:         alice,
+			args:        `{"operation": "delete", "id": "` + id + `"}`,
+			wantContain: []string{"Deleted note " + id},
+		},
+		{
+			name:           "deleted notes are gone",
+			ctx:            alice,
+			args:           `{"operation": "list"}`,
+			wantContain:    []string{"1 note(s)"},
+			wantNotContain: []string{"Passport"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(tt.ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+			for _, unwanted := range tt.wantNotContain {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("expected result not to contain '%s', got: %s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestNotesTool_DefaultUser(t *testing.T) {
+	store := &memoryNotes{}
+	tool := NewNotesTool(store)
+
+	if _, err := tool.Handle(context.Background(), `{"operation": "create", "content": "Buy sunscreen"}`); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if got := store.notes[0].UserID; got != auth.DefaultUser {
+		t.Errorf("note saved for %q, want %q", got, auth.DefaultUser)
+	}
+	if got := store.notes[0].Title; got != "Buy sunscreen" {
+		t.Errorf("title = %q, want the start of the content", got)
+	}
+}
+
+func TestNotesTool_StoreError(t *testing.T) {
+	tool := NewNotesTool(&memoryNotes{err: errors.New("connection refused")})
+
+	if _, err := tool.Handle(context.Background(), `{"operation": "list"}`); err == nil {
+		t.Error("expected error when the store fails")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"a bit too long", 8, "a bit t…"},
+		{"Zürich café", 7, "Zürich…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
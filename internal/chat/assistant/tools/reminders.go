@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxReminderLength bounds the text of one reminder, in characters
+	maxReminderLength = 500
+
+	// maxReminderHorizon is how far ahead reminders can be set
+	maxReminderHorizon = 2 * 365 * 24 * time.Hour
+)
+
+// ReminderStore persists reminders for the scheduler to deliver
+type ReminderStore interface {
+	CreateReminder(ctx context.Context, r *model.Reminder) error
+}
+
+// RemindersTool sets reminders delivered at a time in the user's time zone
+type RemindersTool struct {
+	store  ReminderStore
+	places *geo.Gazetteer
+	now    func() time.Time
+}
+
+// NewRemindersTool creates a new reminder tool; city and country names are
+// accepted as time zones when places is not nil
+func NewRemindersTool(store ReminderStore, places *geo.Gazetteer) *RemindersTool {
+	return &RemindersTool{
+		store:  store,
+		places: places,
+		now:    time.Now,
+	}
+}
+
+func (t *RemindersTool) Name() string {
+	return "create_reminder"
+}
+
+func (t *RemindersTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "create_reminder",
+		Description: openai.String("Set a reminder that is delivered to the user at a given time, in this conversation and any " +
+			"configured notification channel. Give either 'due' as a local wall clock time with its 'time_zone', " +
+			"or 'in' as a delay from now. Always pass the user's time zone with 'due'; ask for it if unknown."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]string{
+					"type":        "string",
+					"description": "What to remind the user of",
+				},
+				"due": map[string]string{
+					"type":        "string",
+					"description": "Local time to deliver at, in YYYY-MM-DDTHH:MM format (e.g., '2025-12-15T09:30')",
+				},
+				"time_zone": map[string]string{
+					"type":        "string",
+					"description": "IANA time zone (e.g., 'Europe/Madrid') or city the due time is in",
+				},
+				"in": map[string]string{
+					"type":        "string",
+					"description": "Delay from now instead of a due time, as a duration (e.g., '20m', '1h30m')",
+				},
+			},
+			"required": []string{"text"},
+		},
+	})
+}
+
+func (t *RemindersTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Text     string `json:"text"`
+		Due      string `json:"due"`
+		TimeZone string `json:"time_zone"`
+		In       string `json:"in"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid create_reminder parameters: %w", err)
+	}
+
+	text := strings.TrimSpace(params.Text)
+	switch {
+	case text == "":
+		return "", fmt.Errorf("text is required to set a reminder")
+	case utf8.RuneCountInString(text) > maxReminderLength:
+		return "", fmt.Errorf("reminder is too long, the limit is %d characters", maxReminderLength)
+	}
+
+	now := t.now()
+
+	// Delays still get a zone, so the reminder reads in the user's local time
+	loc, label := time.UTC, "UTC"
+	if params.TimeZone != "" {
+		var err error
+		if loc, label, err = resolveZone(t.places, params.TimeZone); err != nil {
+			return "", fmt.Errorf("invalid time_zone '%s': %w", params.TimeZone, err)
+		}
+	}
+
+	var due time.Time
+	switch {
+	case params.Due != "" && params.In != "":
+		return "", fmt.Errorf("give either due or in, not both")
+
+	case params.In != "":
+		d, err := time.ParseDuration(params.In)
+		if err != nil || d <= 0 {
+			return "", fmt.Errorf("invalid in '%s', expected a positive duration such as '20m' or '1h30m'", params.In)
+		}
+		due = now.Add(d)
+
+	case params.Due != "":
+		if params.TimeZone == "" {
+			return "", fmt.Errorf("time_zone is required with due, ask the user where they are")
+		}
+		var err error
+		if due, err = parseLocalTime(params.Due, loc); err != nil {
+			return "", err
+		}
+
+	default:
+		return "", fmt.Errorf("due or in is required to set a reminder")
+	}
+
+	switch {
+	case due.Before(now):
+		return "", fmt.Errorf("%s is in the past, it is now %s", due.In(loc).Format("Mon 2 Jan 2006 15:04 MST"), now.In(loc).Format("Mon 2 Jan 2006 15:04 MST"))
+	case due.Sub(now) > maxReminderHorizon:
+		return "", fmt.Errorf("reminders can be set at most 2 years ahead")
+	}
+
+	r := &model.Reminder{
+		ID:        primitive.NewObjectID(),
+		UserID:    auth.FromContext(ctx),
+		Text:      text,
+		DueAt:     due.UTC(),
+		TimeZone:  loc.String(),
+		Status:    model.ReminderPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Delivered: []string{},
+	}
+	if id, ok := model.ConversationIDFromContext(ctx); ok {
+		r.ConversationID = id
+	}
+
+	if err := t.store.CreateReminder(ctx, r); err != nil {
+		return "", fmt.Errorf("failed to save reminder: %w", err)
+	}
+
+	return fmt.Sprintf("Reminder set for %s (%s; %s UTC, in %s): %s [id %s]",
+		r.Local().Format("Mon 2 Jan 2006 15:04 MST"),
+		label,
+		r.DueAt.Format("15:04"),
+		describeDelay(due.Sub(now)),
+		r.Text,
+		r.ID.Hex()), nil
+}
+
+// parseLocalTime parses a wall clock time in loc. Times skipped by a DST change
+// move forward by the size of the gap, as time.Date does.
+func parseLocalTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	// Explicit offsets are honoured, the zone is then only used for display
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid due '%s', expected YYYY-MM-DDTHH:MM", value)
+}
+
+// describeDelay renders a delay in days, hours and minutes
+func describeDelay(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	for _, p := range []struct {
+		n    int
+		unit string
+	}{{days, "day"}, {hours, "hour"}, {minutes, "minute"}} {
+		switch {
+		case p.n == 1:
+			parts = append(parts, "1 "+p.unit)
+		case p.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", p.n, p.unit))
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryReminders is an in-memory ReminderStore
+type memoryReminders struct {
+	reminders []*model.Reminder
+	err       error
+}
+
+func (m *memoryReminders) CreateReminder(ctx context.Context, r *model.Reminder) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.reminders = append(m.reminders, r)
+	return nil
+}
+
+func TestRemindersTool_Handle(t *testing.T) {
+	now := time.Date(2026, 3, 28, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		args        string
+		wantDue     time.Time
+		wantZone    string
+		wantContain []string
+		wantErr     string
+	}{
+		{
+			name:        "local time across a DST change",
+			args:        `{"text": "Call the dentist", "due": "2026-03-30T09:00", "time_zone": "Europe/Madrid"}`,
+			wantDue:     time.Date(2026, 3, 30, 7, 0, 0, 0, time.UTC),
+			wantZone:    "Europe/Madrid",
+			wantContain: []string{"Mon 30 Mar 2026 09:00 CEST", "07:00 UTC", "in 1 day 21 hours", "Call the dentist"},
+		},
+		{
+			name:        "city as time zone",
+			args:        `{"text": "Check in for the flight", "due": "2026-03-29 06:15", "time_zone": "Tokyo"}`,
+			wantDue:     time.Date(2026, 3, 28, 21, 15, 0, 0, time.UTC),
+			wantZone:    "Asia/Tokyo",
+			wantContain: []string{"Sun 29 Mar 2026 06:15 JST", "Asia/Tokyo"},
+		},
+		{
+			name:        "explicit offset",
+			args:        `{"text": "Standup", "due": "2026-03-28T09:00:00-04:00", "time_zone": "America/New_York"}`,
+			wantDue:     time.Date(2026, 3, 28, 13, 0, 0, 0, time.UTC),
+			wantZone:    "America/New_York",
+			wantContain: []string{"Sat 28 Mar 2026 09:00 EDT", "in 3 hours"},
+		},
+		{
+			name:        "delay",
+			args:        `{"text": "Take the bread out", "in": "1h30m"}`,
+			wantDue:     now.Add(90 * time.Minute),
+			wantZone:    "UTC",
+			wantContain: []string{"11:30 UTC", "in 1 hour 30 minutes"},
+		},
+		{
+			name:        "delay shown in the user's zone",
+			args:        `{"text": "Stretch", "in": "20m", "time_zone": "Europe/London"}`,
+			wantDue:     now.Add(20 * time.Minute),
+			wantZone:    "Europe/London",
+			wantContain: []string{"Sat 28 Mar 2026 10:20 GMT", "in 20 minutes"},
+		},
+		{
+			name:    "in the past",
+			args:    `{"text": "Too late", "due": "2026-03-28T09:00", "time_zone": "Europe/Madrid"}`,
+			wantErr: "is in the past, it is now Sat 28 Mar 2026 11:00 CET",
+		},
+		{
+			name:    "too far ahead",
+			args:    `{"text": "Renew passport", "due": "2036-03-28T09:00", "time_zone": "Europe/Madrid"}`,
+			wantErr: "at most 2 years ahead",
+		},
+		{
+			name:    "due without a time zone",
+			args:    `{"text": "Standup", "due": "2026-03-30T09:00"}`,
+			wantErr: "time_zone is required",
+		},
+		{
+			name:    "due and delay",
+			args:    `{"text": "Standup", "due": "2026-03-30T09:00", "time_zone": "UTC", "in": "1h"}`,
+			wantErr: "either due or in",
+		},
+		{
+			name:    "no time",
+			args:    `{"text": "Standup"}`,
+			wantErr: "due or in is required",
+		},
+		{
+			name:    "negative delay",
+			args:    `{"text": "Standup", "in": "-5m"}`,
+			wantErr: "expected a positive duration",
+		},
+		{
+			name:    "bad due",
+			args:    `{"text": "Standup", "due": "next monday", "time_zone": "UTC"}`,
+			wantErr: "expected YYYY-MM-DDTHH:MM",
+		},
+		{
+			name:    "unknown zone",
+			args:    `{"text": "Standup", "due": "2026-03-30T09:00", "time_zone": "Atlantis"}`,
+			wantErr: "invalid time_zone 'Atlantis'",
+		},
+		{
+			name:    "no text",
+			args:    `{"text": "  ", "in": "1h"}`,
+			wantErr: "text is required",
+		},
+		{
+			name:    "text too long",
+			args:    `{"text": "` + strings.Repeat("a", maxReminderLength+1) + `", "in": "1h"}`,
+			wantErr: "reminder is too long",
+		},
+		{
+			name:    "invalid json",
+			args:    `{"text": `,
+			wantErr: "invalid create_reminder parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryReminders{}
+			tool := NewRemindersTool(store, geo.Default())
+			tool.now = func() time.Time { return now }
+
+			result, err := tool.Handle(context.Background(), tt.args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				if len(store.reminders) != 0 {
+					t.Errorf("expected nothing stored, got %d reminders", len(store.reminders))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+
+			if len(store.reminders) != 1 {
+				t.Fatalf("expected one stored reminder, got %d", len(store.reminders))
+			}
+			r := store.reminders[0]
+			if !r.DueAt.Equal(tt.wantDue) || r.TimeZone != tt.wantZone {
+				t.Errorf("stored due %s in %s, want %s in %s", r.DueAt, r.TimeZone, tt.wantDue, tt.wantZone)
+			}
+			if r.Status != model.ReminderPending || !strings.Contains(result, r.ID.Hex()) {
+				t.Errorf("expected a pending reminder with its ID in the result, got %+v", r)
+			}
+		})
+	}
+}
+
+func TestRemindersTool_Context(t *testing.T) {
+	store := &memoryReminders{}
+	tool := NewRemindersTool(store, nil)
+
+	conversation := primitive.NewObjectID()
+	ctx := model.WithConversationID(auth.WithUser(context.Background(), "alice"), conversation)
+
+	if _, err := tool.Handle(ctx, `{"text": "Stretch", "in": "20m"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tool.Handle(context.Background(), `{"text": "Stretch", "in": "20m"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, second := store.reminders[0], store.reminders[1]
+	if first.UserID != "alice" || first.ConversationID != conversation {
+		t.Errorf("expected alice's reminder in conversation %s, got user %q in %s", conversation.Hex(), first.UserID, first.ConversationID.Hex())
+	}
+	if second.UserID != auth.DefaultUser || !second.ConversationID.IsZero() {
+		t.Errorf("expected a default user reminder without conversation, got user %q in %s", second.UserID, second.ConversationID.Hex())
+	}
+
+	store.err = errors.New("connection reset")
+	if _, err := tool.Handle(ctx, `{"text": "Stretch", "in": "20m"}`); err == nil || !strings.Contains(err.Error(), "failed to save reminder") {
+		t.Errorf("expected a storage error, got: %v", err)
+	}
+}
+
+func TestDescribeDelay(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{20 * time.Second, "less than a minute"},
+		{time.Minute, "1 minute"},
+		{61 * time.Minute, "1 hour 1 minute"},
+		{48 * time.Hour, "2 days"},
+		{25*time.Hour + 29*time.Second, "1 day 1 hour"},
+	}
+	for _, tt := range tests {
+		if got := describeDelay(tt.in); got != tt.want {
+			t.Errorf("describeDelay(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
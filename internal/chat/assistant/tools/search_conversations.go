@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/recall"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// snippetLength is how much of each matching message the tool returns, in characters
+const snippetLength = 400
+
+// ConversationSearcher finds messages of a user's past conversations by meaning
+type ConversationSearcher interface {
+	Search(ctx context.Context, q recall.Query) ([]recall.Match, error)
+}
+
+// SearchConversationsTool lets the model look up what was said in the user's other conversations
+type SearchConversationsTool struct {
+	searcher ConversationSearcher
+}
+
+// NewSearchConversationsTool creates a new past conversation search tool
+func NewSearchConversationsTool(searcher ConversationSearcher) *SearchConversationsTool {
+	return &SearchConversationsTool{
+		searcher: searcher,
+	}
+}
+
+func (t *SearchConversationsTool) Name() string {
+	return "search_past_conversations"
+}
+
+func (t *SearchConversationsTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "search_past_conversations",
+		Description: openai.String("Search the user's earlier conversations with you by meaning, not exact words. " +
+			"Use it when the user refers to something discussed before, e.g. 'what hotel did you recommend in Lisbon last month?'. " +
+			"Returns the closest messages with their date and conversation; the current conversation is not searched."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]string{
+					"type":        "string",
+					"description": "What to look for, as a short description, e.g. 'hotel recommendation in Lisbon'",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("How many messages to return, default %d, at most %d", recall.DefaultLimit, recall.MaxLimit),
+				},
+			},
+			"required": []string{"query"},
+		},
+	})
+}
+
+func (t *SearchConversationsTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid search parameters: %w", err)
+	}
+
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+
+	q := recall.Query{
+		UserID: auth.FromContext(ctx),
+		Text:   query,
+		Limit:  params.Limit,
+	}
+	if id, ok := model.ConversationIDFromContext(ctx); ok {
+		q.ExcludeConversationID = id
+	}
+
+	matches, err := t.searcher.Search(ctx, q)
+	if err != nil {
+		return "", fmt.Errorf("failed to search past conversations: %w", err)
+	}
+	if len(matches) == 0 {
+		return "No past conversations found.", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d message(s) from past conversations, closest first:", len(matches))
+	for _, m := range matches {
+		speaker := "the user"
+		if m.Role == model.RoleAssistant {
+			speaker = "you"
+		}
+		fmt.Fprintf(&b, "\n- %s, conversation %q (id %s), %s said:\n  %s",
+			m.CreatedAt.Format(time.DateOnly), m.Title, m.ConversationID.Hex(), speaker,
+			strings.Join(strings.Fields(truncate(m.Text, snippetLength)), " "))
+	}
+	return b.String(), nil
+}
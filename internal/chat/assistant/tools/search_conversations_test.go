@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/recall"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fixedSearcher returns the same matches for every query and records the last one
+type fixedSearcher struct {
+	matches []recall.Match
+	err     error
+	query   recall.Query
+}
+
+func (s *fixedSearcher) Search(ctx context.Context, q recall.Query) ([]recall.Match, error) {
+	s.query = q
+	return s.matches, s.err
+}
+
+func TestSearchConversationsTool_Handle(t *testing.T) {
+	lisbon := primitive.NewObjectID()
+	current := primitive.NewObjectID()
+	ctx := model.WithConversationID(auth.WithUser(context.Background(), "ana"), current)
+
+	searcher := &fixedSearcher{matches: []recall.Match{{
+		Document: recall.Document{
+			ConversationID: lisbon,
+			Title:          "Trip to Lisbon",
+			Role:           model.RoleAssistant,
+			Text:           "Memmo Alfama is a boutique hotel\nwith views over the river. " + strings.Repeat("More details. ", 50),
+			CreatedAt:      time.Date(2026, 9, 12, 10, 0, 0, 0, time.UTC),
+		},
+		Score: 0.82,
+	}}}
+	tool := NewSearchConversationsTool(searcher)
+
+	out, err := tool.Handle(ctx, `{"query": "hotel recommendation in Lisbon", "limit": 3}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q := searcher.query; q.UserID != "ana" || q.Text != "hotel recommendation in Lisbon" || q.Limit != 3 || q.ExcludeConversationID != current {
+		t.Errorf("unexpected query %+v", q)
+	}
+	for _, want := range []string{"2026-09-12", `"Trip to Lisbon"`, lisbon.Hex(), "you said", "boutique hotel with views", "…"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected result to contain %q, got: %s", want, out)
+		}
+	}
+	if len(out) > snippetLength+200 {
+		t.Errorf("expected the snippet to be shortened, got %d bytes", len(out))
+	}
+
+	t.Run("no matches", func(t *testing.T) {
+		out, err := NewSearchConversationsTool(&fixedSearcher{}).Handle(ctx, `{"query": "hotel"}`)
+		if err != nil || out != "No past conversations found." {
+			t.Errorf("unexpected result %q, %v", out, err)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, args := range []string{`{"query": " "}`, `not json`} {
+			if _, err := tool.Handle(ctx, args); err == nil {
+				t.Errorf("expected an error for %s", args)
+			}
+		}
+
+		failing := NewSearchConversationsTool(&fixedSearcher{err: errors.New("index unavailable")})
+		if _, err := failing.Handle(ctx, `{"query": "hotel"}`); err == nil || !strings.Contains(err.Error(), "index unavailable") {
+			t.Errorf("expected the search error, got %v", err)
+		}
+	})
+}
@@ -4,17 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
 	"github.com/openai/openai-go/v2"
 )
 
 // TimeZoneTool converts times between different time zones
-type TimeZoneTool struct{}
+type TimeZoneTool struct {
+	places *geo.Gazetteer
+}
 
-// NewTimeZoneTool creates a new time zone converter tool
-func NewTimeZoneTool() *TimeZoneTool {
-	return &TimeZoneTool{}
+// NewTimeZoneTool creates a new time zone converter tool; city and country
+// names are resolved with the given gazetteer
+func NewTimeZoneTool(places *geo.Gazetteer) *TimeZoneTool {
+	return &TimeZoneTool{
+		places: places,
+	}
 }
 
 func (t *TimeZoneTool) Name() string {
@@ -24,7 +31,7 @@ func (t *TimeZoneTool) Name() string {
 func (t *TimeZoneTool) Definition() openai.ChatCompletionToolUnionParam {
 	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
 		Name:        "convert_timezone",
-		Description: openai.String("Convert a time from one timezone to another. Useful for travelers scheduling across different locations. Supports IANA timezone names (e.g., 'America/New_York', 'Europe/Madrid', 'Asia/Tokyo')."),
+		Description: openai.String("Convert a time from one timezone to another. Useful for travelers scheduling across different locations. Accepts IANA timezone names (e.g., 'America/New_York', 'Europe/Madrid', 'Asia/Tokyo') or city, island and country names (e.g., 'Austin', 'Bali', 'Japan')."),
 		Parameters: openai.FunctionParameters{
 			"type": "object",
 			"properties": map[string]any{
@@ -34,11 +41,11 @@ func (t *TimeZoneTool) Definition() openai.ChatCompletionToolUnionParam {
 				},
 				"from_timezone": map[string]string{
 					"type":        "string",
-					"description": "Source timezone in IANA format (e.g., 'America/New_York', 'Europe/Madrid', 'UTC')",
+					"description": "Source timezone in IANA format (e.g., 'America/New_York', 'Europe/Madrid', 'UTC') or a place name (e.g., 'Austin, TX', 'Bali')",
 				},
 				"to_timezone": map[string]string{
 					"type":        "string",
-					"description": "Target timezone in IANA format (e.g., 'America/New_York', 'Europe/Madrid', 'Asia/Tokyo')",
+					"description": "Target timezone in IANA format (e.g., 'America/New_York', 'Europe/Madrid', 'Asia/Tokyo') or a place name (e.g., 'Tokyo', 'São Paulo')",
 				},
 			},
 			"required": []string{"from_timezone", "to_timezone"},
@@ -46,24 +53,44 @@ func (t *TimeZoneTool) Definition() openai.ChatCompletionToolUnionParam {
 	})
 }
 
-func (t *TimeZoneTool) Handle(ctx context.Context, args string) (string, error) {
-	var params struct {
-		Time         string `json:"time"`
-		FromTimezone string `json:"from_timezone"`
-		ToTimezone   string `json:"to_timezone"`
+type timeZoneArgs struct {
+	Time         string `json:"time"`
+	FromTimezone string `json:"from_timezone"`
+	ToTimezone   string `json:"to_timezone"`
+}
+
+// CacheKey normalises the arguments of conversions of a given time; those of
+// the current time aren't cached
+func (t *TimeZoneTool) CacheKey(args string) (string, bool) {
+	var params timeZoneArgs
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", false
 	}
 
+	at, err := time.Parse(time.RFC3339, params.Time)
+	if err != nil {
+		return "", false
+	}
+
+	// The same instant written in another offset converts the same
+	params.Time = at.UTC().Format(time.RFC3339)
+	return normalArgs(params)
+}
+
+func (t *TimeZoneTool) Handle(ctx context.Context, args string) (string, error) {
+	var params timeZoneArgs
+
 	if err := json.Unmarshal([]byte(args), &params); err != nil {
 		return "", fmt.Errorf("invalid timezone parameters: %w", err)
 	}
 
 	// Load timezones
-	fromLoc, err := time.LoadLocation(params.FromTimezone)
+	fromLoc, fromLabel, err := t.zone(params.FromTimezone)
 	if err != nil {
 		return "", fmt.Errorf("invalid source timezone '%s': %w", params.FromTimezone, err)
 	}
 
-	toLoc, err := time.LoadLocation(params.ToTimezone)
+	toLoc, toLabel, err := t.zone(params.ToTimezone)
 	if err != nil {
 		return "", fmt.Errorf("invalid target timezone '%s': %w", params.ToTimezone, err)
 	}
@@ -103,9 +130,54 @@ func (t *TimeZoneTool) Handle(ctx context.Context, args string) (string, error)
 			"To:   %s (%s)\n"+
 			"Time difference: %s",
 		inputTime.Format("2006-01-02 15:04:05 MST"),
-		params.FromTimezone,
+		fromLabel,
 		convertedTime.Format("2006-01-02 15:04:05 MST"),
-		params.ToTimezone,
+		toLabel,
 		diffStr,
 	), nil
 }
+
+func (t *TimeZoneTool) zone(name string) (*time.Location, string, error) {
+	return resolveZone(t.places, name)
+}
+
+// resolveZone resolves an IANA name, or a city or country from the gazetteer, to a location.
+// The label names the zone and, for places, what the name was matched to.
+func resolveZone(places *geo.Gazetteer, name string) (*time.Location, string, error) {
+	loc, err := time.LoadLocation(name)
+	if err == nil {
+		return loc, name, nil
+	}
+	if places == nil {
+		return nil, "", err
+	}
+
+	if matches := places.Lookup(name); len(matches) > 0 {
+		p := matches[0]
+		label := p.TimeZone + ", " + p.Label()
+
+		// Mention namesakes in other zones so the model can correct a wrong guess
+		var others []string
+		for _, m := range matches[1:] {
+			if m.TimeZone != p.TimeZone {
+				others = append(others, fmt.Sprintf("%s (%s)", m.Label(), m.TimeZone))
+			}
+		}
+		if len(others) > 0 {
+			label += "; also matches " + strings.Join(others, ", ")
+		}
+
+		loc, err := time.LoadLocation(p.TimeZone)
+		return loc, label, err
+	}
+
+	if c, ok := places.Country(name); ok {
+		if len(c.TimeZones) > 1 {
+			return nil, "", fmt.Errorf("%s spans several time zones (%s), name a city instead", c.Name, strings.Join(c.TimeZones, ", "))
+		}
+		loc, err := time.LoadLocation(c.Capital.TimeZone)
+		return loc, c.Capital.TimeZone + ", " + c.Name, err
+	}
+
+	return nil, "", fmt.Errorf("unknown time zone or place, use an IANA name such as 'Europe/Madrid'")
+}
@@ -4,17 +4,19 @@ import (
 	"context"
 	"strings"
 	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
 )
 
 func TestTimeZoneTool_Name(t *testing.T) {
-	tool := NewTimeZoneTool()
+	tool := NewTimeZoneTool(geo.Default())
 	if tool.Name() != "convert_timezone" {
 		t.Errorf("expected 'convert_timezone', got '%s'", tool.Name())
 	}
 }
 
 func TestTimeZoneTool_Handle(t *testing.T) {
-	tool := NewTimeZoneTool()
+	tool := NewTimeZoneTool(geo.Default())
 	ctx := context.Background()
 
 	tests := []struct {
@@ -80,6 +82,45 @@ func TestTimeZoneTool_Handle(t *testing.T) {
 			}`,
 			wantErr: true,
 		},
+		{
+			name: "city names resolve to their zones",
+			args: `{
+				"time": "2025-12-15T14:00:00Z",
+				"from_timezone": "Austin",
+				"to_timezone": "Bali"
+			}`,
+			wantContain: []string{
+				"2025-12-15 08:00:00 CST (America/Chicago, Austin, Texas, United States)",
+				"2025-12-15 22:00:00 WITA (Asia/Makassar, Denpasar, Bali, Indonesia)",
+				"+14.0 hours",
+			},
+		},
+		{
+			name: "namesakes in other zones are mentioned",
+			args: `{
+				"time": "2025-12-15T14:00:00Z",
+				"from_timezone": "UTC",
+				"to_timezone": "San Jose"
+			}`,
+			wantContain: []string{"America/Los_Angeles, San Jose, California", "also matches San José, Costa Rica (America/Costa_Rica)"},
+		},
+		{
+			name: "country with a single zone",
+			args: `{
+				"time": "2025-07-01T12:00:00Z",
+				"from_timezone": "Germany",
+				"to_timezone": "Türkiye"
+			}`,
+			wantContain: []string{"(Europe/Berlin, Germany)", "(Europe/Istanbul, Turkey)", "+1.0 hours"},
+		},
+		{
+			name: "country spanning several zones",
+			args: `{
+				"from_timezone": "United States",
+				"to_timezone": "Europe/Madrid"
+			}`,
+			wantErr: true,
+		},
 		{
 			name: "invalid time format",
 			args: `{
@@ -122,7 +163,7 @@ func TestTimeZoneTool_Handle(t *testing.T) {
 }
 
 func TestTimeZoneTool_Definition(t *testing.T) {
-	tool := NewTimeZoneTool()
+	tool := NewTimeZoneTool(geo.Default())
 	def := tool.Definition()
 
 	// Verify it returns a valid tool definition (basic smoke test)
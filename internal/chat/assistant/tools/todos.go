@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxTodoLength bounds the text of one to-do item, in characters
+const maxTodoLength = 500
+
+// TodoStore persists to-do items per user
+type TodoStore interface {
+	CreateTodo(ctx context.Context, t *model.Todo) error
+	ListTodos(ctx context.Context, userID string, f model.TodoFilter) ([]*model.Todo, error)
+	CompleteTodo(ctx context.Context, userID, id string) (*model.Todo, error)
+	DeleteTodo(ctx context.Context, userID, id string) error
+}
+
+// TodosTool keeps the user's to-do list, shared by all of their conversations
+type TodosTool struct {
+	store TodoStore
+}
+
+// NewTodosTool creates a new to-do list tool backed by the given store
+func NewTodosTool(store TodoStore) *TodosTool {
+	return &TodosTool{
+		store: store,
+	}
+}
+
+func (t *TodosTool) Name() string {
+	return "todos"
+}
+
+func (t *TodosTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name: "todos",
+		Description: openai.String("Manage the user's to-do list, which persists across all of their conversations. " +
+			"'create' adds an item with an optional due date, 'list' shows open items (or all with include_completed), " +
+			"'search' finds items containing a text, 'complete' marks an item done and 'delete' removes it, both by ID."),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"operation": map[string]any{
+					"type":        "string",
+					"description": "What to do",
+					"enum":        []string{"create", "list", "search", "complete", "delete"},
+				},
+				"text": map[string]string{
+					"type":        "string",
+					"description": "What needs doing (create)",
+				},
+				"due": map[string]string{
+					"type":        "string",
+					"description": "Optional due date in YYYY-MM-DD format (create)",
+				},
+				"query": map[string]string{
+					"type":        "string",
+					"description": "Text to look for in items, ignoring case (search)",
+				},
+				"include_completed": map[string]string{
+					"type":        "boolean",
+					"description": "Also show completed items (list, search)",
+				},
+				"id": map[string]string{
+					"type":        "string",
+					"description": "ID of the item, as shown by list or search (complete, delete)",
+				},
+			},
+			"required": []string{"operation"},
+		},
+	})
+}
+
+func (t *TodosTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Operation        string `json:"operation"`
+		Text             string `json:"text"`
+		Due              string `json:"due"`
+		Query            string `json:"query"`
+		IncludeCompleted bool   `json:"include_completed"`
+		ID               string `json:"id"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("invalid todos parameters: %w", err)
+	}
+
+	userID := auth.FromContext(ctx)
+
+	switch params.Operation {
+	case "create":
+		return t.create(ctx, userID, strings.TrimSpace(params.Text), params.Due)
+
+	case "list", "search":
+		filter := model.TodoFilter{IncludeCompleted: params.IncludeCompleted}
+		if params.Operation == "search" {
+			if filter.Query = strings.TrimSpace(params.Query); filter.Query == "" {
+				return "", fmt.Errorf("query is required to search the to-do list")
+			}
+		}
+
+		todos, err := t.store.ListTodos(ctx, userID, filter)
+		if err != nil {
+			return "", fmt.Errorf("failed to list to-do items: %w", err)
+		}
+		if len(todos) == 0 {
+			switch {
+			case filter.Query != "":
+				return fmt.Sprintf("No to-do items contain %q.", filter.Query), nil
+			case filter.IncludeCompleted:
+				return "The to-do list is empty.", nil
+			default:
+				return "No open to-do items.", nil
+			}
+		}
+		return describeTodos(todos, time.Now()), nil
+
+	case "complete":
+		if params.ID == "" {
+			return "", fmt.Errorf("id is required to complete a to-do item")
+		}
+		todo, err := t.store.CompleteTodo(ctx, userID, params.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to complete to-do item %s: %w", params.ID, err)
+		}
+		return fmt.Sprintf("Marked as done: %s", todo.Text), nil
+
+	case "delete":
+		if params.ID == "" {
+			return "", fmt.Errorf("id is required to delete a to-do item")
+		}
+		if err := t.store.DeleteTodo(ctx, userID, params.ID); err != nil {
+			return "", fmt.Errorf("failed to delete to-do item %s: %w", params.ID, err)
+		}
+		return fmt.Sprintf("Deleted to-do item %s.", params.ID), nil
+
+	default:
+		return "", fmt.Errorf("unknown operation '%s', expected create, list, search, complete or delete", params.Operation)
+	}
+}
+
+func (t *TodosTool) create(ctx context.Context, userID, text, due string) (string, error) {
+	if text == "" {
+		return "", fmt.Errorf("text is required to create a to-do item")
+	}
+	if n := utf8.RuneCountInString(text); n > maxTodoLength {
+		return "", fmt.Errorf("to-do item is too long (%d characters, max %d)", n, maxTodoLength)
+	}
+
+	now := time.Now()
+	todo := &model.Todo{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Text:      text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if due != "" {
+		d, err := parseDate("due", due)
+		if err != nil {
+			return "", err
+		}
+		todo.Due = &d
+	}
+
+	if err := t.store.CreateTodo(ctx, todo); err != nil {
+		return "", fmt.Errorf("failed to save to-do item: %w", err)
+	}
+
+	out := fmt.Sprintf("Added to-do item %s: %s", todo.ID.Hex(), todo.Text)
+	if todo.Due != nil {
+		out += fmt.Sprintf(" (due %s, %s)", todo.Due.Format(time.DateOnly), todo.Due.Weekday())
+	}
+	return out, nil
+}
+
+// describeTodos lists items as a checklist with their IDs; now flags overdue items
+func describeTodos(todos []*model.Todo, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d to-do item(s):", len(todos))
+	for _, t := range todos {
+		box := "[ ]"
+		if t.Done {
+			box = "[x]"
+		}
+		fmt.Fprintf(&b, "\n- %s %s (id %s", box, t.Text, t.ID.Hex())
+		if t.Due != nil {
+			fmt.Fprintf(&b, ", due %s", t.Due.Format(time.DateOnly))
+			if !t.Done && t.Due.Before(today) {
+				b.WriteString(", overdue")
+			}
+		}
+		if t.Done && t.CompletedAt != nil {
+			fmt.Fprintf(&b, ", done %s", t.CompletedAt.Format(time.DateOnly))
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryTodos is an in-memory TodoStore
+type memoryTodos struct {
+	todos []*model.Todo
+}
+
+func (m *memoryTodos) CreateTodo(ctx context.Context, t *model.Todo) error {
+	m.todos = append(m.todos, t)
+	return nil
+}
+
+func (m *memoryTodos) ListTodos(ctx context.Context, userID string, f model.TodoFilter) ([]*model.Todo, error) {
+	var open, done []*model.Todo
+	for i := len(m.todos) - 1; i >= 0; i-- {
+		t := m.todos[i]
+		if t.UserID != userID || !containsFold(t.Text, f.Query) || t.Done && !f.IncludeCompleted {
+			continue
+		}
+		if t.Done {
+			done = append(done, t)
+		} else {
+			open = append(open, t)
+		}
+	}
+	return append(open, done...), nil
+}
+
+func (m *memoryTodos) CompleteTodo(ctx context.Context, userID, id string) (*model.Todo, error) {
+	for _, t := range m.todos {
+		if t.UserID == userID && t.ID.Hex() == id {
+			if !t.Done {
+				now := time.Now()
+				t.Done, t.CompletedAt = true, &now
+			}
+			return t, nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *memoryTodos) DeleteTodo(ctx context.Context, userID, id string) error {
+	for i, t := range m.todos {
+		if t.UserID == userID && t.ID.Hex() == id {
+			m.todos = append(m.todos[:i], m.todos[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("todo not found")
+}
+
+func TestTodosTool_Handle(t *testing.T) {
+	alice := auth.WithUser(context.Background(), "alice")
+	bob := auth.WithUser(context.Background(), "bob")
+
+	store := &memoryTodos{}
+	tool := NewTodosTool(store)
+
+	created, err := tool.Handle(alice, `{"operation": "create", "text": "Book airport transfer", "due": "2020-03-02"}`)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if !strings.Contains(created, "Book airport transfer (due 2020-03-02, Monday)") {
+		t.Errorf("unexpected create result: %s", created)
+	}
+	transfer := idPattern.FindString(created)
+
+	created, err = tool.Handle(alice, `{"operation": "create", "text": "Buy travel adapter"}`)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	adapter := idPattern.FindString(created)
+
+	if _, err := tool.Handle(bob, `{"operation": "create", "text": "Water bob's plants"}`); err != nil {
+		t.Fatalf("create for bob failed: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		args           string
+		wantContain    []string
+		wantNotContain []string
+		wantErr        bool
+	}{
+		{
+			name:           "list shows the user's open items",
+			ctx:            alice,
+			args:           `{"operation": "list"}`,
+			wantContain:    []string{"2 to-do item(s)", "[ ] Buy travel adapter (id " + adapter + ")", "[ ] Book airport transfer (id " + transfer + ", due 2020-03-02, overdue)"},
+			wantNotContain: []string{"plants"},
+		},
+		{
+			name:        "complete",
+			ctx:         alice,
+			args:        `{"operation": "complete", "id": "` + adapter + `"}`,
+			wantContain: []string{"Marked as done: Buy travel adapter"},
+		},
+		{
+			name:           "completed items are hidden by default",
+			ctx:            alice,
+			args:           `{"operation": "list"}`,
+			wantContain:    []string{"1 to-do item(s)", "Book airport transfer"},
+			wantNotContain: []string{"adapter"},
+		},
+		{
+			name:        "completed items on request",
+			ctx:         alice,
+			args:        `{"operation": "list", "include_completed": true}`,
+			wantContain: []string{"2 to-do item(s)", "[x] Buy travel adapter (id " + adapter + ", done "},
+		},
+		{
+			name:           "search ignores case",
+			ctx:            alice,
+			args:           `{"operation": "search", "query": "AIRPORT"}`,
+			wantContain:    []string{"1 to-do item(s)", "Book airport transfer"},
+			wantNotContain: []string{"adapter"},
+		},
+		{
+			name:        "search without matches",
+			ctx:         alice,
+			args:        `{"operation": "search", "query": "visa"}`,
+			wantContain: []string{`No to-do items contain "visa"`},
+		},
+		{
+			name:        "other users' items are unreachable",
+			ctx:         bob,
+			args:        `{"operation": "search", "query": "airport"}`,
+			wantContain: []string{"No to-do items contain"},
+		},
+		{
+			name:    "other users can't complete",
+			ctx:     bob,
+			args:    `{"operation": "complete", "id": "` + transfer + `"}`,
+			wantErr: true,
+		},
+		{
+			name:    "create needs text",
+			ctx:     alice,
+			args:    `{"operation": "create", "due": "2026-01-01"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid due date",
+			ctx:     alice,
+			args:    `{"operation": "create", "text": "Pack", "due": "next Friday"}`,
+			wantErr: true,
+		},
+		{
+			name:    "complete needs an id",
+			ctx:     alice,
+			args:    `{"operation": "complete"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown operation",
+			ctx:     alice,
+			args:    `{"operation": "archive", "id": "` + transfer + `"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			ctx:     alice,
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+		{
+			name:        "delete",
+			ctx:         alice,
+			args:        `{"operation": "delete", "id": "` + transfer + `"}`,
+			wantContain: []string{"Deleted to-do item " + transfer},
+		},
+		{
+			name:        "nothing open",
+			ctx:         alice,
+			args:        `{"operation": "list"}`,
+			wantContain: []string{"No open to-do items."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Handle(tt.ctx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got: %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+			for _, unwanted := range tt.wantNotContain {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("expected result not to contain '%s', got: %s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeTodos_Overdue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	day := func(d int) *time.Time {
+		t := time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	todos := []*model.Todo{
+		{ID: primitive.NewObjectID(), Text: "yesterday", Due: day(17)},
+		{ID: primitive.NewObjectID(), Text: "today", Due: day(18)},
+		{ID: primitive.NewObjectID(), Text: "done late", Due: day(1), Done: true},
+	}
+
+	got := describeTodos(todos, now)
+	if !strings.Contains(got, "yesterday (id "+todos[0].ID.Hex()+", due 2026-10-17, overdue)") {
+		t.Errorf("expected yesterday's item to be overdue, got: %s", got)
+	}
+	if strings.Count(got, "overdue") != 1 {
+		t.Errorf("expected only one overdue item, got: %s", got)
+	}
+}
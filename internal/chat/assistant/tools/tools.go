@@ -2,9 +2,11 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
 	"github.com/openai/openai-go/v2"
 )
 
@@ -23,15 +25,59 @@ type Tool interface {
 	Handle(ctx context.Context, args string) (string, error)
 }
 
-// Definitions converts a slice of tools into OpenAI tool parameters
-func Definitions(tools []Tool) []openai.ChatCompletionToolUnionParam {
-	defs := make([]openai.ChatCompletionToolUnionParam, len(tools))
-	for i, tool := range tools {
+// Definitions converts a slice of tools into OpenAI tool parameters, leaving
+// out the tools the policies don't allow
+func Definitions(tools []Tool, policies ...model.ToolPolicy) []openai.ChatCompletionToolUnionParam {
+	allowed := Allowed(tools, policies...)
+	defs := make([]openai.ChatCompletionToolUnionParam, len(allowed))
+	for i, tool := range allowed {
 		defs[i] = tool.Definition()
 	}
 	return defs
 }
 
+// Allowed returns the tools every policy allows
+func Allowed(tools []Tool, policies ...model.ToolPolicy) []Tool {
+	if len(policies) == 0 {
+		return tools
+	}
+
+	var allowed []Tool
+	for _, tool := range tools {
+		if model.ToolPolicies(policies).Allows(tool.Name()) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// ErrUnknownTool is returned by Execute when no tool matches the requested name
+var ErrUnknownTool = errors.New("unknown tool")
+
+// Execute finds the tool with the given name and runs it with the given JSON arguments.
+// Failures are logged here so every caller (chat replies, MCP) reports them the same way.
+func Execute(ctx context.Context, tools []Tool, name, arguments string) (string, error) {
+	for _, tool := range tools {
+		if tool.Name() != name {
+			continue
+		}
+
+		result, err := tool.Handle(ctx, arguments)
+		if err != nil {
+			// The arguments are logged, masked, when the call is received
+			slog.ErrorContext(ctx, "Tool execution failed",
+				"tool", tool.Name(),
+				"error", err,
+			)
+			return "", err
+		}
+		return result, nil
+	}
+
+	slog.WarnContext(ctx, "Unknown tool called", "tool", name)
+	return "", fmt.Errorf("%w: %s", ErrUnknownTool, name)
+}
+
 // Dispatch finds and executes the appropriate tool for a given tool call.
 // Returns an OpenAI tool message with the result or error.
 func Dispatch(ctx context.Context, tools []Tool, call openai.ChatCompletionMessageToolCallUnion) openai.ChatCompletionMessageParamUnion {
@@ -50,21 +96,19 @@ func Dispatch(ctx context.Context, tools []Tool, call openai.ChatCompletionMessa
 		return openai.ToolMessage(fmt.Sprintf("Unknown tool call type: %s", call.Type), call.ID)
 	}
 
-	for _, tool := range tools {
-		if tool.Name() == functionName {
-			result, err := tool.Handle(ctx, arguments)
-			if err != nil {
-				slog.ErrorContext(ctx, "Tool execution failed",
-					"tool", tool.Name(),
-					"error", err,
-					"args", arguments,
-				)
-				return openai.ToolMessage(fmt.Sprintf("Tool failed: %v", err), call.ID)
-			}
-			return openai.ToolMessage(result, call.ID)
-		}
+	return openai.ToolMessage(Result(ctx, tools, functionName, arguments), call.ID)
+}
+
+// Result executes the named tool and returns what to tell the model: the
+// tool's result, or why it couldn't run
+func Result(ctx context.Context, tools []Tool, name, arguments string) string {
+	result, err := Execute(ctx, tools, name, arguments)
+	if errors.Is(err, ErrUnknownTool) {
+		return fmt.Sprintf("Unknown tool: %s", name)
+	}
+	if err != nil {
+		return fmt.Sprintf("Tool failed: %v", err)
 	}
 
-	slog.WarnContext(ctx, "Unknown tool called", "tool", functionName)
-	return openai.ToolMessage(fmt.Sprintf("Unknown tool: %s", functionName), call.ID)
+	return result
 }
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/openai/openai-go/v2"
+)
+
+// namedTool is a tool that only has a name
+type namedTool string
+
+func (t namedTool) Name() string { return string(t) }
+
+func (t namedTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{Name: string(t)})
+}
+
+func (t namedTool) Handle(ctx context.Context, args string) (string, error) {
+	return string(t) + " ran", nil
+}
+
+func TestDefinitions(t *testing.T) {
+	all := []Tool{namedTool("get_weather"), namedTool("notes"), namedTool("create_reminder"), namedTool("calculate")}
+
+	names := func(defs []openai.ChatCompletionToolUnionParam) []string {
+		var out []string
+		for _, d := range defs {
+			out = append(out, d.GetFunction().Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		policies []model.ToolPolicy
+		want     []string
+	}{
+		{"no policy", nil, []string{"get_weather", "notes", "create_reminder", "calculate"}},
+		{"deny", []model.ToolPolicy{{Deny: []string{"notes"}}}, []string{"get_weather", "create_reminder", "calculate"}},
+		{"allow", []model.ToolPolicy{{Allow: []string{"calculate", "notes"}}}, []string{"notes", "calculate"}},
+		{"every policy must allow", []model.ToolPolicy{
+			{Allow: []string{"calculate", "notes", "create_reminder"}},
+			{Deny: []string{"create_reminder"}},
+			{},
+		}, []string{"notes", "calculate"}},
+		{"confirmation doesn't remove tools", []model.ToolPolicy{{Confirm: []string{"notes"}}}, []string{"get_weather", "notes", "create_reminder", "calculate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(Definitions(all, tt.policies...)); !slices.Equal(got, tt.want) {
+				t.Errorf("Definitions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("results", func(t *testing.T) {
+		ctx := context.Background()
+		if got := Result(ctx, all, "notes", `{}`); got != "notes ran" {
+			t.Errorf("unexpected result %q", got)
+		}
+		if got := Result(ctx, all, "send_email", `{}`); got != "Unknown tool: send_email" {
+			t.Errorf("unexpected result %q", got)
+		}
+	})
+}
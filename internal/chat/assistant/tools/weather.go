@@ -5,20 +5,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/weather"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
 	"github.com/openai/openai-go/v2"
 )
 
+// maxForecastDays is the forecast range shared by all weather providers;
+// later dates are answered with climate normals
+const maxForecastDays = 10
+
 // WeatherTool provides current weather and forecast information
 type WeatherTool struct {
-	client *weather.Client
+	client weather.Provider
+	places *geo.Gazetteer
 }
 
-// NewWeatherTool creates a new weather tool with the provided weather client
-func NewWeatherTool(client *weather.Client) *WeatherTool {
+// NewWeatherTool creates a new weather tool with the provided weather provider.
+// Places known to the gazetteer are sent to the provider as coordinates; places may be nil.
+func NewWeatherTool(client weather.Provider, places *geo.Gazetteer) *WeatherTool {
 	return &WeatherTool{
 		client: client,
+		places: places,
 	}
 }
 
@@ -29,7 +40,7 @@ func (t *WeatherTool) Name() string {
 func (t *WeatherTool) Definition() openai.ChatCompletionToolUnionParam {
 	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
 		Name:        "get_weather",
-		Description: openai.String("Get current weather or multi-day forecast for a given location. Use forecast_days for future weather predictions (1-10 days)."),
+		Description: openai.String("Get current weather or multi-day forecast for a given location. Use forecast_days for future weather predictions (1-10 days). Use date for a specific day: past dates return the observed weather, dates more than 10 days ahead return typical weather for that time of year. Can also include weather alerts, air quality, sunrise/sunset times and an hourly breakdown for a given day (e.g. 'will it rain at 3pm?')."),
 		Parameters: openai.FunctionParameters{
 			"type": "object",
 			"properties": map[string]any{
@@ -43,6 +54,26 @@ func (t *WeatherTool) Definition() openai.ChatCompletionToolUnionParam {
 					"minimum":     1,
 					"maximum":     10,
 				},
+				"date": map[string]string{
+					"type":        "string",
+					"description": "Optional specific date (YYYY-MM-DD), e.g. 'last Tuesday' or 'mid-March' for trip planning. Past dates give historical weather, the next 10 days the forecast for that day, later dates climate normals.",
+				},
+				"hourly_date": map[string]string{
+					"type":        "string",
+					"description": "Optional local date (YYYY-MM-DD) within the next 10 days to get an hour-by-hour forecast for, including chance of rain and precipitation amounts.",
+				},
+				"include_alerts": map[string]string{
+					"type":        "boolean",
+					"description": "Include active government weather alerts and warnings (storms, heat, floods).",
+				},
+				"include_air_quality": map[string]string{
+					"type":        "boolean",
+					"description": "Include current air quality (PM2.5, PM10, ozone, US EPA index).",
+				},
+				"include_astronomy": map[string]string{
+					"type":        "boolean",
+					"description": "Include sunrise, sunset and moon times for each forecast day.",
+				},
 			},
 			"required": []string{"location"},
 		},
@@ -51,37 +82,191 @@ func (t *WeatherTool) Definition() openai.ChatCompletionToolUnionParam {
 
 func (t *WeatherTool) Handle(ctx context.Context, args string) (string, error) {
 	var params struct {
-		Location     string `json:"location"`
-		ForecastDays int    `json:"forecast_days,omitempty"`
+		Location          string `json:"location"`
+		ForecastDays      int    `json:"forecast_days,omitempty"`
+		Date              string `json:"date,omitempty"`
+		HourlyDate        string `json:"hourly_date,omitempty"`
+		IncludeAlerts     bool   `json:"include_alerts,omitempty"`
+		IncludeAirQuality bool   `json:"include_air_quality,omitempty"`
+		IncludeAstronomy  bool   `json:"include_astronomy,omitempty"`
 	}
 
 	if err := json.Unmarshal([]byte(args), &params); err != nil {
 		return "", fmt.Errorf("invalid weather parameters: %w", err)
 	}
 
+	// Render in the user's preferred units
+	u := units.FromContext(ctx)
+
+	location, place := t.locate(params.Location)
+
+	today, _ := time.Parse(time.DateOnly, time.Now().Format(time.DateOnly))
+
+	// A specific date outside the forecast range needs another endpoint
+	if params.Date != "" {
+		date, err := time.Parse(time.DateOnly, params.Date)
+		if err != nil {
+			return "", fmt.Errorf("invalid date '%s', expected YYYY-MM-DD: %w", params.Date, err)
+		}
+
+		switch {
+		case date.Before(today):
+			return t.history(ctx, location, place, date, u)
+		case date.After(today.AddDate(0, 0, maxForecastDays-1)):
+			return t.climateNormals(ctx, location, place, date, u)
+		}
+	}
+
+	// Alerts, air quality, astronomy and hourly data only come with forecasts
+	extras := params.Date != "" || params.HourlyDate != "" || params.IncludeAlerts || params.IncludeAirQuality || params.IncludeAstronomy
+
 	// Get forecast if requested
-	if params.ForecastDays > 0 {
-		forecast, err := t.client.GetForecast(ctx, params.Location, params.ForecastDays)
+	if params.ForecastDays > 0 || extras {
+		days := max(params.ForecastDays, 1)
+		for _, d := range []struct{ field, value string }{{"date", params.Date}, {"hourly_date", params.HourlyDate}} {
+			if d.value == "" {
+				continue
+			}
+			date, err := time.Parse(time.DateOnly, d.value)
+			if err != nil {
+				return "", fmt.Errorf("invalid %s '%s', expected YYYY-MM-DD: %w", d.field, d.value, err)
+			}
+			// Make sure the requested day is part of the forecast; one extra day
+			// covers locations that are already a day ahead of the server
+			days = max(days, int(date.Sub(today).Hours()/24)+2)
+		}
+
+		var opts []weather.ForecastOption
+		if params.IncludeAlerts {
+			opts = append(opts, weather.WithAlerts())
+		}
+		if params.IncludeAirQuality {
+			opts = append(opts, weather.WithAirQuality())
+		}
+
+		forecast, err := t.client.GetForecast(ctx, location, min(days, maxForecastDays), opts...)
 		if err != nil {
 			slog.ErrorContext(ctx, "Failed to fetch weather forecast",
 				"error", err,
-				"location", params.Location,
-				"days", params.ForecastDays,
+				"location", location,
+				"days", days,
 			)
 			return "", fmt.Errorf("failed to fetch weather forecast: %w", err)
 		}
-		return weather.FormatForecast(forecast), nil
+		if place != nil {
+			relabeled := *forecast
+			relabeled.Location = relabel(forecast.Location, place)
+			forecast = &relabeled
+		}
+
+		sections := []string{weather.FormatForecast(forecast, u)}
+		if params.Date != "" {
+			day, err := weather.FormatForecastDay(forecast, params.Date, u)
+			if err != nil {
+				return "", err
+			}
+			sections[0] = day
+		}
+		if params.HourlyDate != "" {
+			hourly, err := weather.FormatHourly(forecast, params.HourlyDate, u)
+			if err != nil {
+				return "", err
+			}
+			sections = append(sections, hourly)
+		}
+		if params.IncludeAstronomy {
+			sections = append(sections, weather.FormatAstronomy(forecast))
+		}
+		if params.IncludeAirQuality {
+			sections = append(sections, weather.FormatAirQuality(forecast))
+		}
+		if params.IncludeAlerts {
+			sections = append(sections, weather.FormatAlerts(forecast))
+		}
+
+		return strings.Join(sections, "\n\n"), nil
 	}
 
 	// Get current weather
-	w, err := t.client.GetCurrentWeather(ctx, params.Location)
+	w, err := t.client.GetCurrentWeather(ctx, location)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to fetch current weather",
 			"error", err,
-			"location", params.Location,
+			"location", location,
 		)
 		return "", fmt.Errorf("failed to fetch weather: %w", err)
 	}
+	if place != nil {
+		relabeled := *w
+		relabeled.Location = relabel(w.Location, place)
+		w = &relabeled
+	}
+
+	return weather.FormatCurrentWeather(w, u), nil
+}
+
+// history answers questions about a past day
+func (t *WeatherTool) history(ctx context.Context, location string, place *geo.Place, date time.Time, u units.System) (string, error) {
+	h, err := t.client.GetHistory(ctx, location, date)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch weather history",
+			"error", err,
+			"location", location,
+			"date", date.Format(time.DateOnly),
+		)
+		return "", fmt.Errorf("failed to fetch weather history: %w", err)
+	}
+	if place != nil {
+		relabeled := *h
+		relabeled.Location = relabel(h.Location, place)
+		h = &relabeled
+	}
+
+	return weather.FormatHistory(h, u), nil
+}
+
+// climateNormals answers questions about a day beyond the forecast range
+func (t *WeatherTool) climateNormals(ctx context.Context, location string, place *geo.Place, date time.Time, u units.System) (string, error) {
+	n, err := t.client.GetClimateNormals(ctx, location, date)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch climate normals",
+			"error", err,
+			"location", location,
+			"date", date.Format(time.DateOnly),
+		)
+		return "", fmt.Errorf("failed to fetch typical weather: %w", err)
+	}
+	if place != nil {
+		relabeled := *n
+		relabeled.Location = relabel(n.Location, place)
+		n = &relabeled
+	}
+
+	return weather.FormatClimateNormals(n, u), nil
+}
+
+// locate resolves a location the gazetteer knows without ambiguity to its coordinates,
+// so providers don't have to guess between namesakes. Anything else, including
+// coordinates and ambiguous names, is passed through unchanged.
+func (t *WeatherTool) locate(location string) (string, *geo.Place) {
+	if t.places == nil {
+		return location, nil
+	}
+
+	matches := t.places.Lookup(location)
+	if len(matches) != 1 {
+		return location, nil
+	}
+	return matches[0].Coordinates(), &matches[0]
+}
 
-	return weather.FormatCurrentWeather(w), nil
+// relabel returns the location of a report named after the gazetteer place it
+// was requested for; providers would otherwise name it after the coordinates
+// or a nearby suburb. Callers relabel a copy of the report, as the weather
+// cache shares reports between requests.
+func relabel(loc weather.Location, place *geo.Place) weather.Location {
+	loc.Name = place.Name
+	loc.Region = place.Region
+	loc.Country = place.Country
+	return loc
 }
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/geo"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/weather"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+)
+
+// stubWeather records the request and returns fixed answers;
+// forecasts start today
+type stubWeather struct {
+	days     int
+	opts     weather.ForecastOptions
+	lookup   string
+	location string
+}
+
+func (s *stubWeather) Name() string { return "stub" }
+
+func (s *stubWeather) GetCurrentWeather(ctx context.Context, location string) (*weather.CurrentWeatherResponse, error) {
+	s.location = location
+	w := &weather.CurrentWeatherResponse{}
+	w.Location.Name = location
+	w.Current.Condition.Text = "Sunny"
+	w.Current.TempC = 30
+	return w, nil
+}
+
+func (s *stubWeather) GetForecast(ctx context.Context, location string, days int, opts ...weather.ForecastOption) (*weather.ForecastWeatherResponse, error) {
+	s.days = days
+	s.location = location
+	s.opts = weather.ForecastOptions{}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+
+	f := &weather.ForecastWeatherResponse{}
+	f.Location.Name = location
+	for i := range days {
+		date := time.Now().AddDate(0, 0, i).Format(time.DateOnly)
+		f.Forecast.ForecastDay = append(f.Forecast.ForecastDay, weather.ForecastDay{
+			Date:  date,
+			Day:   weather.Day{Condition: weather.Condition{Text: "Showers"}},
+			Astro: weather.Astro{Sunrise: "07:07 AM", Sunset: "07:35 PM"},
+			Hour:  []weather.Hour{{Time: date + " 15:00", Condition: weather.Condition{Text: "Rain"}, ChanceOfRain: 90}},
+		})
+	}
+	return f, nil
+}
+
+func (s *stubWeather) GetHistory(ctx context.Context, location string, date time.Time) (*weather.HistoryWeatherResponse, error) {
+	s.lookup = "history"
+	s.location = location
+	h := &weather.HistoryWeatherResponse{}
+	h.Location.Name = location
+	h.Forecast.ForecastDay = []weather.ForecastDay{{Date: date.Format(time.DateOnly)}}
+	return h, nil
+}
+
+func (s *stubWeather) GetClimateNormals(ctx context.Context, location string, date time.Time) (*weather.ClimateNormals, error) {
+	s.lookup = "normals"
+	s.location = location
+	return &weather.ClimateNormals{Location: weather.Location{Name: location}, Date: date.Format("01-02")}, nil
+}
+
+func TestWeatherTool_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		args        string
+		wantDays    int
+		wantLookup  string
+		wantQuery   string
+		wantOpts    weather.ForecastOptions
+		wantContain []string
+		wantErr     bool
+	}{
+		{
+			name:        "current weather",
+			args:        `{"location": "Miami"}`,
+			wantContain: []string{"Current weather in Miami", "Sunny"},
+		},
+		{
+			name:        "current weather in imperial units",
+			ctx:         units.WithContext(ctx, units.Imperial),
+			args:        `{"location": "Miami"}`,
+			wantContain: []string{"Temperature: 86.0°F"},
+		},
+		{
+			name:        "forecast",
+			args:        `{"location": "Miami", "forecast_days": 3}`,
+			wantDays:    3,
+			wantContain: []string{"Weather forecast for Miami"},
+		},
+		{
+			name:        "alerts and air quality switch to the forecast endpoint",
+			args:        `{"location": "Miami", "include_alerts": true, "include_air_quality": true}`,
+			wantDays:    1,
+			wantOpts:    weather.ForecastOptions{Alerts: true, AirQuality: true},
+			wantContain: []string{"Weather alerts: none active", "Air quality: no data"},
+		},
+		{
+			name:        "astronomy",
+			args:        `{"location": "Miami", "include_astronomy": true}`,
+			wantDays:    1,
+			wantContain: []string{"sunset 07:35 PM"},
+		},
+		{
+			name:    "hourly breakdown for a day outside the forecast",
+			args:    `{"location": "Miami", "hourly_date": "2025-09-11"}`,
+			wantErr: true,
+		},
+		{
+			name:        "invalid hourly date",
+			args:        `{"location": "Miami", "hourly_date": "tomorrow"}`,
+			wantErr:     true,
+			wantContain: []string{"invalid hourly_date 'tomorrow'"},
+		},
+		{
+			name:        "past date returns observed weather",
+			args:        `{"location": "Rome", "date": "2024-05-14"}`,
+			wantLookup:  "history",
+			wantContain: []string{"Observed weather in Rome", "2024-05-14"},
+		},
+		{
+			name:        "far future date returns climate normals",
+			args:        `{"location": "Lisbon", "date": "` + inDays(90) + `"}`,
+			wantLookup:  "normals",
+			wantContain: []string{"Typical weather in Lisbon", "not a forecast"},
+		},
+		{
+			name:        "date within the forecast range returns that day's forecast",
+			args:        `{"location": "Miami", "date": "` + inDays(2) + `"}`,
+			wantDays:    4,
+			wantContain: []string{"Weather forecast for Miami", "on " + inDays(2), "Showers"},
+		},
+		{
+			name:        "invalid date",
+			args:        `{"location": "Miami", "date": "next week"}`,
+			wantErr:     true,
+			wantContain: []string{"invalid date 'next week'"},
+		},
+		{
+			name:        "known places are sent as coordinates and keep their name",
+			args:        `{"location": "Bali"}`,
+			wantQuery:   "-8.6705,115.2126",
+			wantContain: []string{"Current weather in Denpasar, Indonesia"},
+		},
+		{
+			name:        "qualified names pick the right namesake",
+			args:        `{"location": "San Jose, Costa Rica", "date": "2024-05-14"}`,
+			wantLookup:  "history",
+			wantQuery:   "9.9281,-84.0907",
+			wantContain: []string{"Observed weather in San José, Costa Rica"},
+		},
+		{
+			name:        "ambiguous names are passed through",
+			args:        `{"location": "San Jose"}`,
+			wantQuery:   "San Jose",
+			wantContain: []string{"Current weather in San Jose"},
+		},
+		{
+			name:        "unknown places are passed through",
+			args:        `{"location": "Sitges"}`,
+			wantQuery:   "Sitges",
+			wantContain: []string{"Current weather in Sitges"},
+		},
+		{
+			name:    "invalid JSON",
+			args:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubWeather{}
+			callCtx := ctx
+			if tt.ctx != nil {
+				callCtx = tt.ctx
+			}
+			result, err := NewWeatherTool(stub, geo.Default()).Handle(callCtx, tt.args)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				for _, want := range tt.wantContain {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("expected error to contain '%s', got: %v", want, err)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if stub.lookup != tt.wantLookup {
+				t.Errorf("expected %q lookup, got %q", tt.wantLookup, stub.lookup)
+			}
+			if tt.wantQuery != "" && stub.location != tt.wantQuery {
+				t.Errorf("expected provider query %q, got %q", tt.wantQuery, stub.location)
+			}
+			if stub.days != tt.wantDays {
+				t.Errorf("expected forecast for %d days, got %d", tt.wantDays, stub.days)
+			}
+			if stub.opts != tt.wantOpts {
+				t.Errorf("expected options %+v, got %+v", tt.wantOpts, stub.opts)
+			}
+
+			for _, want := range tt.wantContain {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected result to contain '%s', got: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+// inDays returns the date n days from today as YYYY-MM-DD
+func inDays(n int) string {
+	return time.Now().AddDate(0, 0, n).Format(time.DateOnly)
+}
+
+func TestWeatherTool_HandleSharesCachedReports(t *testing.T) {
+	ctx := context.Background()
+	cache := weather.NewCache(&stubWeather{})
+	tool := NewWeatherTool(cache, geo.Default())
+
+	// The first lookup fills the cache, the next ones share its reports
+	args := []string{`{"location": "Barcelona"}`, `{"location": "Barcelona", "forecast_days": 2}`}
+	for _, a := range args {
+		if _, err := tool.Handle(ctx, a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	done := make(chan string, 2*len(args))
+	for range 2 {
+		for _, a := range args {
+			go func() {
+				result, err := tool.Handle(ctx, a)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				done <- result
+			}()
+		}
+	}
+	for range 2 * len(args) {
+		if result := <-done; !strings.Contains(result, "Barcelona") {
+			t.Errorf("expected the report named after the place, got %q", result)
+		}
+	}
+
+	w, _ := cache.GetCurrentWeather(ctx, geo.Default().Lookup("Barcelona")[0].Coordinates())
+	if w.Location.Name == "Barcelona" {
+		t.Error("expected the cached report to keep the provider's name")
+	}
+}
@@ -0,0 +1,156 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default cache lifetimes: current conditions change quickly, forecasts are refreshed
+// by the upstream APIs only a few times a day, and the past doesn't change.
+const (
+	DefaultCurrentTTL  = 10 * time.Minute
+	DefaultForecastTTL = 3 * time.Hour
+	DefaultHistoryTTL  = 24 * time.Hour
+)
+
+// maxCacheEntries bounds memory use; expired entries are pruned when the limit is hit
+const maxCacheEntries = 1024
+
+// Cache is a Provider that keeps answers of the wrapped provider for a while,
+// so repeated questions in a conversation don't hit the upstream API again
+type Cache struct {
+	next        Provider
+	currentTTL  time.Duration
+	forecastTTL time.Duration
+	historyTTL  time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// CacheOption configures a Cache
+type CacheOption func(*Cache)
+
+// WithCacheTTL overrides the lifetimes of current conditions and forecasts
+func WithCacheTTL(current, forecast time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.currentTTL = current
+		c.forecastTTL = forecast
+	}
+}
+
+// NewCache wraps a provider with a TTL cache keyed by normalised location
+func NewCache(next Provider, opts ...CacheOption) *Cache {
+	c := &Cache{
+		next:        next,
+		currentTTL:  DefaultCurrentTTL,
+		forecastTTL: DefaultForecastTTL,
+		historyTTL:  DefaultHistoryTTL,
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name returns the name of the wrapped provider
+func (c *Cache) Name() string {
+	return c.next.Name()
+}
+
+// GetCurrentWeather returns cached current weather or fetches it from the wrapped provider
+func (c *Cache) GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error) {
+	return cached(c, "current|"+normalizeLocation(location), c.currentTTL, func() (*CurrentWeatherResponse, error) {
+		return c.next.GetCurrentWeather(ctx, location)
+	})
+}
+
+// GetForecast returns a cached forecast or fetches it from the wrapped provider
+func (c *Cache) GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error) {
+	o := forecastOptions(opts)
+	key := fmt.Sprintf("forecast|%d|%t|%t|%s", days, o.Alerts, o.AirQuality, normalizeLocation(location))
+	return cached(c, key, c.forecastTTL, func() (*ForecastWeatherResponse, error) {
+		return c.next.GetForecast(ctx, location, days, opts...)
+	})
+}
+
+// GetHistory returns cached past weather or fetches it from the wrapped provider
+func (c *Cache) GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error) {
+	key := "history|" + date.Format(time.DateOnly) + "|" + normalizeLocation(location)
+	return cached(c, key, c.historyTTL, func() (*HistoryWeatherResponse, error) {
+		return c.next.GetHistory(ctx, location, date)
+	})
+}
+
+// GetClimateNormals returns cached climate normals or fetches them from the wrapped provider.
+// Normals only depend on the calendar day, so the year is not part of the key.
+func (c *Cache) GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error) {
+	key := "normals|" + date.Format("01-02") + "|" + normalizeLocation(location)
+	return cached(c, key, c.historyTTL, func() (*ClimateNormals, error) {
+		return c.next.GetClimateNormals(ctx, location, date)
+	})
+}
+
+// cached looks up key and calls fetch on a miss. Errors are never cached.
+func cached[T any](c *Cache, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return entry.value.(T), nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCacheEntries {
+		c.prune()
+	}
+	c.entries[key] = cacheEntry{value: value, expires: c.now().Add(ttl)}
+
+	return value, nil
+}
+
+// prune drops expired entries, or everything if none have expired yet.
+// Callers must hold c.mu.
+func (c *Cache) prune() {
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	if len(c.entries) >= maxCacheEntries {
+		clear(c.entries)
+	}
+}
+
+// normalizeLocation makes equivalent spellings of a location share a cache entry,
+// e.g. "Paris, France" and " paris,france "
+func normalizeLocation(location string) string {
+	parts := strings.Split(strings.ToLower(location), ",")
+	for i, p := range parts {
+		parts[i] = strings.Join(strings.Fields(p), " ")
+	}
+	return strings.Join(parts, ",")
+}
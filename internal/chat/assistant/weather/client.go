@@ -1,4 +1,5 @@
-// Package weather provides weather API client functionality using WeatherAPI.com
+// Package weather provides weather data from pluggable providers
+// (WeatherAPI.com and Open-Meteo), with fallback and caching.
 package weather
 
 import (
@@ -37,6 +38,11 @@ func NewClientWithKey(apiKey string) *Client {
 	}
 }
 
+// Name identifies the provider in logs and configuration
+func (c *Client) Name() string {
+	return ProviderWeatherAPI
+}
+
 // GetCurrentWeather retrieves current weather for a location
 func (c *Client) GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error) {
 	if c.apiKey == "" {
@@ -80,7 +86,7 @@ func (c *Client) GetCurrentWeather(ctx context.Context, location string) (*Curre
 
 // GetForecast retrieves weather forecast for a location
 // days parameter specifies number of days (1-10)
-func (c *Client) GetForecast(ctx context.Context, location string, days int) (*ForecastWeatherResponse, error) {
+func (c *Client) GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error) {
 	if c.apiKey == "" {
 		return nil, fmt.Errorf("WEATHER_API_KEY environment variable not set")
 	}
@@ -93,12 +99,16 @@ func (c *Client) GetForecast(ctx context.Context, location string, days int) (*F
 		days = 10
 	}
 
+	o := forecastOptions(opts)
+
 	// Build request URL
-	reqURL := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=%d&aqi=no&alerts=no",
+	reqURL := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=%d&aqi=%s&alerts=%s",
 		c.baseURL,
 		url.QueryEscape(c.apiKey),
 		url.QueryEscape(location),
 		days,
+		yesNo(o.AirQuality),
+		yesNo(o.Alerts),
 	)
 
 	// Create request with context
@@ -128,3 +138,60 @@ func (c *Client) GetForecast(ctx context.Context, location string, days int) (*F
 
 	return &forecastResp, nil
 }
+
+// GetHistory retrieves observed weather for a past day.
+// The free WeatherAPI plan only covers the last 7 days, older dates fail
+// and are left to the next provider.
+func (c *Client) GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("WEATHER_API_KEY environment variable not set")
+	}
+
+	// Build request URL
+	reqURL := fmt.Sprintf("%s/history.json?key=%s&q=%s&dt=%s",
+		c.baseURL,
+		url.QueryEscape(c.apiKey),
+		url.QueryEscape(location),
+		date.Format(time.DateOnly),
+	)
+
+	// Create request with context
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Execute request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("weather API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var historyResp HistoryWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&historyResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &historyResp, nil
+}
+
+// GetClimateNormals is not offered by WeatherAPI
+func (c *Client) GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error) {
+	return nil, fmt.Errorf("climate normals: %w", ErrUnsupported)
+}
+
+// yesNo renders a flag the way WeatherAPI query parameters expect
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
@@ -2,9 +2,16 @@ package weather
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
 )
 
 func TestWeatherClient_GetCurrentWeather(t *testing.T) {
@@ -162,7 +169,7 @@ func TestFormatCurrentWeather(t *testing.T) {
 	weather.Current.VisKm = 10.0
 	weather.Current.UV = 4.0
 
-	formatted := FormatCurrentWeather(weather)
+	formatted := FormatCurrentWeather(weather, units.Both)
 
 	// Check that all key information is included
 	requiredFields := []string{
@@ -193,20 +200,7 @@ func TestFormatForecast(t *testing.T) {
 	forecast.Current.Humidity = 60
 
 	// Add 2 forecast days
-	forecast.Forecast.ForecastDay = make([]struct {
-		Date string `json:"date"`
-		Day  struct {
-			MaxTempC  float64 `json:"maxtemp_c"`
-			MinTempC  float64 `json:"mintemp_c"`
-			AvgTempC  float64 `json:"avgtemp_c"`
-			Condition struct {
-				Text string `json:"text"`
-			} `json:"condition"`
-			MaxWindKph   float64 `json:"maxwind_kph"`
-			AvgHumidity  float64 `json:"avghumidity"`
-			ChanceOfRain int     `json:"daily_chance_of_rain"`
-		} `json:"day"`
-	}, 2)
+	forecast.Forecast.ForecastDay = make([]ForecastDay, 2)
 
 	forecast.Forecast.ForecastDay[0].Date = "2025-12-08"
 	forecast.Forecast.ForecastDay[0].Day.MaxTempC = 22.0
@@ -226,7 +220,7 @@ func TestFormatForecast(t *testing.T) {
 	forecast.Forecast.ForecastDay[1].Day.AvgHumidity = 60.0
 	forecast.Forecast.ForecastDay[1].Day.ChanceOfRain = 30
 
-	formatted := FormatForecast(forecast)
+	formatted := FormatForecast(forecast, units.Both)
 
 	// Check structure
 	requiredFields := []string{
@@ -279,3 +273,220 @@ func TestWeatherClient_NoAPIKey(t *testing.T) {
 		}
 	})
 }
+
+// weatherAPIForecastJSON is a trimmed forecast.json response with alerts, air quality, astro and hourly data
+const weatherAPIForecastJSON = `{
+	"location": {"name": "Miami", "country": "United States of America", "localtime": "2025-09-10 14:00"},
+	"current": {
+		"temp_c": 31.0, "condition": {"text": "Sunny"}, "wind_kph": 12.0, "humidity": 70,
+		"air_quality": {"co": 210.5, "no2": 8.1, "o3": 95.0, "so2": 1.2, "pm2_5": 12.4, "pm10": 20.1, "us-epa-index": 2, "gb-defra-index": 2}
+	},
+	"forecast": {"forecastday": [{
+		"date": "2025-09-10",
+		"day": {"maxtemp_c": 33.0, "mintemp_c": 26.0, "avgtemp_c": 29.0, "condition": {"text": "Patchy rain nearby"},
+			"maxwind_kph": 20.0, "avghumidity": 75, "daily_chance_of_rain": 85, "totalprecip_mm": 12.7, "totalprecip_in": 0.5},
+		"astro": {"sunrise": "07:07 AM", "sunset": "07:35 PM", "moonrise": "08:15 PM", "moonset": "08:02 AM", "moon_phase": "Waning Gibbous"},
+		"hour": [
+			{"time": "2025-09-10 14:00", "temp_c": 32.0, "condition": {"text": "Sunny"}, "wind_kph": 14.0, "wind_dir": "E", "precip_mm": 0.0, "chance_of_rain": 10},
+			{"time": "2025-09-10 15:00", "temp_c": 30.5, "condition": {"text": "Thundery outbreaks"}, "wind_kph": 22.0, "wind_dir": "SE", "precip_mm": 6.3, "chance_of_rain": 90}
+		]
+	}]},
+	"alerts": {"alert": [{
+		"headline": "Flood Watch issued September 10 at 4:00AM EDT",
+		"severity": "Moderate", "areas": "Miami-Dade", "event": "Flood Watch",
+		"effective": "2025-09-10T08:00:00-04:00", "expires": "2025-09-10T20:00:00-04:00",
+		"instruction": "Avoid driving through flooded roads."
+	}]}
+}`
+
+func TestWeatherClient_GetForecastExtras(t *testing.T) {
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{"aqi": r.URL.Query().Get("aqi"), "alerts": r.URL.Query().Get("alerts")}
+		_, _ = w.Write([]byte(weatherAPIForecastJSON))
+	}))
+	defer srv.Close()
+
+	client := NewClientWithKey("test-key")
+	client.baseURL = srv.URL
+	ctx := context.Background()
+
+	t.Run("does not request extras by default", func(t *testing.T) {
+		if _, err := client.GetForecast(ctx, "Miami", 1); err != nil {
+			t.Fatalf("GetForecast failed: %v", err)
+		}
+		if query["aqi"] != "no" || query["alerts"] != "no" {
+			t.Errorf("unexpected query flags: %v", query)
+		}
+	})
+
+	t.Run("requests and parses alerts, air quality, astro and hours", func(t *testing.T) {
+		f, err := client.GetForecast(ctx, "Miami", 1, WithAlerts(), WithAirQuality())
+		if err != nil {
+			t.Fatalf("GetForecast failed: %v", err)
+		}
+		if query["aqi"] != "yes" || query["alerts"] != "yes" {
+			t.Errorf("unexpected query flags: %v", query)
+		}
+
+		if len(f.Alerts.Alert) != 1 || f.Alerts.Alert[0].Event != "Flood Watch" {
+			t.Errorf("unexpected alerts: %+v", f.Alerts)
+		}
+		if f.Current.AirQuality == nil || f.Current.AirQuality.USEPAIndex != 2 {
+			t.Errorf("unexpected air quality: %+v", f.Current.AirQuality)
+		}
+
+		day := f.Forecast.ForecastDay[0]
+		if day.Astro.Sunset != "07:35 PM" || len(day.Hour) != 2 || day.Day.TotalPrecipMm != 12.7 {
+			t.Errorf("unexpected forecast day: %+v", day)
+		}
+	})
+}
+
+func TestFormatForecastExtras(t *testing.T) {
+	var f ForecastWeatherResponse
+	if err := json.Unmarshal([]byte(weatherAPIForecastJSON), &f); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		format      func() (string, error)
+		wantContain []string
+	}{
+		{
+			name:        "forecast includes precipitation amount",
+			format:      func() (string, error) { return FormatForecast(&f, units.Both), nil },
+			wantContain: []string{"Total precipitation: 12.7 mm"},
+		},
+		{
+			name:        "hourly breakdown",
+			format:      func() (string, error) { return FormatHourly(&f, "2025-09-10", units.Both) },
+			wantContain: []string{"Hourly forecast for Miami", "15:00: Thundery outbreaks", "rain 90%, 6.3 mm"},
+		},
+		{
+			name:        "astronomy",
+			format:      func() (string, error) { return FormatAstronomy(&f), nil },
+			wantContain: []string{"sunrise 07:07 AM", "sunset 07:35 PM", "Waning Gibbous"},
+		},
+		{
+			name:        "air quality",
+			format:      func() (string, error) { return FormatAirQuality(&f), nil },
+			wantContain: []string{"Moderate (US EPA index 2)", "PM2.5: 12.4"},
+		},
+		{
+			name:        "alerts",
+			format:      func() (string, error) { return FormatAlerts(&f), nil },
+			wantContain: []string{"Flood Watch issued", "[Moderate]", "Areas: Miami-Dade", "Avoid driving"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.format()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out)
+				}
+			}
+		})
+	}
+
+	t.Run("hourly for a day outside the forecast", func(t *testing.T) {
+		if _, err := FormatHourly(&f, "2025-09-20", units.Both); err == nil {
+			t.Error("expected error for date outside forecast range")
+		}
+	})
+
+	t.Run("no alerts versus unavailable alerts", func(t *testing.T) {
+		var none ForecastWeatherResponse
+		none.Location.Name = "Miami"
+		if out := FormatAlerts(&none); !strings.Contains(out, "none active") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		none.Unavailable = []string{"alerts", "air_quality"}
+		if out := FormatAlerts(&none); !strings.Contains(out, "not available") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if out := FormatAirQuality(&none); !strings.Contains(out, "not available") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+}
+
+func TestWeatherClient_GetHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/history.json" || r.URL.Query().Get("dt") != "2025-12-02" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{
+			"location": {"name": "Rome", "country": "Italy"},
+			"forecast": {"forecastday": [{
+				"date": "2025-12-02",
+				"day": {"maxtemp_c": 14.2, "mintemp_c": 6.1, "avgtemp_c": 10.0, "condition": {"text": "Light rain"},
+					"maxwind_kph": 18.0, "avghumidity": 81, "totalprecip_mm": 3.4}
+			}]}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewClientWithKey("test-key")
+	client.baseURL = srv.URL
+	ctx := context.Background()
+
+	h, err := client.GetHistory(ctx, "Rome", time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+
+	out := FormatHistory(h, units.Metric)
+	for _, want := range []string{"Observed weather in Rome, Italy on 2025-12-02", "Light rain", "6.1°C to 14.2°C", "Total precipitation: 3.4 mm"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	t.Run("has no climate normals", func(t *testing.T) {
+		_, err := client.GetClimateNormals(ctx, "Rome", time.Now())
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
+
+func TestFormatUnits(t *testing.T) {
+	var f ForecastWeatherResponse
+	if err := json.Unmarshal([]byte(weatherAPIForecastJSON), &f); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	tests := []struct {
+		system  units.System
+		want    []string
+		notWant []string
+	}{
+		{system: units.Metric, want: []string{"31.0°C", "12.0 km/h", "12.7 mm"}, notWant: []string{"°F", "mph", " in"}},
+		{system: units.Imperial, want: []string{"87.8°F", "7.5 mph", "0.50 in"}, notWant: []string{"°C", "km/h", " mm"}},
+		{system: units.Both, want: []string{"31.0°C (87.8°F)", "12.0 km/h (7.5 mph)", "12.7 mm (0.50 in)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.system), func(t *testing.T) {
+			out := FormatForecast(&f, tt.system)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Errorf("expected output not to contain %q, got:\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
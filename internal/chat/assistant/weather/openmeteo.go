@@ -0,0 +1,534 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenMeteoClient handles communication with Open-Meteo, which needs no API key
+type OpenMeteoClient struct {
+	httpClient   *http.Client
+	forecastURL  string
+	archiveURL   string
+	geocodingURL string
+	now          func() time.Time
+}
+
+// NewOpenMeteoClient creates a new Open-Meteo client
+func NewOpenMeteoClient() *OpenMeteoClient {
+	return &OpenMeteoClient{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		forecastURL:  "https://api.open-meteo.com/v1/forecast",
+		archiveURL:   "https://archive-api.open-meteo.com/v1/archive",
+		geocodingURL: "https://geocoding-api.open-meteo.com/v1/search",
+		now:          time.Now,
+	}
+}
+
+// Name identifies the provider in logs and configuration
+func (c *OpenMeteoClient) Name() string {
+	return ProviderOpenMeteo
+}
+
+// openMeteoForecast is the subset of the Open-Meteo forecast response we use
+type openMeteoForecast struct {
+	Current struct {
+		Time                string  `json:"time"`
+		Temperature         float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity    float64 `json:"relative_humidity_2m"`
+		WeatherCode         int     `json:"weather_code"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+		Visibility          float64 `json:"visibility"`
+		UVIndex             float64 `json:"uv_index"`
+		Precipitation       float64 `json:"precipitation"`
+	} `json:"current"`
+	Daily struct {
+		Time                     []string  `json:"time"`
+		WeatherCode              []int     `json:"weather_code"`
+		TemperatureMax           []float64 `json:"temperature_2m_max"`
+		TemperatureMin           []float64 `json:"temperature_2m_min"`
+		WindSpeedMax             []float64 `json:"wind_speed_10m_max"`
+		RelativeHumidityMean     []float64 `json:"relative_humidity_2m_mean"`
+		PrecipitationProbability []float64 `json:"precipitation_probability_max"`
+		PrecipitationSum         []float64 `json:"precipitation_sum"`
+		UVIndexMax               []float64 `json:"uv_index_max"`
+		Sunrise                  []string  `json:"sunrise"`
+		Sunset                   []string  `json:"sunset"`
+	} `json:"daily"`
+	Hourly struct {
+		Time                     []string  `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		RelativeHumidity         []float64 `json:"relative_humidity_2m"`
+		WeatherCode              []int     `json:"weather_code"`
+		WindSpeed                []float64 `json:"wind_speed_10m"`
+		WindDirection            []float64 `json:"wind_direction_10m"`
+		Precipitation            []float64 `json:"precipitation"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+	} `json:"hourly"`
+}
+
+const openMeteoCurrentFields = "temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,wind_speed_10m,wind_direction_10m,visibility,uv_index,precipitation"
+
+const openMeteoDailyFields = "weather_code,temperature_2m_max,temperature_2m_min,wind_speed_10m_max,relative_humidity_2m_mean,precipitation_probability_max,precipitation_sum,uv_index_max,sunrise,sunset"
+
+const openMeteoHourlyFields = "temperature_2m,relative_humidity_2m,weather_code,wind_speed_10m,wind_direction_10m,precipitation,precipitation_probability"
+
+// Past days only have observed values, no probabilities or UV
+const (
+	openMeteoHistoryDailyFields  = "weather_code,temperature_2m_max,temperature_2m_min,wind_speed_10m_max,relative_humidity_2m_mean,precipitation_sum,sunrise,sunset"
+	openMeteoHistoryHourlyFields = "temperature_2m,relative_humidity_2m,weather_code,wind_speed_10m,wind_direction_10m,precipitation"
+)
+
+// archiveDelay is how far the archive API lags behind; more recent days
+// are served from the forecast API
+const archiveDelay = 5 * 24 * time.Hour
+
+// Climate normals average the days around the requested calendar day over recent years
+const (
+	climateYears      = 5
+	climateWindowDays = 3
+)
+
+// GetCurrentWeather retrieves current weather for a location
+func (c *OpenMeteoClient) GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error) {
+	place, err := c.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := place.query()
+	query.Set("current", openMeteoCurrentFields)
+
+	var data openMeteoForecast
+	if err := c.get(ctx, c.forecastURL, query, &data); err != nil {
+		return nil, err
+	}
+
+	return &CurrentWeatherResponse{
+		Location: place.location(data.Current.Time),
+		Current:  data.current(),
+	}, nil
+}
+
+// GetForecast retrieves weather forecast for a location
+// days parameter specifies number of days (1-10).
+// Open-Meteo has no alerts or air quality in its forecast API, requesting them
+// marks them as unavailable in the response.
+func (c *OpenMeteoClient) GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error) {
+	// Keep the same range as WeatherAPI so providers are interchangeable
+	days = max(1, min(days, 10))
+
+	place, err := c.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := place.query()
+	query.Set("current", openMeteoCurrentFields)
+	query.Set("daily", openMeteoDailyFields)
+	query.Set("hourly", openMeteoHourlyFields)
+	query.Set("forecast_days", strconv.Itoa(days))
+
+	var data openMeteoForecast
+	if err := c.get(ctx, c.forecastURL, query, &data); err != nil {
+		return nil, err
+	}
+
+	resp := &ForecastWeatherResponse{
+		Location: place.location(data.Current.Time),
+		Current:  data.current(),
+	}
+
+	o := forecastOptions(opts)
+	if o.Alerts {
+		resp.Unavailable = append(resp.Unavailable, "alerts")
+	}
+	if o.AirQuality {
+		resp.Unavailable = append(resp.Unavailable, "air_quality")
+	}
+
+	resp.Forecast.ForecastDay = data.days()
+
+	return resp, nil
+}
+
+// GetHistory retrieves observed weather for a past day, from the archive API
+// or, for the last few days, from the forecast API
+func (c *OpenMeteoClient) GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error) {
+	if !date.Before(c.now()) {
+		return nil, fmt.Errorf("date %s is not in the past", date.Format(time.DateOnly))
+	}
+
+	place, err := c.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := c.archiveURL
+	if c.now().Sub(date) < archiveDelay {
+		endpoint = c.forecastURL
+	}
+
+	day := date.Format(time.DateOnly)
+	query := place.query()
+	query.Set("daily", openMeteoHistoryDailyFields)
+	query.Set("hourly", openMeteoHistoryHourlyFields)
+	query.Set("start_date", day)
+	query.Set("end_date", day)
+
+	var data openMeteoForecast
+	if err := c.get(ctx, endpoint, query, &data); err != nil {
+		return nil, err
+	}
+
+	resp := &HistoryWeatherResponse{Location: place.location("")}
+	resp.Forecast.ForecastDay = data.days()
+
+	return resp, nil
+}
+
+// GetClimateNormals averages the archived weather of the days around date's
+// calendar day over the last climateYears complete years
+func (c *OpenMeteoClient) GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error) {
+	place, err := c.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	toYear := c.now().Year() - 1
+	fromYear := toYear - climateYears + 1
+	centre := func(year int) time.Time {
+		return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	// One request for the whole span, the days outside the windows are skipped below
+	query := place.query()
+	query.Set("daily", "temperature_2m_max,temperature_2m_min,precipitation_sum")
+	query.Set("start_date", centre(fromYear).AddDate(0, 0, -climateWindowDays).Format(time.DateOnly))
+	query.Set("end_date", centre(toYear).AddDate(0, 0, climateWindowDays).Format(time.DateOnly))
+
+	var data openMeteoForecast
+	if err := c.get(ctx, c.archiveURL, query, &data); err != nil {
+		return nil, err
+	}
+
+	var (
+		n                      int
+		maxSum, minSum, precip float64
+		wetDays                int
+	)
+	window := time.Duration(climateWindowDays) * 24 * time.Hour
+	d := data.Daily
+	for i, ts := range d.Time {
+		day, err := time.Parse(time.DateOnly, ts)
+		if err != nil {
+			continue
+		}
+
+		// The window may cross a year boundary, so compare with the centres on either side
+		inWindow := false
+		for _, year := range []int{day.Year() - 1, day.Year(), day.Year() + 1} {
+			if diff := day.Sub(centre(year)); diff >= -window && diff <= window {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			continue
+		}
+
+		n++
+		maxSum += at(d.TemperatureMax, i)
+		minSum += at(d.TemperatureMin, i)
+		p := at(d.PrecipitationSum, i)
+		precip += p
+		if p >= 1 {
+			wetDays++
+		}
+	}
+
+	if n == 0 {
+		return nil, fmt.Errorf("no climate data available for %s", location)
+	}
+
+	return &ClimateNormals{
+		Location:    place.location(""),
+		Date:        date.Format("01-02"),
+		WindowDays:  climateWindowDays,
+		FromYear:    fromYear,
+		ToYear:      toYear,
+		AvgMaxTempC: round1(maxSum / float64(n)),
+		AvgMinTempC: round1(minSum / float64(n)),
+		AvgPrecipMm: round1(precip / float64(n)),
+		WetDaysPct:  int(math.Round(float64(wetDays) * 100 / float64(n))),
+	}, nil
+}
+
+// days maps the daily series, with the hours of each day attached
+func (d *openMeteoForecast) days() []ForecastDay {
+	hours := d.hours()
+
+	var days []ForecastDay
+	daily := d.Daily
+	for i, date := range daily.Time {
+		maxTemp, minTemp := at(daily.TemperatureMax, i), at(daily.TemperatureMin, i)
+		precip := at(daily.PrecipitationSum, i)
+		days = append(days, ForecastDay{
+			Date: date,
+			Day: Day{
+				MaxTempC:      maxTemp,
+				MinTempC:      minTemp,
+				AvgTempC:      round1((maxTemp + minTemp) / 2),
+				Condition:     Condition{Text: weatherCodeText(at(daily.WeatherCode, i))},
+				MaxWindKph:    at(daily.WindSpeedMax, i),
+				AvgHumidity:   at(daily.RelativeHumidityMean, i),
+				ChanceOfRain:  int(at(daily.PrecipitationProbability, i)),
+				TotalPrecipMm: precip,
+				TotalPrecipIn: round2(precip / 25.4),
+				UV:            at(daily.UVIndexMax, i),
+			},
+			Astro: Astro{
+				Sunrise: clockTime(at(daily.Sunrise, i)),
+				Sunset:  clockTime(at(daily.Sunset, i)),
+			},
+			Hour: hours[date],
+		})
+	}
+
+	return days
+}
+
+// hours groups the hourly series by local date
+func (d *openMeteoForecast) hours() map[string][]Hour {
+	h := d.Hourly
+	byDate := make(map[string][]Hour)
+	for i, ts := range h.Time {
+		date, _, _ := strings.Cut(ts, "T")
+		temp, wind, precip := at(h.Temperature, i), at(h.WindSpeed, i), at(h.Precipitation, i)
+		byDate[date] = append(byDate[date], Hour{
+			Time:         strings.Replace(ts, "T", " ", 1),
+			TempC:        temp,
+			TempF:        celsiusToFahrenheit(temp),
+			Condition:    Condition{Text: weatherCodeText(at(h.WeatherCode, i))},
+			WindKph:      wind,
+			WindMph:      round1(wind / 1.609344),
+			WindDir:      compassDirection(at(h.WindDirection, i)),
+			Humidity:     int(at(h.RelativeHumidity, i)),
+			PrecipMm:     precip,
+			PrecipIn:     round2(precip / 25.4),
+			ChanceOfRain: int(at(h.PrecipitationProbability, i)),
+		})
+	}
+	return byDate
+}
+
+// clockTime converts an ISO local timestamp ("2025-12-07T08:05") to WeatherAPI's "08:05 AM"
+func clockTime(ts string) string {
+	t, err := time.Parse("2006-01-02T15:04", ts)
+	if err != nil {
+		return ts
+	}
+	return t.Format("03:04 PM")
+}
+
+// place is a geocoded location
+type place struct {
+	Name      string  `json:"name"`
+	Region    string  `json:"admin1"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (p *place) query() url.Values {
+	q := url.Values{}
+	q.Set("latitude", strconv.FormatFloat(p.Latitude, 'f', 4, 64))
+	q.Set("longitude", strconv.FormatFloat(p.Longitude, 'f', 4, 64))
+	q.Set("timezone", "auto")
+	return q
+}
+
+func (p *place) location(localTime string) Location {
+	return Location{
+		Name:      p.Name,
+		Region:    p.Region,
+		Country:   p.Country,
+		Lat:       p.Latitude,
+		Lon:       p.Longitude,
+		LocalTime: strings.Replace(localTime, "T", " ", 1),
+	}
+}
+
+func (d *openMeteoForecast) current() Current {
+	cur := d.Current
+	return Current{
+		TempC:      cur.Temperature,
+		TempF:      celsiusToFahrenheit(cur.Temperature),
+		Condition:  Condition{Text: weatherCodeText(cur.WeatherCode)},
+		WindKph:    cur.WindSpeed,
+		WindMph:    round1(cur.WindSpeed / 1.609344),
+		WindDir:    compassDirection(cur.WindDirection),
+		Humidity:   int(cur.RelativeHumidity),
+		FeelsLikeC: cur.ApparentTemperature,
+		FeelsLikeF: celsiusToFahrenheit(cur.ApparentTemperature),
+		VisKm:      round1(cur.Visibility / 1000),
+		UV:         cur.UVIndex,
+		PrecipMm:   cur.Precipitation,
+		PrecipIn:   round2(cur.Precipitation / 25.4),
+	}
+}
+
+// geocode resolves a location query to coordinates.
+// "lat,lon" queries are used as-is, anything else goes through the geocoding API.
+func (c *OpenMeteoClient) geocode(ctx context.Context, location string) (*place, error) {
+	if lat, lon, ok := parseCoordinates(location); ok {
+		return &place{Name: strings.TrimSpace(location), Latitude: lat, Longitude: lon}, nil
+	}
+
+	// The geocoding API matches on a name only, so drop qualifiers like ", France"
+	name, _, _ := strings.Cut(location, ",")
+
+	query := url.Values{}
+	query.Set("name", strings.TrimSpace(name))
+	query.Set("count", "1")
+	query.Set("format", "json")
+
+	var data struct {
+		Results []place `json:"results"`
+	}
+	if err := c.get(ctx, c.geocodingURL, query, &data); err != nil {
+		return nil, err
+	}
+
+	if len(data.Results) == 0 {
+		return nil, fmt.Errorf("location not found: %s", location)
+	}
+
+	return &data.Results[0], nil
+}
+
+func (c *OpenMeteoClient) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch weather: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("open-meteo returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// parseCoordinates parses "lat,lon" location queries
+func parseCoordinates(location string) (lat, lon float64, ok bool) {
+	latStr, lonStr, found := strings.Cut(location, ",")
+	if !found {
+		return 0, 0, false
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+
+	return lat, lon, true
+}
+
+// weatherCodes maps WMO weather interpretation codes to text
+var weatherCodes = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snow fall",
+	73: "Moderate snow fall",
+	75: "Heavy snow fall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+func weatherCodeText(code int) string {
+	if text, ok := weatherCodes[code]; ok {
+		return text
+	}
+	return fmt.Sprintf("Unknown (WMO code %d)", code)
+}
+
+// compassDirection converts a wind direction in degrees to a 16-point compass name
+func compassDirection(degrees float64) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	i := int(math.Round(math.Mod(degrees, 360)/22.5)) % len(points)
+	if i < 0 {
+		i += len(points)
+	}
+	return points[i]
+}
+
+func celsiusToFahrenheit(c float64) float64 {
+	return round1(c*9/5 + 32)
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// at returns s[i], or the zero value when the provider omitted the series
+func at[T any](s []T, i int) T {
+	var zero T
+	if i < len(s) {
+		return s[i]
+	}
+	return zero
+}
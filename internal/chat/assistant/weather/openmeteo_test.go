@@ -0,0 +1,323 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+)
+
+// newOpenMeteoStandIn serves canned geocoding, forecast and archive responses.
+// The client's clock is fixed at 2025-12-07.
+func newOpenMeteoStandIn(t *testing.T) (*OpenMeteoClient, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Barcelona" {
+			_, _ = w.Write([]byte(`{"generationtime_ms":0.1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"name":"Barcelona","admin1":"Catalonia","country":"Spain","latitude":41.3888,"longitude":2.159}]}`))
+	})
+	mux.HandleFunc("/v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("latitude") != "41.3888" || q.Get("longitude") != "2.1590" {
+			t.Errorf("unexpected coordinates: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{
+			"current": {
+				"time": "2025-12-07T15:30",
+				"temperature_2m": 18.5,
+				"apparent_temperature": 17.2,
+				"relative_humidity_2m": 65,
+				"weather_code": 2,
+				"wind_speed_10m": 15.8,
+				"wind_direction_10m": 315,
+				"visibility": 10000,
+				"uv_index": 4
+			},
+			"daily": {
+				"time": ["2025-12-07", "2025-12-08"],
+				"weather_code": [0, 61],
+				"temperature_2m_max": [22, 20],
+				"temperature_2m_min": [15, 14],
+				"wind_speed_10m_max": [20, 25],
+				"relative_humidity_2m_mean": [55, 60],
+				"precipitation_probability_max": [10, 80],
+				"precipitation_sum": [0, 7.5],
+				"sunrise": ["2025-12-07T08:05", "2025-12-08T08:06"],
+				"sunset": ["2025-12-07T17:23", "2025-12-08T17:23"]
+			},
+			"hourly": {
+				"time": ["2025-12-08T14:00", "2025-12-08T15:00"],
+				"temperature_2m": [19, 18],
+				"weather_code": [3, 63],
+				"wind_speed_10m": [10, 12],
+				"wind_direction_10m": [90, 180],
+				"precipitation": [0, 2.4],
+				"precipitation_probability": [20, 85]
+			}
+		}`))
+	})
+
+	mux.HandleFunc("/v1/archive", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("start_date") == q.Get("end_date") {
+			_, _ = w.Write([]byte(`{
+				"daily": {
+					"time": ["` + q.Get("start_date") + `"],
+					"weather_code": [65],
+					"temperature_2m_max": [16],
+					"temperature_2m_min": [9],
+					"wind_speed_10m_max": [30],
+					"relative_humidity_2m_mean": [88],
+					"precipitation_sum": [21.3]
+				}
+			}`))
+			return
+		}
+		// A sparse span of past years for climate normals
+		_, _ = w.Write([]byte(`{
+			"daily": {
+				"time": ["2019-12-31", "2020-03-12", "2021-03-18", "2022-06-01", "2024-03-15"],
+				"temperature_2m_max": [5, 16, 18, 30, 20],
+				"temperature_2m_min": [-1, 8, 10, 21, 9],
+				"precipitation_sum": [0, 0, 4, 0, 2]
+			}
+		}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewOpenMeteoClient()
+	client.forecastURL = srv.URL + "/v1/forecast"
+	client.archiveURL = srv.URL + "/v1/archive"
+	client.geocodingURL = srv.URL + "/v1/search"
+	client.now = func() time.Time { return time.Date(2025, 12, 7, 15, 30, 0, 0, time.UTC) }
+
+	return client, srv
+}
+
+func TestOpenMeteoClient_GetCurrentWeather(t *testing.T) {
+	client, _ := newOpenMeteoStandIn(t)
+	ctx := context.Background()
+
+	t.Run("geocodes and maps current conditions", func(t *testing.T) {
+		w, err := client.GetCurrentWeather(ctx, "Barcelona, Spain")
+		if err != nil {
+			t.Fatalf("GetCurrentWeather failed: %v", err)
+		}
+
+		if w.Location.Name != "Barcelona" || w.Location.Country != "Spain" {
+			t.Errorf("unexpected location: %+v", w.Location)
+		}
+		if w.Location.LocalTime != "2025-12-07 15:30" {
+			t.Errorf("unexpected local time: %q", w.Location.LocalTime)
+		}
+		if w.Current.TempC != 18.5 || w.Current.TempF != 65.3 {
+			t.Errorf("unexpected temperature: %.1f°C / %.1f°F", w.Current.TempC, w.Current.TempF)
+		}
+		if w.Current.Condition.Text != "Partly cloudy" {
+			t.Errorf("unexpected condition: %q", w.Current.Condition.Text)
+		}
+		if w.Current.WindDir != "NW" {
+			t.Errorf("unexpected wind direction: %q", w.Current.WindDir)
+		}
+		if w.Current.VisKm != 10 {
+			t.Errorf("unexpected visibility: %.1f", w.Current.VisKm)
+		}
+	})
+
+	t.Run("uses coordinates without geocoding", func(t *testing.T) {
+		w, err := client.GetCurrentWeather(ctx, "41.3888, 2.159")
+		if err != nil {
+			t.Fatalf("GetCurrentWeather failed: %v", err)
+		}
+		if w.Location.Lat != 41.3888 {
+			t.Errorf("unexpected latitude: %f", w.Location.Lat)
+		}
+	})
+
+	t.Run("unknown location", func(t *testing.T) {
+		_, err := client.GetCurrentWeather(ctx, "Atlantis")
+		if err == nil || !strings.Contains(err.Error(), "location not found") {
+			t.Errorf("expected location not found error, got %v", err)
+		}
+	})
+}
+
+func TestOpenMeteoClient_GetForecast(t *testing.T) {
+	client, _ := newOpenMeteoStandIn(t)
+
+	forecast, err := client.GetForecast(context.Background(), "Barcelona", 2)
+	if err != nil {
+		t.Fatalf("GetForecast failed: %v", err)
+	}
+
+	if len(forecast.Forecast.ForecastDay) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(forecast.Forecast.ForecastDay))
+	}
+
+	day := forecast.Forecast.ForecastDay[1]
+	if day.Date != "2025-12-08" || day.Day.Condition.Text != "Slight rain" {
+		t.Errorf("unexpected day: %+v", day)
+	}
+	if day.Day.AvgTempC != 17 || day.Day.ChanceOfRain != 80 {
+		t.Errorf("unexpected day values: %+v", day.Day)
+	}
+
+	if day.Astro.Sunrise != "08:06 AM" || day.Astro.Sunset != "05:23 PM" {
+		t.Errorf("unexpected astro: %+v", day.Astro)
+	}
+	if len(day.Hour) != 2 || day.Hour[1].Time != "2025-12-08 15:00" || day.Hour[1].ChanceOfRain != 85 {
+		t.Errorf("unexpected hours: %+v", day.Hour)
+	}
+
+	// The result renders with the shared formatters
+	if out := FormatForecast(forecast, units.Both); !strings.Contains(out, "Chance of rain: 80%") {
+		t.Errorf("unexpected formatted forecast:\n%s", out)
+	}
+	if out, err := FormatHourly(forecast, "2025-12-08", units.Both); err != nil || !strings.Contains(out, "15:00: Moderate rain") {
+		t.Errorf("unexpected hourly forecast (%v):\n%s", err, out)
+	}
+
+	t.Run("marks alerts and air quality as unavailable", func(t *testing.T) {
+		f, err := client.GetForecast(context.Background(), "Barcelona", 1, WithAlerts(), WithAirQuality())
+		if err != nil {
+			t.Fatalf("GetForecast failed: %v", err)
+		}
+		if len(f.Unavailable) != 2 {
+			t.Errorf("expected alerts and air quality to be unavailable, got %v", f.Unavailable)
+		}
+	})
+}
+
+func TestOpenMeteoClient_GetHistory(t *testing.T) {
+	client, _ := newOpenMeteoStandIn(t)
+	ctx := context.Background()
+
+	t.Run("reads older days from the archive", func(t *testing.T) {
+		h, err := client.GetHistory(ctx, "Barcelona", time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(h.Forecast.ForecastDay) != 1 {
+			t.Fatalf("expected 1 day, got %d", len(h.Forecast.ForecastDay))
+		}
+		day := h.Forecast.ForecastDay[0]
+		if day.Date != "2025-11-20" || day.Day.Condition.Text != "Heavy rain" || day.Day.TotalPrecipMm != 21.3 {
+			t.Errorf("unexpected day: %+v", day)
+		}
+		if out := FormatHistory(h, units.Metric); !strings.Contains(out, "Observed weather in Barcelona, Spain on 2025-11-20") {
+			t.Errorf("unexpected formatted history:\n%s", out)
+		}
+	})
+
+	t.Run("reads recent days from the forecast API", func(t *testing.T) {
+		var paths []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			_, _ = w.Write([]byte(`{"daily":{"time":["2025-12-05"]}}`))
+		}))
+		defer srv.Close()
+
+		recent := NewOpenMeteoClient()
+		recent.forecastURL = srv.URL + "/forecast"
+		recent.archiveURL = srv.URL + "/archive"
+		recent.now = client.now
+
+		if _, err := recent.GetHistory(ctx, "41.39,2.16", time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(paths) != 1 || paths[0] != "/forecast" {
+			t.Errorf("expected a forecast API request, got %v", paths)
+		}
+	})
+
+	t.Run("rejects future dates", func(t *testing.T) {
+		if _, err := client.GetHistory(ctx, "Barcelona", time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)); err == nil {
+			t.Error("expected error for a future date")
+		}
+	})
+}
+
+func TestOpenMeteoClient_GetClimateNormals(t *testing.T) {
+	client, _ := newOpenMeteoStandIn(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		want    ClimateNormals
+		wantOut string
+	}{
+		{
+			name: "averages the window around the day over past years",
+			date: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+			want: ClimateNormals{
+				Date: "03-15", WindowDays: 3, FromYear: 2020, ToYear: 2024,
+				AvgMaxTempC: 18, AvgMinTempC: 9, AvgPrecipMm: 2, WetDaysPct: 67,
+			},
+			wantOut: "around March 15",
+		},
+		{
+			name: "window crosses the year boundary",
+			date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			want: ClimateNormals{
+				Date: "01-02", WindowDays: 3, FromYear: 2020, ToYear: 2024,
+				AvgMaxTempC: 5, AvgMinTempC: -1,
+			},
+			wantOut: "High: 5.0°C, Low: -1.0°C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := client.GetClimateNormals(ctx, "Barcelona", tt.date)
+			if err != nil {
+				t.Fatalf("GetClimateNormals failed: %v", err)
+			}
+
+			got := *n
+			got.Location = Location{}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+
+			if out := FormatClimateNormals(n, units.Metric); !strings.Contains(out, tt.wantOut) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.wantOut, out)
+			}
+		})
+	}
+}
+
+func TestOpenMeteoClient_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":true,"reason":"boom"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	client := NewOpenMeteoClient()
+	client.forecastURL = srv.URL
+	client.geocodingURL = srv.URL
+
+	_, err := client.GetCurrentWeather(context.Background(), "Barcelona")
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestCompassDirection(t *testing.T) {
+	tests := map[float64]string{0: "N", 22.5: "NNE", 90: "E", 180: "S", 270: "W", 315: "NW", 359: "N", 360: "N"}
+	for degrees, want := range tests {
+		if got := compassDirection(degrees); got != want {
+			t.Errorf("compassDirection(%v) = %q, want %q", degrees, got, want)
+		}
+	}
+}
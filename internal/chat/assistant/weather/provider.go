@@ -0,0 +1,187 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider names used in the WEATHER_PROVIDERS configuration
+const (
+	ProviderWeatherAPI = "weatherapi"
+	ProviderOpenMeteo  = "openmeteo"
+)
+
+// defaultProviders is the fallback order used when WEATHER_PROVIDERS is not set
+const defaultProviders = ProviderWeatherAPI + "," + ProviderOpenMeteo
+
+// Provider is a source of weather data
+type Provider interface {
+	// Name identifies the provider in logs and configuration
+	Name() string
+
+	// GetCurrentWeather retrieves current weather for a location
+	GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error)
+
+	// GetForecast retrieves a forecast of 1-10 days for a location,
+	// including hourly and astronomy data for each day
+	GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error)
+
+	// GetHistory retrieves observed weather for a past day
+	GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error)
+
+	// GetClimateNormals retrieves typical weather around the calendar day of date,
+	// averaged over recent years. Providers without climate data return ErrUnsupported.
+	GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error)
+}
+
+// ErrUnsupported is returned by providers for lookups they can't answer;
+// Fallback moves on to the next provider
+var ErrUnsupported = errors.New("not supported by this weather provider")
+
+// ForecastOptions selects optional extras of a forecast
+type ForecastOptions struct {
+	Alerts     bool
+	AirQuality bool
+}
+
+// ForecastOption configures a forecast request
+type ForecastOption func(*ForecastOptions)
+
+// WithAlerts requests active government weather alerts
+func WithAlerts() ForecastOption {
+	return func(o *ForecastOptions) { o.Alerts = true }
+}
+
+// WithAirQuality requests current air quality data
+func WithAirQuality() ForecastOption {
+	return func(o *ForecastOptions) { o.AirQuality = true }
+}
+
+func forecastOptions(opts []ForecastOption) ForecastOptions {
+	var o ForecastOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewProviderFromEnv builds the weather provider chain from the environment.
+// WEATHER_PROVIDERS sets the fallback order as a comma separated list
+// (default "weatherapi,openmeteo"). WeatherAPI is skipped when WEATHER_API_KEY is not set,
+// so the assistant keeps working with the keyless Open-Meteo provider.
+// The chain is wrapped in a cache.
+func NewProviderFromEnv() Provider {
+	order := os.Getenv("WEATHER_PROVIDERS")
+	if order == "" {
+		order = defaultProviders
+	}
+
+	var providers []Provider
+	for _, name := range strings.Split(order, ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case ProviderWeatherAPI:
+			if os.Getenv("WEATHER_API_KEY") == "" {
+				slog.Info("Skipping weather provider without API key", "provider", name)
+				continue
+			}
+			providers = append(providers, NewClient())
+		case ProviderOpenMeteo:
+			providers = append(providers, NewOpenMeteoClient())
+		case "":
+		default:
+			slog.Warn("Ignoring unknown weather provider", "provider", name)
+		}
+	}
+
+	if len(providers) == 0 {
+		slog.Warn("No weather providers configured, falling back to Open-Meteo")
+		providers = append(providers, NewOpenMeteoClient())
+	}
+
+	return NewCache(NewFallback(providers...))
+}
+
+// Fallback tries each provider in order and returns the first successful answer
+type Fallback struct {
+	providers []Provider
+}
+
+// NewFallback creates a provider that falls back through the given providers in order
+func NewFallback(providers ...Provider) *Fallback {
+	return &Fallback{providers: providers}
+}
+
+// Name lists the providers in fallback order
+func (f *Fallback) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// GetCurrentWeather retrieves current weather from the first provider that succeeds
+func (f *Fallback) GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error) {
+	return try(ctx, f.providers, func(p Provider) (*CurrentWeatherResponse, error) {
+		return p.GetCurrentWeather(ctx, location)
+	})
+}
+
+// GetForecast retrieves a forecast from the first provider that succeeds
+func (f *Fallback) GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error) {
+	return try(ctx, f.providers, func(p Provider) (*ForecastWeatherResponse, error) {
+		return p.GetForecast(ctx, location, days, opts...)
+	})
+}
+
+// GetHistory retrieves past weather from the first provider that succeeds
+func (f *Fallback) GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error) {
+	return try(ctx, f.providers, func(p Provider) (*HistoryWeatherResponse, error) {
+		return p.GetHistory(ctx, location, date)
+	})
+}
+
+// GetClimateNormals retrieves climate normals from the first provider that supports them
+func (f *Fallback) GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error) {
+	return try(ctx, f.providers, func(p Provider) (*ClimateNormals, error) {
+		return p.GetClimateNormals(ctx, location, date)
+	})
+}
+
+// try calls fn for each provider until one succeeds, joining the errors otherwise
+func try[T any](ctx context.Context, providers []Provider, fn func(Provider) (T, error)) (T, error) {
+	var (
+		zero T
+		errs []error
+	)
+
+	if len(providers) == 0 {
+		return zero, errors.New("no weather providers configured")
+	}
+
+	for _, p := range providers {
+		result, err := fn(p)
+		if err == nil {
+			return result, nil
+		}
+
+		// Stop early when the caller gave up, the next provider would fail the same way
+		if ctx.Err() != nil {
+			return zero, err
+		}
+
+		if errors.Is(err, ErrUnsupported) {
+			slog.DebugContext(ctx, "Weather provider does not support lookup, trying next", "provider", p.Name())
+		} else {
+			slog.WarnContext(ctx, "Weather provider failed, trying next", "provider", p.Name(), "error", err)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,253 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider counts calls and returns canned answers or an error
+type fakeProvider struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) GetCurrentWeather(ctx context.Context, location string) (*CurrentWeatherResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	w := &CurrentWeatherResponse{}
+	w.Location.Name = location
+	w.Current.Condition.Text = p.name
+	return w, nil
+}
+
+func (p *fakeProvider) GetForecast(ctx context.Context, location string, days int, opts ...ForecastOption) (*ForecastWeatherResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	f := &ForecastWeatherResponse{}
+	f.Location.Name = location
+	f.Forecast.ForecastDay = make([]ForecastDay, days)
+	return f, nil
+}
+
+func (p *fakeProvider) GetHistory(ctx context.Context, location string, date time.Time) (*HistoryWeatherResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	h := &HistoryWeatherResponse{}
+	h.Location.Name = location
+	h.Forecast.ForecastDay = []ForecastDay{{Date: date.Format(time.DateOnly)}}
+	return h, nil
+}
+
+func (p *fakeProvider) GetClimateNormals(ctx context.Context, location string, date time.Time) (*ClimateNormals, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ClimateNormals{Location: Location{Name: location}, Date: date.Format("01-02")}, nil
+}
+
+func TestFallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses first provider that succeeds", func(t *testing.T) {
+		broken := &fakeProvider{name: "broken", err: errors.New("down")}
+		working := &fakeProvider{name: "working"}
+
+		w, err := NewFallback(broken, working).GetCurrentWeather(ctx, "Barcelona")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.Current.Condition.Text != "working" {
+			t.Errorf("expected answer from second provider, got %q", w.Current.Condition.Text)
+		}
+		if broken.calls != 1 || working.calls != 1 {
+			t.Errorf("unexpected calls: broken=%d working=%d", broken.calls, working.calls)
+		}
+	})
+
+	t.Run("does not call later providers on success", func(t *testing.T) {
+		first, second := &fakeProvider{name: "first"}, &fakeProvider{name: "second"}
+
+		if _, err := NewFallback(first, second).GetForecast(ctx, "Barcelona", 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if second.calls != 0 {
+			t.Errorf("expected second provider to be skipped, got %d calls", second.calls)
+		}
+	})
+
+	t.Run("joins errors when all providers fail", func(t *testing.T) {
+		a := &fakeProvider{name: "a", err: errors.New("quota exceeded")}
+		b := &fakeProvider{name: "b", err: errors.New("timeout")}
+
+		_, err := NewFallback(a, b).GetCurrentWeather(ctx, "Barcelona")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, want := range []string{"a: quota exceeded", "b: timeout"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("skips providers without climate normals", func(t *testing.T) {
+		weatherAPI := NewClientWithKey("test-key")
+		working := &fakeProvider{name: "working"}
+
+		n, err := NewFallback(weatherAPI, working).GetClimateNormals(ctx, "Lisbon", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n.Date != "03-15" || working.calls != 1 {
+			t.Errorf("unexpected normals %+v after %d calls", n, working.calls)
+		}
+	})
+
+	t.Run("name lists providers in order", func(t *testing.T) {
+		f := NewFallback(&fakeProvider{name: "weatherapi"}, &fakeProvider{name: "openmeteo"})
+		if f.Name() != "weatherapi,openmeteo" {
+			t.Errorf("unexpected name %q", f.Name())
+		}
+	})
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses answers for equivalent locations", func(t *testing.T) {
+		p := &fakeProvider{name: "p"}
+		c := NewCache(p)
+
+		for _, loc := range []string{"Paris, France", " paris,france", "PARIS,  FRANCE "} {
+			if _, err := c.GetCurrentWeather(ctx, loc); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if p.calls != 1 {
+			t.Errorf("expected 1 upstream call, got %d", p.calls)
+		}
+	})
+
+	t.Run("expires entries after their TTL", func(t *testing.T) {
+		p := &fakeProvider{name: "p"}
+		c := NewCache(p)
+		now := time.Date(2025, 12, 7, 12, 0, 0, 0, time.UTC)
+		c.now = func() time.Time { return now }
+
+		_, _ = c.GetCurrentWeather(ctx, "Barcelona")
+		_, _ = c.GetForecast(ctx, "Barcelona", 3)
+
+		now = now.Add(DefaultCurrentTTL + time.Second)
+		_, _ = c.GetCurrentWeather(ctx, "Barcelona")
+		_, _ = c.GetForecast(ctx, "Barcelona", 3)
+
+		// Current conditions were refetched, the forecast is still fresh
+		if p.calls != 3 {
+			t.Errorf("expected 3 upstream calls, got %d", p.calls)
+		}
+	})
+
+	t.Run("keys forecasts by number of days", func(t *testing.T) {
+		p := &fakeProvider{name: "p"}
+		c := NewCache(p)
+
+		_, _ = c.GetForecast(ctx, "Barcelona", 3)
+		f, _ := c.GetForecast(ctx, "Barcelona", 5)
+
+		if len(f.Forecast.ForecastDay) != 5 || p.calls != 2 {
+			t.Errorf("expected separate entries per day count, got %d days after %d calls", len(f.Forecast.ForecastDay), p.calls)
+		}
+	})
+
+	t.Run("keys climate normals by calendar day only", func(t *testing.T) {
+		p := &fakeProvider{name: "p"}
+		c := NewCache(p)
+
+		_, _ = c.GetClimateNormals(ctx, "Lisbon", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+		_, _ = c.GetClimateNormals(ctx, "Lisbon", time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC))
+		_, _ = c.GetHistory(ctx, "Lisbon", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC))
+
+		if p.calls != 2 {
+			t.Errorf("expected 2 upstream calls, got %d", p.calls)
+		}
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		p := &fakeProvider{name: "p", err: errors.New("down")}
+		c := NewCache(p)
+
+		_, _ = c.GetCurrentWeather(ctx, "Barcelona")
+		_, _ = c.GetCurrentWeather(ctx, "Barcelona")
+
+		if p.calls != 2 {
+			t.Errorf("expected errors to be retried, got %d calls", p.calls)
+		}
+	})
+}
+
+func TestCache_WithStandIns(t *testing.T) {
+	// WeatherAPI is down, Open-Meteo answers; the second question is served from cache
+	weatherAPICalls := 0
+	weatherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		weatherAPICalls++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer weatherAPI.Close()
+
+	primary := NewClientWithKey("test-key")
+	primary.baseURL = weatherAPI.URL
+
+	secondary, _ := newOpenMeteoStandIn(t)
+
+	provider := NewCache(NewFallback(primary, secondary))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		w, err := provider.GetCurrentWeather(ctx, "Barcelona")
+		if err != nil {
+			t.Fatalf("GetCurrentWeather failed: %v", err)
+		}
+		if w.Location.Name != "Barcelona" {
+			t.Errorf("unexpected location %q", w.Location.Name)
+		}
+	}
+
+	if weatherAPICalls != 1 {
+		t.Errorf("expected 1 call to the failing provider, got %d", weatherAPICalls)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	t.Run("skips WeatherAPI without a key", func(t *testing.T) {
+		t.Setenv("WEATHER_API_KEY", "")
+		t.Setenv("WEATHER_PROVIDERS", "")
+
+		if name := NewProviderFromEnv().Name(); name != ProviderOpenMeteo {
+			t.Errorf("expected only Open-Meteo, got %q", name)
+		}
+	})
+
+	t.Run("honours configured order", func(t *testing.T) {
+		t.Setenv("WEATHER_API_KEY", "key")
+		t.Setenv("WEATHER_PROVIDERS", "openmeteo, weatherapi, unknown")
+
+		if name := NewProviderFromEnv().Name(); name != "openmeteo,weatherapi" {
+			t.Errorf("unexpected provider order %q", name)
+		}
+	})
+}
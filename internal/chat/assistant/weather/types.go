@@ -1,122 +1,208 @@
 package weather
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+)
+
+// Location describes the place a weather report is for
+type Location struct {
+	Name      string  `json:"name"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	LocalTime string  `json:"localtime"`
+}
+
+// Condition is a short textual description of the weather (e.g. "Partly cloudy")
+type Condition struct {
+	Text string `json:"text"`
+	Icon string `json:"icon"`
+}
+
+// Current holds the current weather conditions
+type Current struct {
+	TempC      float64     `json:"temp_c"`
+	TempF      float64     `json:"temp_f"`
+	Condition  Condition   `json:"condition"`
+	WindKph    float64     `json:"wind_kph"`
+	WindMph    float64     `json:"wind_mph"`
+	WindDir    string      `json:"wind_dir"`
+	Humidity   int         `json:"humidity"`
+	FeelsLikeC float64     `json:"feelslike_c"`
+	FeelsLikeF float64     `json:"feelslike_f"`
+	VisKm      float64     `json:"vis_km"`
+	UV         float64     `json:"uv"`
+	PrecipMm   float64     `json:"precip_mm"`
+	PrecipIn   float64     `json:"precip_in"`
+	AirQuality *AirQuality `json:"air_quality,omitempty"`
+}
+
+// AirQuality holds pollutant concentrations (μg/m3) and indexes,
+// only present when air quality data was requested
+type AirQuality struct {
+	CO           float64 `json:"co"`
+	NO2          float64 `json:"no2"`
+	O3           float64 `json:"o3"`
+	SO2          float64 `json:"so2"`
+	PM2_5        float64 `json:"pm2_5"`
+	PM10         float64 `json:"pm10"`
+	USEPAIndex   int     `json:"us-epa-index"`
+	GBDefraIndex int     `json:"gb-defra-index"`
+}
+
+// Day summarises the forecast for a single day
+type Day struct {
+	MaxTempC      float64   `json:"maxtemp_c"`
+	MinTempC      float64   `json:"mintemp_c"`
+	AvgTempC      float64   `json:"avgtemp_c"`
+	Condition     Condition `json:"condition"`
+	MaxWindKph    float64   `json:"maxwind_kph"`
+	AvgHumidity   float64   `json:"avghumidity"`
+	ChanceOfRain  int       `json:"daily_chance_of_rain"`
+	ChanceOfSnow  int       `json:"daily_chance_of_snow"`
+	TotalPrecipMm float64   `json:"totalprecip_mm"`
+	TotalPrecipIn float64   `json:"totalprecip_in"`
+	UV            float64   `json:"uv"`
+}
+
+// Astro holds sun and moon times for a day, in local time (e.g. "07:45 AM")
+type Astro struct {
+	Sunrise   string `json:"sunrise"`
+	Sunset    string `json:"sunset"`
+	Moonrise  string `json:"moonrise"`
+	Moonset   string `json:"moonset"`
+	MoonPhase string `json:"moon_phase"`
+}
+
+// Hour is the forecast for a single hour
+type Hour struct {
+	Time         string    `json:"time"` // local time, "2006-01-02 15:04"
+	TempC        float64   `json:"temp_c"`
+	TempF        float64   `json:"temp_f"`
+	Condition    Condition `json:"condition"`
+	WindKph      float64   `json:"wind_kph"`
+	WindMph      float64   `json:"wind_mph"`
+	WindDir      string    `json:"wind_dir"`
+	Humidity     int       `json:"humidity"`
+	PrecipMm     float64   `json:"precip_mm"`
+	PrecipIn     float64   `json:"precip_in"`
+	ChanceOfRain int       `json:"chance_of_rain"`
+	ChanceOfSnow int       `json:"chance_of_snow"`
+}
+
+// ForecastDay is a single day of a forecast
+type ForecastDay struct {
+	Date  string `json:"date"`
+	Day   Day    `json:"day"`
+	Astro Astro  `json:"astro"`
+	Hour  []Hour `json:"hour"`
+}
+
+// Alert is a government weather warning for the forecast area
+type Alert struct {
+	Headline    string `json:"headline"`
+	Severity    string `json:"severity"`
+	Urgency     string `json:"urgency"`
+	Areas       string `json:"areas"`
+	Event       string `json:"event"`
+	Effective   string `json:"effective"`
+	Expires     string `json:"expires"`
+	Description string `json:"desc"`
+	Instruction string `json:"instruction"`
+}
 
 // CurrentWeatherResponse represents the response from WeatherAPI current weather endpoint
 type CurrentWeatherResponse struct {
-	Location struct {
-		Name      string  `json:"name"`
-		Region    string  `json:"region"`
-		Country   string  `json:"country"`
-		Lat       float64 `json:"lat"`
-		Lon       float64 `json:"lon"`
-		LocalTime string  `json:"localtime"`
-	} `json:"location"`
-	Current struct {
-		TempC     float64 `json:"temp_c"`
-		TempF     float64 `json:"temp_f"`
-		Condition struct {
-			Text string `json:"text"`
-			Icon string `json:"icon"`
-		} `json:"condition"`
-		WindKph    float64 `json:"wind_kph"`
-		WindMph    float64 `json:"wind_mph"`
-		WindDir    string  `json:"wind_dir"`
-		Humidity   int     `json:"humidity"`
-		FeelsLikeC float64 `json:"feelslike_c"`
-		FeelsLikeF float64 `json:"feelslike_f"`
-		VisKm      float64 `json:"vis_km"`
-		UV         float64 `json:"uv"`
-	} `json:"current"`
+	Location Location `json:"location"`
+	Current  Current  `json:"current"`
 }
 
 // ForecastWeatherResponse represents the response from WeatherAPI forecast endpoint
 type ForecastWeatherResponse struct {
-	Location struct {
-		Name      string `json:"name"`
-		Region    string `json:"region"`
-		Country   string `json:"country"`
-		LocalTime string `json:"localtime"`
-	} `json:"location"`
-	Current struct {
-		TempC     float64 `json:"temp_c"`
-		TempF     float64 `json:"temp_f"`
-		Condition struct {
-			Text string `json:"text"`
-		} `json:"condition"`
-		WindKph  float64 `json:"wind_kph"`
-		Humidity int     `json:"humidity"`
-	} `json:"current"`
+	Location Location `json:"location"`
+	Current  Current  `json:"current"`
+	Forecast struct {
+		ForecastDay []ForecastDay `json:"forecastday"`
+	} `json:"forecast"`
+	Alerts struct {
+		Alert []Alert `json:"alert"`
+	} `json:"alerts"`
+
+	// Unavailable lists requested extras the provider could not supply
+	// (e.g. "alerts"), so formatters don't mistake missing data for "none"
+	Unavailable []string `json:"-"`
+}
+
+// HistoryWeatherResponse represents the response from WeatherAPI history endpoint:
+// the observed weather of a single past day
+type HistoryWeatherResponse struct {
+	Location Location `json:"location"`
 	Forecast struct {
-		ForecastDay []struct {
-			Date string `json:"date"`
-			Day  struct {
-				MaxTempC  float64 `json:"maxtemp_c"`
-				MinTempC  float64 `json:"mintemp_c"`
-				AvgTempC  float64 `json:"avgtemp_c"`
-				Condition struct {
-					Text string `json:"text"`
-				} `json:"condition"`
-				MaxWindKph   float64 `json:"maxwind_kph"`
-				AvgHumidity  float64 `json:"avghumidity"`
-				ChanceOfRain int     `json:"daily_chance_of_rain"`
-			} `json:"day"`
-		} `json:"forecastday"`
+		ForecastDay []ForecastDay `json:"forecastday"`
 	} `json:"forecast"`
 }
 
+// ClimateNormals describes typical weather around a calendar day, averaged over past years
+type ClimateNormals struct {
+	Location    Location
+	Date        string // calendar day the window is centred on, "01-02"
+	WindowDays  int    // days either side of Date included in the averages
+	FromYear    int
+	ToYear      int
+	AvgMaxTempC float64
+	AvgMinTempC float64
+	AvgPrecipMm float64 // per day
+	WetDaysPct  int     // share of days with at least 1 mm of precipitation
+}
+
 // FormatCurrentWeather formats current weather data into a human-readable string
-func FormatCurrentWeather(w *CurrentWeatherResponse) string {
+func FormatCurrentWeather(w *CurrentWeatherResponse, u units.System) string {
 	return fmt.Sprintf(
 		"Current weather in %s, %s:\n"+
 			"Condition: %s\n"+
-			"Temperature: %.1f°C (%.1f°F)\n"+
-			"Feels like: %.1f°C (%.1f°F)\n"+
-			"Wind: %.1f km/h %s\n"+
+			"Temperature: %s\n"+
+			"Feels like: %s\n"+
+			"Wind: %s %s\n"+
 			"Humidity: %d%%\n"+
-			"Visibility: %.1f km\n"+
+			"Visibility: %s\n"+
 			"UV Index: %.1f\n"+
 			"Local time: %s",
 		w.Location.Name,
 		w.Location.Country,
 		w.Current.Condition.Text,
-		w.Current.TempC,
-		w.Current.TempF,
-		w.Current.FeelsLikeC,
-		w.Current.FeelsLikeF,
-		w.Current.WindKph,
+		u.Temperature(w.Current.TempC),
+		u.Temperature(w.Current.FeelsLikeC),
+		u.Speed(w.Current.WindKph),
 		w.Current.WindDir,
 		w.Current.Humidity,
-		w.Current.VisKm,
+		u.Distance(w.Current.VisKm),
 		w.Current.UV,
 		w.Location.LocalTime,
 	)
 }
 
 // FormatForecast formats forecast data into a human-readable string
-func FormatForecast(f *ForecastWeatherResponse) string {
+func FormatForecast(f *ForecastWeatherResponse, u units.System) string {
 	result := fmt.Sprintf("Weather forecast for %s, %s:\n\n", f.Location.Name, f.Location.Country)
 
 	// Add current weather
-	result += fmt.Sprintf("Current: %s, %.1f°C, Wind: %.1f km/h, Humidity: %d%%\n\n",
+	result += fmt.Sprintf("Current: %s, %s, Wind: %s, Humidity: %d%%\n\n",
 		f.Current.Condition.Text,
-		f.Current.TempC,
-		f.Current.WindKph,
+		u.Temperature(f.Current.TempC),
+		u.Speed(f.Current.WindKph),
 		f.Current.Humidity,
 	)
 
 	// Add forecast days
 	for i, day := range f.Forecast.ForecastDay {
 		result += fmt.Sprintf("Day %d (%s):\n", i+1, day.Date)
-		result += fmt.Sprintf("  Condition: %s\n", day.Day.Condition.Text)
-		result += fmt.Sprintf("  Temperature: %.1f°C to %.1f°C (avg: %.1f°C)\n",
-			day.Day.MinTempC,
-			day.Day.MaxTempC,
-			day.Day.AvgTempC,
-		)
-		result += fmt.Sprintf("  Max wind: %.1f km/h\n", day.Day.MaxWindKph)
-		result += fmt.Sprintf("  Avg humidity: %.0f%%\n", day.Day.AvgHumidity)
-		result += fmt.Sprintf("  Chance of rain: %d%%\n", day.Day.ChanceOfRain)
+		result += formatDay(day.Day, u)
 		if i < len(f.Forecast.ForecastDay)-1 {
 			result += "\n"
 		}
@@ -124,3 +210,174 @@ func FormatForecast(f *ForecastWeatherResponse) string {
 
 	return result
 }
+
+// FormatForecastDay formats the forecast of the day matching date (YYYY-MM-DD)
+func FormatForecastDay(f *ForecastWeatherResponse, date string, u units.System) (string, error) {
+	for _, day := range f.Forecast.ForecastDay {
+		if day.Date == date {
+			return fmt.Sprintf("Weather forecast for %s, %s on %s:\n", f.Location.Name, f.Location.Country, date) +
+				formatDay(day.Day, u), nil
+		}
+	}
+
+	return "", fmt.Errorf("date %s is not within the forecast range", date)
+}
+
+// FormatHistory formats the observed weather of a past day
+func FormatHistory(h *HistoryWeatherResponse, u units.System) string {
+	if len(h.Forecast.ForecastDay) == 0 {
+		return fmt.Sprintf("No historical weather available for %s, %s", h.Location.Name, h.Location.Country)
+	}
+
+	day := h.Forecast.ForecastDay[0]
+	return fmt.Sprintf(
+		"Observed weather in %s, %s on %s:\n"+
+			"  Condition: %s\n"+
+			"  Temperature: %s (avg: %s)\n"+
+			"  Max wind: %s\n"+
+			"  Avg humidity: %.0f%%\n"+
+			"  Total precipitation: %s",
+		h.Location.Name, h.Location.Country, day.Date,
+		day.Day.Condition.Text,
+		u.TemperatureRange(day.Day.MinTempC, day.Day.MaxTempC), u.Temperature(day.Day.AvgTempC),
+		u.Speed(day.Day.MaxWindKph),
+		day.Day.AvgHumidity,
+		u.Precipitation(day.Day.TotalPrecipMm),
+	)
+}
+
+// FormatClimateNormals formats typical weather around a calendar day
+func FormatClimateNormals(n *ClimateNormals, u units.System) string {
+	date := n.Date
+	if t, err := time.Parse("01-02", n.Date); err == nil {
+		date = t.Format("January 2")
+	}
+
+	return fmt.Sprintf(
+		"Typical weather in %s, %s around %s (±%d days, %d-%d averages, not a forecast):\n"+
+			"  High: %s, Low: %s\n"+
+			"  Precipitation: %s per day, wet days: %d%%",
+		n.Location.Name, n.Location.Country, date, n.WindowDays, n.FromYear, n.ToYear,
+		u.Temperature(n.AvgMaxTempC), u.Temperature(n.AvgMinTempC),
+		u.Precipitation(n.AvgPrecipMm), n.WetDaysPct,
+	)
+}
+
+// formatDay formats the summary lines of a single day
+func formatDay(d Day, u units.System) string {
+	result := fmt.Sprintf("  Condition: %s\n", d.Condition.Text)
+	result += fmt.Sprintf("  Temperature: %s (avg: %s)\n", u.TemperatureRange(d.MinTempC, d.MaxTempC), u.Temperature(d.AvgTempC))
+	result += fmt.Sprintf("  Max wind: %s\n", u.Speed(d.MaxWindKph))
+	result += fmt.Sprintf("  Avg humidity: %.0f%%\n", d.AvgHumidity)
+	result += fmt.Sprintf("  Chance of rain: %d%%\n", d.ChanceOfRain)
+	if d.TotalPrecipMm > 0 {
+		result += fmt.Sprintf("  Total precipitation: %s\n", u.Precipitation(d.TotalPrecipMm))
+	}
+	return result
+}
+
+// FormatHourly formats the hourly breakdown of the forecast day matching date (YYYY-MM-DD)
+func FormatHourly(f *ForecastWeatherResponse, date string, u units.System) (string, error) {
+	for _, day := range f.Forecast.ForecastDay {
+		if day.Date != date {
+			continue
+		}
+
+		if len(day.Hour) == 0 {
+			return "", fmt.Errorf("no hourly data available for %s", date)
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Hourly forecast for %s, %s on %s:\n", f.Location.Name, f.Location.Country, date)
+		for _, h := range day.Hour {
+			// Hour times are "YYYY-MM-DD HH:MM", only the clock time is interesting here
+			clock := h.Time
+			if _, t, ok := strings.Cut(h.Time, " "); ok {
+				clock = t
+			}
+			fmt.Fprintf(&b, "  %s: %s, %s, rain %d%%, %s, wind %s %s\n",
+				clock, h.Condition.Text, u.Temperature(h.TempC), h.ChanceOfRain, u.Precipitation(h.PrecipMm), u.Speed(h.WindKph), h.WindDir)
+		}
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+
+	return "", fmt.Errorf("date %s is not within the forecast range", date)
+}
+
+// FormatAstronomy formats sunrise, sunset and moon data for each forecast day
+func FormatAstronomy(f *ForecastWeatherResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Astronomy for %s, %s:\n", f.Location.Name, f.Location.Country)
+	for _, day := range f.Forecast.ForecastDay {
+		fmt.Fprintf(&b, "  %s: sunrise %s, sunset %s", day.Date, day.Astro.Sunrise, day.Astro.Sunset)
+		if day.Astro.MoonPhase != "" {
+			fmt.Fprintf(&b, ", moon %s (rise %s, set %s)", day.Astro.MoonPhase, day.Astro.Moonrise, day.Astro.Moonset)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// FormatAlerts formats active weather alerts
+func FormatAlerts(f *ForecastWeatherResponse) string {
+	if slices.Contains(f.Unavailable, "alerts") {
+		return "Weather alerts: not available from the current weather provider"
+	}
+
+	if len(f.Alerts.Alert) == 0 {
+		return fmt.Sprintf("Weather alerts: none active for %s", f.Location.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Weather alerts for %s (%d):\n", f.Location.Name, len(f.Alerts.Alert))
+	for _, a := range f.Alerts.Alert {
+		fmt.Fprintf(&b, "- %s [%s]\n", firstNonEmpty(a.Headline, a.Event), firstNonEmpty(a.Severity, "unknown severity"))
+		if a.Areas != "" {
+			fmt.Fprintf(&b, "  Areas: %s\n", a.Areas)
+		}
+		if a.Effective != "" || a.Expires != "" {
+			fmt.Fprintf(&b, "  From %s until %s\n", a.Effective, a.Expires)
+		}
+		if a.Instruction != "" {
+			fmt.Fprintf(&b, "  Instruction: %s\n", a.Instruction)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// usEPACategories maps the US EPA index (1-6) to its category
+var usEPACategories = []string{"", "Good", "Moderate", "Unhealthy for sensitive groups", "Unhealthy", "Very unhealthy", "Hazardous"}
+
+// FormatAirQuality formats current air quality
+func FormatAirQuality(f *ForecastWeatherResponse) string {
+	aq := f.Current.AirQuality
+	if aq == nil {
+		if slices.Contains(f.Unavailable, "air_quality") {
+			return "Air quality: not available from the current weather provider"
+		}
+		return "Air quality: no data"
+	}
+
+	category := "unknown"
+	if aq.USEPAIndex > 0 && aq.USEPAIndex < len(usEPACategories) {
+		category = usEPACategories[aq.USEPAIndex]
+	}
+
+	return fmt.Sprintf(
+		"Air quality in %s: %s (US EPA index %d)\n"+
+			"  PM2.5: %.1f μg/m3, PM10: %.1f μg/m3\n"+
+			"  O3: %.1f μg/m3, NO2: %.1f μg/m3, SO2: %.1f μg/m3, CO: %.1f μg/m3",
+		f.Location.Name, category, aq.USEPAIndex,
+		aq.PM2_5, aq.PM10,
+		aq.O3, aq.NO2, aq.SO2, aq.CO,
+	)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
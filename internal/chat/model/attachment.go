@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Attachment is a file a user uploaded, whose text is split into chunks the
+// assistant searches when answering questions about it
+type Attachment struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	UserID      string             `bson:"user_id"`
+	Filename    string             `bson:"filename"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	Data        []byte             `bson:"data"`
+
+	// Pages is the number of pages of a PDF, 0 for other files
+	Pages int `bson:"pages"`
+
+	// ConversationID is the conversation the file was sent in, zero until then
+	ConversationID primitive.ObjectID `bson:"conversation_id,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// AttachmentChunk is a passage of an attachment's text with its embedding
+type AttachmentChunk struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	AttachmentID primitive.ObjectID `bson:"attachment_id"`
+
+	// Index is the position of the chunk in the attachment
+	Index int `bson:"index"`
+
+	// Page is the PDF page the chunk starts on, 0 for other files
+	Page int `bson:"page,omitempty"`
+
+	Text      string    `bson:"text"`
+	Embedding []float64 `bson:"embedding"`
+}
+
+// AttachmentRef is what a message keeps of the files sent with it
+type AttachmentRef struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Filename    string             `bson:"filename"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	Pages       int                `bson:"pages,omitempty"`
+}
+
+// Ref returns the reference to a stored with messages
+func (a *Attachment) Ref() *AttachmentRef {
+	return &AttachmentRef{
+		ID:          a.ID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Pages:       a.Pages,
+	}
+}
+
+func (a *Attachment) Proto() *pb.Attachment {
+	proto := &pb.Attachment{
+		Id:          a.ID.Hex(),
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Pages:       int32(a.Pages),
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}
+	if !a.ConversationID.IsZero() {
+		proto.ConversationId = a.ConversationID.Hex()
+	}
+	return proto
+}
+
+func (r *AttachmentRef) Proto() *pb.Attachment {
+	return &pb.Attachment{
+		Id:          r.ID.Hex(),
+		Filename:    r.Filename,
+		ContentType: r.ContentType,
+		Size:        r.Size,
+		Pages:       int32(r.Pages),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -14,6 +15,23 @@ type Conversation struct {
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
 	Messages  []*Message         `bson:"messages"`
+	Units     units.System       `bson:"units,omitempty"`
+
+	// UserID is the user the conversation belongs to; only they can see or continue it
+	UserID string `bson:"user_id"`
+
+	// Persona is the name of the persona the assistant replies as, empty for the default one
+	Persona string `bson:"persona,omitempty"`
+
+	// Tools restricts the tools of the conversation, on top of the user's and the deployment's policies
+	Tools ToolPolicy `bson:"tools,omitempty"`
+
+	// Pending is the reply waiting for the user to confirm tool calls, nil otherwise
+	Pending *PendingReply `bson:"pending,omitempty"`
+
+	// stored is the number of messages already in the database, so that
+	// UpdateConversation only adds the ones after them
+	stored int
 }
 
 func (c *Conversation) Proto() *pb.Conversation {
@@ -21,7 +39,14 @@ func (c *Conversation) Proto() *pb.Conversation {
 		Id:        c.ID.Hex(),
 		Title:     c.Title,
 		Timestamp: timestamppb.New(c.UpdatedAt),
+		Units:     unitsProto(c.Units),
+		Persona:   c.Persona,
+	}
+
+	if !c.Tools.IsZero() {
+		proto.Tools = c.Tools.Proto()
 	}
+	proto.PendingToolCalls = c.PendingToolCallsProto()
 
 	for _, m := range c.Messages {
 		proto.Messages = append(proto.Messages, m.Proto())
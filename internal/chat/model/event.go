@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Event is an entry in a user's calendar
+type Event struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	UserID string             `bson:"user_id"`
+
+	// UID is the iCalendar UID; importing an event with a known UID updates it
+	UID string `bson:"uid"`
+
+	Title       string `bson:"title"`
+	Description string `bson:"description,omitempty"`
+	Location    string `bson:"location,omitempty"`
+
+	// Start and End are instants; all-day events run from midnight UTC of their
+	// first day to midnight UTC of the day after their last
+	Start  time.Time `bson:"start"`
+	End    time.Time `bson:"end"`
+	AllDay bool      `bson:"all_day"`
+
+	// TimeZone is the IANA zone the event is shown in
+	TimeZone string `bson:"time_zone"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// EventFilter selects calendar events to list
+type EventFilter struct {
+	// From and To select events overlapping [From, To); zero means unbounded
+	From, To time.Time
+
+	// Query matches events whose title, description or location contains it, ignoring case
+	Query string
+
+	// Limit caps the number of events, 0 means the default list limit
+	Limit int
+}
+
+// Zone returns the zone the event is shown in, UTC for all-day events
+func (e *Event) Zone() *time.Location {
+	if e.AllDay {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (e *Event) Proto() *pb.Event {
+	return &pb.Event{
+		Id:          e.ID.Hex(),
+		Title:       e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		Start:       timestamppb.New(e.Start),
+		End:         timestamppb.New(e.End),
+		AllDay:      e.AllDay,
+		TimeZone:    e.TimeZone,
+		Uid:         e.UID,
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+	}
+}
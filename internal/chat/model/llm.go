@@ -0,0 +1,17 @@
+package model
+
+import "errors"
+
+// Errors of the language model replies are generated with. The assistant wraps
+// provider errors in them so the server can report a matching status.
+var (
+	// ErrModelUnavailable means the model and its fallbacks are down or unreachable
+	ErrModelUnavailable = errors.New("the language model is unavailable")
+
+	// ErrModelRateLimited means the provider's rate limit or quota was exceeded
+	ErrModelRateLimited = errors.New("the language model is rate limited")
+
+	// ErrModelRejected means the provider refused the request itself, e.g. a
+	// conversation too long for the model's context
+	ErrModelRejected = errors.New("the language model rejected the request")
+)
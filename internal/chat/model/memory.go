@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Memory is a durable fact about a user, learned in one conversation and
+// recalled in later ones
+type Memory struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	UserID string             `bson:"user_id"`
+	Text   string             `bson:"text"`
+
+	// Key is the normalised text, so a fact learned twice is stored once
+	Key string `bson:"key"`
+
+	// ConversationID is the conversation the fact was learned in
+	ConversationID primitive.ObjectID `bson:"conversation_id,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// MemoryKey normalises a fact for de-duplication: case, spacing and trailing
+// punctuation don't make a fact different
+func MemoryKey(text string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(strings.ToLower(text)), " "), ".!")
+}
+
+func (m *Memory) Proto() *pb.Memory {
+	proto := &pb.Memory{
+		Id:        m.ID.Hex(),
+		Text:      m.Text,
+		CreatedAt: timestamppb.New(m.CreatedAt),
+	}
+	if !m.ConversationID.IsZero() {
+		proto.ConversationId = m.ConversationID.Hex()
+	}
+	return proto
+}
@@ -14,13 +14,31 @@ type Message struct {
 	Content   string             `bson:"content"`
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
+
+	// Attachments are the files sent with the message
+	Attachments []*AttachmentRef `bson:"attachments,omitempty"`
+
+	// ResponseSchema is the JSON Schema a user asked the reply to follow
+	ResponseSchema string `bson:"response_schema,omitempty"`
+
+	// Structured is the JSON document of a reply that followed a response schema
+	Structured string `bson:"structured,omitempty"`
 }
 
 func (m *Message) Proto() *pb.Conversation_Message {
-	return &pb.Conversation_Message{
+	proto := &pb.Conversation_Message{
 		Id:        m.ID.Hex(),
 		Role:      m.Role.Proto(),
 		Content:   m.Content,
 		Timestamp: timestamppb.New(m.CreatedAt),
+
+		ResponseSchema:    m.ResponseSchema,
+		StructuredContent: m.Structured,
 	}
+
+	for _, a := range m.Attachments {
+		proto.Attachments = append(proto.Attachments, a.Proto())
+	}
+
+	return proto
 }
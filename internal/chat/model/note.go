@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Note is a note saved by a user, reachable from all of their conversations
+type Note struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    string             `bson:"user_id"`
+	Title     string             `bson:"title"`
+	Content   string             `bson:"content"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+func (n *Note) Proto() *pb.Note {
+	return &pb.Note{
+		Id:        n.ID.Hex(),
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: timestamppb.New(n.CreatedAt),
+		UpdatedAt: timestamppb.New(n.UpdatedAt),
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+)
+
+// ErrUnknownPersona is returned when a conversation asks for a persona that isn't defined
+var ErrUnknownPersona = errors.New("unknown persona")
+
+// personaName allows lower-case names such as "travel_planner"
+var personaName = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,39}$`)
+
+// Persona is a named set of instructions, model and tools the assistant
+// replies with. Personas come built in, from a configuration file or from the
+// personas collection, and a conversation keeps the one it was started with.
+type Persona struct {
+	Name        string `bson:"_id" json:"name"`
+	Description string `bson:"description" json:"description"`
+
+	// SystemPrompt opens every reply; the unit preference and what is
+	// remembered about the user are added to it
+	SystemPrompt string `bson:"system_prompt" json:"system_prompt"`
+
+	// TitlePrompt replaces the default instructions for conversation titles
+	TitlePrompt string `bson:"title_prompt,omitempty" json:"title_prompt,omitempty"`
+
+	// Language replies and titles are written in, e.g. "Spanish"; empty to follow the user
+	Language string `bson:"language,omitempty" json:"language,omitempty"`
+
+	// Model replies are generated with, the assistant's default when empty
+	Model string `bson:"model,omitempty" json:"model,omitempty"`
+
+	// Temperature of replies between 0 and 2, the model's default when nil
+	Temperature *float64 `bson:"temperature,omitempty" json:"temperature,omitempty"`
+
+	// Tools the persona may use by name, all of them when empty
+	Tools []string `bson:"tools,omitempty" json:"tools,omitempty"`
+}
+
+// Validate reports the first problem that keeps the persona from being used
+func (p *Persona) Validate() error {
+	if !personaName.MatchString(p.Name) {
+		return fmt.Errorf("invalid persona name %q: use up to 40 lower-case letters, digits, '_' or '-'", p.Name)
+	}
+	if strings.TrimSpace(p.SystemPrompt) == "" {
+		return fmt.Errorf("persona %s needs a system prompt", p.Name)
+	}
+	if t := p.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return fmt.Errorf("persona %s: temperature must be between 0 and 2, got %g", p.Name, *t)
+	}
+	return nil
+}
+
+// ToolPolicy limits replies to the persona's tools
+func (p *Persona) ToolPolicy() ToolPolicy {
+	return ToolPolicy{Allow: p.Tools}
+}
+
+func (p *Persona) Proto() *pb.Persona {
+	return &pb.Persona{
+		Name:        p.Name,
+		Description: p.Description,
+		Language:    p.Language,
+		Model:       p.Model,
+		Tools:       p.Tools,
+	}
+}
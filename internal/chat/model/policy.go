@@ -0,0 +1,29 @@
+package model
+
+import "fmt"
+
+// Stages of a conversation guardrails check
+const (
+	StageInput  = "input"
+	StageOutput = "output"
+)
+
+// PolicyViolation is the error of a message or reply a guardrail rejected
+type PolicyViolation struct {
+	// Policy names the rule broken, e.g. "blocklist" or "moderation"
+	Policy string
+
+	// Reason explains the violation without repeating the offending text
+	Reason string
+
+	// Stage is StageInput for user messages and StageOutput for replies
+	Stage string
+}
+
+func (v *PolicyViolation) Error() string {
+	subject := "the message"
+	if v.Stage == StageOutput {
+		subject = "the reply"
+	}
+	return fmt.Sprintf("%s violates the %s policy: %s", subject, v.Policy, v.Reason)
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReminderStatus is where a reminder is in its life cycle
+type ReminderStatus string
+
+const (
+	ReminderPending   ReminderStatus = "pending"
+	ReminderDelivered ReminderStatus = "delivered"
+	ReminderCancelled ReminderStatus = "cancelled"
+	ReminderFailed    ReminderStatus = "failed"
+)
+
+// Reminder is a message to deliver to a user at a given time
+type Reminder struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	UserID string             `bson:"user_id"`
+
+	// ConversationID is the conversation the reminder was set in, if any
+	ConversationID primitive.ObjectID `bson:"conversation_id,omitempty"`
+
+	Text string `bson:"text"`
+
+	// DueAt is when to deliver, TimeZone the IANA zone the user gave it in
+	DueAt    time.Time `bson:"due_at"`
+	TimeZone string    `bson:"time_zone"`
+
+	Status    ReminderStatus `bson:"status"`
+	CreatedAt time.Time      `bson:"created_at"`
+	UpdatedAt time.Time      `bson:"updated_at"`
+
+	// Delivery bookkeeping: a scheduler holds a lease while delivering, channels
+	// that succeeded are recorded so retries don't deliver twice
+	LeaseUntil  *time.Time `bson:"lease_until,omitempty"`
+	Attempts    int        `bson:"attempts"`
+	Delivered   []string   `bson:"delivered"`
+	LastError   string     `bson:"last_error,omitempty"`
+	DeliveredAt *time.Time `bson:"delivered_at,omitempty"`
+}
+
+// Local returns the due time in the reminder's time zone
+func (r *Reminder) Local() time.Time {
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return r.DueAt.UTC()
+	}
+	return r.DueAt.In(loc)
+}
+
+func (r *Reminder) Proto() *pb.Reminder {
+	proto := &pb.Reminder{
+		Id:        r.ID.Hex(),
+		Text:      r.Text,
+		DueAt:     timestamppb.New(r.DueAt),
+		TimeZone:  r.TimeZone,
+		Status:    reminderStatusProto(r.Status),
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+	if !r.ConversationID.IsZero() {
+		proto.ConversationId = r.ConversationID.Hex()
+	}
+	if r.DeliveredAt != nil {
+		proto.DeliveredAt = timestamppb.New(*r.DeliveredAt)
+	}
+	return proto
+}
+
+func reminderStatusProto(s ReminderStatus) pb.Reminder_Status {
+	switch s {
+	case ReminderPending:
+		return pb.Reminder_PENDING
+	case ReminderDelivered:
+		return pb.Reminder_DELIVERED
+	case ReminderCancelled:
+		return pb.Reminder_CANCELLED
+	case ReminderFailed:
+		return pb.Reminder_FAILED
+	default:
+		return pb.Reminder_STATUS_UNSPECIFIED
+	}
+}
+
+type conversationKey struct{}
+
+// WithConversationID returns a context for work done on behalf of a conversation
+func WithConversationID(ctx context.Context, id primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, conversationKey{}, id)
+}
+
+// ConversationIDFromContext returns the conversation ctx works for, if any
+func ConversationIDFromContext(ctx context.Context) (primitive.ObjectID, bool) {
+	id, ok := ctx.Value(conversationKey{}).(primitive.ObjectID)
+	return id, ok && !id.IsZero()
+}
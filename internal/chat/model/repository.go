@@ -3,7 +3,12 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
 	"github.com/twitchtv/twirp"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,24 +18,62 @@ import (
 
 const (
 	conversationCollection = "conversations"
+	noteCollection         = "notes"
+	todoCollection         = "todos"
+	reminderCollection     = "reminders"
+	eventCollection        = "events"
+	memoryCollection       = "memories"
+	attachmentCollection   = "attachments"
+	chunkCollection        = "attachment_chunks"
+	personaCollection      = "personas"
+	toolPolicyCollection   = "tool_policies"
+
+	// listLimit caps how many notes or to-do items one list returns
+	listLimit = 200
 )
 
 type Repository struct {
-	conn *mongo.Database
+	conn   *mongo.Database
+	sealer Sealer
 }
 
-func New(conn *mongo.Database) *Repository {
-	return &Repository{
+func New(conn *mongo.Database, opts ...Option) *Repository {
+	r := &Repository{
 		conn: conn,
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 func (r *Repository) CreateConversation(ctx context.Context, c *Conversation) error {
-	_, err := r.conn.Collection(conversationCollection).InsertOne(ctx, c)
-	return err
+	sealed, err := r.sealConversation(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.conn.Collection(conversationCollection).InsertOne(ctx, sealed); err != nil {
+		return err
+	}
+
+	c.stored = len(c.Messages)
+	return nil
 }
 
-func (r *Repository) DescribeConversation(ctx context.Context, id string) (*Conversation, error) {
+// ownedBy matches the conversations of a user. Conversations stored before
+// they had an owner belong to the default user.
+func ownedBy(userID string) any {
+	if userID == auth.DefaultUser {
+		return bson.M{"$in": bson.A{userID, nil}}
+	}
+	return userID
+}
+
+// DescribeConversation returns a user's conversation; other users' conversations are not found
+func (r *Repository) DescribeConversation(ctx context.Context, userID, id string) (*Conversation, error) {
 	var c Conversation
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -38,7 +81,7 @@ func (r *Repository) DescribeConversation(ctx context.Context, id string) (*Conv
 		return nil, twirp.NotFoundError("invalid conversation ID")
 	}
 
-	err = r.conn.Collection(conversationCollection).FindOne(ctx, map[string]any{"_id": oid}).Decode(&c)
+	err = r.conn.Collection(conversationCollection).FindOne(ctx, bson.M{"_id": oid, "user_id": ownedBy(userID)}).Decode(&c)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, twirp.NotFoundError("conversation not found")
 	}
@@ -47,15 +90,25 @@ func (r *Repository) DescribeConversation(ctx context.Context, id string) (*Conv
 		return nil, err
 	}
 
+	if c.UserID == "" {
+		c.UserID = auth.DefaultUser
+	}
+	c.stored = len(c.Messages)
+
+	if err := r.openConversation(&c); err != nil {
+		return nil, err
+	}
+
 	return &c, nil
 }
 
-func (r *Repository) ListConversations(ctx context.Context) ([]*Conversation, error) {
+// ListConversations returns a user's conversations, most recent first
+func (r *Repository) ListConversations(ctx context.Context, userID string) ([]*Conversation, error) {
 	opts := options.Find().
 		SetSort(bson.D{{Key: "created_at", Value: -1}})
 
 	cursor, err := r.conn.Collection(conversationCollection).
-		Find(ctx, map[string]any{}, opts)
+		Find(ctx, bson.M{"user_id": ownedBy(userID)}, opts)
 
 	if err != nil {
 		return nil, err
@@ -74,6 +127,15 @@ func (r *Repository) ListConversations(ctx context.Context) ([]*Conversation, er
 			return nil, err
 		}
 
+		if c.UserID == "" {
+			c.UserID = auth.DefaultUser
+		}
+		c.stored = len(c.Messages)
+
+		if err := r.openConversation(&c); err != nil {
+			return nil, err
+		}
+
 		items = append(items, &c)
 	}
 
@@ -84,16 +146,115 @@ func (r *Repository) ListConversations(ctx context.Context) ([]*Conversation, er
 	return items, nil
 }
 
+// UpdateConversation stores the changes to a conversation read from the
+// repository. Messages are only ever added: the ones after those read are
+// appended, so messages added in the meantime, e.g. by AppendMessage, are kept.
 func (r *Repository) UpdateConversation(ctx context.Context, c *Conversation) error {
-	_, err := r.conn.Collection(conversationCollection).UpdateOne(ctx,
-		map[string]any{"_id": c.ID},
-		map[string]any{"$set": c})
+	sealed, err := r.sealConversation(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := bson.Marshal(sealed)
+	if err != nil {
+		return err
+	}
+	var set bson.M
+	if err := bson.Unmarshal(data, &set); err != nil {
+		return err
+	}
+	delete(set, "_id")
+	delete(set, "messages")
+
+	update := bson.M{"$set": set}
+	if added := sealed.Messages[min(c.stored, len(sealed.Messages)):]; len(added) > 0 {
+		update["$push"] = bson.M{"messages": bson.M{"$each": added}}
+	}
+	if c.Pending == nil {
+		// A reply that went on is no longer waiting on tool calls
+		update["$unset"] = bson.M{"pending": ""}
+	}
+
+	_, err = r.conn.Collection(conversationCollection).UpdateOne(ctx,
+		bson.M{"_id": c.ID, "user_id": ownedBy(c.UserID)},
+		update)
 
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return twirp.NotFoundError("conversation not found")
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	c.stored = len(c.Messages)
+	return nil
+}
+
+// DecideToolCall records the user's decision on a call of a conversation's
+// pending reply and reads the pending reply back into c. Only a call still
+// waiting can be decided, so concurrent confirmations record one decision.
+func (r *Repository) DecideToolCall(ctx context.Context, c *Conversation, id string, status ToolCallStatus) error {
+	if c.Pending == nil || len(c.Pending.Steps) == 0 {
+		return twirp.NewError(twirp.FailedPrecondition, "no tool call is waiting for confirmation")
+	}
+
+	step := fmt.Sprintf("pending.steps.%d", len(c.Pending.Steps)-1)
+	filter := bson.M{
+		"_id":     c.ID,
+		"user_id": ownedBy(c.UserID),
+		step:      bson.M{"$elemMatch": bson.M{"id": id, "status": ToolCallPending}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetArrayFilters(options.ArrayFilters{Filters: bson.A{bson.M{"call.id": id}}}).
+		SetProjection(bson.M{"pending": 1}).
+		SetReturnDocument(options.After)
+
+	var stored Conversation
+	err := r.conn.Collection(conversationCollection).
+		FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{step + ".$[call].status": status}}, opts).
+		Decode(&stored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return twirp.NewError(twirp.FailedPrecondition, "the tool call was already decided")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.openConversation(&stored); err != nil {
+		return err
+	}
+
+	c.Pending = stored.Pending
+	return nil
+}
+
+// ClaimPendingReply leases a conversation's pending reply to the caller
+// resuming it, so that concurrent or repeated confirmations run its tool calls
+// once. Storing the conversation releases the lease; a lease that expired, e.g.
+// because the server stopped while resuming, can be claimed again.
+func (r *Repository) ClaimPendingReply(ctx context.Context, c *Conversation, now time.Time, lease time.Duration) error {
+	filter := bson.M{
+		"_id":     c.ID,
+		"user_id": ownedBy(c.UserID),
+		"pending": bson.M{"$ne": nil},
+		"$or": bson.A{
+			bson.M{"pending.lease_until": nil},
+			bson.M{"pending.lease_until": bson.M{"$lte": now}},
+		},
+	}
+
+	res, err := r.conn.Collection(conversationCollection).UpdateOne(ctx, filter,
+		bson.M{"$set": bson.M{"pending.lease_until": now.Add(lease)}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return twirp.NewError(twirp.FailedPrecondition, "the reply is already being resumed")
+	}
+
+	return nil
 }
 
 func (r *Repository) DeleteConversation(ctx context.Context, id string) error {
@@ -104,3 +265,791 @@ func (r *Repository) DeleteConversation(ctx context.Context, id string) error {
 
 	return err
 }
+
+func (r *Repository) CreateNote(ctx context.Context, n *Note) error {
+	sealed := *n
+	if err := r.seal(&sealed.Title, &sealed.Content); err != nil {
+		return err
+	}
+
+	_, err := r.conn.Collection(noteCollection).InsertOne(ctx, sealed)
+	return err
+}
+
+// ListNotes returns a user's notes, most recently updated first.
+// A query keeps notes whose title or content contains it, ignoring case.
+func (r *Repository) ListNotes(ctx context.Context, userID, query string) ([]*Note, error) {
+	filter := bson.M{"user_id": userID}
+	if query != "" {
+		filter["$or"] = bson.A{
+			bson.M{"title": containsIgnoringCase(query)},
+			bson.M{"content": containsIgnoringCase(query)},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetLimit(listLimit)
+
+	cursor, err := r.conn.Collection(noteCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Note
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, n := range items {
+		if err := r.open(&n.Title, &n.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+func (r *Repository) DeleteNote(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("note not found")
+	}
+
+	res, err := r.conn.Collection(noteCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("note not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateTodo(ctx context.Context, t *Todo) error {
+	sealed := *t
+	if err := r.seal(&sealed.Text); err != nil {
+		return err
+	}
+
+	_, err := r.conn.Collection(todoCollection).InsertOne(ctx, sealed)
+	return err
+}
+
+// ListTodos returns a user's to-do items, open items first, then most recent first
+func (r *Repository) ListTodos(ctx context.Context, userID string, f TodoFilter) ([]*Todo, error) {
+	filter := bson.M{"user_id": userID}
+	if f.Query != "" {
+		filter["text"] = containsIgnoringCase(f.Query)
+	}
+	if !f.IncludeCompleted {
+		filter["done"] = false
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "done", Value: 1}, {Key: "created_at", Value: -1}}).
+		SetLimit(listLimit)
+
+	cursor, err := r.conn.Collection(todoCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Todo
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, t := range items {
+		if err := r.open(&t.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// CompleteTodo marks a user's to-do item as done and returns it.
+// Completing an item twice keeps the first completion time.
+func (r *Repository) CompleteTodo(ctx context.Context, userID, id string) (*Todo, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, twirp.NotFoundError("todo not found")
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": oid, "user_id": userID}
+	update := bson.A{bson.M{"$set": bson.M{
+		"done":         true,
+		"updated_at":   now,
+		"completed_at": bson.M{"$ifNull": bson.A{"$completed_at", now}},
+	}}}
+
+	var t Todo
+	err = r.conn.Collection(todoCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&t)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, twirp.NotFoundError("todo not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.open(&t.Text); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *Repository) DeleteTodo(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("todo not found")
+	}
+
+	res, err := r.conn.Collection(todoCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("todo not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, e *Event) error {
+	sealed := *e
+	if err := r.seal(&sealed.Title, &sealed.Description, &sealed.Location); err != nil {
+		return err
+	}
+
+	_, err := r.conn.Collection(eventCollection).InsertOne(ctx, sealed)
+	return err
+}
+
+// ListEvents returns a user's calendar events, earliest first
+func (r *Repository) ListEvents(ctx context.Context, userID string, f EventFilter) ([]*Event, error) {
+	filter := bson.M{"user_id": userID}
+	if !f.From.IsZero() {
+		filter["end"] = bson.M{"$gt": f.From}
+	}
+	if !f.To.IsZero() {
+		filter["start"] = bson.M{"$lt": f.To}
+	}
+	if f.Query != "" {
+		q := containsIgnoringCase(f.Query)
+		filter["$or"] = bson.A{bson.M{"title": q}, bson.M{"description": q}, bson.M{"location": q}}
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = listLimit
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.conn.Collection(eventCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Event
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, e := range items {
+		if err := r.open(&e.Title, &e.Description, &e.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// ImportEvent adds an event to a user's calendar, or updates the event with the
+// same UID, and reports whether it was created. On return e holds the stored event.
+func (r *Repository) ImportEvent(ctx context.Context, e *Event) (bool, error) {
+	now := time.Now()
+	id := primitive.NewObjectID()
+
+	sealed := *e
+	if err := r.seal(&sealed.Title, &sealed.Description, &sealed.Location); err != nil {
+		return false, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"title":       sealed.Title,
+			"description": sealed.Description,
+			"location":    sealed.Location,
+			"start":       e.Start,
+			"end":         e.End,
+			"all_day":     e.AllDay,
+			"time_zone":   e.TimeZone,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{"_id": id, "created_at": now},
+	}
+
+	err := r.conn.Collection(eventCollection).FindOneAndUpdate(ctx,
+		bson.M{"user_id": e.UserID, "uid": e.UID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(e)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.open(&e.Title, &e.Description, &e.Location); err != nil {
+		return false, err
+	}
+
+	return e.ID == id, nil
+}
+
+func (r *Repository) DeleteEvent(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("event not found")
+	}
+
+	res, err := r.conn.Collection(eventCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("event not found")
+	}
+
+	return nil
+}
+
+// SaveMemory stores a fact about a user. A fact with the same key is kept as it
+// was learned first, only its update time changes.
+func (r *Repository) SaveMemory(ctx context.Context, m *Memory) error {
+	now := time.Now()
+	if m.Key == "" {
+		m.Key = MemoryKey(m.Text)
+	}
+	key := m.Key
+
+	text := m.Text
+	if err := r.seal(&text); err != nil {
+		return err
+	}
+
+	insert := bson.M{"_id": primitive.NewObjectID(), "text": text, "created_at": now}
+	if !m.ConversationID.IsZero() {
+		insert["conversation_id"] = m.ConversationID
+	}
+
+	err := r.conn.Collection(memoryCollection).FindOneAndUpdate(ctx,
+		bson.M{"user_id": m.UserID, "key": r.memoryKey(key)},
+		bson.M{"$set": bson.M{"updated_at": now}, "$setOnInsert": insert},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(m)
+	if err != nil {
+		return err
+	}
+
+	m.Key = key
+	return r.open(&m.Text)
+}
+
+// ListMemories returns what is remembered about a user, most recently learned first
+func (r *Repository) ListMemories(ctx context.Context, userID string) ([]*Memory, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(listLimit)
+
+	cursor, err := r.conn.Collection(memoryCollection).Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Memory
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	// Sealed facts are stored under a digest, give them back their key
+	for _, m := range items {
+		if err := r.open(&m.Text); err != nil {
+			return nil, err
+		}
+		if r.sealer != nil {
+			m.Key = MemoryKey(m.Text)
+		}
+	}
+
+	return items, nil
+}
+
+func (r *Repository) DeleteMemory(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("memory not found")
+	}
+
+	res, err := r.conn.Collection(memoryCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("memory not found")
+	}
+
+	return nil
+}
+
+// CreateAttachment stores an uploaded file with the chunks of its text
+func (r *Repository) CreateAttachment(ctx context.Context, a *Attachment, chunks []*AttachmentChunk) error {
+	sealed := *a
+	if err := r.seal(&sealed.Filename); err != nil {
+		return err
+	}
+
+	docs := make([]any, len(chunks))
+	for i, c := range chunks {
+		chunk := *c
+		if err := r.seal(&chunk.Text); err != nil {
+			return err
+		}
+		docs[i] = chunk
+	}
+
+	if _, err := r.conn.Collection(attachmentCollection).InsertOne(ctx, sealed); err != nil {
+		return err
+	}
+
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	if _, err := r.conn.Collection(chunkCollection).InsertMany(ctx, docs); err != nil {
+		// Without its chunks the file can't be searched, so don't keep it
+		_, _ = r.conn.Collection(attachmentCollection).DeleteOne(ctx, bson.M{"_id": a.ID})
+		_, _ = r.conn.Collection(chunkCollection).DeleteMany(ctx, bson.M{"attachment_id": a.ID})
+		return err
+	}
+
+	return nil
+}
+
+// BindAttachments marks a user's attachments as sent in a conversation and
+// returns them, without their data. Attachments already sent in another
+// conversation can't be sent again, unless that conversation was never stored.
+func (r *Repository) BindAttachments(ctx context.Context, userID string, ids []string, conversationID primitive.ObjectID) ([]*Attachment, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, twirp.NotFoundError("attachment not found")
+		}
+		if !slices.Contains(oids, oid) {
+			oids = append(oids, oid)
+		}
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": oids}, "user_id": userID}
+	cursor, err := r.conn.Collection(attachmentCollection).Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"data": 0}))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Attachment
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, a := range items {
+		if err := r.open(&a.Filename); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(items) != len(oids) {
+		return nil, twirp.NotFoundError("attachment not found")
+	}
+
+	// Files are bound before the conversation is stored, so a conversation whose
+	// first reply failed was never created and its files can be sent again
+	for _, a := range items {
+		if a.ConversationID.IsZero() || a.ConversationID == conversationID {
+			continue
+		}
+		n, err := r.conn.Collection(conversationCollection).CountDocuments(ctx,
+			bson.M{"_id": a.ConversationID}, options.Count().SetLimit(1))
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, twirp.NewError(twirp.FailedPrecondition, "attachment "+a.ID.Hex()+" was sent in another conversation")
+		}
+	}
+
+	for _, a := range items {
+		a.ConversationID = conversationID
+	}
+
+	_, err = r.conn.Collection(attachmentCollection).UpdateMany(ctx, filter,
+		bson.M{"$set": bson.M{"conversation_id": conversationID}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the order they were sent in
+	slices.SortFunc(items, func(a, b *Attachment) int {
+		return slices.Index(oids, a.ID) - slices.Index(oids, b.ID)
+	})
+
+	return items, nil
+}
+
+// ListConversationAttachments returns the files sent in a conversation by a
+// user, oldest first, without their data
+func (r *Repository) ListConversationAttachments(ctx context.Context, userID string, conversationID primitive.ObjectID) ([]*Attachment, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"data": 0}).
+		SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.conn.Collection(attachmentCollection).Find(ctx,
+		bson.M{"user_id": userID, "conversation_id": conversationID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Attachment
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, a := range items {
+		if err := r.open(&a.Filename); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// ListAttachmentChunks returns the chunks of the given attachments, in order
+func (r *Repository) ListAttachmentChunks(ctx context.Context, attachmentIDs []primitive.ObjectID) ([]*AttachmentChunk, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "attachment_id", Value: 1}, {Key: "index", Value: 1}})
+
+	cursor, err := r.conn.Collection(chunkCollection).Find(ctx,
+		bson.M{"attachment_id": bson.M{"$in": attachmentIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*AttachmentChunk
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, c := range items {
+		if err := r.open(&c.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+func (r *Repository) DeleteAttachment(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("attachment not found")
+	}
+
+	res, err := r.conn.Collection(attachmentCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("attachment not found")
+	}
+
+	_, err = r.conn.Collection(chunkCollection).DeleteMany(ctx, bson.M{"attachment_id": oid})
+	return err
+}
+
+// AppendMessage adds a message to the end of a conversation. Appending a message
+// whose ID is already in the conversation does nothing, so retries are safe.
+func (r *Repository) AppendMessage(ctx context.Context, conversationID primitive.ObjectID, m *Message) error {
+	coll := r.conn.Collection(conversationCollection)
+
+	sealed, err := r.sealMessage(m)
+	if err != nil {
+		return err
+	}
+
+	res, err := coll.UpdateOne(ctx,
+		bson.M{"_id": conversationID, "messages._id": bson.M{"$ne": m.ID}},
+		bson.M{
+			"$push": bson.M{"messages": sealed},
+			"$set":  bson.M{"updated_at": m.CreatedAt},
+		})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	n, err := coll.CountDocuments(ctx, bson.M{"_id": conversationID})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return twirp.NotFoundError("conversation not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateReminder(ctx context.Context, rem *Reminder) error {
+	sealed := *rem
+	if err := r.seal(&sealed.Text); err != nil {
+		return err
+	}
+
+	_, err := r.conn.Collection(reminderCollection).InsertOne(ctx, sealed)
+	return err
+}
+
+// ListReminders returns a user's reminders, soonest first. Delivered, cancelled and
+// failed reminders are only included when asked for.
+func (r *Repository) ListReminders(ctx context.Context, userID string, includeFinished bool) ([]*Reminder, error) {
+	filter := bson.M{"user_id": userID}
+	if !includeFinished {
+		filter["status"] = ReminderPending
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "due_at", Value: 1}}).
+		SetLimit(listLimit)
+
+	cursor, err := r.conn.Collection(reminderCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Reminder
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	for _, rem := range items {
+		if err := r.open(&rem.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// CancelReminder cancels a user's pending reminder and returns it
+func (r *Repository) CancelReminder(ctx context.Context, userID, id string) (*Reminder, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, twirp.NotFoundError("reminder not found")
+	}
+
+	coll := r.conn.Collection(reminderCollection)
+	filter := bson.M{"_id": oid, "user_id": userID}
+
+	var rem Reminder
+	err = coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": oid, "user_id": userID, "status": ReminderPending},
+		bson.M{"$set": bson.M{"status": ReminderCancelled, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rem)
+	if err == nil {
+		if err := r.open(&rem.Text); err != nil {
+			return nil, err
+		}
+		return &rem, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// Tell a reminder that doesn't exist from one that is no longer pending
+	err = coll.FindOne(ctx, filter).Decode(&rem)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, twirp.NotFoundError("reminder not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, twirp.NewError(twirp.FailedPrecondition, "reminder is already "+string(rem.Status))
+}
+
+func (r *Repository) DeleteReminder(ctx context.Context, userID, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return twirp.NotFoundError("reminder not found")
+	}
+
+	res, err := r.conn.Collection(reminderCollection).DeleteOne(ctx, bson.M{"_id": oid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return twirp.NotFoundError("reminder not found")
+	}
+
+	return nil
+}
+
+// ClaimDueReminder leases the most overdue pending reminder for delivery and counts
+// the attempt, or returns nil when none is due. Reminders whose lease has expired,
+// e.g. because the server stopped while delivering them, can be claimed again.
+func (r *Repository) ClaimDueReminder(ctx context.Context, now time.Time, lease time.Duration) (*Reminder, error) {
+	filter := bson.M{
+		"status": ReminderPending,
+		"due_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"lease_until": nil},
+			bson.M{"lease_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"lease_until": now.Add(lease), "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "due_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var rem Reminder
+	err := r.conn.Collection(reminderCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&rem)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.open(&rem.Text); err != nil {
+		return nil, err
+	}
+
+	return &rem, nil
+}
+
+// MarkReminderChannelDelivered records that a delivery channel has delivered a reminder
+func (r *Repository) MarkReminderChannelDelivered(ctx context.Context, id primitive.ObjectID, channel string) error {
+	_, err := r.conn.Collection(reminderCollection).UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$addToSet": bson.M{"delivered": channel}})
+	return err
+}
+
+// DeferReminder keeps a reminder pending but unclaimable until the given time
+func (r *Repository) DeferReminder(ctx context.Context, id primitive.ObjectID, until time.Time, lastError string) error {
+	_, err := r.conn.Collection(reminderCollection).UpdateOne(ctx,
+		bson.M{"_id": id, "status": ReminderPending},
+		bson.M{"$set": bson.M{"lease_until": until, "last_error": lastError, "updated_at": time.Now()}})
+	return err
+}
+
+// FinishReminder moves a pending reminder to a final status. Reminders cancelled
+// while being delivered stay cancelled.
+func (r *Repository) FinishReminder(ctx context.Context, id primitive.ObjectID, status ReminderStatus, lastError string, at time.Time) error {
+	set := bson.M{"status": status, "last_error": lastError, "updated_at": at}
+	if status == ReminderDelivered {
+		set["delivered_at"] = at
+	}
+
+	_, err := r.conn.Collection(reminderCollection).UpdateOne(ctx,
+		bson.M{"_id": id, "status": ReminderPending},
+		bson.M{"$set": set, "$unset": bson.M{"lease_until": ""}})
+	return err
+}
+
+// containsIgnoringCase matches strings containing s literally, ignoring case
+func containsIgnoringCase(s string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(s), "$options": "i"}
+}
+
+// SavePersona adds a persona, replacing the one with the same name
+func (r *Repository) SavePersona(ctx context.Context, p *Persona) error {
+	if err := p.Validate(); err != nil {
+		return twirp.InvalidArgumentError("persona", err.Error())
+	}
+
+	_, err := r.conn.Collection(personaCollection).ReplaceOne(ctx, bson.M{"_id": p.Name}, p, options.Replace().SetUpsert(true))
+	return err
+}
+
+// ListPersonas returns the personas defined in the database, by name
+func (r *Repository) ListPersonas(ctx context.Context) ([]*Persona, error) {
+	cursor, err := r.conn.Collection(personaCollection).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*Persona
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindPersona returns the persona defined in the database with the given name, nil if there is none
+func (r *Repository) FindPersona(ctx context.Context, name string) (*Persona, error) {
+	var p Persona
+	err := r.conn.Collection(personaCollection).FindOne(ctx, bson.M{"_id": name}).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// UserToolPolicy returns the tool policy of a user, an empty one if they have none
+func (r *Repository) UserToolPolicy(ctx context.Context, userID string) (ToolPolicy, error) {
+	var p ToolPolicy
+	err := r.conn.Collection(toolPolicyCollection).FindOne(ctx, bson.M{"_id": userID}).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ToolPolicy{}, nil
+	}
+
+	return p, err
+}
+
+// SaveUserToolPolicy replaces the tool policy of a user
+func (r *Repository) SaveUserToolPolicy(ctx context.Context, userID string, p ToolPolicy) error {
+	_, err := r.conn.Collection(toolPolicyCollection).ReplaceOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"allow": p.Allow, "deny": p.Deny, "confirm": p.Confirm, "updated_at": time.Now()},
+		options.Replace().SetUpsert(true))
+	return err
+}
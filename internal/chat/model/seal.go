@@ -0,0 +1,177 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Sealer protects the personal data of stored conversations, notes, to-do
+// items, events, memories, reminders and attachments, e.g. by redacting or
+// encrypting it. Seal runs on texts being written and Open on texts read back.
+type Sealer interface {
+	Seal(text string) (string, error)
+	Open(text string) (string, error)
+}
+
+// Option configures a Repository
+type Option func(*Repository)
+
+// WithSealer seals the texts the repository stores: conversation titles,
+// messages and pending tool calls, notes, to-do items, events, memories,
+// reminders, attachment filenames and the text of their chunks. The raw data
+// of attachments is stored as it was uploaded.
+func WithSealer(s Sealer) Option {
+	return func(r *Repository) {
+		r.sealer = s
+	}
+}
+
+// sealConversation returns a copy of c to store, with its texts sealed
+func (r *Repository) sealConversation(c *Conversation) (*Conversation, error) {
+	if r.sealer == nil {
+		return c, nil
+	}
+
+	sealed := *c
+	var err error
+	if sealed.Title, err = r.sealer.Seal(c.Title); err != nil {
+		return nil, fmt.Errorf("failed to seal conversation: %w", err)
+	}
+
+	sealed.Messages = make([]*Message, len(c.Messages))
+	for i, m := range c.Messages {
+		if sealed.Messages[i], err = r.sealMessage(m); err != nil {
+			return nil, err
+		}
+	}
+
+	if sealed.Pending, err = r.sealPending(c.Pending); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// sealPending returns a copy of p to store, with the arguments and results of
+// its tool calls sealed
+func (r *Repository) sealPending(p *PendingReply) (*PendingReply, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	sealed := *p
+	sealed.Steps = make([][]*ToolCall, len(p.Steps))
+	for i, step := range p.Steps {
+		sealed.Steps[i] = make([]*ToolCall, len(step))
+		for j, call := range step {
+			c := *call
+			var err error
+			if c.Arguments, err = r.sealer.Seal(call.Arguments); err != nil {
+				return nil, fmt.Errorf("failed to seal tool call: %w", err)
+			}
+			if c.Result, err = r.sealer.Seal(call.Result); err != nil {
+				return nil, fmt.Errorf("failed to seal tool call: %w", err)
+			}
+			sealed.Steps[i][j] = &c
+		}
+	}
+	return &sealed, nil
+}
+
+// sealMessage returns a copy of m to store, with its texts sealed
+func (r *Repository) sealMessage(m *Message) (*Message, error) {
+	if r.sealer == nil {
+		return m, nil
+	}
+
+	sealed := *m
+	var err error
+	if sealed.Content, err = r.sealer.Seal(m.Content); err != nil {
+		return nil, fmt.Errorf("failed to seal message: %w", err)
+	}
+	if sealed.Structured, err = r.sealer.Seal(m.Structured); err != nil {
+		return nil, fmt.Errorf("failed to seal message: %w", err)
+	}
+	return &sealed, nil
+}
+
+// openConversation reads back the texts of a stored conversation, in place
+func (r *Repository) openConversation(c *Conversation) error {
+	if r.sealer == nil {
+		return nil
+	}
+
+	var err error
+	if c.Title, err = r.sealer.Open(c.Title); err != nil {
+		return fmt.Errorf("failed to open conversation: %w", err)
+	}
+	for _, m := range c.Messages {
+		if m.Content, err = r.sealer.Open(m.Content); err != nil {
+			return fmt.Errorf("failed to open message: %w", err)
+		}
+		if m.Structured, err = r.sealer.Open(m.Structured); err != nil {
+			return fmt.Errorf("failed to open message: %w", err)
+		}
+	}
+	return r.openPending(c.Pending)
+}
+
+// openPending reads back the tool calls of a stored pending reply, in place
+func (r *Repository) openPending(p *PendingReply) error {
+	if r.sealer == nil || p == nil {
+		return nil
+	}
+
+	var err error
+	for _, step := range p.Steps {
+		for _, call := range step {
+			if call.Arguments, err = r.sealer.Open(call.Arguments); err != nil {
+				return fmt.Errorf("failed to open tool call: %w", err)
+			}
+			if call.Result, err = r.sealer.Open(call.Result); err != nil {
+				return fmt.Errorf("failed to open tool call: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// seal seals texts in place, for the copies of items about to be stored
+func (r *Repository) seal(texts ...*string) error {
+	if r.sealer == nil {
+		return nil
+	}
+
+	var err error
+	for _, t := range texts {
+		if *t, err = r.sealer.Seal(*t); err != nil {
+			return fmt.Errorf("failed to seal personal data: %w", err)
+		}
+	}
+	return nil
+}
+
+// open reads back stored texts in place
+func (r *Repository) open(texts ...*string) error {
+	if r.sealer == nil {
+		return nil
+	}
+
+	var err error
+	for _, t := range texts {
+		if *t, err = r.sealer.Open(*t); err != nil {
+			return fmt.Errorf("failed to open personal data: %w", err)
+		}
+	}
+	return nil
+}
+
+// memoryKey returns the key a memory is stored under. With a sealer, it is a
+// digest of the key, which is the fact's plain text otherwise.
+func (r *Repository) memoryKey(key string) string {
+	if r.sealer == nil {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Todo is a to-do item of a user, reachable from all of their conversations
+type Todo struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	UserID      string             `bson:"user_id"`
+	Text        string             `bson:"text"`
+	Done        bool               `bson:"done"`
+	Due         *time.Time         `bson:"due,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+}
+
+// TodoFilter selects to-do items to list
+type TodoFilter struct {
+	// Query matches items whose text contains it, ignoring case
+	Query string
+
+	// IncludeCompleted also lists items that are done
+	IncludeCompleted bool
+}
+
+func (t *Todo) Proto() *pb.Todo {
+	proto := &pb.Todo{
+		Id:        t.ID.Hex(),
+		Text:      t.Text,
+		Done:      t.Done,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+	if t.Due != nil {
+		proto.Due = timestamppb.New(*t.Due)
+	}
+	if t.CompletedAt != nil {
+		proto.CompletedAt = timestamppb.New(*t.CompletedAt)
+	}
+	return proto
+}
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+)
+
+// ErrConfirmationRequired is returned by a reply that stopped on tool calls
+// the user has to approve; the conversation's Pending holds them
+var ErrConfirmationRequired = errors.New("tool calls are waiting for the user's confirmation")
+
+// ToolPolicy restricts the tools the assistant may use. Policies are set for
+// the deployment, the user, the persona and the conversation: a tool is
+// available when every policy allows it, and waits for the user's approval
+// when any policy asks to confirm it.
+type ToolPolicy struct {
+	// Allow lists the tools that may be used, all of them when empty
+	Allow []string `bson:"allow,omitempty"`
+
+	// Deny lists tools that may not be used, even when allowed
+	Deny []string `bson:"deny,omitempty"`
+
+	// Confirm lists tools whose calls wait for the user's approval
+	Confirm []string `bson:"confirm,omitempty"`
+}
+
+// IsZero reports whether the policy restricts nothing, which also keeps empty
+// policies out of stored documents
+func (p ToolPolicy) IsZero() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0 && len(p.Confirm) == 0
+}
+
+// Allows reports whether the policy lets the named tool be used
+func (p ToolPolicy) Allows(name string) bool {
+	if slices.Contains(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, name)
+}
+
+// Confirms reports whether calls to the named tool wait for the user's approval
+func (p ToolPolicy) Confirms(name string) bool {
+	return slices.Contains(p.Confirm, name)
+}
+
+// ToolPolicies are the policies that apply together to one reply
+type ToolPolicies []ToolPolicy
+
+// Allows reports whether every policy lets the named tool be used
+func (ps ToolPolicies) Allows(name string) bool {
+	for _, p := range ps {
+		if !p.Allows(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Confirms reports whether any policy makes calls to the named tool wait for
+// the user's approval
+func (ps ToolPolicies) Confirms(name string) bool {
+	for _, p := range ps {
+		if p.Confirms(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolPolicyFromProto converts a policy from the API, dropping blank and
+// repeated names
+func ToolPolicyFromProto(p *pb.ToolPolicy) ToolPolicy {
+	return ToolPolicy{
+		Allow:   toolNames(p.GetAllow()),
+		Deny:    toolNames(p.GetDeny()),
+		Confirm: toolNames(p.GetConfirm()),
+	}
+}
+
+func (p ToolPolicy) Proto() *pb.ToolPolicy {
+	return &pb.ToolPolicy{
+		Allow:   p.Allow,
+		Deny:    p.Deny,
+		Confirm: p.Confirm,
+	}
+}
+
+// ParseToolNames splits a comma-separated list of tool names, as used in
+// environment variables
+func ParseToolNames(list string) []string {
+	return toolNames(strings.Split(list, ","))
+}
+
+func toolNames(names []string) []string {
+	var out []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// ToolCallStatus tracks a tool call through the user's confirmation
+type ToolCallStatus string
+
+const (
+	// ToolCallPending calls wait for the user to approve or decline them
+	ToolCallPending ToolCallStatus = "pending"
+	// ToolCallApproved calls run when the reply resumes
+	ToolCallApproved ToolCallStatus = "approved"
+	// ToolCallDeclined calls are reported to the model as declined when the reply resumes
+	ToolCallDeclined ToolCallStatus = "declined"
+	// ToolCallDone calls have a result
+	ToolCallDone ToolCallStatus = "done"
+)
+
+// ToolCall is a call the model made to a tool while replying
+type ToolCall struct {
+	ID        string         `bson:"id"`
+	Name      string         `bson:"name"`
+	Arguments string         `bson:"arguments"`
+	Status    ToolCallStatus `bson:"status"`
+	Result    string         `bson:"result,omitempty"`
+}
+
+func (c *ToolCall) Proto() *pb.ToolCall {
+	return &pb.ToolCall{
+		Id:        c.ID,
+		Name:      c.Name,
+		Arguments: c.Arguments,
+	}
+}
+
+// PendingReply is a reply stopped until the user approves or declines some
+// of its tool calls
+type PendingReply struct {
+	// Steps are the rounds of tool calls the model made so far, in order; the
+	// calls waiting for the user are in the last one
+	Steps     [][]*ToolCall `bson:"steps"`
+	CreatedAt time.Time     `bson:"created_at"`
+
+	// LeaseUntil is set while a confirmation resumes the reply, see ClaimPendingReply
+	LeaseUntil *time.Time `bson:"lease_until,omitempty"`
+}
+
+// Waiting returns the calls still waiting for the user's decision
+func (p *PendingReply) Waiting() []*ToolCall {
+	if p == nil || len(p.Steps) == 0 {
+		return nil
+	}
+
+	var waiting []*ToolCall
+	for _, c := range p.Steps[len(p.Steps)-1] {
+		if c.Status == ToolCallPending {
+			waiting = append(waiting, c)
+		}
+	}
+	return waiting
+}
+
+// Call returns the call of the last step with the given ID, or nil
+func (p *PendingReply) Call(id string) *ToolCall {
+	if p == nil || len(p.Steps) == 0 {
+		return nil
+	}
+
+	for _, c := range p.Steps[len(p.Steps)-1] {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// PendingToolCallsProto returns the calls the conversation's reply waits on
+func (c *Conversation) PendingToolCallsProto() []*pb.ToolCall {
+	var calls []*pb.ToolCall
+	for _, call := range c.Pending.Waiting() {
+		calls = append(calls, call.Proto())
+	}
+	return calls
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
+)
+
+// UnitsFromProto converts the API unit preference, an unspecified preference yields ""
+func UnitsFromProto(u pb.Units) units.System {
+	switch u {
+	case pb.Units_METRIC:
+		return units.Metric
+	case pb.Units_IMPERIAL:
+		return units.Imperial
+	case pb.Units_BOTH:
+		return units.Both
+	default:
+		return ""
+	}
+}
+
+func unitsProto(s units.System) pb.Units {
+	switch s.OrDefault() {
+	case units.Metric:
+		return pb.Units_METRIC
+	case units.Imperial:
+		return pb.Units_IMPERIAL
+	default:
+		return pb.Units_BOTH
+	}
+}
@@ -2,11 +2,17 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/calendar"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/schema"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
 	"github.com/isabermoussa/personal-assistant-API/internal/pb"
 	"github.com/twitchtv/twirp"
@@ -15,11 +21,60 @@ import (
 
 var _ pb.ChatService = (*Server)(nil)
 
+const (
+	// maxImportSize bounds the iCalendar text ImportCalendar accepts, in bytes
+	maxImportSize = 1 << 20
+
+	// maxExportEvents bounds how many events ExportCalendar returns
+	maxExportEvents = 5000
+
+	// maxAttachmentSize bounds the files UploadAttachment accepts, in bytes
+	maxAttachmentSize = 10 << 20
+
+	// afterTurnTimeout bounds the work learning from one turn, which runs after the response
+	afterTurnTimeout = time.Minute
+
+	// resumeLease reserves a confirmed reply for the confirmation resuming it
+	resumeLease = 5 * time.Minute
+)
+
 type Assistant interface {
 	Title(ctx context.Context, conv *model.Conversation) (string, error)
 	Reply(ctx context.Context, conv *model.Conversation) (string, error)
 }
 
+// Memorizer learns about users from their conversations
+type Memorizer interface {
+	Remember(ctx context.Context, conv *model.Conversation) error
+}
+
+// AttachmentIndexer makes uploaded files searchable by the assistant
+type AttachmentIndexer interface {
+	AddAttachment(ctx context.Context, a *model.Attachment) error
+}
+
+// PersonaCatalog resolves the personas conversations can be started with
+type PersonaCatalog interface {
+	Persona(ctx context.Context, name string) (*model.Persona, error)
+	Personas(ctx context.Context) ([]*model.Persona, error)
+}
+
+// StructuredReplier also returns replies as JSON documents matching the
+// response schema of the message replied to
+type StructuredReplier interface {
+	StructuredReply(ctx context.Context, conv *model.Conversation) (reply, structured string, err error)
+}
+
+// Guardrail screens user messages before they are stored and replied to
+type Guardrail interface {
+	CheckMessage(ctx context.Context, text string) error
+}
+
+// Indexer makes conversations searchable from later ones
+type Indexer interface {
+	IndexConversation(ctx context.Context, conv *model.Conversation) error
+}
+
 type Server struct {
 	repo   *model.Repository
 	assist Assistant
@@ -32,9 +87,12 @@ func NewServer(repo *model.Repository, assist Assistant) *Server {
 func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversationRequest) (*pb.StartConversationResponse, error) {
 	conversation := &model.Conversation{
 		ID:        primitive.NewObjectID(),
+		UserID:    auth.FromContext(ctx),
 		Title:     "Untitled conversation",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Units:     model.UnitsFromProto(req.GetUnits()),
+		Tools:     model.ToolPolicyFromProto(req.GetTools()),
 		Messages: []*model.Message{{
 			ID:        primitive.NewObjectID(),
 			Role:      model.RoleUser,
@@ -48,13 +106,34 @@ func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversatio
 		return nil, twirp.RequiredArgumentError("message")
 	}
 
+	if err := s.checkMessage(ctx, req.GetMessage()); err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.GetPersona()); name != "" {
+		persona, err := s.persona(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		conversation.Persona = persona.Name
+	}
+
+	if err := s.responseSchema(conversation.Messages[0], req.GetResponseSchema()); err != nil {
+		return nil, err
+	}
+
+	if err := s.attach(ctx, conversation.ID, conversation.Messages[0], req.GetAttachmentIds()); err != nil {
+		return nil, err
+	}
+
 	// Generate title and reply concurrently for better performance
 	var (
-		title    string
-		titleErr error
-		reply    string
-		replyErr error
-		wg       sync.WaitGroup
+		title      string
+		titleErr   error
+		reply      string
+		structured string
+		replyErr   error
+		wg         sync.WaitGroup
 	)
 
 	wg.Add(2)
@@ -71,15 +150,17 @@ func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversatio
 	// Generate reply in background (critical)
 	go func() {
 		defer wg.Done()
-		reply, replyErr = s.assist.Reply(ctx, conversation)
+		reply, structured, replyErr = s.generate(ctx, conversation)
 	}()
 
 	// Wait for both operations to complete
 	wg.Wait()
 
-	// Check if reply generation failed (critical error)
-	if replyErr != nil {
-		return nil, replyErr
+	// A reply waiting for the user to confirm tool calls is stored without an
+	// answer; any other failure is critical
+	paused := errors.Is(replyErr, model.ErrConfirmationRequired)
+	if replyErr != nil && !paused {
+		return nil, replyFailed(replyErr)
 	}
 
 	// Use generated title if successful, otherwise keep default
@@ -87,22 +168,24 @@ func (s *Server) StartConversation(ctx context.Context, req *pb.StartConversatio
 		conversation.Title = title
 	}
 
-	conversation.Messages = append(conversation.Messages, &model.Message{
-		ID:        primitive.NewObjectID(),
-		Role:      model.RoleAssistant,
-		Content:   reply,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	})
+	if !paused {
+		appendReply(conversation, reply, structured)
+	}
 
 	if err := s.repo.CreateConversation(ctx, conversation); err != nil {
 		return nil, err
 	}
 
+	if !paused {
+		s.afterTurn(ctx, conversation)
+	}
+
 	return &pb.StartConversationResponse{
-		ConversationId: conversation.ID.Hex(),
-		Title:          conversation.Title,
-		Reply:          reply,
+		ConversationId:   conversation.ID.Hex(),
+		Title:            conversation.Title,
+		Reply:            reply,
+		PendingToolCalls: conversation.PendingToolCallsProto(),
+		StructuredReply:  structured,
 	}, nil
 }
 
@@ -115,42 +198,299 @@ func (s *Server) ContinueConversation(ctx context.Context, req *pb.ContinueConve
 		return nil, twirp.RequiredArgumentError("message")
 	}
 
-	conversation, err := s.repo.DescribeConversation(ctx, req.GetConversationId())
+	if err := s.checkMessage(ctx, req.GetMessage()); err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.repo.DescribeConversation(ctx, auth.FromContext(ctx), req.GetConversationId())
 	if err != nil {
 		return nil, err
 	}
 
-	conversation.UpdatedAt = time.Now()
-	conversation.Messages = append(conversation.Messages, &model.Message{
+	if conversation.Pending != nil {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "the last reply is waiting for tool calls to be confirmed or declined")
+	}
+
+	if u := model.UnitsFromProto(req.GetUnits()); u != "" {
+		conversation.Units = u
+	}
+
+	message := &model.Message{
 		ID:        primitive.NewObjectID(),
 		Role:      model.RoleUser,
 		Content:   req.GetMessage(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
-	})
+	}
+
+	if err := s.responseSchema(message, req.GetResponseSchema()); err != nil {
+		return nil, err
+	}
+
+	if err := s.attach(ctx, conversation.ID, message, req.GetAttachmentIds()); err != nil {
+		return nil, err
+	}
+
+	conversation.UpdatedAt = time.Now()
+	conversation.Messages = append(conversation.Messages, message)
+
+	reply, structured, err := s.reply(ctx, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ContinueConversationResponse{
+		Reply:            reply,
+		PendingToolCalls: conversation.PendingToolCallsProto(),
+		StructuredReply:  structured,
+	}, nil
+}
+
+// ConfirmToolCall records the user's decision on a tool call the last reply is
+// waiting on. Once no call is waiting, the reply goes on and is returned.
+func (s *Server) ConfirmToolCall(ctx context.Context, req *pb.ConfirmToolCallRequest) (*pb.ConfirmToolCallResponse, error) {
+	if req.GetConversationId() == "" {
+		return nil, twirp.RequiredArgumentError("conversation_id")
+	}
+
+	if req.GetToolCallId() == "" {
+		return nil, twirp.RequiredArgumentError("tool_call_id")
+	}
+
+	conversation, err := s.repo.DescribeConversation(ctx, auth.FromContext(ctx), req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	if conversation.Pending == nil {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "no tool call is waiting for confirmation")
+	}
+
+	call := conversation.Pending.Call(req.GetToolCallId())
+	if call == nil {
+		return nil, twirp.NotFoundError("tool call not found")
+	}
+
+	decision := model.ToolCallDeclined
+	if req.GetApprove() {
+		decision = model.ToolCallApproved
+	}
+
+	switch call.Status {
+	case model.ToolCallPending:
+		// Recorded atomically, a call is decided once whatever confirmations run concurrently
+		if err := s.repo.DecideToolCall(ctx, conversation, call.ID, decision); err != nil {
+			return nil, err
+		}
+	case decision, model.ToolCallDone:
+		// Confirming again resumes a reply that failed after the decision
+	default:
+		return nil, twirp.NewError(twirp.FailedPrecondition, "the tool call was already "+string(call.Status))
+	}
+
+	if len(conversation.Pending.Waiting()) > 0 {
+		return &pb.ConfirmToolCallResponse{PendingToolCalls: conversation.PendingToolCallsProto()}, nil
+	}
+
+	// Approved calls run as the caller, who owns the conversation since it was
+	// found for them. Only one confirmation resumes the reply, so they run once.
+	if err := s.repo.ClaimPendingReply(ctx, conversation, time.Now(), resumeLease); err != nil {
+		return nil, err
+	}
+
+	conversation.UpdatedAt = time.Now()
+
+	reply, structured, err := s.reply(ctx, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmToolCallResponse{
+		Reply:            reply,
+		PendingToolCalls: conversation.PendingToolCallsProto(),
+		StructuredReply:  structured,
+	}, nil
+}
+
+// reply generates the assistant's reply to a stored conversation and stores it.
+// A reply that stops on tool calls to confirm is stored as pending and returns
+// an empty reply.
+func (s *Server) reply(ctx context.Context, conversation *model.Conversation) (string, string, error) {
+	reply, structured, err := s.generate(ctx, conversation)
+	paused := errors.Is(err, model.ErrConfirmationRequired)
+	if err != nil && !paused {
+		// Keep the results of tool calls that ran, so they don't run again
+		if conversation.Pending != nil {
+			if err := s.repo.UpdateConversation(ctx, conversation); err != nil {
+				slog.ErrorContext(ctx, "Failed to store tool call results", "error", err)
+			}
+		}
+		return "", "", replyFailed(err)
+	}
+
+	if !paused {
+		appendReply(conversation, reply, structured)
+	}
+
+	if err := s.repo.UpdateConversation(ctx, conversation); err != nil {
+		return "", "", twirp.InternalErrorWith(err)
+	}
+
+	if !paused {
+		s.afterTurn(ctx, conversation)
+	}
+
+	return reply, structured, nil
+}
+
+// generate asks the assistant for its reply, as a JSON document too when the
+// assistant can provide one
+func (s *Server) generate(ctx context.Context, conversation *model.Conversation) (string, string, error) {
+	if r, ok := s.assist.(StructuredReplier); ok {
+		return r.StructuredReply(ctx, conversation)
+	}
 
 	reply, err := s.assist.Reply(ctx, conversation)
+	return reply, "", err
+}
+
+// replyFailed reports a failed reply with the status matching the language
+// model's failure, so clients know whether to retry, or the guardrail violated
+func replyFailed(err error) error {
+	var violation *model.PolicyViolation
+	if errors.As(err, &violation) {
+		return policyViolated(violation)
+	}
+
+	switch {
+	case errors.Is(err, model.ErrModelRateLimited):
+		return twirp.WrapError(twirp.NewError(twirp.ResourceExhausted, err.Error()), err)
+	case errors.Is(err, model.ErrModelUnavailable):
+		return twirp.WrapError(twirp.NewError(twirp.Unavailable, err.Error()), err)
+	case errors.Is(err, model.ErrModelRejected):
+		return twirp.WrapError(twirp.NewError(twirp.InvalidArgument, err.Error()), err)
+	}
+	return twirp.InternalErrorWith(err)
+}
+
+// checkMessage runs the assistant's guardrails on a user message
+func (s *Server) checkMessage(ctx context.Context, text string) error {
+	g, ok := s.assist.(Guardrail)
+	if !ok {
+		return nil
+	}
+
+	err := g.CheckMessage(ctx, text)
+	var violation *model.PolicyViolation
+	if errors.As(err, &violation) {
+		return policyViolated(violation)
+	}
 	if err != nil {
-		return nil, twirp.InternalErrorWith(err)
+		return twirp.InternalErrorWith(err)
 	}
+	return nil
+}
+
+// policyViolated reports a guardrail violation as InvalidArgument, with the
+// policy broken in the "policy" metadata
+func policyViolated(v *model.PolicyViolation) error {
+	return twirp.WrapError(twirp.NewError(twirp.InvalidArgument, v.Error()).WithMeta("policy", v.Policy), v)
+}
 
+// appendReply adds the assistant's reply to the conversation
+func appendReply(conversation *model.Conversation, reply, structured string) {
 	conversation.Messages = append(conversation.Messages, &model.Message{
-		ID:        primitive.NewObjectID(),
-		Role:      model.RoleAssistant,
-		Content:   reply,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:         primitive.NewObjectID(),
+		Role:       model.RoleAssistant,
+		Content:    reply,
+		Structured: structured,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	})
+}
 
-	if err := s.repo.UpdateConversation(ctx, conversation); err != nil {
+// responseSchema records the JSON Schema a message asks the reply to follow
+func (s *Server) responseSchema(m *model.Message, raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	if _, ok := s.assist.(StructuredReplier); !ok {
+		return twirp.InvalidArgumentError("response_schema", "structured replies are not available")
+	}
+
+	parsed, err := schema.Parse([]byte(raw))
+	if err != nil {
+		return twirp.InvalidArgumentError("response_schema", err.Error())
+	}
+
+	m.ResponseSchema = parsed.String()
+	return nil
+}
+
+// persona looks up the persona a conversation is started with
+func (s *Server) persona(ctx context.Context, name string) (*model.Persona, error) {
+	catalog, ok := s.assist.(PersonaCatalog)
+	if !ok {
+		return nil, twirp.InvalidArgumentError("persona", "personas are not available")
+	}
+
+	persona, err := catalog.Persona(ctx, name)
+	if errors.Is(err, model.ErrUnknownPersona) {
+		return nil, twirp.InvalidArgumentError("persona", err.Error())
+	}
+	if err != nil {
 		return nil, twirp.InternalErrorWith(err)
 	}
 
-	return &pb.ContinueConversationResponse{Reply: reply}, nil
+	return persona, nil
+}
+
+// attach binds uploaded files to the conversation they are sent in and records
+// them on the message
+func (s *Server) attach(ctx context.Context, conversationID primitive.ObjectID, m *model.Message, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	files, err := s.repo.BindAttachments(ctx, auth.FromContext(ctx), ids, conversationID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		m.Attachments = append(m.Attachments, f.Ref())
+	}
+
+	return nil
+}
+
+// afterTurn lets the assistant learn from the latest turn and index it in the
+// background, so the reply isn't held up by it
+func (s *Server) afterTurn(ctx context.Context, conv *model.Conversation) {
+	// The request is done by the time this runs, but its user still applies
+	ctx = context.WithoutCancel(ctx)
+
+	if m, ok := s.assist.(Memorizer); ok {
+		go background(ctx, "Failed to update user memory", conv, m.Remember)
+	}
+	if i, ok := s.assist.(Indexer); ok {
+		go background(ctx, "Failed to index conversation", conv, i.IndexConversation)
+	}
+}
+
+// background runs fn on conv with a timeout, logging its failure
+func background(ctx context.Context, msg string, conv *model.Conversation, fn func(context.Context, *model.Conversation) error) {
+	ctx, cancel := context.WithTimeout(ctx, afterTurnTimeout)
+	defer cancel()
+
+	if err := fn(ctx, conv); err != nil {
+		slog.ErrorContext(ctx, msg, "conversation_id", conv.ID, "error", err)
+	}
 }
 
 func (s *Server) ListConversations(ctx context.Context, req *pb.ListConversationsRequest) (*pb.ListConversationsResponse, error) {
-	conversations, err := s.repo.ListConversations(ctx)
+	conversations, err := s.repo.ListConversations(ctx, auth.FromContext(ctx))
 	if err != nil {
 		return nil, twirp.InternalErrorWith(err)
 	}
@@ -169,7 +509,7 @@ func (s *Server) DescribeConversation(ctx context.Context, req *pb.DescribeConve
 		return nil, twirp.RequiredArgumentError("conversation_id")
 	}
 
-	conversation, err := s.repo.DescribeConversation(ctx, req.GetConversationId())
+	conversation, err := s.repo.DescribeConversation(ctx, auth.FromContext(ctx), req.GetConversationId())
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +520,235 @@ func (s *Server) DescribeConversation(ctx context.Context, req *pb.DescribeConve
 
 	return &pb.DescribeConversationResponse{Conversation: conversation.Proto()}, nil
 }
+
+func (s *Server) ListNotes(ctx context.Context, req *pb.ListNotesRequest) (*pb.ListNotesResponse, error) {
+	notes, err := s.repo.ListNotes(ctx, auth.FromContext(ctx), strings.TrimSpace(req.GetQuery()))
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.ListNotesResponse{}
+	for _, n := range notes {
+		resp.Notes = append(resp.Notes, n.Proto())
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ListTodos(ctx context.Context, req *pb.ListTodosRequest) (*pb.ListTodosResponse, error) {
+	todos, err := s.repo.ListTodos(ctx, auth.FromContext(ctx), model.TodoFilter{
+		Query:            strings.TrimSpace(req.GetQuery()),
+		IncludeCompleted: req.GetIncludeCompleted(),
+	})
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.ListTodosResponse{}
+	for _, t := range todos {
+		resp.Todos = append(resp.Todos, t.Proto())
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ListReminders(ctx context.Context, req *pb.ListRemindersRequest) (*pb.ListRemindersResponse, error) {
+	reminders, err := s.repo.ListReminders(ctx, auth.FromContext(ctx), req.GetIncludeFinished())
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.ListRemindersResponse{}
+	for _, r := range reminders {
+		resp.Reminders = append(resp.Reminders, r.Proto())
+	}
+
+	return resp, nil
+}
+
+func (s *Server) CancelReminder(ctx context.Context, req *pb.CancelReminderRequest) (*pb.CancelReminderResponse, error) {
+	if req.GetReminderId() == "" {
+		return nil, twirp.RequiredArgumentError("reminder_id")
+	}
+
+	reminder, err := s.repo.CancelReminder(ctx, auth.FromContext(ctx), req.GetReminderId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CancelReminderResponse{Reminder: reminder.Proto()}, nil
+}
+
+func (s *Server) ExportCalendar(ctx context.Context, req *pb.ExportCalendarRequest) (*pb.ExportCalendarResponse, error) {
+	events, err := s.repo.ListEvents(ctx, auth.FromContext(ctx), model.EventFilter{Limit: maxExportEvents})
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	return &pb.ExportCalendarResponse{Ics: calendar.Export(events, "Personal assistant")}, nil
+}
+
+func (s *Server) ImportCalendar(ctx context.Context, req *pb.ImportCalendarRequest) (*pb.ImportCalendarResponse, error) {
+	switch {
+	case strings.TrimSpace(req.GetIcs()) == "":
+		return nil, twirp.RequiredArgumentError("ics")
+	case len(req.GetIcs()) > maxImportSize:
+		return nil, twirp.InvalidArgumentError("ics", "must be at most 1 MiB")
+	}
+
+	loc := time.UTC
+	if req.GetTimeZone() != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.GetTimeZone()); err != nil {
+			return nil, twirp.InvalidArgumentError("time_zone", "must be an IANA time zone such as Europe/Madrid")
+		}
+	}
+
+	events, err := calendar.Parse(strings.NewReader(req.GetIcs()), loc)
+	if err != nil {
+		return nil, twirp.InvalidArgumentError("ics", err.Error())
+	}
+
+	userID := auth.FromContext(ctx)
+	resp := &pb.ImportCalendarResponse{}
+	for _, e := range events {
+		e.UserID = userID
+		created, err := s.repo.ImportEvent(ctx, e)
+		if err != nil {
+			return nil, twirp.InternalErrorWith(err)
+		}
+		if created {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+		resp.Events = append(resp.Events, e.Proto())
+	}
+
+	slog.InfoContext(ctx, "Imported calendar", "user_id", userID, "created", resp.Created, "updated", resp.Updated)
+
+	return resp, nil
+}
+
+func (s *Server) ListMemories(ctx context.Context, req *pb.ListMemoriesRequest) (*pb.ListMemoriesResponse, error) {
+	memories, err := s.repo.ListMemories(ctx, auth.FromContext(ctx))
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	resp := &pb.ListMemoriesResponse{}
+	for _, m := range memories {
+		resp.Memories = append(resp.Memories, m.Proto())
+	}
+
+	return resp, nil
+}
+
+func (s *Server) DeleteMemory(ctx context.Context, req *pb.DeleteMemoryRequest) (*pb.DeleteMemoryResponse, error) {
+	if req.GetMemoryId() == "" {
+		return nil, twirp.RequiredArgumentError("memory_id")
+	}
+
+	if err := s.repo.DeleteMemory(ctx, auth.FromContext(ctx), req.GetMemoryId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteMemoryResponse{}, nil
+}
+
+func (s *Server) UploadAttachment(ctx context.Context, req *pb.UploadAttachmentRequest) (*pb.UploadAttachmentResponse, error) {
+	filename := filepath.Base(strings.TrimSpace(req.GetFilename()))
+	if filename == "." || filename == "/" {
+		return nil, twirp.RequiredArgumentError("filename")
+	}
+
+	if len(req.GetData()) == 0 {
+		return nil, twirp.RequiredArgumentError("data")
+	}
+
+	if len(req.GetData()) > maxAttachmentSize {
+		return nil, twirp.InvalidArgumentError("data", "must be at most 10 MiB")
+	}
+
+	contentType, err := attachments.ContentType(filename, req.GetContentType())
+	if err != nil {
+		return nil, twirp.InvalidArgumentError("content_type", err.Error())
+	}
+
+	indexer, ok := s.assist.(AttachmentIndexer)
+	if !ok {
+		return nil, twirp.NewError(twirp.Unimplemented, attachments.ErrDisabled.Error())
+	}
+
+	attachment := &model.Attachment{
+		ID:          primitive.NewObjectID(),
+		UserID:      auth.FromContext(ctx),
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        int64(len(req.GetData())),
+		Data:        req.GetData(),
+		CreatedAt:   time.Now(),
+	}
+
+	if id := req.GetConversationId(); id != "" {
+		conversation, err := s.repo.DescribeConversation(ctx, auth.FromContext(ctx), id)
+		if err != nil {
+			return nil, err
+		}
+		attachment.ConversationID = conversation.ID
+	}
+
+	if err := indexer.AddAttachment(ctx, attachment); err != nil {
+		switch {
+		case errors.Is(err, attachments.ErrUnsupportedType),
+			errors.Is(err, attachments.ErrUnreadable),
+			errors.Is(err, attachments.ErrNoText),
+			errors.Is(err, attachments.ErrTooLong):
+			return nil, twirp.InvalidArgumentError("data", err.Error())
+		case errors.Is(err, attachments.ErrDisabled):
+			return nil, twirp.NewError(twirp.Unimplemented, err.Error())
+		default:
+			return nil, twirp.InternalErrorWith(err)
+		}
+	}
+
+	return &pb.UploadAttachmentResponse{Attachment: attachment.Proto()}, nil
+}
+
+func (s *Server) ListPersonas(ctx context.Context, req *pb.ListPersonasRequest) (*pb.ListPersonasResponse, error) {
+	catalog, ok := s.assist.(PersonaCatalog)
+	if !ok {
+		return nil, twirp.NewError(twirp.Unimplemented, "personas are not available")
+	}
+
+	personas, err := catalog.Personas(ctx)
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	out := &pb.ListPersonasResponse{}
+	for _, p := range personas {
+		out.Personas = append(out.Personas, p.Proto())
+	}
+
+	return out, nil
+}
+
+func (s *Server) GetToolPolicy(ctx context.Context, req *pb.GetToolPolicyRequest) (*pb.GetToolPolicyResponse, error) {
+	policy, err := s.repo.UserToolPolicy(ctx, auth.FromContext(ctx))
+	if err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	return &pb.GetToolPolicyResponse{Policy: policy.Proto()}, nil
+}
+
+func (s *Server) SetToolPolicy(ctx context.Context, req *pb.SetToolPolicyRequest) (*pb.SetToolPolicyResponse, error) {
+	policy := model.ToolPolicyFromProto(req.GetPolicy())
+
+	if err := s.repo.SaveUserToolPolicy(ctx, auth.FromContext(ctx), policy); err != nil {
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	return &pb.SetToolPolicyResponse{Policy: policy.Proto()}, nil
+}
@@ -1,14 +1,28 @@
 package chat
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/attachments"
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/pii"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
 	. "github.com/isabermoussa/personal-assistant-API/internal/chat/testing"
 	"github.com/isabermoussa/personal-assistant-API/internal/pb"
+	"github.com/isabermoussa/personal-assistant-API/internal/units"
 	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
@@ -59,7 +73,7 @@ func TestServer_StartConversation(t *testing.T) {
 		}
 
 		// Verify conversation was saved to database
-		saved, err := f.Repository.DescribeConversation(ctx, resp.GetConversationId())
+		saved, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), resp.GetConversationId())
 		if err != nil {
 			t.Fatalf("failed to retrieve saved conversation: %v", err)
 		}
@@ -122,8 +136,8 @@ func TestServer_StartConversation(t *testing.T) {
 			t.Fatal("expected error when reply generation fails, got nil")
 		}
 
-		if err.Error() != "simulated reply generation error" {
-			t.Errorf("expected reply error to propagate, got: %v", err)
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.Internal || te.Msg() != "simulated reply generation error" {
+			t.Errorf("expected reply error to propagate as twirp.Internal, got: %v", err)
 		}
 	}))
 
@@ -162,7 +176,7 @@ func TestServer_StartConversation(t *testing.T) {
 		}
 
 		// Retrieve and verify conversation structure
-		saved, err := f.Repository.DescribeConversation(ctx, resp.GetConversationId())
+		saved, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), resp.GetConversationId())
 		if err != nil {
 			t.Fatalf("failed to retrieve conversation: %v", err)
 		}
@@ -231,6 +245,43 @@ func TestServer_StartConversation(t *testing.T) {
 		// Clean up
 		f.Repository.DeleteConversation(ctx, resp.GetConversationId())
 	}))
+
+	t.Run("stores the unit preference and passes it to the assistant", WithFixture(func(t *testing.T, f *Fixture) {
+		var replyUnits units.System
+
+		assist := newMockAssistant().
+			withTitleFunc(titleSummarizer).
+			withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+				replyUnits = conv.Units
+				return "It is 86°F in Miami.", nil
+			})
+
+		srv := NewServer(f.Repository, assist)
+
+		resp, err := srv.StartConversation(ctx, &pb.StartConversationRequest{
+			Message: "How hot is it in Miami?",
+			Units:   pb.Units_IMPERIAL,
+		})
+
+		if err != nil {
+			t.Fatalf("StartConversation failed: %v", err)
+		}
+
+		defer f.Repository.DeleteConversation(ctx, resp.GetConversationId())
+
+		if replyUnits != units.Imperial {
+			t.Errorf("expected assistant to see imperial units, got %q", replyUnits)
+		}
+
+		saved, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), resp.GetConversationId())
+		if err != nil {
+			t.Fatalf("failed to retrieve saved conversation: %v", err)
+		}
+
+		if saved.Proto().GetUnits() != pb.Units_IMPERIAL {
+			t.Errorf("expected imperial units to be stored, got %v", saved.Proto().GetUnits())
+		}
+	}))
 }
 
 func TestServer_DescribeConversation(t *testing.T) {
@@ -261,4 +312,1238 @@ func TestServer_DescribeConversation(t *testing.T) {
 			t.Fatalf("expected twirp.NotFound error, got %v", err)
 		}
 	}))
+
+	t.Run("other users' conversations are not found", WithFixture(func(t *testing.T, f *Fixture) {
+		c := f.CreateConversation(func(c *model.Conversation) { c.UserID = uuid.New().String() })
+		other := auth.WithUser(context.Background(), uuid.New().String())
+
+		_, err := srv.DescribeConversation(other, &pb.DescribeConversationRequest{ConversationId: c.ID.Hex()})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+
+		_, err = srv.ContinueConversation(other, &pb.ContinueConversationRequest{ConversationId: c.ID.Hex(), Message: "Hello"})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+
+		_, err = srv.ConfirmToolCall(other, &pb.ConfirmToolCallRequest{ConversationId: c.ID.Hex(), ToolCallId: "call_1", Approve: true})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+
+		out, err := srv.ListConversations(other, &pb.ListConversationsRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, conv := range out.GetConversations() {
+			if conv.GetId() == c.ID.Hex() {
+				t.Errorf("listed another user's conversation %s", conv.GetId())
+			}
+		}
+	}))
+}
+
+func TestServer_ListNotes(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("lists the user's notes, most recent first", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		older := f.CreateNote(user)
+		newer := f.CreateNote(user, func(n *model.Note) {
+			n.Content = "Hotel in Kyoto"
+			n.UpdatedAt = n.UpdatedAt.Add(time.Hour)
+		})
+		f.CreateNote(uuid.New().String()) // another user's note
+
+		out, err := srv.ListNotes(auth.WithUser(context.Background(), user), &pb.ListNotesRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, want := out.GetNotes(), []*pb.Note{newer.Proto(), older.Proto()}
+		if !cmp.Equal(got, want, protocmp.Transform()) {
+			t.Errorf("ListNotes() mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+		}
+	}))
+
+	t.Run("filters by query ignoring case", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		f.CreateNote(user)
+		kyoto := f.CreateNote(user, func(n *model.Note) { n.Content = "Hotel in Kyoto (check-in 15:00)" })
+
+		out, err := srv.ListNotes(auth.WithUser(context.Background(), user), &pb.ListNotesRequest{Query: "kyoto (check"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(out.GetNotes()) != 1 || out.GetNotes()[0].GetId() != kyoto.ID.Hex() {
+			t.Errorf("expected only the Kyoto note, got %v", out.GetNotes())
+		}
+	}))
+}
+
+func TestServer_ListTodos(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("lists open items unless completed ones are requested", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		due := time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC)
+		open := f.CreateTodo(user, func(t *model.Todo) { t.Due = &due })
+		done := f.CreateTodo(user, func(t *model.Todo) { t.Done = true })
+		f.CreateTodo(uuid.New().String()) // another user's item
+
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.ListTodos(ctx, &pb.ListTodosRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, want := out.GetTodos(), []*pb.Todo{open.Proto()}
+		if !cmp.Equal(got, want, protocmp.Transform()) {
+			t.Errorf("ListTodos() mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+		}
+
+		out, err = srv.ListTodos(ctx, &pb.ListTodosRequest{IncludeCompleted: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, want = out.GetTodos(), []*pb.Todo{open.Proto(), done.Proto()}
+		if !cmp.Equal(got, want, protocmp.Transform()) {
+			t.Errorf("ListTodos(include_completed) mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+		}
+	}))
+
+	t.Run("completed items keep their completion time", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		todo := f.CreateTodo(user)
+		ctx := context.Background()
+
+		first, err := f.Repository.CompleteTodo(ctx, user, todo.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !first.Done || first.CompletedAt == nil {
+			t.Fatalf("expected item to be completed, got %+v", first)
+		}
+
+		again, err := f.Repository.CompleteTodo(ctx, user, todo.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !again.CompletedAt.Equal(*first.CompletedAt) {
+			t.Errorf("completion time changed from %v to %v", first.CompletedAt, again.CompletedAt)
+		}
+
+		if _, err := f.Repository.CompleteTodo(ctx, uuid.New().String(), todo.ID.Hex()); err == nil {
+			t.Error("expected another user's completion to fail")
+		} else if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+}
+
+func TestServer_ListReminders(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("lists pending reminders soonest first unless finished ones are requested", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		later := f.CreateReminder(user, func(r *model.Reminder) { r.DueAt = time.Date(2023, 10, 9, 9, 0, 0, 0, time.UTC) })
+		sooner := f.CreateReminder(user, func(r *model.Reminder) {
+			r.DueAt = time.Date(2023, 10, 3, 9, 0, 0, 0, time.UTC)
+			r.TimeZone = "Europe/Madrid"
+			r.ConversationID = primitive.NewObjectID()
+		})
+		delivered := f.CreateReminder(user, func(r *model.Reminder) { r.Status = model.ReminderDelivered })
+		f.CreateReminder(uuid.New().String()) // another user's reminder
+
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.ListReminders(ctx, &pb.ListRemindersRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, want := out.GetReminders(), []*pb.Reminder{sooner.Proto(), later.Proto()}
+		if !cmp.Equal(got, want, protocmp.Transform()) {
+			t.Errorf("ListReminders() mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+		}
+
+		out, err = srv.ListReminders(ctx, &pb.ListRemindersRequest{IncludeFinished: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, want = out.GetReminders(), []*pb.Reminder{delivered.Proto(), sooner.Proto(), later.Proto()}
+		if !cmp.Equal(got, want, protocmp.Transform()) {
+			t.Errorf("ListReminders(include_finished) mismatch (-got +want):\n%s", cmp.Diff(got, want, protocmp.Transform()))
+		}
+	}))
+}
+
+func TestServer_CancelReminder(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("cancels a pending reminder once", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		reminder := f.CreateReminder(user)
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.CancelReminder(ctx, &pb.CancelReminderRequest{ReminderId: reminder.ID.Hex()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := out.GetReminder().GetStatus(); got != pb.Reminder_CANCELLED {
+			t.Errorf("status = %s, want CANCELLED", got)
+		}
+
+		_, err = srv.CancelReminder(ctx, &pb.CancelReminderRequest{ReminderId: reminder.ID.Hex()})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.FailedPrecondition {
+			t.Errorf("expected twirp.FailedPrecondition error, got %v", err)
+		}
+	}))
+
+	t.Run("reminders of other users are not found", WithFixture(func(t *testing.T, f *Fixture) {
+		reminder := f.CreateReminder(uuid.New().String())
+		ctx := auth.WithUser(context.Background(), uuid.New().String())
+
+		_, err := srv.CancelReminder(ctx, &pb.CancelReminderRequest{ReminderId: reminder.ID.Hex()})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+
+	t.Run("requires a reminder ID", func(t *testing.T) {
+		_, err := srv.CancelReminder(context.Background(), &pb.CancelReminderRequest{})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+			t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+		}
+	})
+}
+
+func TestRepository_ClaimDueReminder(t *testing.T) {
+	t.Run("leases a due reminder until it is finished", WithFixture(func(t *testing.T, f *Fixture) {
+		ctx := context.Background()
+		due := time.Date(2001, 1, 1, 9, 0, 0, 0, time.UTC)
+		reminder := f.CreateReminder(uuid.New().String(), func(r *model.Reminder) { r.DueAt = due })
+
+		if got, err := f.Repository.ClaimDueReminder(ctx, due.Add(-time.Second), time.Minute); err != nil || got != nil {
+			t.Fatalf("expected nothing due yet, got %v, %v", got, err)
+		}
+
+		now := due.Add(time.Second)
+		claimed, err := f.Repository.ClaimDueReminder(ctx, now, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claimed == nil || claimed.ID != reminder.ID || claimed.Attempts != 1 {
+			t.Fatalf("expected the reminder to be claimed once, got %+v", claimed)
+		}
+
+		// Leased reminders are left alone until the lease expires
+		if got, _ := f.Repository.ClaimDueReminder(ctx, now.Add(30*time.Second), time.Minute); got != nil {
+			t.Fatalf("leased reminder was claimed again")
+		}
+		again, err := f.Repository.ClaimDueReminder(ctx, now.Add(2*time.Minute), time.Minute)
+		if err != nil || again == nil || again.Attempts != 2 {
+			t.Fatalf("expected the reminder to be claimed after its lease, got %+v, %v", again, err)
+		}
+
+		if err := f.Repository.MarkReminderChannelDelivered(ctx, reminder.ID, "webhook"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := f.Repository.FinishReminder(ctx, reminder.ID, model.ReminderDelivered, "", now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, _ := f.Repository.ClaimDueReminder(ctx, now.Add(time.Hour), time.Minute); got != nil && got.ID == reminder.ID {
+			t.Fatalf("delivered reminder was claimed again")
+		}
+
+		reminders, err := f.Repository.ListReminders(ctx, reminder.UserID, true)
+		if err != nil || len(reminders) != 1 {
+			t.Fatalf("expected one reminder, got %d, %v", len(reminders), err)
+		}
+		if got := reminders[0]; got.Status != model.ReminderDelivered || got.LeaseUntil != nil || len(got.Delivered) != 1 {
+			t.Errorf("unexpected delivered reminder: %+v", got)
+		}
+	}))
+}
+
+func TestRepository_AppendMessage(t *testing.T) {
+	t.Run("appends each message once", WithFixture(func(t *testing.T, f *Fixture) {
+		ctx := context.Background()
+		conv := f.CreateConversation()
+		msg := &model.Message{
+			ID:        primitive.NewObjectID(),
+			Role:      model.RoleAssistant,
+			Content:   "Reminder: stretch",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := f.Repository.AppendMessage(ctx, conv.ID, msg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		got, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), conv.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(got.Messages); n != len(conv.Messages)+1 || got.Messages[n-1].Content != msg.Content {
+			t.Errorf("expected the message appended once, got %d messages", n)
+		}
+
+		err = f.Repository.AppendMessage(ctx, primitive.NewObjectID(), msg)
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+}
+
+func TestRepository_UpdateConversation(t *testing.T) {
+	t.Run("keeps messages appended since the conversation was read", WithFixture(func(t *testing.T, f *Fixture) {
+		ctx := context.Background()
+		conv := f.CreateConversation()
+
+		loaded, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), conv.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// A reminder is delivered while the reply is being generated
+		reminder := &model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "Reminder: stretch", CreatedAt: time.Now()}
+		if err := f.Repository.AppendMessage(ctx, conv.ID, reminder); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reply := &model.Message{ID: primitive.NewObjectID(), Role: model.RoleAssistant, Content: "It's sunny.", CreatedAt: time.Now()}
+		loaded.Messages = append(loaded.Messages, reply)
+		for i := 0; i < 2; i++ {
+			if err := f.Repository.UpdateConversation(ctx, loaded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		got, err := f.Repository.DescribeConversation(ctx, auth.FromContext(ctx), conv.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var contents []string
+		for _, m := range got.Messages {
+			contents = append(contents, m.Content)
+		}
+		if want := []string{conv.Messages[0].Content, reminder.Content, reply.Content}; !cmp.Equal(contents, want) {
+			t.Errorf("UpdateConversation() messages mismatch (-got +want):\n%s", cmp.Diff(contents, want))
+		}
+	}))
+}
+
+func TestServer_ExportCalendar(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("exports the user's events", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		dentist := f.CreateEvent(user, func(e *model.Event) { e.Title = "Dentist" })
+		f.CreateEvent(uuid.New().String(), func(e *model.Event) { e.Title = "Someone else's event" })
+
+		out, err := srv.ExportCalendar(auth.WithUser(context.Background(), user), &pb.ExportCalendarRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []string{"BEGIN:VCALENDAR", "UID:" + dentist.UID, "SUMMARY:Dentist", "DTSTART:20231002T090000Z"} {
+			if !strings.Contains(out.GetIcs(), want) {
+				t.Errorf("expected export to contain %q, got:\n%s", want, out.GetIcs())
+			}
+		}
+		if strings.Contains(out.GetIcs(), "Someone else") {
+			t.Errorf("export contains another user's event:\n%s", out.GetIcs())
+		}
+	}))
+}
+
+func TestServer_ImportCalendar(t *testing.T) {
+	repo := model.New(ConnectMongo())
+	srv := NewServer(repo, nil)
+
+	booking := func(departure string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Airline//Booking//EN\r\n" +
+			"BEGIN:VEVENT\r\nUID:" + departure + "-IB3166@iberia.example\r\nDTSTAMP:20250820T101500Z\r\n" +
+			"DTSTART:" + departure + "T071500Z\r\nDTEND:" + departure + "T193000Z\r\nSUMMARY:Flight IB3166\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+	}
+
+	t.Run("imports events and updates them on re-import", func(t *testing.T) {
+		user := uuid.New().String()
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.ImportCalendar(ctx, &pb.ImportCalendarRequest{Ics: booking("20250902"), TimeZone: "Europe/Madrid"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.GetCreated() != 1 || out.GetUpdated() != 0 || len(out.GetEvents()) != 1 {
+			t.Fatalf("expected one created event, got %+v", out)
+		}
+		event := out.GetEvents()[0]
+		defer func() {
+			if err := repo.DeleteEvent(context.Background(), user, event.GetId()); err != nil {
+				t.Logf("failed to cleanup event %s: %v", event.GetId(), err)
+			}
+		}()
+
+		if event.GetTitle() != "Flight IB3166" || event.GetTimeZone() != "Europe/Madrid" ||
+			!event.GetStart().AsTime().Equal(time.Date(2025, 9, 2, 7, 15, 0, 0, time.UTC)) {
+			t.Errorf("unexpected imported event: %+v", event)
+		}
+
+		// The same booking, rescheduled: the event is updated in place
+		again, err := srv.ImportCalendar(ctx, &pb.ImportCalendarRequest{Ics: booking("20250902")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.GetCreated() != 0 || again.GetUpdated() != 1 || again.GetEvents()[0].GetId() != event.GetId() {
+			t.Errorf("expected the event to be updated, got %+v", again)
+		}
+
+		events, err := repo.ListEvents(context.Background(), user, model.EventFilter{})
+		if err != nil || len(events) != 1 {
+			t.Errorf("expected one stored event, got %d, %v", len(events), err)
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name string
+			req  *pb.ImportCalendarRequest
+		}{
+			{name: "empty", req: &pb.ImportCalendarRequest{}},
+			{name: "not a calendar", req: &pb.ImportCalendarRequest{Ics: "hello"}},
+			{name: "too large", req: &pb.ImportCalendarRequest{Ics: strings.Repeat("x", maxImportSize+1)}},
+			{name: "unknown zone", req: &pb.ImportCalendarRequest{Ics: booking("20250902"), TimeZone: "Mars/Olympus"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := srv.ImportCalendar(context.Background(), tt.req)
+				if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+					t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestServer_Memories(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("lists and forgets the user's memories", WithFixture(func(t *testing.T, f *Fixture) {
+		user := uuid.New().String()
+		vegetarian := f.CreateMemory(user, "Is vegetarian")
+		barcelona := f.CreateMemory(user, "Home city is Barcelona")
+		f.CreateMemory(uuid.New().String(), "Home city is Lisbon") // another user's memory
+
+		// Learning a fact again keeps the original
+		if again := f.CreateMemory(user, "is  vegetarian."); again.ID != vegetarian.ID {
+			t.Errorf("expected the same fact to be stored once, got %s and %s", vegetarian.ID.Hex(), again.ID.Hex())
+		}
+
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.ListMemories(ctx, &pb.ListMemoriesRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var texts []string
+		for _, m := range out.GetMemories() {
+			texts = append(texts, m.GetText())
+		}
+		if !cmp.Equal(texts, []string{"Is vegetarian", "Home city is Barcelona"}) {
+			t.Errorf("ListMemories() = %q", texts)
+		}
+
+		if _, err := srv.DeleteMemory(ctx, &pb.DeleteMemoryRequest{MemoryId: barcelona.ID.Hex()}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = srv.DeleteMemory(ctx, &pb.DeleteMemoryRequest{MemoryId: barcelona.ID.Hex()})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+
+	t.Run("memories of other users are not found", WithFixture(func(t *testing.T, f *Fixture) {
+		memory := f.CreateMemory(uuid.New().String(), "Prefers window seats")
+		ctx := auth.WithUser(context.Background(), uuid.New().String())
+
+		_, err := srv.DeleteMemory(ctx, &pb.DeleteMemoryRequest{MemoryId: memory.ID.Hex()})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+
+	t.Run("requires a memory ID", func(t *testing.T) {
+		_, err := srv.DeleteMemory(context.Background(), &pb.DeleteMemoryRequest{})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+			t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+		}
+	})
+}
+
+// attachingAssistant indexes uploaded files in a library on the repository
+type attachingAssistant struct {
+	*mockAssistant
+	library *attachments.Library
+}
+
+func (a *attachingAssistant) AddAttachment(ctx context.Context, att *model.Attachment) error {
+	return a.library.Add(ctx, att)
+}
+
+// constantEmbedder embeds every text as the same vector
+type constantEmbedder struct{}
+
+func (constantEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = []float64{1}
+	}
+	return vectors, nil
+}
+
+func TestServer_UploadAttachment(t *testing.T) {
+	repo := model.New(ConnectMongo())
+
+	t.Run("uploads a file and sends it with a message", WithFixture(func(t *testing.T, f *Fixture) {
+		var seen []*model.AttachmentRef
+		assist := &attachingAssistant{
+			mockAssistant: newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+				seen = conv.Messages[0].Attachments
+				return "Check-in at Ryokan Yachiyo is from 15:00 (itinerary.md).", nil
+			}),
+			library: attachments.NewLibrary(constantEmbedder{}, f.Repository),
+		}
+		srv := NewServer(f.Repository, assist)
+
+		user := uuid.New().String()
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.UploadAttachment(ctx, &pb.UploadAttachmentRequest{
+			Filename: "trips/itinerary.md",
+			Data:     []byte("# Kyoto\n\nRyokan Yachiyo, check-in from 15:00"),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		uploaded := out.GetAttachment()
+		t.Cleanup(func() {
+			if err := f.Repository.DeleteAttachment(context.Background(), user, uploaded.GetId()); err != nil {
+				t.Logf("failed to cleanup attachment %s: %v", uploaded.GetId(), err)
+			}
+		})
+
+		if uploaded.GetFilename() != "itinerary.md" || uploaded.GetContentType() != "text/markdown" || uploaded.GetConversationId() != "" {
+			t.Errorf("unexpected attachment %+v", uploaded)
+		}
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{
+			Message:       "When can we check in in Kyoto?",
+			AttachmentIds: []string{uploaded.GetId()},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(seen) != 1 || seen[0].Filename != "itinerary.md" {
+			t.Errorf("expected the assistant to see the file, got %+v", seen)
+		}
+
+		conv, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: started.GetConversationId()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		refs := conv.GetConversation().GetMessages()[0].GetAttachments()
+		if len(refs) != 1 || refs[0].GetId() != uploaded.GetId() {
+			t.Errorf("expected the message to reference the file, got %+v", refs)
+		}
+
+		// A file belongs to the conversation it was first sent in
+		_, err = srv.StartConversation(ctx, &pb.StartConversationRequest{
+			Message:       "And in Tokyo?",
+			AttachmentIds: []string{uploaded.GetId()},
+		})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.FailedPrecondition {
+			t.Errorf("expected twirp.FailedPrecondition error, got %v", err)
+		}
+
+		// Other users can't send it
+		_, err = srv.StartConversation(auth.WithUser(context.Background(), uuid.New().String()), &pb.StartConversationRequest{
+			Message:       "What's in this file?",
+			AttachmentIds: []string{uploaded.GetId()},
+		})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+
+	t.Run("a file sent with a failed first reply can be sent again", WithFixture(func(t *testing.T, f *Fixture) {
+		failures := 1
+		assist := &attachingAssistant{
+			mockAssistant: newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+				if failures > 0 {
+					failures--
+					return "", errors.New("model unavailable")
+				}
+				return "Check-in is from 15:00.", nil
+			}),
+			library: attachments.NewLibrary(constantEmbedder{}, f.Repository),
+		}
+		srv := NewServer(f.Repository, assist)
+
+		user := uuid.New().String()
+		ctx := auth.WithUser(context.Background(), user)
+
+		out, err := srv.UploadAttachment(ctx, &pb.UploadAttachmentRequest{Filename: "itinerary.md", Data: []byte("Check-in from 15:00")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		id := out.GetAttachment().GetId()
+		t.Cleanup(func() {
+			if err := f.Repository.DeleteAttachment(context.Background(), user, id); err != nil {
+				t.Logf("failed to cleanup attachment %s: %v", id, err)
+			}
+		})
+
+		req := &pb.StartConversationRequest{Message: "When can we check in?", AttachmentIds: []string{id}}
+		if _, err := srv.StartConversation(ctx, req); err == nil {
+			t.Fatal("expected the first reply to fail")
+		}
+
+		started, err := srv.StartConversation(ctx, req)
+		if err != nil {
+			t.Fatalf("expected the retry to succeed, got %v", err)
+		}
+		cid, err := primitive.ObjectIDFromHex(started.GetConversationId())
+		if err != nil {
+			t.Fatalf("invalid conversation ID: %v", err)
+		}
+		files, err := f.Repository.ListConversationAttachments(ctx, user, cid)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(files) != 1 || files[0].ID.Hex() != id {
+			t.Errorf("expected the file to belong to the new conversation, got %+v", files)
+		}
+	}))
+
+	t.Run("rejects invalid uploads", func(t *testing.T) {
+		srv := NewServer(repo, &attachingAssistant{
+			mockAssistant: newMockAssistant(),
+			library:       attachments.NewLibrary(constantEmbedder{}, repo),
+		})
+
+		tests := []struct {
+			name string
+			req  *pb.UploadAttachmentRequest
+		}{
+			{name: "no filename", req: &pb.UploadAttachmentRequest{Data: []byte("hello")}},
+			{name: "no data", req: &pb.UploadAttachmentRequest{Filename: "notes.txt"}},
+			{name: "too large", req: &pb.UploadAttachmentRequest{Filename: "notes.txt", Data: make([]byte, maxAttachmentSize+1)}},
+			{name: "unsupported type", req: &pb.UploadAttachmentRequest{Filename: "photo.jpg", Data: []byte("jpeg")}},
+			{name: "no text", req: &pb.UploadAttachmentRequest{Filename: "notes.txt", Data: []byte(" \n ")}},
+			{name: "damaged pdf", req: &pb.UploadAttachmentRequest{Filename: "itinerary.pdf", Data: []byte("%PDF-1.4\nnot really")}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := srv.UploadAttachment(context.Background(), tt.req)
+				if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+					t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("requires an assistant that reads files", func(t *testing.T) {
+		srv := NewServer(repo, newMockAssistant())
+
+		_, err := srv.UploadAttachment(context.Background(), &pb.UploadAttachmentRequest{Filename: "notes.txt", Data: []byte("hello")})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.Unimplemented {
+			t.Errorf("expected twirp.Unimplemented error, got %v", err)
+		}
+	})
+}
+
+// personaAssistant replies as the personas it knows
+type personaAssistant struct {
+	*mockAssistant
+	personas []*model.Persona
+}
+
+func (a *personaAssistant) Persona(ctx context.Context, name string) (*model.Persona, error) {
+	for _, p := range a.personas {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, model.ErrUnknownPersona
+}
+
+func (a *personaAssistant) Personas(ctx context.Context) ([]*model.Persona, error) {
+	return a.personas, nil
+}
+
+func TestServer_Personas(t *testing.T) {
+	ctx := context.Background()
+	personas := []*model.Persona{
+		{Name: "default", Description: "Helpful and concise general assistant", SystemPrompt: "You are helpful."},
+		{Name: "travel_planner", Description: "Plans trips", SystemPrompt: "You plan trips.", Tools: []string{"get_weather"}},
+	}
+
+	t.Run("conversations keep the persona they start with", WithFixture(func(t *testing.T, f *Fixture) {
+		var seen []string
+		assist := &personaAssistant{
+			mockAssistant: newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+				seen = append(seen, conv.Persona)
+				return "Day 1: Fushimi Inari early in the morning.", nil
+			}),
+			personas: personas,
+		}
+		srv := NewServer(f.Repository, assist)
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Plan two days in Kyoto", Persona: " travel_planner "})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: started.GetConversationId(), Message: "And a third day?"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !cmp.Equal(seen, []string{"travel_planner", "travel_planner"}) {
+			t.Errorf("expected both replies to use the persona, got %q", seen)
+		}
+
+		out, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: started.GetConversationId()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := out.GetConversation().GetPersona(); got != "travel_planner" {
+			t.Errorf("expected the persona to be stored, got %q", got)
+		}
+	}))
+
+	t.Run("rejects unknown personas", func(t *testing.T) {
+		for _, assist := range []Assistant{newMockAssistant(), &personaAssistant{mockAssistant: newMockAssistant(), personas: personas}} {
+			srv := NewServer(nil, assist)
+
+			_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Hello", Persona: "pirate"})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+			}
+		}
+	})
+
+	t.Run("lists personas", func(t *testing.T) {
+		srv := NewServer(nil, &personaAssistant{mockAssistant: newMockAssistant(), personas: personas})
+
+		out, err := srv.ListPersonas(ctx, &pb.ListPersonasRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := &pb.ListPersonasResponse{Personas: []*pb.Persona{
+			{Name: "default", Description: "Helpful and concise general assistant"},
+			{Name: "travel_planner", Description: "Plans trips", Tools: []string{"get_weather"}},
+		}}
+		if diff := cmp.Diff(want, out, protocmp.Transform()); diff != "" {
+			t.Errorf("ListPersonas() mismatch (-want +got):\n%s", diff)
+		}
+
+		_, err = NewServer(nil, newMockAssistant()).ListPersonas(ctx, &pb.ListPersonasRequest{})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.Unimplemented {
+			t.Errorf("expected twirp.Unimplemented error, got %v", err)
+		}
+	})
+}
+
+// confirmingReply asks to confirm one tool call, then reports whether it was approved
+func confirmingReply(ctx context.Context, conv *model.Conversation) (string, error) {
+	if conv.Pending == nil {
+		conv.Pending = &model.PendingReply{Steps: [][]*model.ToolCall{{
+			{ID: "call_1", Name: "create_reminder", Arguments: `{"text": "Check in online"}`, Status: model.ToolCallPending},
+		}}}
+		return "", model.ErrConfirmationRequired
+	}
+
+	call := conv.Pending.Call("call_1")
+	conv.Pending = nil
+	if call.Status == model.ToolCallApproved {
+		return "Done, I'll remind you to check in online.", nil
+	}
+	return "OK, no reminder then.", nil
+}
+
+func TestServer_ConfirmToolCall(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replies wait for tool calls to be approved", WithFixture(func(t *testing.T, f *Fixture) {
+		srv := NewServer(f.Repository, newMockAssistant().withReplyFunc(confirmingReply))
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{
+			Message: "Remind me to check in online tomorrow",
+			Tools:   &pb.ToolPolicy{Confirm: []string{"create_reminder", " ", "create_reminder"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []*pb.ToolCall{{Id: "call_1", Name: "create_reminder", Arguments: `{"text": "Check in online"}`}}
+		if started.GetReply() != "" || !cmp.Equal(want, started.GetPendingToolCalls(), protocmp.Transform()) {
+			t.Fatalf("expected the reply to wait, got %+v", started)
+		}
+
+		id := started.GetConversationId()
+		stored, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if conv := stored.GetConversation(); len(conv.GetMessages()) != 1 || len(conv.GetPendingToolCalls()) != 1 || !cmp.Equal(conv.GetTools().GetConfirm(), []string{"create_reminder"}) {
+			t.Errorf("unexpected stored conversation %+v", conv)
+		}
+
+		_, err = srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: id, Message: "Hello?"})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.FailedPrecondition {
+			t.Errorf("expected twirp.FailedPrecondition error, got %v", err)
+		}
+
+		_, err = srv.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{ConversationId: id, ToolCallId: "call_2", Approve: true})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+
+		out, err := srv.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{ConversationId: id, ToolCallId: "call_1", Approve: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.GetReply() != "Done, I'll remind you to check in online." || len(out.GetPendingToolCalls()) != 0 {
+			t.Errorf("unexpected response %+v", out)
+		}
+
+		stored, err = srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if conv := stored.GetConversation(); len(conv.GetMessages()) != 2 || len(conv.GetPendingToolCalls()) != 0 {
+			t.Errorf("expected the reply to be stored and nothing left waiting, got %+v", conv)
+		}
+
+		_, err = srv.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{ConversationId: id, ToolCallId: "call_1", Approve: true})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.FailedPrecondition {
+			t.Errorf("expected twirp.FailedPrecondition error, got %v", err)
+		}
+	}))
+
+	t.Run("declined calls", WithFixture(func(t *testing.T, f *Fixture) {
+		srv := NewServer(f.Repository, newMockAssistant().withReplyFunc(confirmingReply))
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Remind me to check in online tomorrow"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out, err := srv.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{ConversationId: started.GetConversationId(), ToolCallId: "call_1"})
+		if err != nil || out.GetReply() != "OK, no reminder then." {
+			t.Errorf("unexpected response %+v, %v", out, err)
+		}
+	}))
+
+	t.Run("concurrent confirmations run the call once", WithFixture(func(t *testing.T, f *Fixture) {
+		var runs atomic.Int32
+		srv := NewServer(f.Repository, newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+			if conv.Pending != nil && conv.Pending.Call("call_1").Status == model.ToolCallApproved {
+				runs.Add(1)
+				time.Sleep(50 * time.Millisecond)
+			}
+			return confirmingReply(ctx, conv)
+		}))
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Remind me to check in online tomorrow"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := srv.ConfirmToolCall(ctx, &pb.ConfirmToolCallRequest{ConversationId: started.GetConversationId(), ToolCallId: "call_1", Approve: true})
+				if te, ok := err.(twirp.Error); err != nil && (!ok || te.Code() != twirp.FailedPrecondition) {
+					t.Errorf("expected success or twirp.FailedPrecondition error, got %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := runs.Load(); n != 1 {
+			t.Errorf("expected the approved call to run once, ran %d times", n)
+		}
+	}))
+
+	t.Run("only the owner can confirm", WithFixture(func(t *testing.T, f *Fixture) {
+		srv := NewServer(f.Repository, newMockAssistant().withReplyFunc(confirmingReply))
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Remind me to check in online tomorrow"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		other := auth.WithUser(context.Background(), uuid.New().String())
+		_, err = srv.ConfirmToolCall(other, &pb.ConfirmToolCallRequest{ConversationId: started.GetConversationId(), ToolCallId: "call_1", Approve: true})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.NotFound {
+			t.Errorf("expected twirp.NotFound error, got %v", err)
+		}
+	}))
+
+	t.Run("requires a conversation and a tool call", func(t *testing.T) {
+		srv := NewServer(nil, newMockAssistant())
+
+		for _, req := range []*pb.ConfirmToolCallRequest{{ToolCallId: "call_1"}, {ConversationId: primitive.NewObjectID().Hex()}} {
+			_, err := srv.ConfirmToolCall(ctx, req)
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Errorf("expected twirp.InvalidArgument error, got %v", err)
+			}
+		}
+	})
+}
+
+func TestServer_ToolPolicy(t *testing.T) {
+	srv := NewServer(model.New(ConnectMongo()), nil)
+
+	t.Run("stores the user's policy", WithFixture(func(t *testing.T, f *Fixture) {
+		ctx := auth.WithUser(context.Background(), uuid.New().String())
+
+		empty, err := srv.GetToolPolicy(ctx, &pb.GetToolPolicyRequest{})
+		if err != nil || len(empty.GetPolicy().GetAllow()) != 0 || len(empty.GetPolicy().GetDeny()) != 0 || len(empty.GetPolicy().GetConfirm()) != 0 {
+			t.Fatalf("expected an empty policy, got %+v, %v", empty, err)
+		}
+
+		if _, err := srv.SetToolPolicy(ctx, &pb.SetToolPolicyRequest{Policy: &pb.ToolPolicy{
+			Deny:    []string{"get_weather", "get_weather"},
+			Confirm: []string{" create_event ", "create_reminder"},
+		}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out, err := srv.GetToolPolicy(ctx, &pb.GetToolPolicyRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := &pb.ToolPolicy{Deny: []string{"get_weather"}, Confirm: []string{"create_event", "create_reminder"}}
+		if diff := cmp.Diff(want, out.GetPolicy(), protocmp.Transform()); diff != "" {
+			t.Errorf("GetToolPolicy() mismatch (-want +got):\n%s", diff)
+		}
+
+		other, err := srv.GetToolPolicy(auth.WithUser(context.Background(), uuid.New().String()), &pb.GetToolPolicyRequest{})
+		if err != nil || len(other.GetPolicy().GetDeny()) != 0 {
+			t.Errorf("expected other users to be unaffected, got %+v, %v", other, err)
+		}
+	}))
+}
+
+// structuredAssistant answers every message with an itinerary document
+type structuredAssistant struct {
+	*mockAssistant
+}
+
+func (a *structuredAssistant) StructuredReply(ctx context.Context, conv *model.Conversation) (string, string, error) {
+	if conv.Messages[len(conv.Messages)-1].ResponseSchema == "" {
+		return "Kyoto is lovely in autumn.", "", nil
+	}
+	return "Day 1: Fushimi Inari.", `{"days":[{"city":"Kyoto","activities":["Fushimi Inari"]}]}`, nil
+}
+
+func TestServer_StructuredReply(t *testing.T) {
+	ctx := context.Background()
+	itinerary := `{
+		"type": "object",
+		"properties": {"days": {"type": "array", "items": {"type": "object", "required": ["city"]}}},
+		"required": ["days"]
+	}`
+
+	t.Run("returns and stores the document", WithFixture(func(t *testing.T, f *Fixture) {
+		srv := NewServer(f.Repository, &structuredAssistant{mockAssistant: newMockAssistant()})
+
+		started, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Plan a day in Kyoto", ResponseSchema: itinerary})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if started.GetReply() != "Day 1: Fushimi Inari." || started.GetStructuredReply() != `{"days":[{"city":"Kyoto","activities":["Fushimi Inari"]}]}` {
+			t.Errorf("unexpected reply %q, %q", started.GetReply(), started.GetStructuredReply())
+		}
+
+		// The schema applies to one message only
+		continued, err := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: started.GetConversationId(), Message: "Is it nice in autumn?"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if continued.GetStructuredReply() != "" {
+			t.Errorf("expected no document, got %q", continued.GetStructuredReply())
+		}
+
+		out, err := srv.DescribeConversation(ctx, &pb.DescribeConversationRequest{ConversationId: started.GetConversationId()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		messages := out.GetConversation().GetMessages()
+		if len(messages) != 4 || messages[0].GetResponseSchema() == "" || messages[1].GetStructuredContent() != started.GetStructuredReply() {
+			t.Errorf("expected the schema and the document to be stored, got %v", messages)
+		}
+	}))
+
+	t.Run("rejects invalid schemas", func(t *testing.T) {
+		srv := NewServer(nil, &structuredAssistant{mockAssistant: newMockAssistant()})
+
+		for _, schema := range []string{`{"type": "itinerary"}`, `not json`} {
+			_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Plan a day in Kyoto", ResponseSchema: schema})
+			if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+				t.Errorf("expected twirp.InvalidArgument error for %s, got %v", schema, err)
+			}
+		}
+
+		_, err := NewServer(nil, newMockAssistant()).StartConversation(ctx, &pb.StartConversationRequest{Message: "Plan a day in Kyoto", ResponseSchema: itinerary})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != twirp.InvalidArgument {
+			t.Errorf("expected twirp.InvalidArgument error without structured replies, got %v", err)
+		}
+	})
+}
+
+func TestServer_ReplyErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		err  error
+		code twirp.ErrorCode
+	}{
+		{fmt.Errorf("%w: Rate limit reached", model.ErrModelRateLimited), twirp.ResourceExhausted},
+		{fmt.Errorf("%w: Service Unavailable", model.ErrModelUnavailable), twirp.Unavailable},
+		{fmt.Errorf("%w: maximum context length exceeded", model.ErrModelRejected), twirp.InvalidArgument},
+		{errors.New("too many tool calls, unable to generate reply"), twirp.Internal},
+	}
+
+	for _, tt := range tests {
+		srv := NewServer(nil, newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+			return "", tt.err
+		}))
+
+		_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Weather in Barcelona?"})
+		if te, ok := err.(twirp.Error); !ok || te.Code() != tt.code || te.Msg() != tt.err.Error() {
+			t.Errorf("expected twirp.%s error for %q, got %v", tt.code, tt.err, err)
+		}
+	}
+}
+
+// guardedAssistant rejects messages mentioning the blocked project
+type guardedAssistant struct {
+	*mockAssistant
+}
+
+func (a *guardedAssistant) CheckMessage(ctx context.Context, text string) error {
+	if strings.Contains(text, "Project Nightshade") {
+		return &model.PolicyViolation{Policy: "blocklist", Reason: "contains a blocked term", Stage: model.StageInput}
+	}
+	return nil
+}
+
+func TestServer_Guardrails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects messages the guardrails block", func(t *testing.T) {
+		srv := NewServer(nil, &guardedAssistant{mockAssistant: newMockAssistant()})
+
+		_, startErr := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Summarise Project Nightshade"})
+		_, continueErr := srv.ContinueConversation(ctx, &pb.ContinueConversationRequest{ConversationId: primitive.NewObjectID().Hex(), Message: "And Project Nightshade?"})
+
+		for _, err := range []error{startErr, continueErr} {
+			te, ok := err.(twirp.Error)
+			if !ok || te.Code() != twirp.InvalidArgument || te.Meta("policy") != "blocklist" {
+				t.Errorf("expected twirp.InvalidArgument error with the blocklist policy, got %v", err)
+			}
+		}
+	})
+
+	t.Run("rejects replies the guardrails block", func(t *testing.T) {
+		violation := &model.PolicyViolation{Policy: "moderation", Reason: "flagged for violence", Stage: model.StageOutput}
+		srv := NewServer(nil, newMockAssistant().withReplyFunc(func(ctx context.Context, conv *model.Conversation) (string, error) {
+			return "", violation
+		}))
+
+		_, err := srv.StartConversation(ctx, &pb.StartConversationRequest{Message: "Weather in Barcelona?"})
+		te, ok := err.(twirp.Error)
+		if !ok || te.Code() != twirp.InvalidArgument || te.Meta("policy") != "moderation" || te.Msg() != violation.Error() {
+			t.Errorf("expected twirp.InvalidArgument error with the moderation policy, got %v", err)
+		}
+	})
+}
+
+func TestRepository_Sealer(t *testing.T) {
+	ctx := context.Background()
+	db := ConnectMongo()
+	sealer, err := pii.NewEncrypter(pii.New(), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := model.New(db, model.WithSealer(sealer))
+
+	const email = "ana@example.com"
+	userID := uuid.NewString()
+	now := time.Now().Truncate(time.Millisecond)
+
+	// stored fails when the raw document holds the email address
+	stored := func(t *testing.T, collection string, id primitive.ObjectID) {
+		t.Helper()
+		raw, err := db.Collection(collection).FindOne(ctx, bson.M{"_id": id}).Raw()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(raw.String(), email) {
+			t.Errorf("expected the %s document sealed, got %s", collection, raw)
+		}
+	}
+
+	t.Run("conversations and their pending tool calls", func(t *testing.T) {
+		conv := &model.Conversation{
+			ID: primitive.NewObjectID(), UserID: userID, Title: "Mail " + email, CreatedAt: now, UpdatedAt: now,
+			Messages: []*model.Message{{ID: primitive.NewObjectID(), Role: model.RoleUser, Content: "Write to " + email, CreatedAt: now}},
+			Pending: &model.PendingReply{CreatedAt: now, Steps: [][]*model.ToolCall{{
+				{ID: "call_1", Name: "send_email", Arguments: `{"to":"` + email + `"}`, Status: model.ToolCallPending},
+			}}},
+		}
+		if err := repo.CreateConversation(ctx, conv); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteConversation(ctx, conv.ID.Hex())
+		stored(t, "conversations", conv.ID)
+
+		got, err := repo.DescribeConversation(ctx, userID, conv.ID.Hex())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Title != conv.Title || got.Messages[0].Content != conv.Messages[0].Content {
+			t.Errorf("expected the conversation opened, got %q and %q", got.Title, got.Messages[0].Content)
+		}
+		if args := got.Pending.Call("call_1").Arguments; args != conv.Pending.Steps[0][0].Arguments {
+			t.Errorf("expected the tool call opened, got %q", args)
+		}
+	})
+
+	t.Run("notes, to-do items and reminders", func(t *testing.T) {
+		note := &model.Note{ID: primitive.NewObjectID(), UserID: userID, Title: email, Content: "Write to " + email, CreatedAt: now, UpdatedAt: now}
+		if err := repo.CreateNote(ctx, note); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteNote(ctx, userID, note.ID.Hex())
+		stored(t, "notes", note.ID)
+
+		todo := &model.Todo{ID: primitive.NewObjectID(), UserID: userID, Text: "Write to " + email, CreatedAt: now, UpdatedAt: now}
+		if err := repo.CreateTodo(ctx, todo); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteTodo(ctx, userID, todo.ID.Hex())
+		stored(t, "todos", todo.ID)
+
+		rem := &model.Reminder{ID: primitive.NewObjectID(), UserID: userID, Text: "Write to " + email, DueAt: now.Add(time.Hour), Status: model.ReminderPending, CreatedAt: now, UpdatedAt: now}
+		if err := repo.CreateReminder(ctx, rem); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteReminder(ctx, userID, rem.ID.Hex())
+		stored(t, "reminders", rem.ID)
+
+		notes, err := repo.ListNotes(ctx, userID, "write to")
+		if err != nil || len(notes) != 1 || notes[0].Content != note.Content || notes[0].Title != email {
+			t.Errorf("expected the note opened, got %v, %v", notes, err)
+		}
+		done, err := repo.CompleteTodo(ctx, userID, todo.ID.Hex())
+		if err != nil || done.Text != todo.Text {
+			t.Errorf("expected the to-do item opened, got %v, %v", done, err)
+		}
+		reminders, err := repo.ListReminders(ctx, userID, false)
+		if err != nil || len(reminders) != 1 || reminders[0].Text != rem.Text {
+			t.Errorf("expected the reminder opened, got %v, %v", reminders, err)
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		e := &model.Event{UserID: userID, UID: uuid.NewString(), Title: "Call " + email, Location: email, Start: now, End: now.Add(time.Hour)}
+		if _, err := repo.ImportEvent(ctx, e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteEvent(ctx, userID, e.ID.Hex())
+		stored(t, "events", e.ID)
+
+		raw, err := db.Collection("events").FindOne(ctx, bson.M{"_id": e.ID}).Raw()
+		if err != nil || raw.Lookup("uid").StringValue() != e.UID {
+			t.Errorf("expected the UID stored as it is, got %v, %v", raw, err)
+		}
+		if e.Title != "Call "+email || e.Location != email {
+			t.Errorf("expected the imported event opened, got %q at %q", e.Title, e.Location)
+		}
+		events, err := repo.ListEvents(ctx, userID, model.EventFilter{})
+		if err != nil || len(events) != 1 || events[0].Title != e.Title {
+			t.Errorf("expected the event opened, got %v, %v", events, err)
+		}
+	})
+
+	t.Run("memories keep their keys", func(t *testing.T) {
+		m := &model.Memory{UserID: userID, Text: "Their email is " + email}
+		if err := repo.SaveMemory(ctx, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteMemory(ctx, userID, m.ID.Hex())
+		stored(t, "memories", m.ID)
+
+		again := &model.Memory{UserID: userID, Text: "their email is " + email + "."}
+		if err := repo.SaveMemory(ctx, again); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.ID != m.ID {
+			t.Errorf("expected the same fact to be kept once")
+		}
+
+		memories, err := repo.ListMemories(ctx, userID)
+		if err != nil || len(memories) != 1 || memories[0].Text != m.Text || memories[0].Key != model.MemoryKey(m.Text) {
+			t.Errorf("expected the memory opened with its key, got %v, %v", memories, err)
+		}
+	})
+
+	t.Run("attachments keep their data as uploaded", func(t *testing.T) {
+		a := &model.Attachment{ID: primitive.NewObjectID(), UserID: userID, Filename: email + ".txt", ContentType: "text/plain", Data: []byte("Write to " + email), CreatedAt: now}
+		chunk := &model.AttachmentChunk{ID: primitive.NewObjectID(), AttachmentID: a.ID, Text: "Write to " + email}
+		if err := repo.CreateAttachment(ctx, a, []*model.AttachmentChunk{chunk}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer repo.DeleteAttachment(ctx, userID, a.ID.Hex())
+		stored(t, "attachment_chunks", chunk.ID)
+
+		raw, err := db.Collection("attachments").FindOne(ctx, bson.M{"_id": a.ID}).Raw()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if filename := raw.Lookup("filename").StringValue(); strings.Contains(filename, email) {
+			t.Errorf("expected the filename sealed, got %q", filename)
+		}
+		if _, data := raw.Lookup("data").Binary(); !bytes.Equal(data, a.Data) {
+			t.Errorf("expected the data stored as uploaded, got %q", data)
+		}
+
+		chunks, err := repo.ListAttachmentChunks(ctx, []primitive.ObjectID{a.ID})
+		if err != nil || len(chunks) != 1 || chunks[0].Text != chunk.Text {
+			t.Errorf("expected the chunk opened, got %v, %v", chunks, err)
+		}
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/isabermoussa/personal-assistant-API/internal/auth"
 	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -27,6 +28,7 @@ func WithFixture(runner func(t *testing.T, f *Fixture)) func(t *testing.T) {
 func (f *Fixture) CreateConversation(mods ...func(*model.Conversation)) *model.Conversation {
 	c := &model.Conversation{
 		ID:        primitive.NewObjectID(),
+		UserID:    auth.DefaultUser,
 		Title:     uuid.New().String(),
 		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
@@ -58,6 +60,145 @@ func (f *Fixture) CreateConversation(mods ...func(*model.Conversation)) *model.C
 	return c
 }
 
+func (f *Fixture) CreateNote(userID string, mods ...func(*model.Note)) *model.Note {
+	n := &model.Note{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Title:     uuid.New().String(),
+		Content:   "Renew passport before the trip",
+		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, mod := range mods {
+		mod(n)
+	}
+
+	ctx := context.Background()
+
+	if err := f.Repository.CreateNote(ctx, n); err != nil {
+		f.test.Fatalf("failed to create note: %v", err)
+	}
+
+	f.defers = append(f.defers, func() {
+		if err := f.Repository.DeleteNote(ctx, n.UserID, n.ID.Hex()); err != nil {
+			f.test.Logf("failed to cleanup note %s: %v", n.ID.Hex(), err)
+		}
+	})
+
+	return n
+}
+
+func (f *Fixture) CreateTodo(userID string, mods ...func(*model.Todo)) *model.Todo {
+	t := &model.Todo{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Text:      uuid.New().String(),
+		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, mod := range mods {
+		mod(t)
+	}
+
+	ctx := context.Background()
+
+	if err := f.Repository.CreateTodo(ctx, t); err != nil {
+		f.test.Fatalf("failed to create todo: %v", err)
+	}
+
+	f.defers = append(f.defers, func() {
+		if err := f.Repository.DeleteTodo(ctx, t.UserID, t.ID.Hex()); err != nil {
+			f.test.Logf("failed to cleanup todo %s: %v", t.ID.Hex(), err)
+		}
+	})
+
+	return t
+}
+
+func (f *Fixture) CreateReminder(userID string, mods ...func(*model.Reminder)) *model.Reminder {
+	r := &model.Reminder{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Text:      uuid.New().String(),
+		DueAt:     time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC),
+		TimeZone:  "UTC",
+		Status:    model.ReminderPending,
+		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Delivered: []string{},
+	}
+
+	for _, mod := range mods {
+		mod(r)
+	}
+
+	ctx := context.Background()
+
+	if err := f.Repository.CreateReminder(ctx, r); err != nil {
+		f.test.Fatalf("failed to create reminder: %v", err)
+	}
+
+	f.defers = append(f.defers, func() {
+		if err := f.Repository.DeleteReminder(ctx, r.UserID, r.ID.Hex()); err != nil {
+			f.test.Logf("failed to cleanup reminder %s: %v", r.ID.Hex(), err)
+		}
+	})
+
+	return r
+}
+
+func (f *Fixture) CreateEvent(userID string, mods ...func(*model.Event)) *model.Event {
+	e := &model.Event{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		UID:       uuid.New().String(),
+		Title:     uuid.New().String(),
+		Start:     time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC),
+		End:       time.Date(2023, 10, 2, 10, 0, 0, 0, time.UTC),
+		TimeZone:  "UTC",
+		CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, mod := range mods {
+		mod(e)
+	}
+
+	ctx := context.Background()
+
+	if err := f.Repository.CreateEvent(ctx, e); err != nil {
+		f.test.Fatalf("failed to create event: %v", err)
+	}
+
+	f.defers = append(f.defers, func() {
+		if err := f.Repository.DeleteEvent(ctx, e.UserID, e.ID.Hex()); err != nil {
+			f.test.Logf("failed to cleanup event %s: %v", e.ID.Hex(), err)
+		}
+	})
+
+	return e
+}
+
+func (f *Fixture) CreateMemory(userID, text string) *model.Memory {
+	m := &model.Memory{UserID: userID, Text: text}
+
+	ctx := context.Background()
+
+	if err := f.Repository.SaveMemory(ctx, m); err != nil {
+		f.test.Fatalf("failed to create memory: %v", err)
+	}
+
+	f.defers = append(f.defers, func() {
+		if err := f.Repository.DeleteMemory(ctx, m.UserID, m.ID.Hex()); err != nil {
+			f.test.Logf("failed to cleanup memory %s: %v", m.ID.Hex(), err)
+		}
+	})
+
+	return m
+}
+
 func (f *Fixture) Teardown() {
 	for _, d := range f.defers {
 		d()
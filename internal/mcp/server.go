@@ -0,0 +1,297 @@
+// Package mcp exposes assistant tools over the Model Context Protocol.
+// It implements the JSON-RPC 2.0 subset needed for tool discovery and invocation,
+// served either over stdio (newline-delimited messages) or HTTP (one message per POST).
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
+)
+
+// ProtocolVersion is the latest MCP revision this server speaks
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions lists every revision the server accepts during initialization
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// maxMessageSize bounds a single JSON-RPC message read from stdio or HTTP
+const maxMessageSize = 4 << 20
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Server answers MCP requests using a fixed set of tools
+type Server struct {
+	name    string
+	version string
+	tools   []tools.Tool
+}
+
+// NewServer creates an MCP server that advertises and runs the given tools
+func NewServer(name, version string, list []tools.Tool) *Server {
+	return &Server{name: name, version: version, tools: list}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Tool is the MCP description of a tool returned by tools/list
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// Content is a single block of a tools/call result
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallToolResult is the result of tools/call
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Handle processes a single JSON-RPC message and returns the encoded response.
+// It returns nil for notifications, which must not be answered.
+func (s *Server) Handle(ctx context.Context, msg []byte) []byte {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return encode(response{ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error"}})
+	}
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return encode(response{ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}})
+	}
+
+	// Requests without an ID are notifications (e.g. notifications/initialized)
+	if len(req.ID) == 0 {
+		slog.DebugContext(ctx, "MCP notification received", "method", req.Method)
+		return nil
+	}
+
+	result, rerr := s.dispatch(ctx, req)
+	if rerr != nil {
+		return encode(response{ID: req.ID, Error: rerr})
+	}
+
+	return encode(response{ID: req.ID, Result: result})
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		version := ProtocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities": map[string]any{
+				"tools": map[string]any{"listChanged": false},
+			},
+			"serverInfo": map[string]any{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		return map[string]any{"tools": s.List()}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Name == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "tool name is required"}
+		}
+
+		result, err := s.Call(ctx, params.Name, params.Arguments)
+		if errors.Is(err, tools.ErrUnknownTool) {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("Unknown tool: %s", params.Name)}
+		}
+
+		return result, nil
+
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// List translates every tool definition into its MCP description.
+// Only function tools are exported, since MCP has no notion of free-form custom tools.
+func (s *Server) List() []Tool {
+	list := make([]Tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		def := t.Definition().OfFunction
+		if def == nil {
+			continue
+		}
+
+		schema := map[string]any{}
+		for k, v := range def.Function.Parameters {
+			schema[k] = v
+		}
+		// MCP requires an object schema even for tools without parameters
+		if _, ok := schema["type"]; !ok {
+			schema["type"] = "object"
+		}
+
+		list = append(list, Tool{
+			Name:        def.Function.Name,
+			Description: def.Function.Description.Value,
+			InputSchema: schema,
+		})
+	}
+	return list
+}
+
+// Call runs the named tool. Tool failures are reported inside the result with IsError set,
+// as MCP expects, while an unknown tool name is returned as tools.ErrUnknownTool.
+func (s *Server) Call(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	args := string(arguments)
+	if len(arguments) == 0 || string(arguments) == "null" {
+		args = "{}"
+	}
+
+	result, err := tools.Execute(ctx, s.tools, name, args)
+	if errors.Is(err, tools.ErrUnknownTool) {
+		return nil, err
+	}
+	if err != nil {
+		return &CallToolResult{
+			Content: []Content{{Type: "text", Text: fmt.Sprintf("Tool failed: %v", err)}},
+			IsError: true,
+		}, nil
+	}
+
+	return &CallToolResult{Content: []Content{{Type: "text", Text: result}}}, nil
+}
+
+// ServeStdio reads newline-delimited JSON-RPC messages from r and writes responses to w
+// until r is exhausted or ctx is cancelled.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		out := s.Handle(ctx, line)
+		if out == nil {
+			continue
+		}
+
+		if _, err := w.Write(append(out, '\n')); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ServeHTTP implements the request side of the streamable HTTP transport:
+// each POST carries one JSON-RPC message and gets a JSON response.
+// Server-initiated streams are not used, so GET is rejected.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	out := s.Handle(r.Context(), body)
+	if out == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(out)
+}
+
+func unmarshalParams(raw json.RawMessage, v any) *rpcError {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func encode(resp response) []byte {
+	resp.JSONRPC = "2.0"
+	out, err := json.Marshal(resp)
+	if err != nil {
+		// Results are built from plain maps and strings, so this should never happen
+		out, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID, Error: &rpcError{Code: codeInternalError, Message: "internal error"}})
+	}
+	return out
+}
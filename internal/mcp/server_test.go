@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/assistant/tools"
+	"github.com/openai/openai-go/v2"
+)
+
+// echoTool returns its arguments, or fails when asked to
+type echoTool struct{}
+
+func (t *echoTool) Name() string { return "echo" }
+
+func (t *echoTool) Definition() openai.ChatCompletionToolUnionParam {
+	return openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
+		Name:        "echo",
+		Description: openai.String("Echo the given text"),
+		Parameters: openai.FunctionParameters{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]string{"type": "string"},
+			},
+			"required": []string{"text"},
+		},
+	})
+}
+
+func (t *echoTool) Handle(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", err
+	}
+	if params.Text == "fail" {
+		return "", errors.New("echo refused")
+	}
+	return params.Text, nil
+}
+
+func newTestServer() *Server {
+	return NewServer("test", "0.0.1", []tools.Tool{&echoTool{}, tools.NewDateTool()})
+}
+
+func call(t *testing.T, s *Server, msg string) map[string]any {
+	t.Helper()
+
+	out := s.Handle(context.Background(), []byte(msg))
+	if out == nil {
+		t.Fatalf("expected response for %s, got none", msg)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("invalid response JSON %s: %v", out, err)
+	}
+	return resp
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := newTestServer()
+
+	t.Run("initialize negotiates protocol version", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+		result := resp["result"].(map[string]any)
+		if result["protocolVersion"] != "2025-03-26" {
+			t.Errorf("expected requested version, got %v", result["protocolVersion"])
+		}
+
+		resp = call(t, s, `{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+		result = resp["result"].(map[string]any)
+		if result["protocolVersion"] != ProtocolVersion {
+			t.Errorf("expected latest version for unknown request, got %v", result["protocolVersion"])
+		}
+	})
+
+	t.Run("notifications are not answered", func(t *testing.T) {
+		if out := s.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)); out != nil {
+			t.Errorf("expected no response, got %s", out)
+		}
+	})
+
+	t.Run("tools/list translates definitions", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+		list := resp["result"].(map[string]any)["tools"].([]any)
+		if len(list) != 2 {
+			t.Fatalf("expected 2 tools, got %d", len(list))
+		}
+
+		echo := list[0].(map[string]any)
+		if echo["name"] != "echo" || echo["description"] != "Echo the given text" {
+			t.Errorf("unexpected tool description: %v", echo)
+		}
+		if echo["inputSchema"].(map[string]any)["required"] == nil {
+			t.Errorf("expected schema to keep required fields: %v", echo["inputSchema"])
+		}
+
+		// Tools without parameters still get an object schema
+		date := list[1].(map[string]any)
+		if date["inputSchema"].(map[string]any)["type"] != "object" {
+			t.Errorf("expected object schema, got %v", date["inputSchema"])
+		}
+	})
+
+	t.Run("tools/call returns text content", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hello"}}}`)
+		result := resp["result"].(map[string]any)
+		content := result["content"].([]any)[0].(map[string]any)
+		if content["text"] != "hello" {
+			t.Errorf("expected echoed text, got %v", content["text"])
+		}
+		if result["isError"] != nil {
+			t.Errorf("expected success, got %v", result)
+		}
+	})
+
+	t.Run("tools/call reports tool failures as results", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"text":"fail"}}}`)
+		result := resp["result"].(map[string]any)
+		if result["isError"] != true {
+			t.Errorf("expected isError, got %v", result)
+		}
+		content := result["content"].([]any)[0].(map[string]any)
+		if content["text"] != "Tool failed: echo refused" {
+			t.Errorf("unexpected failure text: %v", content["text"])
+		}
+	})
+
+	t.Run("tools/call rejects unknown tools", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`)
+		rerr := resp["error"].(map[string]any)
+		if rerr["code"] != float64(codeInvalidParams) {
+			t.Errorf("expected invalid params, got %v", rerr)
+		}
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		resp := call(t, s, `{"jsonrpc":"2.0","id":6,"method":"resources/list"}`)
+		if resp["error"].(map[string]any)["code"] != float64(codeMethodNotFound) {
+			t.Errorf("expected method not found, got %v", resp)
+		}
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		resp := call(t, s, `{not json`)
+		if resp["error"].(map[string]any)["code"] != float64(codeParseError) {
+			t.Errorf("expected parse error, got %v", resp)
+		}
+	})
+}
+
+func TestServer_ServeStdio(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+	}, "\n"))
+
+	var out bytes.Buffer
+	if err := newTestServer().ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeStdio failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 responses, got %d: %q", len(lines), out.String())
+	}
+	if !strings.Contains(lines[1], `"text":"hi"`) {
+		t.Errorf("expected tool result in second response, got %s", lines[1])
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	srv := httptest.NewServer(newTestServer())
+	defer srv.Close()
+
+	t.Run("answers requests", func(t *testing.T) {
+		resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected JSON content type, got %q", ct)
+		}
+	})
+
+	t.Run("accepts notifications", func(t *testing.T) {
+		resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("expected 202, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("rejects GET", func(t *testing.T) {
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MessageAppender adds messages to conversations
+type MessageAppender interface {
+	AppendMessage(ctx context.Context, conversationID primitive.ObjectID, m *model.Message) error
+}
+
+// ConversationDeliverer posts reminders as assistant messages in the
+// conversation they were set in
+type ConversationDeliverer struct {
+	conversations MessageAppender
+}
+
+// NewConversationDeliverer creates a deliverer appending to conversations
+func NewConversationDeliverer(conversations MessageAppender) *ConversationDeliverer {
+	return &ConversationDeliverer{
+		conversations: conversations,
+	}
+}
+
+func (c *ConversationDeliverer) Name() string {
+	return "conversation"
+}
+
+func (c *ConversationDeliverer) Deliver(ctx context.Context, r *model.Reminder) error {
+	if r.ConversationID.IsZero() {
+		return nil
+	}
+
+	now := time.Now()
+	msg := &model.Message{
+		// The reminder's ID makes redelivery append nothing
+		ID:        r.ID,
+		Role:      model.RoleAssistant,
+		Content:   Message(r),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := c.conversations.AppendMessage(ctx, r.ConversationID, msg)
+
+	// A deleted conversation can't receive the reminder, retrying won't help
+	var te twirp.Error
+	if errors.As(err, &te) && te.Code() == twirp.NotFound {
+		slog.WarnContext(ctx, "Reminder conversation no longer exists", "reminder_id", r.ID.Hex(), "conversation_id", r.ConversationID.Hex())
+		return nil
+	}
+
+	return err
+}
+
+// Message is the text a reminder is delivered with
+func Message(r *model.Reminder) string {
+	return fmt.Sprintf("Reminder: %s (set for %s)", r.Text, r.Local().Format("Mon 2 Jan 2006 15:04 MST"))
+}
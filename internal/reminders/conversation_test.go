@@ -0,0 +1,82 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"github.com/twitchtv/twirp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeAppender records appended messages, or fails with err
+type fakeAppender struct {
+	conversation primitive.ObjectID
+	messages     []*model.Message
+	err          error
+}
+
+func (f *fakeAppender) AppendMessage(ctx context.Context, conversationID primitive.ObjectID, m *model.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.conversation = conversationID
+	f.messages = append(f.messages, m)
+	return nil
+}
+
+func TestConversationDeliverer(t *testing.T) {
+	reminder := &model.Reminder{
+		ID:             primitive.NewObjectID(),
+		ConversationID: primitive.NewObjectID(),
+		Text:           "check in for flight IB3166",
+		DueAt:          time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC),
+		TimeZone:       "Europe/Madrid",
+	}
+
+	t.Run("appends an assistant message", func(t *testing.T) {
+		store := &fakeAppender{}
+		if err := NewConversationDeliverer(store).Deliver(context.Background(), reminder); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if store.conversation != reminder.ConversationID || len(store.messages) != 1 {
+			t.Fatalf("expected one message in conversation %s, got %d in %s", reminder.ConversationID.Hex(), len(store.messages), store.conversation.Hex())
+		}
+		m := store.messages[0]
+		if m.ID != reminder.ID || m.Role != model.RoleAssistant {
+			t.Errorf("expected an assistant message with the reminder's ID, got %+v", m)
+		}
+		if want := "Reminder: check in for flight IB3166 (set for Mon 19 Oct 2026 09:00 CEST)"; m.Content != want {
+			t.Errorf("content = %q, want %q", m.Content, want)
+		}
+	})
+
+	t.Run("skips reminders without a conversation", func(t *testing.T) {
+		store := &fakeAppender{}
+		r := *reminder
+		r.ConversationID = primitive.NilObjectID
+		if err := NewConversationDeliverer(store).Deliver(context.Background(), &r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(store.messages) != 0 {
+			t.Errorf("expected no messages, got %d", len(store.messages))
+		}
+	})
+
+	t.Run("deleted conversations are not retried", func(t *testing.T) {
+		store := &fakeAppender{err: twirp.NotFoundError("conversation not found")}
+		if err := NewConversationDeliverer(store).Deliver(context.Background(), reminder); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("storage errors are returned", func(t *testing.T) {
+		store := &fakeAppender{err: errors.New("connection reset")}
+		if err := NewConversationDeliverer(store).Deliver(context.Background(), reminder); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
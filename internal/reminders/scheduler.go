@@ -0,0 +1,209 @@
+// Package reminders delivers due reminders. The Scheduler claims reminders from
+// the store with a lease, so deliveries resume after a restart and several
+// servers can share one database; each Deliverer is a delivery channel.
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultInterval is how often the scheduler looks for due reminders
+	DefaultInterval = 15 * time.Second
+
+	// DefaultMaxAttempts is how many times a reminder is tried before it fails
+	DefaultMaxAttempts = 5
+
+	// defaultLease is how long a claimed reminder is reserved for its delivery
+	defaultLease = 2 * time.Minute
+
+	// defaultRetryDelay is the wait before the first retry; it doubles after each attempt
+	defaultRetryDelay = time.Minute
+
+	// deliveryTimeout bounds one channel's delivery of one reminder
+	deliveryTimeout = 30 * time.Second
+)
+
+// Store persists reminders and their delivery state
+type Store interface {
+	ClaimDueReminder(ctx context.Context, now time.Time, lease time.Duration) (*model.Reminder, error)
+	MarkReminderChannelDelivered(ctx context.Context, id primitive.ObjectID, channel string) error
+	DeferReminder(ctx context.Context, id primitive.ObjectID, until time.Time, lastError string) error
+	FinishReminder(ctx context.Context, id primitive.ObjectID, status model.ReminderStatus, lastError string, at time.Time) error
+}
+
+// Deliverer is a delivery channel for reminders
+type Deliverer interface {
+	// Name identifies the channel in logs and delivery bookkeeping
+	Name() string
+
+	// Deliver sends a reminder; it may be called again for the same reminder
+	// if the server stops before the delivery is recorded
+	Deliver(ctx context.Context, r *model.Reminder) error
+}
+
+// Scheduler delivers reminders when they are due
+type Scheduler struct {
+	store       Store
+	deliverers  []Deliverer
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	retryDelay  time.Duration
+	now         func() time.Time
+}
+
+// Option configures a Scheduler
+type Option func(*Scheduler)
+
+// WithInterval sets how often the scheduler looks for due reminders
+func WithInterval(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = d
+	}
+}
+
+// WithMaxAttempts sets how many times a reminder is tried before it fails
+func WithMaxAttempts(n int) Option {
+	return func(s *Scheduler) {
+		s.maxAttempts = n
+	}
+}
+
+// WithRetryDelay sets the wait before the first retry of a failed delivery
+func WithRetryDelay(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.retryDelay = d
+	}
+}
+
+// NewScheduler creates a scheduler delivering reminders from store through every deliverer
+func NewScheduler(store Store, deliverers []Deliverer, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		store:       store,
+		deliverers:  deliverers,
+		interval:    DefaultInterval,
+		lease:       defaultLease,
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  defaultRetryDelay,
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// NewDeliverersFromEnv returns the conversation channel, plus a webhook when
+// REMINDER_WEBHOOK_URL is set (signed with REMINDER_WEBHOOK_SECRET, if set)
+func NewDeliverersFromEnv(conversations MessageAppender) []Deliverer {
+	deliverers := []Deliverer{NewConversationDeliverer(conversations)}
+
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		var opts []WebhookOption
+		if secret := os.Getenv("REMINDER_WEBHOOK_SECRET"); secret != "" {
+			opts = append(opts, WithSecret(secret))
+		}
+		deliverers = append(deliverers, NewWebhookDeliverer(url, opts...))
+	}
+
+	return deliverers
+}
+
+// Run delivers due reminders every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	slog.InfoContext(ctx, "Reminder scheduler started", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to claim due reminders", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce delivers every reminder that is due and returns how many were delivered
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	delivered := 0
+	for ctx.Err() == nil {
+		r, err := s.store.ClaimDueReminder(ctx, s.now(), s.lease)
+		if err != nil {
+			return delivered, err
+		}
+		if r == nil {
+			break
+		}
+
+		if s.deliver(ctx, r) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver sends a claimed reminder through the channels that haven't delivered it yet
+func (s *Scheduler) deliver(ctx context.Context, r *model.Reminder) bool {
+	var errs []error
+	for _, d := range s.deliverers {
+		if slices.Contains(r.Delivered, d.Name()) {
+			continue
+		}
+
+		dctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+		err := d.Deliver(dctx, r)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.Name(), err))
+			continue
+		}
+		if err := s.store.MarkReminderChannelDelivered(ctx, r.ID, d.Name()); err != nil {
+			slog.ErrorContext(ctx, "Failed to record reminder delivery", "reminder_id", r.ID.Hex(), "channel", d.Name(), "error", err)
+		}
+	}
+
+	now := s.now()
+	if len(errs) == 0 {
+		if err := s.store.FinishReminder(ctx, r.ID, model.ReminderDelivered, "", now); err != nil {
+			slog.ErrorContext(ctx, "Failed to mark reminder delivered", "reminder_id", r.ID.Hex(), "error", err)
+		}
+		slog.InfoContext(ctx, "Reminder delivered", "reminder_id", r.ID.Hex(), "user_id", r.UserID, "late_by", now.Sub(r.DueAt).Round(time.Second))
+		return true
+	}
+
+	err := errors.Join(errs...)
+	if r.Attempts >= s.maxAttempts {
+		slog.ErrorContext(ctx, "Reminder delivery failed, giving up", "reminder_id", r.ID.Hex(), "attempts", r.Attempts, "error", err)
+		if err := s.store.FinishReminder(ctx, r.ID, model.ReminderFailed, err.Error(), now); err != nil {
+			slog.ErrorContext(ctx, "Failed to mark reminder failed", "reminder_id", r.ID.Hex(), "error", err)
+		}
+		return false
+	}
+
+	retryAt := now.Add(s.retryDelay << max(r.Attempts-1, 0))
+	slog.WarnContext(ctx, "Reminder delivery failed, will retry", "reminder_id", r.ID.Hex(), "attempts", r.Attempts, "retry_at", retryAt, "error", err)
+	if err := s.store.DeferReminder(ctx, r.ID, retryAt, err.Error()); err != nil {
+		slog.ErrorContext(ctx, "Failed to reschedule reminder", "reminder_id", r.ID.Hex(), "error", err)
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore is an in-memory Store with the repository's claiming rules
+type memoryStore struct {
+	mu        sync.Mutex
+	reminders []*model.Reminder
+}
+
+func (m *memoryStore) add(r *model.Reminder) *model.Reminder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r.ID.IsZero() {
+		r.ID = primitive.NewObjectID()
+	}
+	if r.Status == "" {
+		r.Status = model.ReminderPending
+	}
+	m.reminders = append(m.reminders, r)
+	return r
+}
+
+func (m *memoryStore) get(id primitive.ObjectID) *model.Reminder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.reminders {
+		if r.ID == id {
+			c := *r
+			return &c
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) ClaimDueReminder(ctx context.Context, now time.Time, lease time.Duration) (*model.Reminder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due *model.Reminder
+	for _, r := range m.reminders {
+		if r.Status != model.ReminderPending || r.DueAt.After(now) || r.LeaseUntil != nil && r.LeaseUntil.After(now) {
+			continue
+		}
+		if due == nil || r.DueAt.Before(due.DueAt) {
+			due = r
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	until := now.Add(lease)
+	due.LeaseUntil = &until
+	due.Attempts++
+	c := *due
+	c.Delivered = slices.Clone(due.Delivered)
+	return &c, nil
+}
+
+func (m *memoryStore) update(id primitive.ObjectID, fn func(r *model.Reminder)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.reminders {
+		if r.ID == id {
+			fn(r)
+			return nil
+		}
+	}
+	return errors.New("reminder not found")
+}
+
+func (m *memoryStore) MarkReminderChannelDelivered(ctx context.Context, id primitive.ObjectID, channel string) error {
+	return m.update(id, func(r *model.Reminder) {
+		if !slices.Contains(r.Delivered, channel) {
+			r.Delivered = append(r.Delivered, channel)
+		}
+	})
+}
+
+func (m *memoryStore) DeferReminder(ctx context.Context, id primitive.ObjectID, until time.Time, lastError string) error {
+	return m.update(id, func(r *model.Reminder) {
+		if r.Status == model.ReminderPending {
+			r.LeaseUntil, r.LastError = &until, lastError
+		}
+	})
+}
+
+func (m *memoryStore) FinishReminder(ctx context.Context, id primitive.ObjectID, status model.ReminderStatus, lastError string, at time.Time) error {
+	return m.update(id, func(r *model.Reminder) {
+		if r.Status != model.ReminderPending {
+			return
+		}
+		r.Status, r.LastError, r.LeaseUntil = status, lastError, nil
+		if status == model.ReminderDelivered {
+			r.DeliveredAt = &at
+		}
+	})
+}
+
+// recorder is a Deliverer that records deliveries and fails while err is set
+type recorder struct {
+	name string
+	mu   sync.Mutex
+	got  []primitive.ObjectID
+	err  error
+}
+
+func (r *recorder) Name() string { return r.name }
+
+func (r *recorder) Deliver(ctx context.Context, rem *model.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.got = append(r.got, rem.ID)
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.got)
+}
+
+// clock is a settable time source
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestScheduler(store Store, deliverers []Deliverer, c *clock, opts ...Option) *Scheduler {
+	s := NewScheduler(store, deliverers, opts...)
+	s.now = c.Now
+	return s
+}
+
+func TestScheduler_DeliversDueReminders(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+	store := &memoryStore{}
+
+	due := store.add(&model.Reminder{Text: "check in", DueAt: c.now.Add(-time.Minute)})
+	overdue := store.add(&model.Reminder{Text: "call mum", DueAt: c.now.Add(-time.Hour)})
+	later := store.add(&model.Reminder{Text: "pack", DueAt: c.now.Add(time.Hour)})
+	cancelled := store.add(&model.Reminder{Text: "old", DueAt: c.now.Add(-time.Hour), Status: model.ReminderCancelled})
+
+	webhook, conv := &recorder{name: "webhook"}, &recorder{name: "conversation"}
+	s := newTestScheduler(store, []Deliverer{conv, webhook}, c)
+
+	n, err := s.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("delivered %d reminders, want 2", n)
+	}
+
+	// Most overdue first, through every channel
+	want := []primitive.ObjectID{overdue.ID, due.ID}
+	if !slices.Equal(webhook.got, want) || !slices.Equal(conv.got, want) {
+		t.Errorf("deliveries: webhook %v, conversation %v, want %v", webhook.got, conv.got, want)
+	}
+
+	for _, id := range want {
+		r := store.get(id)
+		if r.Status != model.ReminderDelivered || r.DeliveredAt == nil || r.LeaseUntil != nil {
+			t.Errorf("reminder %q not finished: %+v", r.Text, r)
+		}
+		if !slices.Equal(r.Delivered, []string{"conversation", "webhook"}) {
+			t.Errorf("reminder %q delivered through %v", r.Text, r.Delivered)
+		}
+	}
+	if got := store.get(later.ID).Status; got != model.ReminderPending {
+		t.Errorf("future reminder is %s, want pending", got)
+	}
+	if got := store.get(cancelled.ID).Status; got != model.ReminderCancelled {
+		t.Errorf("cancelled reminder is %s", got)
+	}
+
+	// Nothing is delivered twice
+	c.Advance(30 * time.Minute)
+	if n, _ := s.RunOnce(ctx); n != 0 {
+		t.Errorf("second run delivered %d reminders, want 0", n)
+	}
+	c.Advance(time.Hour)
+	if n, _ := s.RunOnce(ctx); n != 1 || webhook.count() != 3 {
+		t.Errorf("expected the later reminder once it is due, delivered %d", n)
+	}
+}
+
+func TestScheduler_RetriesFailedChannels(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+	store := &memoryStore{}
+	r := store.add(&model.Reminder{Text: "check in", DueAt: c.now})
+
+	webhook := &recorder{name: "webhook", err: errors.New("503 Service Unavailable")}
+	conv := &recorder{name: "conversation"}
+	s := newTestScheduler(store, []Deliverer{conv, webhook}, c, WithRetryDelay(time.Minute))
+
+	if n, _ := s.RunOnce(ctx); n != 0 {
+		t.Fatalf("delivered %d reminders, want 0", n)
+	}
+	got := store.get(r.ID)
+	if got.Status != model.ReminderPending || got.LastError == "" || got.Attempts != 1 {
+		t.Fatalf("expected a pending reminder with the error recorded, got %+v", got)
+	}
+
+	// Not retried before the retry delay
+	c.Advance(30 * time.Second)
+	if s.RunOnce(ctx); store.get(r.ID).Attempts != 1 {
+		t.Fatalf("reminder retried before its retry delay")
+	}
+
+	// The retry only uses the channel that failed
+	webhook.err = nil
+	c.Advance(time.Minute)
+	if n, _ := s.RunOnce(ctx); n != 1 {
+		t.Fatalf("retry delivered %d reminders, want 1", n)
+	}
+	if conv.count() != 1 || webhook.count() != 1 {
+		t.Errorf("deliveries: conversation %d, webhook %d, want 1 each", conv.count(), webhook.count())
+	}
+	if got := store.get(r.ID); got.Status != model.ReminderDelivered {
+		t.Errorf("status = %s, want delivered", got.Status)
+	}
+}
+
+func TestScheduler_GivesUp(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+	store := &memoryStore{}
+	r := store.add(&model.Reminder{Text: "check in", DueAt: c.now})
+
+	webhook := &recorder{name: "webhook", err: errors.New("connection refused")}
+	s := newTestScheduler(store, []Deliverer{webhook}, c, WithMaxAttempts(3), WithRetryDelay(time.Minute))
+
+	for i := 0; i < 10; i++ {
+		if _, err := s.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.Advance(10 * time.Minute)
+	}
+
+	got := store.get(r.ID)
+	if got.Status != model.ReminderFailed || got.Attempts != 3 {
+		t.Errorf("expected failed after 3 attempts, got %s after %d", got.Status, got.Attempts)
+	}
+	if got.LastError == "" {
+		t.Error("expected the last error to be recorded")
+	}
+}
+
+func TestScheduler_ResumesAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+	store := &memoryStore{}
+
+	// A server stopped while delivering: the reminder is leased and one channel is done
+	lease := c.now.Add(time.Minute)
+	r := store.add(&model.Reminder{
+		Text:       "check in",
+		DueAt:      c.now.Add(-time.Minute),
+		LeaseUntil: &lease,
+		Attempts:   1,
+		Delivered:  []string{"conversation"},
+	})
+
+	webhook, conv := &recorder{name: "webhook"}, &recorder{name: "conversation"}
+	s := newTestScheduler(store, []Deliverer{conv, webhook}, c)
+
+	if n, _ := s.RunOnce(ctx); n != 0 {
+		t.Fatalf("leased reminder was delivered before its lease expired")
+	}
+
+	c.Advance(2 * time.Minute)
+	if n, _ := s.RunOnce(ctx); n != 1 {
+		t.Fatalf("expected the reminder to be delivered once the lease expired")
+	}
+	if conv.count() != 0 || webhook.count() != 1 {
+		t.Errorf("deliveries: conversation %d, webhook %d, want only the webhook", conv.count(), webhook.count())
+	}
+	if got := store.get(r.ID); got.Status != model.ReminderDelivered {
+		t.Errorf("status = %s, want delivered", got.Status)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	store := &memoryStore{}
+	r := store.add(&model.Reminder{Text: "check in", DueAt: time.Now().Add(-time.Second)})
+
+	conv := &recorder{name: "conversation"}
+	s := NewScheduler(store, []Deliverer{conv}, WithInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for store.get(r.ID).Status != model.ReminderDelivered {
+		select {
+		case <-deadline:
+			t.Fatal("reminder was not delivered")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+}
+
+func TestNewDeliverersFromEnv(t *testing.T) {
+	t.Setenv("REMINDER_WEBHOOK_URL", "")
+	if got := NewDeliverersFromEnv(nil); len(got) != 1 || got[0].Name() != "conversation" {
+		t.Errorf("expected only the conversation channel, got %d channels", len(got))
+	}
+
+	t.Setenv("REMINDER_WEBHOOK_URL", "https://hooks.example.com/reminders")
+	t.Setenv("REMINDER_WEBHOOK_SECRET", "s3cret")
+	got := NewDeliverersFromEnv(nil)
+	if len(got) != 2 || got[1].Name() != "webhook" {
+		t.Fatalf("expected conversation and webhook channels, got %d channels", len(got))
+	}
+	if w := got[1].(*WebhookDeliverer); string(w.secret) != "s3cret" {
+		t.Errorf("webhook secret not set")
+	}
+}
@@ -0,0 +1,121 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the webhook body, as "sha256=<hex>"
+const SignatureHeader = "X-Reminder-Signature"
+
+// WebhookPayload is the JSON body POSTed for each reminder. Deliveries are
+// at least once; receivers can use ID to drop duplicates.
+type WebhookPayload struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	ConversationID string    `json:"conversation_id,omitempty"`
+	Text           string    `json:"text"`
+	DueAt          time.Time `json:"due_at"`
+	TimeZone       string    `json:"time_zone"`
+}
+
+// WebhookDeliverer POSTs reminders to a URL
+type WebhookDeliverer struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+}
+
+// WebhookOption configures a WebhookDeliverer
+type WebhookOption func(*WebhookDeliverer)
+
+// WithSecret signs every request body with the given secret
+func WithSecret(secret string) WebhookOption {
+	return func(w *WebhookDeliverer) {
+		w.secret = []byte(secret)
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *WebhookDeliverer) {
+		w.httpClient = client
+	}
+}
+
+// NewWebhookDeliverer creates a deliverer POSTing reminders to url
+func NewWebhookDeliverer(url string, opts ...WebhookOption) *WebhookDeliverer {
+	w := &WebhookDeliverer{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+func (w *WebhookDeliverer) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookDeliverer) Deliver(ctx context.Context, r *model.Reminder) error {
+	payload := WebhookPayload{
+		ID:       r.ID.Hex(),
+		UserID:   r.UserID,
+		Text:     r.Text,
+		DueAt:    r.Local(),
+		TimeZone: r.TimeZone,
+	}
+	if !r.ConversationID.IsZero() {
+		payload.ConversationID = r.ConversationID.Hex()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// Sign returns the signature header value of a webhook body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
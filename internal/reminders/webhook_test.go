@@ -0,0 +1,111 @@
+package reminders
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabermoussa/personal-assistant-API/internal/chat/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWebhookDeliverer(t *testing.T) {
+	reminder := &model.Reminder{
+		ID:             primitive.NewObjectID(),
+		UserID:         "ana",
+		ConversationID: primitive.NewObjectID(),
+		Text:           "Check in for flight IB3166",
+		DueAt:          time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC),
+		TimeZone:       "Europe/Madrid",
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		status  int
+		wantErr string
+	}{
+		{name: "delivered", status: http.StatusNoContent},
+		{name: "signed", secret: "s3cret", status: http.StatusOK},
+		{name: "rejected", status: http.StatusServiceUnavailable, wantErr: "status 503: try later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				body      []byte
+				signature string
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+				}
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(SignatureHeader)
+				w.WriteHeader(tt.status)
+				if tt.status >= 300 {
+					_, _ = w.Write([]byte("try later\n"))
+				}
+			}))
+			defer srv.Close()
+
+			var opts []WebhookOption
+			if tt.secret != "" {
+				opts = append(opts, WithSecret(tt.secret))
+			}
+			err := NewWebhookDeliverer(srv.URL, opts...).Deliver(context.Background(), reminder)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got WebhookPayload
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("invalid payload %s: %v", body, err)
+			}
+			if got.ID != reminder.ID.Hex() || got.UserID != "ana" || got.ConversationID != reminder.ConversationID.Hex() || got.Text != reminder.Text {
+				t.Errorf("unexpected payload: %s", body)
+			}
+			if !strings.Contains(string(body), `"due_at":"2026-10-19T09:00:00+02:00"`) || got.TimeZone != "Europe/Madrid" {
+				t.Errorf("expected the due time in the reminder's zone, got: %s", body)
+			}
+
+			switch {
+			case tt.secret == "" && signature != "":
+				t.Errorf("unexpected signature %q", signature)
+			case tt.secret != "" && signature != Sign([]byte(tt.secret), body):
+				t.Errorf("signature = %q, want %q", signature, Sign([]byte(tt.secret), body))
+			}
+		})
+	}
+}
+
+func TestWebhookDeliverer_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	err := NewWebhookDeliverer(url).Deliver(context.Background(), &model.Reminder{ID: primitive.NewObjectID()})
+	if err == nil {
+		t.Error("expected error for an unreachable webhook")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Reference value from: printf 'hello' | openssl dgst -sha256 -hmac key
+	want := "sha256=9307b3b915efb5171ff14d8cb55fbcc798c6c0ef1456d66ded1a6aa723a58b7b"
+	if got := Sign([]byte("key"), []byte("hello")); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,233 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Category groups units that convert into each other
+type Category string
+
+const (
+	Length      Category = "length"
+	Mass        Category = "mass"
+	Volume      Category = "volume"
+	Temperature Category = "temperature"
+	Speed       Category = "speed"
+	Area        Category = "area"
+	FuelEconomy Category = "fuel_economy"
+)
+
+// Unit is a unit of measurement. Values convert through the base unit of
+// the category: base = value*factor + offset, or factor/value for inverse
+// units such as litres per 100 km.
+type Unit struct {
+	ID   string // e.g. "km"
+	Name string // e.g. "kilometres"
+
+	factor  float64
+	offset  float64
+	inverse bool
+
+	// min and max bound meaningful values, e.g. absolute zero or the gas mark scale
+	min, max float64
+}
+
+// Conversions are exact by definition unless noted; US customary volumes
+// are used for cups, spoons, fluid ounces, pints, quarts and gallons.
+var measures = map[Category][]Unit{
+	Length: {
+		linear("mm", "millimetres", 0.001),
+		linear("cm", "centimetres", 0.01),
+		linear("m", "metres", 1),
+		linear("km", "kilometres", 1000),
+		linear("in", "inches", mmPerInch/1000),
+		linear("ft", "feet", 0.3048),
+		linear("yd", "yards", 0.9144),
+		linear("mi", "miles", kmPerMile*1000),
+		linear("nmi", "nautical miles", 1852),
+	},
+	Mass: {
+		linear("mg", "milligrams", 1e-6),
+		linear("g", "grams", 0.001),
+		linear("kg", "kilograms", 1),
+		linear("t", "tonnes", 1000),
+		linear("oz", "ounces", 0.028349523125),
+		linear("lb", "pounds", 0.45359237),
+		linear("st", "stone", 6.35029318),
+	},
+	Volume: {
+		linear("ml", "millilitres", 0.001),
+		linear("cl", "centilitres", 0.01),
+		linear("dl", "decilitres", 0.1),
+		linear("l", "litres", 1),
+		linear("m3", "cubic metres", 1000),
+		linear("tsp", "US teaspoons", 0.00492892159375),
+		linear("tbsp", "US tablespoons", 0.01478676478125),
+		linear("cup", "US cups", 0.2365882365),
+		linear("fl_oz", "US fluid ounces", 0.0295735295625),
+		linear("pt", "US pints", 0.473176473),
+		linear("qt", "US quarts", 0.946352946),
+		linear("gal", "US gallons", usGallonLitres),
+		linear("uk_fl_oz", "imperial fluid ounces", 0.0284130625),
+		linear("uk_pt", "imperial pints", 0.56826125),
+		linear("uk_gal", "imperial gallons", ukGallonLitres),
+	},
+	Temperature: {
+		{ID: "c", Name: "degrees Celsius", factor: 1, min: absoluteZero, max: math.Inf(1)},
+		{ID: "f", Name: "degrees Fahrenheit", factor: 5.0 / 9, offset: -32 * 5.0 / 9, min: -459.67, max: math.Inf(1)},
+		{ID: "k", Name: "kelvin", factor: 1, offset: absoluteZero, min: 0, max: math.Inf(1)},
+		// Gas mark n is 250°F + 25°F per mark, marks 1 to 10
+		{ID: "gas_mark", Name: "gas mark", factor: 25 * 5.0 / 9, offset: (250 - 32) * 5.0 / 9, min: 1, max: 10},
+	},
+	Speed: {
+		linear("m_s", "metres per second", 1),
+		linear("km_h", "kilometres per hour", 1/3.6),
+		linear("mph", "miles per hour", kmPerMile/3.6),
+		linear("kn", "knots", 1.852/3.6),
+		linear("ft_s", "feet per second", 0.3048),
+	},
+	Area: {
+		linear("cm2", "square centimetres", 1e-4),
+		linear("m2", "square metres", 1),
+		linear("ha", "hectares", 1e4),
+		linear("km2", "square kilometres", 1e6),
+		linear("in2", "square inches", 0.00064516),
+		linear("ft2", "square feet", 0.09290304),
+		linear("yd2", "square yards", 0.83612736),
+		linear("ac", "acres", 4046.8564224),
+		linear("mi2", "square miles", kmPerMile*kmPerMile*1e6),
+	},
+	FuelEconomy: {
+		linear("km_l", "kilometres per litre", 1),
+		{ID: "l_100km", Name: "litres per 100 km", factor: 100, inverse: true, max: math.Inf(1)},
+		linear("mpg", "US miles per gallon", kmPerMile/usGallonLitres),
+		linear("uk_mpg", "imperial miles per gallon", kmPerMile/ukGallonLitres),
+	},
+}
+
+const (
+	absoluteZero   = -273.15
+	usGallonLitres = 3.785411784
+	ukGallonLitres = 4.54609
+)
+
+// linear is a unit proportional to the base unit, which can't be negative
+func linear(id, name string, factor float64) Unit {
+	return Unit{ID: id, Name: name, factor: factor, max: math.Inf(1)}
+}
+
+// Categories lists every conversion category, measurements first
+func Categories() []Category {
+	return []Category{
+		Length, Mass, Volume, Temperature, Speed, Area, FuelEconomy,
+		ShoeMen, ShoeWomen, WomensClothing, MensSuit,
+	}
+}
+
+// Units lists the unit or size system IDs of a category
+func Units(c Category) []string {
+	if chart, ok := sizeCharts[c]; ok {
+		return slices.Clone(chart.systems)
+	}
+
+	var ids []string
+	for _, u := range measures[c] {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+// LookupUnit finds a unit of a measurement category by ID
+func LookupUnit(c Category, id string) (Unit, error) {
+	list, ok := measures[c]
+	if !ok {
+		return Unit{}, fmt.Errorf("unknown measurement category %q", c)
+	}
+
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, u := range list {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return Unit{}, fmt.Errorf("unknown %s unit %q, expected one of %s", c, id, strings.Join(Units(c), ", "))
+}
+
+// Convert converts a measurement between two units of a category.
+// Size charts are converted with ConvertSize.
+func Convert(c Category, value float64, from, to string) (float64, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("value must be a finite number")
+	}
+
+	src, err := LookupUnit(c, from)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := LookupUnit(c, to)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := src.check(value); err != nil {
+		return 0, err
+	}
+
+	result := dst.fromBase(src.toBase(value))
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return 0, fmt.Errorf("%v %s can't be expressed in %s", value, src.Name, dst.Name)
+	}
+	if err := dst.check(result); err != nil {
+		return 0, fmt.Errorf("%v %s is out of range for %s: %w", value, src.Name, dst.Name, err)
+	}
+	return result, nil
+}
+
+func (u Unit) toBase(v float64) float64 {
+	if u.inverse {
+		return u.factor / v
+	}
+	return v*u.factor + u.offset
+}
+
+func (u Unit) fromBase(v float64) float64 {
+	if u.inverse {
+		return u.factor / v
+	}
+	return (v - u.offset) / u.factor
+}
+
+// check rejects values outside the unit's range; inverse units also exclude zero
+func (u Unit) check(v float64) error {
+	// Allow rounding noise at the bounds, e.g. 0 K from -273.15 °C
+	const epsilon = 1e-9
+
+	if u.inverse && v <= 0 {
+		return fmt.Errorf("%s must be positive", u.Name)
+	}
+	if v < u.min-epsilon {
+		if u.min == 0 {
+			return fmt.Errorf("%s can't be negative", u.Name)
+		}
+		return fmt.Errorf("%s must be at least %v", u.Name, u.min)
+	}
+	if v > u.max+epsilon {
+		return fmt.Errorf("%s must be at most %v", u.Name, u.max)
+	}
+	return nil
+}
+
+// Round rounds v to the given number of significant digits,
+// so results don't show floating point noise
+func Round(v float64, digits int) float64 {
+	// Formatting rounds in decimal, avoiding the binary noise of scaling
+	r, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', digits, 64), 64)
+	if err != nil {
+		return v
+	}
+	return r
+}
@@ -0,0 +1,189 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert_RoundTrips(t *testing.T) {
+	// Every pair of units in every category converts back to the original value
+	values := map[Category]float64{
+		Length:      12.5,
+		Mass:        3.2,
+		Volume:      0.75,
+		Temperature: 200,
+		Speed:       88,
+		Area:        42,
+		FuelEconomy: 7.5,
+	}
+
+	for c, start := range values {
+		for _, from := range Units(c) {
+			for _, to := range Units(c) {
+				v := start
+				if from == "gas_mark" {
+					v = 5
+				}
+				if to == "gas_mark" && from != "gas_mark" {
+					// Stay within the gas mark scale: convert 5 from gas mark first
+					var err error
+					if v, err = Convert(c, 5, "gas_mark", from); err != nil {
+						t.Fatalf("%s: gas_mark to %s: %v", c, from, err)
+					}
+				}
+
+				there, err := Convert(c, v, from, to)
+				if err != nil {
+					t.Errorf("%s: %v %s to %s: %v", c, v, from, to, err)
+					continue
+				}
+				back, err := Convert(c, there, to, from)
+				if err != nil {
+					t.Errorf("%s: %v %s back to %s: %v", c, there, to, from, err)
+					continue
+				}
+				if math.Abs(back-v) > 1e-9*math.Max(1, math.Abs(v)) {
+					t.Errorf("%s: %v %s -> %v %s -> %v %s", c, v, from, there, to, back, from)
+				}
+			}
+		}
+	}
+}
+
+func TestConvert_References(t *testing.T) {
+	tests := []struct {
+		category Category
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{Length, 1, "mi", "km", 1.609344},
+		{Length, 1, "ft", "in", 12},
+		{Length, 1, "yd", "ft", 3},
+		{Mass, 1, "lb", "oz", 16},
+		{Mass, 1, "st", "lb", 14},
+		{Volume, 1, "gal", "qt", 4},
+		{Volume, 1, "qt", "pt", 2},
+		{Volume, 1, "pt", "cup", 2},
+		{Volume, 1, "cup", "fl_oz", 8},
+		{Volume, 1, "fl_oz", "tbsp", 2},
+		{Volume, 1, "uk_pt", "uk_fl_oz", 20},
+		{Volume, 1, "uk_gal", "uk_pt", 8},
+		{Temperature, 100, "c", "f", 212},
+		{Temperature, 0, "c", "k", 273.15},
+		{Temperature, 1, "gas_mark", "f", 275},
+		{Temperature, 10, "gas_mark", "f", 500},
+		{Speed, 1, "kn", "m_s", 1852.0 / 3600},
+		{Area, 1, "ha", "m2", 10000},
+		{Area, 640, "ac", "mi2", 1},
+		{FuelEconomy, 100, "l_100km", "km_l", 1},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.category, tt.value, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("%v %s to %s: %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if Round(got, 9) != Round(tt.want, 9) {
+			t.Errorf("%v %s = %v %s, want %v", tt.value, tt.from, got, tt.to, tt.want)
+		}
+	}
+}
+
+func TestConvert_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		category Category
+		value    float64
+		from, to string
+	}{
+		{"unknown category", "time", 1, "h", "min"},
+		{"unknown unit", Length, 1, "league", "km"},
+		{"unit of another category", Mass, 1, "km", "kg"},
+		{"size chart", ShoeMen, 10, "us", "eu"},
+		{"negative length", Length, -1, "m", "ft"},
+		{"below absolute zero", Temperature, -500, "f", "c"},
+		{"gas mark below the scale", Temperature, 0.5, "gas_mark", "c"},
+		{"result below the gas mark scale", Temperature, 100, "c", "gas_mark"},
+		{"zero consumption", FuelEconomy, 0, "l_100km", "mpg"},
+		{"not a number", Length, math.NaN(), "m", "ft"},
+		{"infinite", Length, math.Inf(1), "m", "ft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Convert(tt.category, tt.value, tt.from, tt.to); err == nil {
+				t.Errorf("expected error, got %v", got)
+			}
+		})
+	}
+}
+
+func TestConvertSize(t *testing.T) {
+	// Every size converts to every system and back
+	for c, chart := range sizeCharts {
+		for _, row := range chart.rows {
+			for i, from := range chart.systems {
+				for j, to := range chart.systems {
+					got, err := ConvertSize(c, row[i], from, to)
+					if err != nil {
+						t.Errorf("%s %s %v to %s: %v", c, from, row[i], to, err)
+						continue
+					}
+					if got != row[j] {
+						t.Errorf("%s %s %v = %v %s, want %v", c, from, row[i], got, to, row[j])
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := ConvertSize(ShoeMen, 10.25, "us", "eu"); err == nil {
+		t.Error("expected error for a size that is not on the chart")
+	}
+	if _, err := ConvertSize(WomensClothing, 8, "us", "jp"); err == nil {
+		t.Error("expected error for an unknown sizing system")
+	}
+	if _, err := ConvertSize(Length, 1, "m", "ft"); err == nil {
+		t.Error("expected error for a measurement category")
+	}
+}
+
+func TestSizeCharts_UniqueSizes(t *testing.T) {
+	// Lookups take the first matching row, so sizes must not repeat within a system
+	for c, chart := range sizeCharts {
+		for col, system := range chart.systems {
+			seen := make(map[float64]bool)
+			for _, row := range chart.rows {
+				if len(row) != len(chart.systems) {
+					t.Fatalf("%s: row %v doesn't match systems %v", c, row, chart.systems)
+				}
+				if seen[row[col]] {
+					t.Errorf("%s: %s %v appears twice", c, system, row[col])
+				}
+				seen[row[col]] = true
+			}
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want float64
+	}{
+		{6.213711922373339, 6.21371},
+		{0.1 + 0.2, 0.3},
+		{1609.344, 1609.34},
+		{0.000123456789, 0.000123457},
+		{-40.00000000001, -40},
+		{0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.v, 6); got != tt.want {
+			t.Errorf("Round(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
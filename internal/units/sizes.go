@@ -0,0 +1,154 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Size chart categories
+const (
+	ShoeMen        Category = "shoe_men"
+	ShoeWomen      Category = "shoe_women"
+	WomensClothing Category = "womens_clothing"
+	MensSuit       Category = "mens_suit"
+)
+
+// sizeChart maps equivalent sizes across sizing systems; each row is one size
+type sizeChart struct {
+	name    string
+	systems []string
+	rows    [][]float64
+}
+
+// Shoe charts follow common sportswear brand charts; "jp" is foot length in cm.
+// Suits are sized by chest in inches in the US and UK.
+var sizeCharts = map[Category]*sizeChart{
+	ShoeMen: {
+		name:    "men's shoe",
+		systems: []string{"us", "uk", "eu", "jp"},
+		rows: [][]float64{
+			{6, 5, 38.5, 24},
+			{6.5, 5.5, 39, 24.5},
+			{7, 6, 40, 25},
+			{7.5, 6.5, 40.5, 25.5},
+			{8, 7, 41, 26},
+			{8.5, 7.5, 42, 26.5},
+			{9, 8, 42.5, 27},
+			{9.5, 8.5, 43, 27.5},
+			{10, 9, 44, 28},
+			{10.5, 9.5, 44.5, 28.5},
+			{11, 10, 45, 29},
+			{11.5, 10.5, 45.5, 29.5},
+			{12, 11, 46, 30},
+			{13, 12, 47.5, 31},
+			{14, 13, 48.5, 32},
+		},
+	},
+	ShoeWomen: {
+		name:    "women's shoe",
+		systems: []string{"us", "uk", "eu", "jp"},
+		rows: [][]float64{
+			{5, 3, 35.5, 22},
+			{5.5, 3.5, 36, 22.5},
+			{6, 4, 36.5, 23},
+			{6.5, 4.5, 37.5, 23.5},
+			{7, 5, 38, 24},
+			{7.5, 5.5, 38.5, 24.5},
+			{8, 6, 39, 25},
+			{8.5, 6.5, 40, 25.5},
+			{9, 7, 40.5, 26},
+			{9.5, 7.5, 41, 26.5},
+			{10, 8, 42, 27},
+			{10.5, 8.5, 42.5, 27.5},
+			{11, 9, 43, 28},
+		},
+	},
+	WomensClothing: {
+		name:    "women's clothing",
+		systems: []string{"us", "uk", "eu"},
+		rows: [][]float64{
+			{0, 4, 32},
+			{2, 6, 34},
+			{4, 8, 36},
+			{6, 10, 38},
+			{8, 12, 40},
+			{10, 14, 42},
+			{12, 16, 44},
+			{14, 18, 46},
+			{16, 20, 48},
+			{18, 22, 50},
+		},
+	},
+	MensSuit: {
+		name:    "men's suit",
+		systems: []string{"us", "uk", "eu"},
+		rows: [][]float64{
+			{34, 34, 44},
+			{36, 36, 46},
+			{38, 38, 48},
+			{40, 40, 50},
+			{42, 42, 52},
+			{44, 44, 54},
+			{46, 46, 56},
+			{48, 48, 58},
+			{50, 50, 60},
+		},
+	},
+}
+
+// IsSizeChart reports whether c converts sizes rather than measurements
+func IsSizeChart(c Category) bool {
+	_, ok := sizeCharts[c]
+	return ok
+}
+
+// SizeChartName names a size chart for output, e.g. "men's shoe"
+func SizeChartName(c Category) string {
+	if chart, ok := sizeCharts[c]; ok {
+		return chart.name
+	}
+	return string(c)
+}
+
+// ConvertSize looks up the equivalent of a size in another sizing system.
+// Only sizes on the chart are accepted; charts differ between brands.
+func ConvertSize(c Category, size float64, from, to string) (float64, error) {
+	chart, ok := sizeCharts[c]
+	if !ok {
+		return 0, fmt.Errorf("unknown size chart %q", c)
+	}
+
+	src, err := chart.column(from)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := chart.column(to)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range chart.rows {
+		if math.Abs(row[src]-size) < 0.01 {
+			return row[dst], nil
+		}
+	}
+
+	sizes := make([]string, len(chart.rows))
+	for i, row := range chart.rows {
+		sizes[i] = strconv.FormatFloat(row[src], 'f', -1, 64)
+	}
+	return 0, fmt.Errorf("%s %v is not on the %s size chart, known sizes: %s",
+		strings.ToUpper(chart.systems[src]), size, chart.name, strings.Join(sizes, ", "))
+}
+
+func (c *sizeChart) column(system string) (int, error) {
+	system = strings.ToLower(strings.TrimSpace(system))
+	for i, s := range c.systems {
+		if s == system {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s sizing system %q, expected one of %s", c.name, system, strings.Join(c.systems, ", "))
+}
@@ -0,0 +1,123 @@
+// Package units holds the user's measurement system preference and renders
+// measurements in it. The preference travels with the request context,
+// so tools can format their output without knowing about conversations.
+// It also converts between units and clothing size charts.
+package units
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// System is a measurement system preference
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+	Both     System = "both"
+)
+
+// Default is used when no preference was given
+const Default = Both
+
+// Parse parses a preference, case-insensitively. An empty string yields Default.
+func Parse(s string) (System, error) {
+	switch v := System(strings.ToLower(strings.TrimSpace(s))); v {
+	case "":
+		return Default, nil
+	case Metric, Imperial, Both:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q, expected metric, imperial or both", s)
+	}
+}
+
+// OrDefault returns s, or Default when s is not set
+func (s System) OrDefault() System {
+	if s == "" {
+		return Default
+	}
+	return s
+}
+
+type contextKey struct{}
+
+// WithContext returns a context carrying the preference
+func WithContext(ctx context.Context, s System) context.Context {
+	return context.WithValue(ctx, contextKey{}, s.OrDefault())
+}
+
+// FromContext returns the preference carried by ctx, or Default
+func FromContext(ctx context.Context) System {
+	if s, ok := ctx.Value(contextKey{}).(System); ok {
+		return s
+	}
+	return Default
+}
+
+// Temperature renders a temperature given in degrees Celsius
+func (s System) Temperature(celsius float64) string {
+	return s.render(celsius, "%.1f°C", fahrenheit(celsius), "%.1f°F")
+}
+
+// TemperatureRange renders a low-high range given in degrees Celsius,
+// e.g. "15.0°C to 22.0°C (59.0°F to 71.6°F)"
+func (s System) TemperatureRange(low, high float64) string {
+	return s.pick(
+		fmt.Sprintf("%.1f°C to %.1f°C", low, high),
+		fmt.Sprintf("%.1f°F to %.1f°F", fahrenheit(low), fahrenheit(high)),
+	)
+}
+
+// Speed renders a speed given in km/h
+func (s System) Speed(kph float64) string {
+	return s.render(kph, "%.1f km/h", kph/kmPerMile, "%.1f mph")
+}
+
+// Distance renders a distance given in kilometres
+func (s System) Distance(km float64) string {
+	return s.render(km, "%.1f km", km/kmPerMile, "%.1f mi")
+}
+
+// Precipitation renders a precipitation amount given in millimetres
+func (s System) Precipitation(mm float64) string {
+	return s.render(mm, "%.1f mm", mm/mmPerInch, "%.2f in")
+}
+
+// Describe explains the preference in a sentence, for system prompts
+func (s System) Describe() string {
+	switch s.OrDefault() {
+	case Metric:
+		return "The user prefers metric units (°C, km, km/h, mm)."
+	case Imperial:
+		return "The user prefers imperial units (°F, miles, mph, inches)."
+	default:
+		return "Give measurements in both metric and imperial units."
+	}
+}
+
+const (
+	kmPerMile = 1.609344
+	mmPerInch = 25.4
+)
+
+func fahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+func (s System) render(metric float64, metricFormat string, imperial float64, imperialFormat string) string {
+	return s.pick(fmt.Sprintf(metricFormat, metric), fmt.Sprintf(imperialFormat, imperial))
+}
+
+func (s System) pick(metric, imperial string) string {
+	switch s.OrDefault() {
+	case Metric:
+		return metric
+	case Imperial:
+		return imperial
+	default:
+		return metric + " (" + imperial + ")"
+	}
+}
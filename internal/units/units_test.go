@@ -0,0 +1,94 @@
+package units
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    System
+		wantErr bool
+	}{
+		{input: "metric", want: Metric},
+		{input: " Imperial ", want: Imperial},
+		{input: "BOTH", want: Both},
+		{input: "", want: Default},
+		{input: "nautical", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("expected default without preference, got %q", got)
+	}
+	if got := FromContext(WithContext(context.Background(), Imperial)); got != Imperial {
+		t.Errorf("expected imperial, got %q", got)
+	}
+	if got := FromContext(WithContext(context.Background(), "")); got != Default {
+		t.Errorf("expected unset preference to fall back to default, got %q", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	tests := []struct {
+		name   string
+		format func(System) string
+		want   map[System]string
+	}{
+		{
+			name:   "temperature",
+			format: func(s System) string { return s.Temperature(18.5) },
+			want:   map[System]string{Metric: "18.5°C", Imperial: "65.3°F", Both: "18.5°C (65.3°F)"},
+		},
+		{
+			name:   "temperature range",
+			format: func(s System) string { return s.TemperatureRange(15, 22) },
+			want:   map[System]string{Metric: "15.0°C to 22.0°C", Imperial: "59.0°F to 71.6°F", Both: "15.0°C to 22.0°C (59.0°F to 71.6°F)"},
+		},
+		{
+			name:   "speed",
+			format: func(s System) string { return s.Speed(16.1) },
+			want:   map[System]string{Metric: "16.1 km/h", Imperial: "10.0 mph", Both: "16.1 km/h (10.0 mph)"},
+		},
+		{
+			name:   "distance",
+			format: func(s System) string { return s.Distance(10) },
+			want:   map[System]string{Metric: "10.0 km", Imperial: "6.2 mi", Both: "10.0 km (6.2 mi)"},
+		},
+		{
+			name:   "precipitation",
+			format: func(s System) string { return s.Precipitation(12.7) },
+			want:   map[System]string{Metric: "12.7 mm", Imperial: "0.50 in", Both: "12.7 mm (0.50 in)"},
+		},
+	}
+
+	for _, tt := range tests {
+		for system, want := range tt.want {
+			t.Run(tt.name+"/"+string(system), func(t *testing.T) {
+				if got := tt.format(system); got != want {
+					t.Errorf("got %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}